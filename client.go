@@ -14,10 +14,10 @@ package ditto
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/protocol"
 	MQTT "github.com/eclipse/paho.mqtt.golang"
-	"github.com/google/uuid"
 )
 
 var (
@@ -27,16 +27,21 @@ var (
 	ErrSubscribeTimeout = errors.New("subscribe timeout")
 	// ErrUnsubscribeTimeout is an error that unsubscription confirmation is not received within the timeout.
 	ErrUnsubscribeTimeout = errors.New("unsubscribe timeout")
+	// ErrIdleTimeout is an error that the Client's handler invocations and publishes are not finished within the timeout.
+	ErrIdleTimeout = errors.New("idle timeout")
 )
 
 // honoClient is the Ditto's library Client's implementation over Hono(MQTT) transport.
 type honoClient struct {
 	cfg                *Configuration
 	pahoClient         MQTT.Client
-	handlers           map[string]Handler
+	handlers           []subscribedHandler
+	lastHandlerID      HandlerID
 	handlersLock       sync.RWMutex
 	externalMQTTClient bool
 	wgConnectHandler   sync.WaitGroup
+	activity           activity
+	stats              statistics
 }
 
 // NewClient creates a new Client instance with the provided Configuration.
@@ -46,8 +51,7 @@ func NewClient(cfg *Configuration) Client {
 	}
 
 	client := &honoClient{
-		cfg:      cfg,
-		handlers: map[string]Handler{},
+		cfg: cfg,
 	}
 	return client
 }
@@ -62,6 +66,10 @@ func NewClient(cfg *Configuration) Client {
 // subscribe and unsubscribe timeout. As an external MQTT client is used, other fields are not needed and
 // regarded as invalid ones.
 //
+// Multiple Clients could be created using the same MQTT client. The MQTT subscriptions are then shared between them,
+// with each of them receiving all incoming messages, and are kept until the last of them is disconnected.
+// In that case, the QoS of a shared subscription is the one requested by the first Client that subscribed to it.
+//
 // Returns an error if the provided MQTT client is not connected or the Configuration contains invalid fields.
 func NewClientMQTT(mqttClient MQTT.Client, cfg *Configuration) (Client, error) {
 	if !mqttClient.IsConnected() {
@@ -91,42 +99,44 @@ func NewClientMQTT(mqttClient MQTT.Client, cfg *Configuration) (Client, error) {
 // there is a provided ConnectHandler, it will be notified.
 // In the case of an external MQTT client, if any error occurs during the internal preparations - it's returned here.
 func (client *honoClient) Connect() error {
+	if err := validateConnectConfiguration(client.cfg); err != nil {
+		return err
+	}
+	client.stats.reset()
 	if client.externalMQTTClient {
 		client.wgConnectHandler.Add(1)
 
-		token := client.pahoClient.Subscribe(honoMQTTTopicSubscribeCommands, 1, client.honoMessageHandler)
-		if !token.WaitTimeout(client.cfg.subscribeTimeout) || token.Error() != nil {
-			client.wgConnectHandler.Done()
-			if err := token.Error(); err != nil {
+		if client.cfg.CommandSubscription() {
+			err := sharedSubscriptions.subscribe(client.pahoClient, client.commandsTopic(), client, client.honoMessageHandler,
+				func(handler MQTT.MessageHandler) error {
+					token := client.pahoClient.Subscribe(client.commandsTopic(), client.cfg.commandSubscriptionQoS(), handler)
+					if !token.WaitTimeout(client.cfg.subscribeTimeout) || token.Error() != nil {
+						if err := token.Error(); err != nil {
+							return err
+						}
+						return ErrSubscribeTimeout
+					}
+					return nil
+				})
+			if err != nil {
+				client.wgConnectHandler.Done()
 				return err
 			}
-			return ErrSubscribeTimeout
+		}
+		if err := client.subscribeAdditional(); err != nil {
+			if client.cfg.CommandSubscription() {
+				client.rollbackSubscriptions(client.commandsTopic())
+			}
+			client.wgConnectHandler.Done()
+			return err
 		}
 
 		go client.notifyClientConnected()
 		return nil
 	}
 
-	pahoOpts := MQTT.NewClientOptions().
-		AddBroker(client.cfg.broker).
-		SetClientID(uuid.New().String()).
-		SetDefaultPublishHandler(client.defaultMessageHandler).
-		SetKeepAlive(client.cfg.keepAlive).
-		SetCleanSession(true).
-		SetAutoReconnect(true).
-		SetOnConnectHandler(client.clientConnectHandler).
-		SetConnectionLostHandler(client.clientConnectionLostHandler).
-		SetTLSConfig(client.cfg.tlsConfig).
-		SetConnectTimeout(client.cfg.connectTimeout)
-
-	if client.cfg.credentials != nil {
-		pahoOpts = pahoOpts.SetCredentialsProvider(func() (username string, password string) {
-			return client.cfg.credentials.Username, client.cfg.credentials.Password
-		})
-	}
-
 	//create and start a client using the created ClientOptions
-	client.pahoClient = MQTT.NewClient(pahoOpts)
+	client.pahoClient = MQTT.NewClient(client.pahoOptions())
 
 	if token := client.pahoClient.Connect(); token.Wait() && token.Error() != nil {
 		return token.Error()
@@ -137,21 +147,31 @@ func (client *honoClient) Connect() error {
 // Disconnect in the case of an external MQTT client, only undoes internal preparations, otherwise - it also disconnects
 // the client from the configured Ditto endpoint. A call to Disconnect will cause a ConnectionLostHandler to be notified
 // only if an external MQTT client is used.
+//
+// In the case of an external MQTT client, the subscriptions shared with other Clients using the same MQTT client
+// are kept until the last of them is disconnected.
 func (client *honoClient) Disconnect() {
 	var err error
-	token := client.pahoClient.Unsubscribe(honoMQTTTopicSubscribeCommands)
-	if token.WaitTimeout(client.cfg.unsubscribeTimeout) {
-		err = token.Error()
-		if client.externalMQTTClient && err == MQTT.ErrNotConnected {
+	var topics []string
+	if client.cfg.CommandSubscription() {
+		topics = append(topics, client.commandsTopic())
+	}
+	for _, subscription := range client.cfg.subscriptions {
+		topics = append(topics, subscription.Topic)
+	}
+	if client.externalMQTTClient {
+		// the topics are unsubscribed from only if no other Client shares them
+		err = sharedSubscriptions.unsubscribe(client.pahoClient, client, topics, client.unsubscribe)
+		if err == MQTT.ErrNotConnected {
 			go client.notifyClientConnectionLost(err) // expected: external MQTT client has already been disconnected
 			return
 		}
 	} else {
-		err = ErrUnsubscribeTimeout
+		err = client.unsubscribe(topics...)
 	}
 
 	if err != nil {
-		ERROR.Printf("error while disconnecting client: %v", err)
+		client.log(LevelError, "error while disconnecting client", Field(LogKeyError, err))
 	}
 
 	if client.externalMQTTClient { // do not disconnect when external MQTT client, the connection should be managed only externally
@@ -165,47 +185,180 @@ func (client *honoClient) Disconnect() {
 // The requestID must be the same as the one provided with the request protocol.Envelope.
 // An error is returned if the reply could not be sent for some reason.
 func (client *honoClient) Reply(requestID string, message *protocol.Envelope) error {
-	if err := client.publish(generateHonoResponseTopic(requestID, message.Status), message, 1, false); err != nil {
+	if err := client.publish(client.responseTopic(requestID, message.Status), message, 1, false); err != nil {
 		return err
 	}
+	client.stats.replySent(requestID, time.Now())
 	return nil
 }
 
+// ReplyTo is an auxiliary method to send replies to the provided request protocol.Envelope received along with the provided requestID.
+// The reply is sent with the request's 'correlation-id', 'content-type' and 'ditto-originator' headers, unless it has such,
+// and with a 'response-required' header of false.
+func (client *honoClient) ReplyTo(requestID string, request *protocol.Envelope, message *protocol.Envelope) error {
+	return client.Reply(requestID, withReplyHeaders(request, message))
+}
+
 // Send sends a protocol.Envelope to the Client's configured Ditto endpoint.
 func (client *honoClient) Send(message *protocol.Envelope) error {
-	if err := client.publish(honoMQTTTopicPublishEvents, message, 1, false); err != nil {
+	if client.cfg.sendDefaults {
+		message = withSendDefaults(message)
+	}
+	if err := client.publish(client.eventsTopic(), message, 1, false); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Subscribe ensures that all incoming Ditto messages will be transferred to the provided Handlers.
+// SendBatch sends the provided protocol.Envelopes to the Client's configured Ditto endpoint in their order.
+// The Envelopes are encoded into a single reusable buffer and published with up to MaxInflight of them waiting
+// to be acknowledged at a time. No further Envelopes are sent after the first failure, whose error is returned
+// along with the failed Envelope's index.
+func (client *honoClient) SendBatch(messages []*protocol.Envelope) error {
+	if client.cfg.sendDefaults {
+		defaulted := make([]*protocol.Envelope, len(messages))
+		for i, message := range messages {
+			defaulted[i] = withSendDefaults(message)
+		}
+		messages = defaulted
+	}
+	return client.publishBatch(client.eventsTopic(), messages, 1)
+}
+
+// SendFrozen sends the provided protocol.FrozenEnvelope to the Client's configured Ditto endpoint with a newly
+// generated 'correlation-id' and the current 'creation-time' headers. The Envelope is sent as it has been frozen,
+// i.e. the configured creation time stamping, compression and CBOR encoding are not applied to it.
+func (client *honoClient) SendFrozen(message *protocol.FrozenEnvelope) error {
+	client.activity.start()
+	defer client.activity.done()
+
+	buf := protocol.GetBuffer()
+	message.EncodeTo(buf, protocol.NewCorrelationID(), time.Now())
+	return client.publishBuffer(client.eventsTopic(), buf, 1, false)
+}
+
+// Stats provides a snapshot of the Client's statistics accumulated since it was last connected.
+func (client *honoClient) Stats() Stats {
+	return client.stats.snapshot()
+}
+
+// Subscribe ensures that all incoming Ditto messages will be transferred to the provided Handlers and provides
+// the IDs of the Handlers' subscriptions in the same order. Each provided Handler gets a subscription of its own,
+// e.g. each of multiple closures created by the same function literal. The Handlers are called in the order of their subscribing.
 // As subscribing in Ditto is transport-specific - this is a lightweight version of a default subscription that is applicable in the MQTT use case.
-func (client *honoClient) Subscribe(handlers ...Handler) {
+func (client *honoClient) Subscribe(handlers ...Handler) []HandlerID {
+	return client.subscribe(handlers, false)
+}
+
+// SubscribeOnce ensures that all incoming Ditto messages will be transferred to the provided Handlers and provides
+// the IDs of the Handlers' subscriptions in the same order. Unlike Subscribe, the Handlers already subscribed,
+// i.e. the ones with the same code as a subscribed Handler, are not subscribed again and the IDs of their existing
+// subscriptions are provided, so that SubscribeOnce could be safely called on each (re)connect.
+func (client *honoClient) SubscribeOnce(handlers ...Handler) []HandlerID {
+	return client.subscribe(handlers, true)
+}
+
+func (client *honoClient) subscribe(handlers []Handler, existing bool) []HandlerID {
 	client.handlersLock.Lock()
 	defer client.handlersLock.Unlock()
 
-	if client.handlers == nil {
-		client.handlers = make(map[string]Handler)
+	ids := make([]HandlerID, 0, len(handlers))
+	for _, handler := range handlers {
+		code := handlerCode(handler)
+		if existing {
+			if id, ok := client.subscriptionID(code); ok {
+				ids = append(ids, id)
+				continue
+			}
+		}
+		client.lastHandlerID++
+		client.handlers = append(client.handlers, subscribedHandler{
+			id:      client.lastHandlerID,
+			handler: handler,
+			code:    code,
+		})
+		ids = append(ids, client.lastHandlerID)
 	}
+	return ids
+}
 
-	for _, handler := range handlers {
-		client.handlers[getHandlerName(handler)] = handler
+// subscriptionID provides the ID of the first subscription of a Handler with the provided code, if any.
+func (client *honoClient) subscriptionID(code uintptr) (HandlerID, bool) {
+	for _, subscribed := range client.handlers {
+		if subscribed.code == code {
+			return subscribed.id, true
+		}
 	}
+	return 0, false
 }
 
 // Unsubscribe cancels sending incoming Ditto messages from the client to the provided Handlers
 // and removes them from the subscriptions list of the client.
+// The subscriptions of all Handlers with the same code as any of the provided ones are removed,
+// e.g. the ones of all closures created by the same function literal - UnsubscribeIDs is to be used for such Handlers.
 // If Unsubscribe is called without arguments, it will cancel and remove all currently subscribed Handlers.
 func (client *honoClient) Unsubscribe(handlers ...Handler) {
 	client.handlersLock.Lock()
 	defer client.handlersLock.Unlock()
 
 	if len(handlers) == 0 {
-		client.handlers = make(map[string]Handler)
-	} else {
-		for _, handler := range handlers {
-			delete(client.handlers, getHandlerName(handler))
+		client.handlers = nil
+		return
+	}
+	codes := make(map[uintptr]bool, len(handlers))
+	for _, handler := range handlers {
+		codes[handlerCode(handler)] = true
+	}
+	client.removeHandlers(func(subscribed subscribedHandler) bool {
+		return codes[subscribed.code]
+	})
+}
+
+// UnsubscribeIDs cancels the subscriptions with the provided IDs as returned by Subscribe.
+// Unknown IDs, e.g. of already cancelled subscriptions, are ignored.
+func (client *honoClient) UnsubscribeIDs(ids ...HandlerID) {
+	client.handlersLock.Lock()
+	defer client.handlersLock.Unlock()
+
+	removed := make(map[HandlerID]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	client.removeHandlers(func(subscribed subscribedHandler) bool {
+		return removed[subscribed.id]
+	})
+}
+
+// Handlers provides the descriptions of the current subscriptions of Handlers in the order of their subscribing.
+func (client *honoClient) Handlers() []HandlerInfo {
+	client.handlersLock.RLock()
+	handlers := client.handlers
+	client.handlersLock.RUnlock()
+
+	infos := make([]HandlerInfo, len(handlers))
+	for i, subscribed := range handlers {
+		infos[i] = HandlerInfo{ID: subscribed.id, Name: handlerName(subscribed.code)}
+	}
+	return infos
+}
+
+// WaitForIdle waits until all Handler invocations dispatched and messages being published by the time of the call finish.
+// ErrIdleTimeout is returned if they don't finish within the provided timeout.
+func (client *honoClient) WaitForIdle(timeout time.Duration) error {
+	if !client.activity.wait(timeout) {
+		return ErrIdleTimeout
+	}
+	return nil
+}
+
+// removeHandlers removes the subscribed Handlers matching the provided predicate.
+// As the Handlers are dispatched from a snapshot of the slice, it is never modified in place.
+func (client *honoClient) removeHandlers(remove func(subscribed subscribedHandler) bool) {
+	var remaining []subscribedHandler
+	for _, subscribed := range client.handlers {
+		if !remove(subscribed) {
+			remaining = append(remaining, subscribed)
 		}
 	}
+	client.handlers = remaining
 }
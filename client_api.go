@@ -12,6 +12,8 @@
 package ditto
 
 import (
+	"time"
+
 	"github.com/eclipse/ditto-clients-golang/protocol"
 )
 
@@ -20,6 +22,51 @@ import (
 // it's also provided to the handler so that chained responses to the ID can be later sent properly.
 type Handler func(requestID string, message *protocol.Envelope)
 
+// RawHandler represents a callback handler that is called on each message received via an additional Subscription.
+// The message is provided as is, along with the MQTT topic it has been received on, i.e. it is not required to be a Ditto message.
+type RawHandler func(topic string, payload []byte)
+
+// Direction is the direction of the MQTT traffic of the Client.
+type Direction int
+
+// Directions of the MQTT traffic of the Client.
+const (
+	// DirectionOutgoing is the direction of the messages published by the Client.
+	DirectionOutgoing Direction = iota
+	// DirectionIncoming is the direction of the messages received by the Client.
+	DirectionIncoming
+)
+
+// String provides the string representation of the Direction, i.e. 'outgoing' or 'incoming'.
+func (direction Direction) String() string {
+	if direction == DirectionIncoming {
+		return "incoming"
+	}
+	return "outgoing"
+}
+
+// WireHook represents a callback that is called with the MQTT topic, the direction and the raw payload of each message
+// published or received by the Client, i.e. after the outgoing Envelopes are serialized and before the incoming ones are
+// deserialized, so that protocol issues could be debugged. It's called synchronously, thus it is to return quickly,
+// and the payload must not be modified or retained after the call.
+type WireHook func(topic string, direction Direction, payload []byte)
+
+// HandlerID is an opaque identifier of a Handler's subscription to a Client as returned by Subscribe.
+// The IDs are unique per Client, so that multiple subscriptions of the same Handler, e.g. closures created
+// by the same function literal or method values of different receivers, could be distinguished.
+type HandlerID uint64
+
+// HandlerInfo describes a Handler's subscription to a Client, e.g. for supervisory code and debug endpoints
+// to display what the Client is currently subscribed to.
+type HandlerInfo struct {
+	// ID is the ID of the subscription as returned by Subscribe.
+	ID HandlerID
+	// Name is the name of the Handler's function, e.g. 'main.messagesHandler' or 'main.newHandler.func1' for closures.
+	Name string
+}
+
+//go:generate mockgen -destination=mock/mock_client.go -package=mock github.com/eclipse/ditto-clients-golang Client
+
 // Client is the Ditto's library main interface definition. The interface is intended to abstract multiple implementations
 // over different transports. Client has connect/disconnect capabilities along with the options to subscribe/unsubscribe
 // for receiving all Ditto messages being exchanged using the underlying transport.
@@ -40,15 +87,60 @@ type Client interface {
 	// An error is returned if the reply could not be sent for some reason.
 	Reply(requestID string, message *protocol.Envelope) error
 
+	// ReplyTo is an auxiliary method to send replies to the provided request protocol.Envelope received along with the provided requestID.
+	// The reply is sent with the request's 'correlation-id', 'content-type' and 'ditto-originator' headers, unless it has such,
+	// and with a 'response-required' header of false. The provided reply protocol.Envelope is not modified.
+	// An error is returned if the reply could not be sent for some reason.
+	ReplyTo(requestID string, request *protocol.Envelope, message *protocol.Envelope) error
+
 	// Send sends a protocol.Envelope to the Client's configured Ditto endpoint.
 	// An error is returned if the envelope could not be sent for some reason.
 	Send(message *protocol.Envelope) error
 
-	// Subscribe ensures that all incoming Ditto messages will be transferred to the provided Handlers.
-	Subscribe(handlers ...Handler)
+	// SendBatch sends the provided protocol.Envelopes to the Client's configured Ditto endpoint in their order,
+	// pipelining them without waiting for each one to be acknowledged, e.g. for telemetry aggregators flushing
+	// multiple Envelopes at once. No further Envelopes are sent after the first failure, whose error is returned
+	// along with the failed Envelope's index.
+	SendBatch(messages []*protocol.Envelope) error
+
+	// SendFrozen sends the provided protocol.FrozenEnvelope to the Client's configured Ditto endpoint with a newly
+	// generated 'correlation-id' and the current 'creation-time' headers, without encoding the Envelope again.
+	// An error is returned if the envelope could not be sent for some reason.
+	SendFrozen(message *protocol.FrozenEnvelope) error
+
+	// Subscribe ensures that all incoming Ditto messages will be transferred to the provided Handlers and provides
+	// the IDs of the Handlers' subscriptions in the same order, to be used for unsubscribing via UnsubscribeIDs.
+	// Each provided Handler gets a subscription of its own, e.g. each of multiple closures created by the same
+	// function literal or method values of different receivers.
+	Subscribe(handlers ...Handler) []HandlerID
+
+	// SubscribeOnce ensures that all incoming Ditto messages will be transferred to the provided Handlers and provides
+	// the IDs of the Handlers' subscriptions in the same order. Unlike Subscribe, the already subscribed Handlers,
+	// i.e. the ones with the same code as a subscribed Handler, are not subscribed again and the IDs of their existing
+	// subscriptions are provided, so that SubscribeOnce could be safely called on each (re)connect.
+	SubscribeOnce(handlers ...Handler) []HandlerID
 
 	// Unsubscribe cancels sending incoming Ditto messages from the client to the provided Handlers
 	// and removes them from the subscriptions list of the client.
+	// As functions are not comparable, the subscriptions of all Handlers with the same code as any of the provided ones
+	// are removed, e.g. the ones of all closures created by the same function literal. UnsubscribeIDs is to be used
+	// to remove specific subscriptions.
 	// If Unsubscribe is called without arguments, it will cancel and remove all currently subscribed Handlers.
 	Unsubscribe(handlers ...Handler)
+
+	// UnsubscribeIDs cancels the subscriptions with the provided IDs as returned by Subscribe.
+	// Unknown IDs, e.g. of already cancelled subscriptions, are ignored.
+	UnsubscribeIDs(ids ...HandlerID)
+
+	// Handlers provides the descriptions of the current subscriptions of Handlers in the order of their subscribing.
+	Handlers() []HandlerInfo
+
+	// Stats provides a snapshot of the Client's statistics accumulated since it was last connected, e.g. the numbers
+	// of the sent and received messages and the reply latencies, as a lightweight alternative to a full metrics integration.
+	Stats() Stats
+
+	// WaitForIdle waits until all Handler invocations dispatched and messages being sent by the time of the call finish,
+	// e.g. for tests and batch jobs that must not exit while the received commands are still being processed.
+	// An error is returned if they don't finish within the provided timeout.
+	WaitForIdle(timeout time.Duration) error
 }
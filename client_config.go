@@ -14,6 +14,9 @@ package ditto
 import (
 	"crypto/tls"
 	"time"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
 )
 
 const (
@@ -23,6 +26,7 @@ const (
 	defaultAcknowledgeTimeout = 15 * time.Second
 	defaultSubscribeTimeout   = 15 * time.Second
 	defaultUnsubscribeTimeout = 5 * time.Second
+	defaultMaxInflight        = 10
 )
 
 // ConnectHandler is called when a successful connection to the configured Ditto endpoint is established and
@@ -38,6 +42,42 @@ type Credentials struct {
 	Password string
 }
 
+// Subscription represents an additional MQTT subscription of the Client, applied next to the default one for Ditto commands.
+// Each message received on the Topic is transferred to the Handler as a Ditto message and/or to the RawHandler as is.
+// The Subscription is (re)applied each time the Client's connection is established and removed on disconnect.
+type Subscription struct {
+	Topic      string
+	QoS        byte
+	Handler    Handler
+	RawHandler RawHandler
+}
+
+// MQTTProtocolVersion defines the MQTT protocol version the Client connects with.
+type MQTTProtocolVersion uint
+
+const (
+	// MQTTProtocolVersionDefault connects with MQTT 3.1.1, falling back to MQTT 3.1 if the broker rejects it.
+	MQTTProtocolVersionDefault MQTTProtocolVersion = 0
+	// MQTTProtocolVersion31 connects with MQTT 3.1 only.
+	MQTTProtocolVersion31 MQTTProtocolVersion = 3
+	// MQTTProtocolVersion311 connects with MQTT 3.1.1 only.
+	MQTTProtocolVersion311 MQTTProtocolVersion = 4
+)
+
+// CommandQoS defines the MQTT QoS of the Client's default subscription for Ditto commands, i.e. 0, 1 or 2.
+type CommandQoS byte
+
+// MQTT QoS levels of the Client's default subscription for Ditto commands.
+const (
+	// CommandQoSAtMostOnce subscribes with QoS 0, e.g. for consumers of events only, which could afford losing messages
+	// for a higher throughput.
+	CommandQoSAtMostOnce CommandQoS = 0
+	// CommandQoSAtLeastOnce subscribes with QoS 1, which is the default.
+	CommandQoSAtLeastOnce CommandQoS = 1
+	// CommandQoSExactlyOnce subscribes with QoS 2.
+	CommandQoSExactlyOnce CommandQoS = 2
+)
+
 // Configuration provides the Client's configuration.
 type Configuration struct {
 	broker                string
@@ -51,17 +91,40 @@ type Configuration struct {
 	connectionLostHandler ConnectionLostHandler
 	tlsConfig             *tls.Config
 	credentials           *Credentials
+	subscriptions         []*Subscription
+	stampCreationTime     bool
+	cborEncoding          bool
+	compressionThreshold  int
+	maxDecompressedSize   int
+	decoder               *protocol.Decoder
+	logger                StructuredLogger
+	wireHook              WireHook
+	encodingBufferSize    int
+	inlineDispatch        bool
+	handlerEnvelopeCopies bool
+	maxInflightMessages   int
+	protocolVersion       MQTTProtocolVersion
+	clientID              string
+	store                 MQTT.Store
+	sendDefaults          bool
+	commandQoS            CommandQoS
+	commandQoSSet         bool
+	noCommandSubscription bool
+	shareGroup            string
+	tenantID              string
+	deviceID              string
 }
 
 // NewConfiguration creates a new Configuration instance.
 func NewConfiguration() *Configuration {
 	return &Configuration{
-		keepAlive:          defaultKeepAlive,
-		disconnectTimeout:  defaultDisconnectTimeout,
-		connectTimeout:     defaultConnectTimeout,
-		acknowledgeTimeout: defaultAcknowledgeTimeout,
-		subscribeTimeout:   defaultSubscribeTimeout,
-		unsubscribeTimeout: defaultUnsubscribeTimeout,
+		keepAlive:           defaultKeepAlive,
+		disconnectTimeout:   defaultDisconnectTimeout,
+		connectTimeout:      defaultConnectTimeout,
+		acknowledgeTimeout:  defaultAcknowledgeTimeout,
+		subscribeTimeout:    defaultSubscribeTimeout,
+		unsubscribeTimeout:  defaultUnsubscribeTimeout,
+		maxInflightMessages: defaultMaxInflight,
 	}
 }
 
@@ -126,6 +189,134 @@ func (cfg *Configuration) TLSConfig() *tls.Config {
 	return cfg.tlsConfig
 }
 
+// Subscriptions provides the currently configured additional subscriptions.
+func (cfg *Configuration) Subscriptions() []*Subscription {
+	return cfg.subscriptions
+}
+
+// StampCreationTime provides if the 'creation-time' header is automatically set to all outgoing messages that don't have it.
+// The default is false.
+func (cfg *Configuration) StampCreationTime() bool {
+	return cfg.stampCreationTime
+}
+
+// CBOREncoding provides if the messages sent by the Client are CBOR encoded instead of JSON encoded.
+// The default is false.
+func (cfg *Configuration) CBOREncoding() bool {
+	return cfg.cborEncoding
+}
+
+// CompressionThreshold provides the minimum size in bytes of the outgoing messages' values to be gzip compressed.
+// The default is 0, meaning that no values are compressed.
+func (cfg *Configuration) CompressionThreshold() int {
+	return cfg.compressionThreshold
+}
+
+// MaxDecompressedSize provides the maximum size in bytes of the incoming messages' decompressed values.
+// The default is 0, meaning that protocol.DefaultMaxDecompressedSize applies.
+func (cfg *Configuration) MaxDecompressedSize() int {
+	return cfg.maxDecompressedSize
+}
+
+// Decoder provides the protocol.Decoder the incoming messages are decoded with.
+// The default is nil, meaning that they are decoded as by the zero protocol.Decoder.
+func (cfg *Configuration) Decoder() *protocol.Decoder {
+	return cfg.decoder
+}
+
+// Logger provides the currently configured StructuredLogger of the Client.
+// The default is nil, meaning that the Client's output is written to the package's DefaultLogger.
+func (cfg *Configuration) Logger() StructuredLogger {
+	return cfg.logger
+}
+
+// WireHook provides the currently configured WireHook.
+func (cfg *Configuration) WireHook() WireHook {
+	return cfg.wireHook
+}
+
+// EncodingBufferSize provides the size in bytes the buffers used to encode the outgoing messages are pre-sized to.
+// The default is 0, meaning that the buffers grow as needed.
+func (cfg *Configuration) EncodingBufferSize() int {
+	return cfg.encodingBufferSize
+}
+
+// InlineDispatch provides if the Handlers are called on the goroutine receiving the messages instead of in their own goroutines.
+// The default is false.
+func (cfg *Configuration) InlineDispatch() bool {
+	return cfg.inlineDispatch
+}
+
+// HandlerEnvelopeCopies provides if each Handler is provided with its own copy of the received messages.
+// The default is false.
+func (cfg *Configuration) HandlerEnvelopeCopies() bool {
+	return cfg.handlerEnvelopeCopies
+}
+
+// MaxInflight provides the maximum number of messages sent via SendBatch that are published without being acknowledged yet.
+// The default is 10.
+func (cfg *Configuration) MaxInflight() int {
+	return cfg.maxInflightMessages
+}
+
+// ProtocolVersion provides the MQTT protocol version the Client connects with.
+// The default is MQTTProtocolVersionDefault.
+func (cfg *Configuration) ProtocolVersion() MQTTProtocolVersion {
+	return cfg.protocolVersion
+}
+
+// ClientID provides the MQTT client ID the Client connects with.
+// The default is empty, meaning that a random client ID is generated for each Client.
+func (cfg *Configuration) ClientID() string {
+	return cfg.clientID
+}
+
+// Store provides the MQTT store the Client's in-flight messages are persisted to.
+// The default is nil, meaning that the messages are kept in memory for the current session only.
+func (cfg *Configuration) Store() MQTT.Store {
+	return cfg.store
+}
+
+// SendDefaults provides if the messages sent via Send and SendBatch are provided with a generated 'correlation-id'
+// and a 'response-required' header of false if they don't have such.
+// The default is false.
+func (cfg *Configuration) SendDefaults() bool {
+	return cfg.sendDefaults
+}
+
+// CommandQoS provides the QoS of the Client's default MQTT subscription for Ditto commands.
+// The default is CommandQoSAtLeastOnce, i.e. QoS 1.
+func (cfg *Configuration) CommandQoS() CommandQoS {
+	if !cfg.commandQoSSet {
+		return CommandQoSAtLeastOnce
+	}
+	return cfg.commandQoS
+}
+
+// CommandSubscription provides if the Client subscribes to the incoming Ditto messages on connect.
+// The default is true.
+func (cfg *Configuration) CommandSubscription() bool {
+	return !cfg.noCommandSubscription
+}
+
+// ShareGroup provides the group of the MQTT shared subscription the Client subscribes to the incoming Ditto messages with.
+// The default is empty, i.e. the subscription is not shared.
+func (cfg *Configuration) ShareGroup() string {
+	return cfg.shareGroup
+}
+
+// TenantID provides the Hono tenant ID included in the Client's MQTT topics.
+// The default is empty, i.e. the tenant is determined by the broker on authentication.
+func (cfg *Configuration) TenantID() string {
+	return cfg.tenantID
+}
+
+// DeviceID provides the Hono device ID included in the Client's MQTT topics.
+// The default is empty, i.e. the device is determined by the broker on authentication.
+func (cfg *Configuration) DeviceID() string {
+	return cfg.deviceID
+}
+
 // WithBroker configures the MQTT's broker the Client to connect to.
 func (cfg *Configuration) WithBroker(broker string) *Configuration {
 	cfg.broker = broker
@@ -192,3 +383,186 @@ func (cfg *Configuration) WithTLSConfig(tlsConfig *tls.Config) *Configuration {
 	initCipherSutesMinVersion(cfg.tlsConfig)
 	return cfg
 }
+
+// WithSubscriptions configures additional MQTT subscriptions to be managed by the Client along with the default one for Ditto commands.
+func (cfg *Configuration) WithSubscriptions(subscriptions ...*Subscription) *Configuration {
+	cfg.subscriptions = subscriptions
+	return cfg
+}
+
+// WithStampCreationTime configures if the 'creation-time' header is to be automatically set to the current time
+// for all messages sent by the Client that don't have it. Combined with the 'ttl' header, this allows
+// receivers to skip expired messages.
+func (cfg *Configuration) WithStampCreationTime(stampCreationTime bool) *Configuration {
+	cfg.stampCreationTime = stampCreationTime
+	return cfg
+}
+
+// WithCBOREncoding configures if the messages sent by the Client are to be CBOR (RFC 8949) encoded instead of JSON encoded,
+// which reduces the bandwidth for constrained devices. It must be enabled only if the receiving side supports CBOR.
+// Incoming messages are always accepted in both CBOR and JSON encodings.
+func (cfg *Configuration) WithCBOREncoding(cborEncoding bool) *Configuration {
+	cfg.cborEncoding = cborEncoding
+	return cfg
+}
+
+// WithCompressionThreshold configures the minimum size in bytes of the JSON representation of the outgoing messages' values
+// to be transparently gzip compressed, signaled via the 'content-encoding' header. A threshold of 0 disables the compression.
+// If the compression is enabled, gzip compressed incoming messages' values are transparently decompressed as well,
+// while values with other content encodings are passed to the handlers as they are.
+// Ditto itself does not decode compressed values, so the compression must be enabled only if the receiving side,
+// e.g. a business application, decompresses them.
+func (cfg *Configuration) WithCompressionThreshold(threshold int) *Configuration {
+	cfg.compressionThreshold = threshold
+	return cfg
+}
+
+// WithMaxDecompressedSize configures the maximum size in bytes of the incoming messages' decompressed values.
+// Messages whose values exceed it are dropped. A size of 0 applies protocol.DefaultMaxDecompressedSize.
+func (cfg *Configuration) WithMaxDecompressedSize(size int) *Configuration {
+	cfg.maxDecompressedSize = size
+	return cfg
+}
+
+// WithDecoder configures the protocol.Decoder the incoming messages are to be decoded with, e.g. one retaining
+// the unknown Envelope fields, so that the decoding could differ between the Clients within a process.
+func (cfg *Configuration) WithDecoder(decoder *protocol.Decoder) *Configuration {
+	cfg.decoder = decoder
+	return cfg
+}
+
+// WithLogger configures the StructuredLogger the Client's output is to be written to instead of the package's DefaultLogger,
+// so that the output of multiple Clients within a process could be separated and leveled independently, e.g. via WithMinLevel.
+func (cfg *Configuration) WithLogger(logger StructuredLogger) *Configuration {
+	cfg.logger = logger
+	return cfg
+}
+
+// WithWireHook configures the WireHook to be called with the raw payload of each message published or received by the Client.
+// It is intended for debugging only, as it is called synchronously on the Client's publishing and receiving paths.
+func (cfg *Configuration) WithWireHook(hook WireHook) *Configuration {
+	cfg.wireHook = hook
+	return cfg
+}
+
+// WithEncodingBufferSize configures the size in bytes the pooled buffers used to encode the outgoing messages are to be
+// pre-sized to, so that devices publishing messages of a known typical size avoid growing the buffers while encoding.
+func (cfg *Configuration) WithEncodingBufferSize(size int) *Configuration {
+	cfg.encodingBufferSize = size
+	return cfg
+}
+
+// WithInlineDispatch configures if the Handlers, including the Subscriptions' ones, are to be called synchronously,
+// one after another, on the goroutine receiving the messages instead of spawning a goroutine per Handler per message,
+// which reduces the latency and the allocations of the message dispatching. As the next messages are not received
+// until the Handlers return, the inline Handlers must return quickly and must not block, e.g. by waiting for a message
+// sent via Send or Reply to be acknowledged, which is to be done in a separate goroutine instead.
+func (cfg *Configuration) WithInlineDispatch(inlineDispatch bool) *Configuration {
+	cfg.inlineDispatch = inlineDispatch
+	return cfg
+}
+
+// WithHandlerEnvelopeCopies configures if each Handler is to be provided with its own deep copy of the received messages,
+// so that Handlers modifying the messages, e.g. their Headers, don't race with the other Handlers reading them concurrently.
+// By default, all Handlers are provided with the same message, which is safe as long as the Handlers only read it.
+func (cfg *Configuration) WithHandlerEnvelopeCopies(handlerEnvelopeCopies bool) *Configuration {
+	cfg.handlerEnvelopeCopies = handlerEnvelopeCopies
+	return cfg
+}
+
+// WithMaxInflight configures the maximum number of messages sent via SendBatch that are to be published without being
+// acknowledged yet, so that the batches are pipelined without overwhelming the broker. Values less than 1 are treated as 1.
+func (cfg *Configuration) WithMaxInflight(maxInflight int) *Configuration {
+	cfg.maxInflightMessages = maxInflight
+	return cfg
+}
+
+// WithProtocolVersion configures the MQTT protocol version the Client is to connect with, for the brokers accepting
+// only one of the versions. By default, MQTT 3.1.1 is tried first, falling back to MQTT 3.1.
+func (cfg *Configuration) WithProtocolVersion(protocolVersion MQTTProtocolVersion) *Configuration {
+	cfg.protocolVersion = protocolVersion
+	return cfg
+}
+
+// WithClientID configures the MQTT client ID the Client is to connect with instead of a randomly generated one.
+// Each Client connected to the same broker must have a unique client ID.
+func (cfg *Configuration) WithClientID(clientID string) *Configuration {
+	cfg.clientID = clientID
+	return cfg
+}
+
+// WithStore configures the MQTT store the Client's in-flight messages are to be persisted to, e.g. MQTT.NewFileStore,
+// so that the QoS 1 messages not acknowledged yet survive process restarts and are resent on the next connect.
+// With a store configured, the Client's MQTT session is not clean, so a stable client ID must be configured
+// via WithClientID as well, for the broker not to keep a stale session for each restart. Otherwise, Connect fails.
+func (cfg *Configuration) WithStore(store MQTT.Store) *Configuration {
+	cfg.store = store
+	return cfg
+}
+
+// WithSendDefaults configures if the messages sent via Send and SendBatch are to be provided with a 'correlation-id'
+// generated via protocol.NewCorrelationID and a 'response-required' header of false unless they already have such,
+// so that all outgoing messages are traceable without setting these headers on each call. The sent messages
+// are not modified, the headers are set on their copies instead.
+func (cfg *Configuration) WithSendDefaults(sendDefaults bool) *Configuration {
+	cfg.sendDefaults = sendDefaults
+	return cfg
+}
+
+// WithCommandQoS configures the QoS of the Client's default MQTT subscription for Ditto commands, trading the delivery
+// guarantees of the incoming messages for throughput. By default, the subscription is with QoS 1.
+// The values above 2 are not valid MQTT QoS levels, thus connecting the Client fails with them.
+func (cfg *Configuration) WithCommandQoS(commandQoS CommandQoS) *Configuration {
+	cfg.commandQoS = commandQoS
+	cfg.commandQoSSet = true
+	return cfg
+}
+
+// WithCommandSubscription configures if the Client is to subscribe to the incoming Ditto messages on connect.
+// Clients only sending messages, e.g. telemetry or events, could disable the subscription, as it fails against the brokers
+// not permitting the device to receive commands. Without the subscription, the subscribed Handlers receive no messages,
+// while the additional subscriptions configured via WithSubscriptions are still applied.
+func (cfg *Configuration) WithCommandSubscription(commandSubscription bool) *Configuration {
+	cfg.noCommandSubscription = !commandSubscription
+	return cfg
+}
+
+// WithShareGroup configures the Client to subscribe to the incoming Ditto messages via an MQTT shared subscription
+// ('$share/<group>/command///req/#') of the provided group, so that the messages are load-balanced by the broker
+// between multiple instances of a consumer, e.g. a backend service. The group must not contain '/', '+' or '#'
+// and the broker must support shared subscriptions.
+func (cfg *Configuration) WithShareGroup(group string) *Configuration {
+	cfg.shareGroup = group
+	return cfg
+}
+
+// WithTenantID configures the Hono tenant ID to be included in the Client's MQTT topics, e.g. for unauthenticated devices
+// or protocol adapters. With a tenant or device ID configured, the Client subscribes to 'command/<tenant-id>/<device-id>/req/#',
+// replies to 'command/<tenant-id>/<device-id>/res/<request-id>/<status>' and sends to 'event/<tenant-id>/<device-id>'
+// instead of the short forms of the topics.
+func (cfg *Configuration) WithTenantID(tenantID string) *Configuration {
+	cfg.tenantID = tenantID
+	return cfg
+}
+
+// WithDeviceID configures the Hono device ID to be included in the Client's MQTT topics, e.g. for unauthenticated devices,
+// or for gateways acting on behalf of the device. See WithTenantID for the topics used.
+func (cfg *Configuration) WithDeviceID(deviceID string) *Configuration {
+	cfg.deviceID = deviceID
+	return cfg
+}
+
+func (cfg *Configuration) maxInflight() int {
+	if cfg.maxInflightMessages < 1 {
+		return 1
+	}
+	return cfg.maxInflightMessages
+}
+
+func (cfg *Configuration) commandSubscriptionQoS() byte {
+	return byte(cfg.CommandQoS())
+}
+
+func (cfg *Configuration) deviceTopics() bool {
+	return cfg.tenantID != "" || cfg.deviceID != ""
+}
@@ -18,16 +18,19 @@ import (
 	"time"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
 )
 
 func TestNewConfiguration(t *testing.T) {
 	want := &Configuration{
-		keepAlive:          defaultKeepAlive,
-		disconnectTimeout:  defaultDisconnectTimeout,
-		connectTimeout:     defaultConnectTimeout,
-		acknowledgeTimeout: defaultAcknowledgeTimeout,
-		subscribeTimeout:   defaultSubscribeTimeout,
-		unsubscribeTimeout: defaultUnsubscribeTimeout,
+		keepAlive:           defaultKeepAlive,
+		disconnectTimeout:   defaultDisconnectTimeout,
+		connectTimeout:      defaultConnectTimeout,
+		acknowledgeTimeout:  defaultAcknowledgeTimeout,
+		subscribeTimeout:    defaultSubscribeTimeout,
+		unsubscribeTimeout:  defaultUnsubscribeTimeout,
+		maxInflightMessages: defaultMaxInflight,
 	}
 
 	got := NewConfiguration()
@@ -579,3 +582,262 @@ func TestWithTLSConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestWithSubscriptions(t *testing.T) {
+	arg := &Subscription{
+		Topic: "notification///#",
+		QoS:   1,
+	}
+
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		subscriptions: []*Subscription{arg},
+	}
+
+	got := testConfiguration.WithSubscriptions(arg)
+	internal.AssertEqual(t, want, got)
+	internal.AssertEqual(t, []*Subscription{arg}, got.Subscriptions())
+}
+
+func TestWithStampCreationTime(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		stampCreationTime: true,
+	}
+
+	got := testConfiguration.WithStampCreationTime(true)
+	internal.AssertEqual(t, want, got)
+	internal.AssertTrue(t, got.StampCreationTime())
+}
+
+func TestWithCBOREncoding(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		cborEncoding: true,
+	}
+
+	got := testConfiguration.WithCBOREncoding(true)
+	internal.AssertEqual(t, want, got)
+	internal.AssertTrue(t, got.CBOREncoding())
+}
+
+func TestWithCompressionThreshold(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		compressionThreshold: 1024,
+	}
+
+	got := testConfiguration.WithCompressionThreshold(1024)
+	internal.AssertEqual(t, want, got)
+	internal.AssertEqual(t, 1024, got.CompressionThreshold())
+}
+
+func TestWithDecoder(t *testing.T) {
+	testConfiguration := &Configuration{}
+	decoder := protocol.NewDecoder(protocol.WithRetainUnknownFields(true))
+
+	got := testConfiguration.WithDecoder(decoder)
+	internal.AssertEqual(t, &Configuration{decoder: decoder}, got)
+	internal.AssertTrue(t, decoder == got.Decoder())
+}
+
+func TestWithLogger(t *testing.T) {
+	testConfiguration := &Configuration{}
+	logger := &recordingStructuredLogger{}
+
+	want := &Configuration{
+		logger: logger,
+	}
+
+	got := testConfiguration.WithLogger(logger)
+	internal.AssertEqual(t, want, got)
+	internal.AssertEqual(t, logger, got.Logger())
+}
+
+func TestWithWireHook(t *testing.T) {
+	var traced []byte
+	got := (&Configuration{}).WithWireHook(func(topic string, direction Direction, payload []byte) {
+		traced = payload
+	})
+
+	internal.AssertNotNil(t, got.WireHook())
+	got.WireHook()("e", DirectionOutgoing, []byte("payload"))
+	internal.AssertEqual(t, []byte("payload"), traced)
+}
+
+func TestWithEncodingBufferSize(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		encodingBufferSize: 512,
+	}
+
+	got := testConfiguration.WithEncodingBufferSize(512)
+	internal.AssertEqual(t, want, got)
+	internal.AssertEqual(t, 512, got.EncodingBufferSize())
+}
+
+func TestWithInlineDispatch(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		inlineDispatch: true,
+	}
+
+	got := testConfiguration.WithInlineDispatch(true)
+	internal.AssertEqual(t, want, got)
+	internal.AssertTrue(t, got.InlineDispatch())
+}
+
+func TestWithHandlerEnvelopeCopies(t *testing.T) {
+	testConfiguration := &Configuration{}
+
+	want := &Configuration{
+		handlerEnvelopeCopies: true,
+	}
+
+	got := testConfiguration.WithHandlerEnvelopeCopies(true)
+	internal.AssertEqual(t, want, got)
+	internal.AssertTrue(t, got.HandlerEnvelopeCopies())
+}
+
+func TestWithMaxInflight(t *testing.T) {
+	tests := map[string]struct {
+		arg          int
+		wantInflight int
+	}{
+		"test_max_inflight": {
+			arg:          5,
+			wantInflight: 5,
+		},
+		"test_max_inflight_zero": {
+			arg:          0,
+			wantInflight: 1,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := (&Configuration{}).WithMaxInflight(testCase.arg)
+			internal.AssertEqual(t, &Configuration{maxInflightMessages: testCase.arg}, got)
+			internal.AssertEqual(t, testCase.arg, got.MaxInflight())
+			internal.AssertEqual(t, testCase.wantInflight, got.maxInflight())
+		})
+	}
+}
+
+func TestWithProtocolVersion(t *testing.T) {
+	tests := map[string]struct {
+		arg MQTTProtocolVersion
+	}{
+		"test_protocol_version_31": {
+			arg: MQTTProtocolVersion31,
+		},
+		"test_protocol_version_311": {
+			arg: MQTTProtocolVersion311,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := (&Configuration{}).WithProtocolVersion(testCase.arg)
+			internal.AssertEqual(t, &Configuration{protocolVersion: testCase.arg}, got)
+			internal.AssertEqual(t, testCase.arg, got.ProtocolVersion())
+		})
+	}
+}
+
+func TestWithClientID(t *testing.T) {
+	got := (&Configuration{}).WithClientID("device")
+	internal.AssertEqual(t, &Configuration{clientID: "device"}, got)
+	internal.AssertEqual(t, "device", got.ClientID())
+}
+
+func TestWithStore(t *testing.T) {
+	store := MQTT.NewMemoryStore()
+	got := (&Configuration{}).WithStore(store)
+	internal.AssertEqual(t, &Configuration{store: store}, got)
+	internal.AssertEqual(t, store, got.Store())
+}
+
+func TestWithSendDefaults(t *testing.T) {
+	got := (&Configuration{}).WithSendDefaults(true)
+	internal.AssertEqual(t, &Configuration{sendDefaults: true}, got)
+	internal.AssertTrue(t, got.SendDefaults())
+}
+
+func TestWithCommandQoS(t *testing.T) {
+	tests := map[string]struct {
+		commandQoS CommandQoS
+		want       byte
+	}{
+		"test_at_most_once": {
+			commandQoS: CommandQoSAtMostOnce,
+			want:       0,
+		},
+		"test_at_least_once": {
+			commandQoS: CommandQoSAtLeastOnce,
+			want:       1,
+		},
+		"test_exactly_once": {
+			commandQoS: CommandQoSExactlyOnce,
+			want:       2,
+		},
+		"test_mqtt_qos_number": {
+			commandQoS: 1,
+			want:       1,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := (&Configuration{}).WithCommandQoS(testCase.commandQoS)
+			internal.AssertEqual(t, &Configuration{commandQoS: testCase.commandQoS, commandQoSSet: true}, got)
+			internal.AssertEqual(t, testCase.commandQoS, got.CommandQoS())
+			internal.AssertEqual(t, testCase.want, got.commandSubscriptionQoS())
+		})
+	}
+}
+
+func TestCommandQoSDefault(t *testing.T) {
+	cfg := &Configuration{}
+	internal.AssertEqual(t, CommandQoSAtLeastOnce, cfg.CommandQoS())
+	internal.AssertEqual(t, byte(1), cfg.commandSubscriptionQoS())
+}
+
+func TestWithCommandSubscription(t *testing.T) {
+	internal.AssertTrue(t, NewConfiguration().CommandSubscription())
+
+	got := (&Configuration{}).WithCommandSubscription(false)
+	internal.AssertEqual(t, &Configuration{noCommandSubscription: true}, got)
+	internal.AssertFalse(t, got.CommandSubscription())
+
+	got.WithCommandSubscription(true)
+	internal.AssertEqual(t, &Configuration{}, got)
+	internal.AssertTrue(t, got.CommandSubscription())
+}
+
+func TestWithShareGroup(t *testing.T) {
+	got := (&Configuration{}).WithShareGroup("consumers")
+	internal.AssertEqual(t, &Configuration{shareGroup: "consumers"}, got)
+	internal.AssertEqual(t, "consumers", got.ShareGroup())
+}
+
+func TestWithTenantID(t *testing.T) {
+	got := (&Configuration{}).WithTenantID("my-tenant")
+	internal.AssertEqual(t, &Configuration{tenantID: "my-tenant"}, got)
+	internal.AssertEqual(t, "my-tenant", got.TenantID())
+	internal.AssertTrue(t, got.deviceTopics())
+}
+
+func TestWithDeviceID(t *testing.T) {
+	got := (&Configuration{}).WithDeviceID("my-device")
+	internal.AssertEqual(t, &Configuration{deviceID: "my-device"}, got)
+	internal.AssertEqual(t, "my-device", got.DeviceID())
+	internal.AssertTrue(t, got.deviceTopics())
+	internal.AssertFalse(t, (&Configuration{}).deviceTopics())
+}
@@ -12,38 +12,140 @@
 package ditto
 
 import (
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
 	//import the Paho Go MQTT library
 	MQTT "github.com/eclipse/paho.mqtt.golang"
 )
 
 func (client *honoClient) defaultMessageHandler(mqttClient MQTT.Client, message MQTT.Message) {
-	DEBUG.Printf("unexpected message received: %v", message)
+	client.traceIncoming(message)
+	client.log(LevelDebug, "unexpected message received", Field(LogKeyMQTTTopic, message.Topic()))
 }
 
 func (client *honoClient) honoMessageHandler(mqttClient MQTT.Client, message MQTT.Message) {
-	DEBUG.Printf("received message for client subscription: %v", message)
+	received := time.Now()
+	client.stats.messageReceived()
+	client.traceIncoming(message)
+	client.log(LevelDebug, "received message for client subscription")
 	// wait for handlers added in the ConnectHandler
 	client.wgConnectHandler.Wait()
 
 	client.handlersLock.RLock()
-	defer client.handlersLock.RUnlock()
+	noHandlers := len(client.handlers) == 0
+	client.handlersLock.RUnlock()
 
-	if len(client.handlers) == 0 {
-		WARN.Printf("message received, but no handlers were found")
+	if noHandlers {
+		client.stats.messageDropped()
+		client.log(LevelWarn, "message received, but no handlers were found")
 		return
 	}
-	dittoMsg, err := getEnvelope(message.Payload())
+	dittoMsg, err := client.receivedEnvelope(message.Payload())
 	if err != nil {
-		ERROR.Printf("error getting Ditto message: %v", err)
+		client.stats.messageDropped()
+		client.log(LevelError, "error getting Ditto message", Field(LogKeyError, err))
 		return
 	}
-	requestID := extractHonoRequestID(message.Topic())
+	topic := message.Topic()
+	requestID := extractHonoRequestID(topic)
 	if requestID == "" {
-		DEBUG.Printf("no request ID is available in the received message with topic: %s", message.Topic())
+		client.log(LevelDebug, "no request ID is available in the received message", Field(LogKeyMQTTTopic, topic))
 	} else {
-		DEBUG.Printf("received a command with request ID: %s", requestID)
+		client.stats.requestReceived(requestID, received)
+	}
+	client.logEnvelope("received a Ditto message", requestID, dittoMsg)
+	client.dispatch(requestID, dittoMsg)
+}
+
+// dispatch transfers the provided message to all subscribed Handlers, each in its own goroutine or, if the inline
+// dispatch is configured, one after another on the calling goroutine. The Handlers are called after releasing
+// the Handlers' lock, so that they could subscribe and unsubscribe Handlers. If the Handlers' envelope copies are
+// configured, each Handler but the last one is provided with its own deep copy of the message, taken before
+// the message is provided to the last Handler.
+func (client *honoClient) dispatch(requestID string, message *protocol.Envelope) {
+	// the subscribed Handlers are never modified in place, so the snapshot is safe to be iterated without the lock
+	client.handlersLock.RLock()
+	handlers := client.handlers
+	client.handlersLock.RUnlock()
+
+	inline := client.inlineDispatch()
+	for i, subscribed := range handlers {
+		envelope := client.handlerEnvelope(message, i, len(handlers))
+		client.activity.start()
+		if inline {
+			client.callHandler(subscribed.handler, requestID, envelope)
+		} else {
+			go client.callHandler(subscribed.handler, requestID, envelope)
+		}
 	}
-	for _, handler := range client.handlers {
-		go handler(requestID, dittoMsg)
+}
+
+// callHandler calls the provided Handler, whose invocation has been started on the Client's activity, and marks it done.
+func (client *honoClient) callHandler(handler Handler, requestID string, message *protocol.Envelope) {
+	defer client.activity.done()
+	handler(requestID, message)
+}
+
+// callRawHandler calls the provided RawHandler, whose invocation has been started on the Client's activity, and marks it done.
+func (client *honoClient) callRawHandler(handler RawHandler, topic string, payload []byte) {
+	defer client.activity.done()
+	handler(topic, payload)
+}
+
+// handlerEnvelope provides the message to be transferred to the Handler with the provided index out of the provided count.
+func (client *honoClient) handlerEnvelope(message *protocol.Envelope, index, count int) *protocol.Envelope {
+	if index == count-1 || client.cfg == nil || !client.cfg.handlerEnvelopeCopies {
+		return message
+	}
+	return message.Clone()
+}
+
+func (client *honoClient) subscriptionMessageHandler(subscription *Subscription) MQTT.MessageHandler {
+	return func(mqttClient MQTT.Client, message MQTT.Message) {
+		client.stats.messageReceived()
+		client.traceIncoming(message)
+		client.log(LevelDebug, "received message for additional subscription", Field("subscription", subscription.Topic))
+		// wait for handlers added in the ConnectHandler
+		client.wgConnectHandler.Wait()
+
+		if subscription.RawHandler != nil {
+			client.activity.start()
+			if client.inlineDispatch() {
+				client.callRawHandler(subscription.RawHandler, message.Topic(), message.Payload())
+			} else {
+				go client.callRawHandler(subscription.RawHandler, message.Topic(), message.Payload())
+			}
+		}
+		if subscription.Handler == nil {
+			return
+		}
+		dittoMsg, err := client.receivedEnvelope(message.Payload())
+		if err != nil {
+			if subscription.RawHandler == nil {
+				client.stats.messageDropped()
+			}
+			client.log(LevelError, "error getting Ditto message", Field(LogKeyMQTTTopic, message.Topic()), Field(LogKeyError, err))
+			return
+		}
+		requestID := extractHonoRequestID(message.Topic())
+		client.logEnvelope("received a Ditto message for additional subscription", requestID, dittoMsg,
+			Field("subscription", subscription.Topic))
+		client.activity.start()
+		if client.inlineDispatch() {
+			client.callHandler(subscription.Handler, requestID, dittoMsg)
+		} else {
+			go client.callHandler(subscription.Handler, requestID, dittoMsg)
+		}
+	}
+}
+
+func (client *honoClient) inlineDispatch() bool {
+	return client.cfg != nil && client.cfg.inlineDispatch
+}
+
+func (client *honoClient) traceIncoming(message MQTT.Message) {
+	if client.cfg != nil && client.cfg.wireHook != nil {
+		client.traceWire(message.Topic(), DirectionIncoming, message.Payload())
 	}
 }
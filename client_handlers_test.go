@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
 	"github.com/eclipse/ditto-clients-golang/internal/mock"
@@ -38,7 +39,7 @@ func TestHonoMessageHandlingSuccess(t *testing.T) {
 	requestID := "expected"
 	topic := createTopic(requestID)
 
-	expectedEnvelope, _ := getEnvelope(validMessage)
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
 
 	handler := func(requestID string, message *protocol.Envelope) {
 		internal.AssertEqual(t, expectedEnvelope, message)
@@ -54,6 +55,30 @@ func TestHonoMessageHandlingSuccess(t *testing.T) {
 	internal.AssertWithTimeout(t, &wg, 5)
 }
 
+func TestHonoMessageHandlingNonStringCorrelationID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	recorder := &recordingStructuredLogger{}
+	unitUnderTest := NewClient(NewConfiguration().WithLogger(recorder))
+	message := []byte(`{"topic":"test.namespace/test-name/things/twin/commands/modify","headers":{"correlation-id":42}}`)
+
+	mockMQTTMessage.EXPECT().Payload().Return(message)
+	mockMQTTMessage.EXPECT().Topic().Return(createTopic("expected"))
+
+	unitUnderTest.Subscribe(func(requestID string, message *protocol.Envelope) {
+		wg.Done()
+	})
+	unitUnderTest.(*honoClient).honoMessageHandler(nil, mockMQTTMessage)
+
+	internal.AssertWithTimeout(t, &wg, 5*time.Second)
+}
+
 func TestHonoInvalidMesssageHandling(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -99,7 +124,7 @@ func TestHonoMultipleHanlders(t *testing.T) {
 	requestID := "expected"
 	topic := createTopic(requestID)
 
-	expectedEnvelope, _ := getEnvelope(validMessage)
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
 
 	handlerOne := func(requestID string, message *protocol.Envelope) {
 		internal.AssertEqual(t, expectedEnvelope, message)
@@ -137,7 +162,7 @@ func TestHonoAddMultipleHanlders(t *testing.T) {
 	requestID := "expected"
 	topic := createTopic(requestID)
 
-	expectedEnvelope, _ := getEnvelope(validMessage)
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
 
 	handlerOne := func(requestID string, message *protocol.Envelope) {
 		internal.AssertEqual(t, expectedEnvelope, message)
@@ -203,7 +228,7 @@ func TestRemoveSingleHanlder(t *testing.T) {
 	validMessage := []byte("{\"test\": 15}")
 	requestID := "expected"
 	topic := createTopic(requestID)
-	expectedEnvelope, _ := getEnvelope(validMessage)
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
 
 	handlerOne := func(requestID string, message *protocol.Envelope) {
 		internal.AssertEqual(t, expectedEnvelope, message)
@@ -226,14 +251,212 @@ func TestRemoveSingleHanlder(t *testing.T) {
 	internal.AssertWithTimeout(t, &wg, 5)
 }
 
-func TestGetHandlerName(t *testing.T) {
-	expectedName := "github.com/eclipse/ditto-clients-golang.testHandler"
-
-	actualName := getHandlerName(testHandler)
+func TestHandlerCode(t *testing.T) {
+	newHandler := func() Handler {
+		return func(requestID string, message *protocol.Envelope) {}
+	}
 
-	internal.AssertEqual(t, expectedName, actualName)
+	internal.AssertEqual(t, handlerCode(testHandler), handlerCode(testHandler))
+	internal.AssertEqual(t, handlerCode(newHandler()), handlerCode(newHandler()))
+	internal.AssertFalse(t, handlerCode(testHandler) == handlerCode(newHandler()))
 }
 
 func createTopic(requestID string) string {
 	return fmt.Sprintf("command///req/%s/dosomething", requestID)
 }
+
+func TestSubscriptionMessageHandling(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	validMessage := []byte("{\"test\": 15}")
+	topic := "notification///x"
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
+
+	subscription := &Subscription{
+		Topic: "notification///#",
+		Handler: func(requestID string, message *protocol.Envelope) {
+			internal.AssertEqual(t, "", requestID)
+			internal.AssertEqual(t, expectedEnvelope, message)
+			wg.Done()
+		},
+		RawHandler: func(actualTopic string, payload []byte) {
+			internal.AssertEqual(t, topic, actualTopic)
+			internal.AssertEqual(t, validMessage, payload)
+			wg.Done()
+		},
+	}
+
+	mockMQTTMessage.EXPECT().Payload().Return(validMessage).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(topic).AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithSubscriptions(subscription))
+	unitUnderTest.(*honoClient).subscriptionMessageHandler(subscription)(nil, mockMQTTMessage)
+
+	internal.AssertWithTimeout(t, &wg, 5)
+}
+
+func TestSubscriptionInvalidMessageHandling(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	subscription := &Subscription{
+		Topic: "notification///#",
+		Handler: func(requestID string, message *protocol.Envelope) {
+			t.Errorf("handler should not be called")
+		},
+	}
+
+	mockMQTTMessage.EXPECT().Payload().Return([]byte("{\"t\"}"))
+	mockMQTTMessage.EXPECT().Topic().Return("notification///x").AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithSubscriptions(subscription))
+	unitUnderTest.(*honoClient).subscriptionMessageHandler(subscription)(nil, mockMQTTMessage)
+}
+
+func TestHonoMessageHandlingWireHook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	validMessage := []byte("{\"test\": 15}")
+	topic := createTopic("expected")
+	var traced []string
+	unitUnderTest := NewClient(NewConfiguration().WithWireHook(func(topic string, direction Direction, payload []byte) {
+		traced = append(traced, topic+" "+direction.String()+" "+string(payload))
+	}))
+
+	mockMQTTMessage.EXPECT().Payload().Return(validMessage).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(topic).AnyTimes()
+
+	unitUnderTest.Subscribe(func(requestID string, message *protocol.Envelope) {
+		wg.Done()
+	})
+	unitUnderTest.(*honoClient).honoMessageHandler(nil, mockMQTTMessage)
+
+	internal.AssertWithTimeout(t, &wg, 5)
+	internal.AssertEqual(t, []string{topic + " incoming " + string(validMessage)}, traced)
+}
+
+func TestHonoMessageHandlingInline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	validMessage := []byte("{\"test\": 15}")
+	topic := createTopic("expected")
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
+
+	mockMQTTMessage.EXPECT().Payload().Return(validMessage).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(topic).AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithInlineDispatch(true))
+	var received []string
+	var unsubscribing Handler
+	unsubscribing = func(requestID string, message *protocol.Envelope) {
+		internal.AssertEqual(t, expectedEnvelope, message)
+		received = append(received, "unsubscribing "+requestID)
+		// (un)subscribing from an inline Handler must not deadlock
+		unitUnderTest.Unsubscribe(unsubscribing)
+	}
+	unitUnderTest.Subscribe(unsubscribing)
+
+	unitUnderTest.(*honoClient).honoMessageHandler(nil, mockMQTTMessage)
+	internal.AssertEqual(t, []string{"unsubscribing expected"}, received)
+	internal.AssertEqual(t, 0, len(unitUnderTest.(*honoClient).handlers))
+}
+
+func TestSubscriptionMessageHandlingInline(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	validMessage := []byte("{\"test\": 15}")
+	topic := "notification///x"
+
+	var received []string
+	subscription := &Subscription{
+		Topic: "notification///#",
+		Handler: func(requestID string, message *protocol.Envelope) {
+			received = append(received, "handler")
+		},
+		RawHandler: func(actualTopic string, payload []byte) {
+			received = append(received, "raw "+actualTopic)
+		},
+	}
+
+	mockMQTTMessage.EXPECT().Payload().Return(validMessage).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(topic).AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithSubscriptions(subscription).WithInlineDispatch(true))
+	unitUnderTest.(*honoClient).subscriptionMessageHandler(subscription)(nil, mockMQTTMessage)
+
+	internal.AssertEqual(t, []string{"raw " + topic, "handler"}, received)
+}
+
+func TestHonoMessageHandlingEnvelopeCopies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	validMessage := []byte("{\"test\": 15}")
+	topic := createTopic("expected")
+	expectedEnvelope, _ := getEnvelope(validMessage, nil)
+
+	mockMQTTMessage.EXPECT().Payload().Return(validMessage).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(topic).AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithInlineDispatch(true).WithHandlerEnvelopeCopies(true))
+	var received []*protocol.Envelope
+	handler := func(requestID string, message *protocol.Envelope) {
+		internal.AssertEqual(t, expectedEnvelope, message)
+		received = append(received, message)
+		message.Headers.Set(protocol.HeaderCorrelationID, "modified")
+	}
+	unitUnderTest.Subscribe(handler, func(requestID string, message *protocol.Envelope) {
+		handler(requestID, message)
+	})
+
+	unitUnderTest.(*honoClient).honoMessageHandler(nil, mockMQTTMessage)
+	internal.AssertEqual(t, 2, len(received))
+	internal.AssertTrue(t, received[0] != received[1])
+}
+
+func TestHonoMessageHandlingEnvelopeCopiesConcurrently(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMQTTMessage := mock.NewMockMessage(mockCtrl)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	mockMQTTMessage.EXPECT().Payload().Return([]byte("{\"test\": 15}")).AnyTimes()
+	mockMQTTMessage.EXPECT().Topic().Return(createTopic("expected")).AnyTimes()
+
+	unitUnderTest := NewClient(NewConfiguration().WithHandlerEnvelopeCopies(true))
+	handler := func(requestID string, message *protocol.Envelope) {
+		message.Headers.Set(protocol.HeaderCorrelationID, message.Headers.CorrelationID()+"-modified")
+		wg.Done()
+	}
+	unitUnderTest.Subscribe(handler, func(requestID string, message *protocol.Envelope) {
+		handler(requestID, message)
+	})
+
+	unitUnderTest.(*honoClient).honoMessageHandler(nil, mockMQTTMessage)
+	internal.AssertWithTimeout(t, &wg, 5)
+}
@@ -12,37 +12,156 @@
 package ditto
 
 import (
-	"encoding/json"
-	"errors"
+	"bytes"
+	"fmt"
 	"github.com/eclipse/ditto-clients-golang/protocol"
 	"sync"
 	"time"
 
 	//import the Paho Go MQTT library
 	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
 )
 
 const (
 	honoMQTTTopicSubscribeCommands = "command///req/#"
 	honoMQTTTopicPublishTelemetry  = "t"
 	honoMQTTTopicPublishEvents     = "e"
+
+	honoMQTTTopicSubscribeDeviceCommandsFormat = "command/%s/%s/req/#"
+	honoMQTTTopicPublishDeviceEventsFormat     = "event/%s/%s"
+
+	mqttTopicSharePrefix = "$share/"
 )
 
+// commandsTopic provides the topic of the Client's subscription for Ditto commands, shared within the configured group if any.
+func (client *honoClient) commandsTopic() string {
+	topic := honoMQTTTopicSubscribeCommands
+	if client.cfg.deviceTopics() {
+		topic = fmt.Sprintf(honoMQTTTopicSubscribeDeviceCommandsFormat, client.cfg.tenantID, client.cfg.deviceID)
+	}
+	return sharedTopic(client.cfg.shareGroup, topic)
+}
+
+// eventsTopic provides the topic the Client sends the messages to.
+func (client *honoClient) eventsTopic() string {
+	if client.cfg.deviceTopics() {
+		return fmt.Sprintf(honoMQTTTopicPublishDeviceEventsFormat, client.cfg.tenantID, client.cfg.deviceID)
+	}
+	return honoMQTTTopicPublishEvents
+}
+
+// responseTopic provides the topic the Client sends the replies to the request with the provided ID to.
+func (client *honoClient) responseTopic(requestID string, status int) string {
+	return generateHonoDeviceResponseTopic(client.cfg.tenantID, client.cfg.deviceID, requestID, status)
+}
+
+func (client *honoClient) pahoOptions() *MQTT.ClientOptions {
+	clientID := client.cfg.clientID
+	if clientID == "" {
+		clientID = uuid.New().String()
+	}
+	pahoOpts := MQTT.NewClientOptions().
+		AddBroker(client.cfg.broker).
+		SetClientID(clientID).
+		SetDefaultPublishHandler(client.defaultMessageHandler).
+		SetKeepAlive(client.cfg.keepAlive).
+		// the stored messages are resent on connect only if the session is not clean
+		SetCleanSession(client.cfg.store == nil).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(client.clientConnectHandler).
+		SetConnectionLostHandler(client.clientConnectionLostHandler).
+		SetTLSConfig(client.cfg.tlsConfig).
+		SetConnectTimeout(client.cfg.connectTimeout).
+		SetProtocolVersion(uint(client.cfg.protocolVersion))
+
+	if client.cfg.store != nil {
+		pahoOpts = pahoOpts.SetStore(client.cfg.store)
+	}
+	if client.cfg.credentials != nil {
+		pahoOpts = pahoOpts.SetCredentialsProvider(func() (username string, password string) {
+			return client.cfg.credentials.Username, client.cfg.credentials.Password
+		})
+	}
+	return pahoOpts
+}
+
 func (client *honoClient) clientConnectHandler(pahoClient MQTT.Client) {
+	client.stats.connectionEstablished()
 	client.wgConnectHandler.Add(1)
-	token := client.pahoClient.Subscribe(honoMQTTTopicSubscribeCommands, 1, client.honoMessageHandler)
+	if client.cfg.CommandSubscription() {
+		token := client.pahoClient.Subscribe(client.commandsTopic(), client.cfg.commandSubscriptionQoS(), client.honoMessageHandler)
+
+		var err error
+		if token.WaitTimeout(client.cfg.subscribeTimeout) {
+			err = token.Error()
+		} else {
+			err = ErrSubscribeTimeout
+		}
 
+		if err != nil {
+			client.log(LevelError, "error subscribing to root Hono topic", Field(LogKeyMQTTTopic, client.commandsTopic()), Field(LogKeyError, err))
+		}
+	}
+	if err := client.subscribeAdditional(); err != nil {
+		client.log(LevelError, "error subscribing to additional topics", Field(LogKeyError, err))
+	}
+	client.notifyClientConnected()
+}
+
+// subscribeAdditional subscribes to the configured additional topics. If any of the subscriptions fails,
+// the ones already succeeded are unsubscribed from.
+func (client *honoClient) subscribeAdditional() error {
+	var subscribed []string
+	for _, subscription := range client.cfg.subscriptions {
+		subscription := subscription
+		subscribe := func(handler MQTT.MessageHandler) error {
+			token := client.pahoClient.Subscribe(subscription.Topic, subscription.QoS, handler)
+			if !token.WaitTimeout(client.cfg.subscribeTimeout) {
+				return ErrSubscribeTimeout
+			}
+			return token.Error()
+		}
+		handler := client.subscriptionMessageHandler(subscription)
+
+		var err error
+		if client.externalMQTTClient {
+			err = sharedSubscriptions.subscribe(client.pahoClient, subscription.Topic, client, handler, subscribe)
+		} else {
+			err = subscribe(handler)
+		}
+		if err != nil {
+			client.rollbackSubscriptions(subscribed...)
+			return err
+		}
+		subscribed = append(subscribed, subscription.Topic)
+	}
+	return nil
+}
+
+// rollbackSubscriptions unsubscribes from the provided topics, e.g. the ones subscribed to before a failed subscription,
+// including the Client's shares of the subscriptions of an external MQTT client. An error is only logged.
+func (client *honoClient) rollbackSubscriptions(topics ...string) {
 	var err error
-	if token.WaitTimeout(client.cfg.subscribeTimeout) {
-		err = token.Error()
+	if client.externalMQTTClient {
+		err = sharedSubscriptions.unsubscribe(client.pahoClient, client, topics, client.unsubscribe)
 	} else {
-		err = ErrSubscribeTimeout
+		err = client.unsubscribe(topics...)
 	}
-
 	if err != nil {
-		ERROR.Printf("error subscribing to root Hono topic %s : %v", honoMQTTTopicSubscribeCommands, err)
+		client.log(LevelError, "error unsubscribing after a failed subscription", Field(LogKeyError, err))
 	}
-	client.notifyClientConnected()
+}
+
+func (client *honoClient) unsubscribe(topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	token := client.pahoClient.Unsubscribe(topics...)
+	if !token.WaitTimeout(client.cfg.unsubscribeTimeout) {
+		return ErrUnsubscribeTimeout
+	}
+	return token.Error()
 }
 
 func (client *honoClient) notifyClientConnected() {
@@ -64,9 +183,9 @@ func (client *honoClient) notifyClientConnected() {
 
 	select {
 	case <-notifyChan:
-		DEBUG.Println("notified for client initialization successfully")
+		client.log(LevelDebug, "notified for client initialization successfully")
 	case <-time.After(60 * time.Second):
-		ERROR.Printf("%v", errors.New("timed out waiting for initialization notification to be handled"))
+		client.log(LevelError, "timed out waiting for initialization notification to be handled")
 	}
 }
 
@@ -92,20 +211,157 @@ func (client *honoClient) notifyClientConnectionLost(err error) {
 
 	select {
 	case <-notifyChan:
-		DEBUG.Println("notified for client connection lost successfully")
+		client.log(LevelDebug, "notified for client connection lost successfully")
 	case <-time.After(60 * time.Second):
-		ERROR.Printf("%v", errors.New("timed out waiting for connection lost notification to be handled"))
+		client.log(LevelError, "timed out waiting for connection lost notification to be handled")
 	}
 }
 
 func (client *honoClient) publish(topic string, message *protocol.Envelope, qos byte, retained bool) error {
-	payload, err := json.Marshal(message)
-	if err != nil {
+	client.activity.start()
+	defer client.activity.done()
+
+	buf := protocol.GetBuffer()
+	if client.cfg.encodingBufferSize > 0 {
+		buf.Grow(client.cfg.encodingBufferSize)
+	}
+	if err := client.encode(buf, message); err != nil {
+		protocol.PutBuffer(buf)
 		return err
 	}
+	return client.publishBuffer(topic, buf, qos, retained)
+}
+
+// publishBuffer publishes the content of the provided pooled buffer, which is returned to the pool
+// once the publishing is acknowledged.
+func (client *honoClient) publishBuffer(topic string, buf *bytes.Buffer, qos byte, retained bool) error {
+	payload := buf.Bytes()
+	client.traceWire(topic, DirectionOutgoing, payload)
 	token := client.pahoClient.Publish(topic, qos, retained, payload)
-	if !token.WaitTimeout(client.cfg.acknowledgeTimeout) {
+	if err := waitPublished(token, client.cfg.acknowledgeTimeout); err != nil {
+		// the payload might still be referenced by the MQTT client, so the buffer is left to the garbage collector
+		return err
+	}
+	client.stats.messagesSent(1)
+	protocol.PutBuffer(buf)
+	return nil
+}
+
+// publishBatch publishes the provided messages one after another without waiting for each one to be acknowledged,
+// with up to the configured maximum of messages in flight. The messages are encoded into a single buffer, which is
+// reused if all of them are published successfully. No further messages are published after the first failure.
+func (client *honoClient) publishBatch(topic string, messages []*protocol.Envelope, qos byte) error {
+	client.activity.start()
+	defer client.activity.done()
+
+	buf := protocol.GetBuffer()
+	inflight := make([]MQTT.Token, 0, client.cfg.maxInflight())
+	// the indexes of the messages in flight
+	indexes := make([]int, 0, cap(inflight))
+
+	var err error
+	for i, message := range messages {
+		if len(inflight) == cap(inflight) {
+			if err = waitPublished(inflight[0], client.cfg.acknowledgeTimeout); err != nil {
+				err = fmt.Errorf("envelope %d: %w", indexes[0], err)
+				break
+			}
+			client.stats.messagesSent(1)
+			inflight, indexes = inflight[1:], indexes[1:]
+		}
+
+		start := buf.Len()
+		if err = client.encode(buf, message); err != nil {
+			err = fmt.Errorf("envelope %d: %w", i, err)
+			break
+		}
+		// the payload is kept intact even if the buffer grows, as its content is then copied to a new array
+		payload := buf.Bytes()[start:buf.Len():buf.Len()]
+		client.traceWire(topic, DirectionOutgoing, payload)
+		inflight = append(inflight, client.pahoClient.Publish(topic, qos, false, payload))
+		indexes = append(indexes, i)
+	}
+
+	published := true
+	for i, token := range inflight {
+		if tokenErr := waitPublished(token, client.cfg.acknowledgeTimeout); tokenErr != nil {
+			published = false
+			if err == nil {
+				err = fmt.Errorf("envelope %d: %w", indexes[i], tokenErr)
+			}
+		} else {
+			client.stats.messagesSent(1)
+		}
+	}
+	if published {
+		protocol.PutBuffer(buf)
+	}
+	return err
+}
+
+// receivedEnvelope decodes the provided MQTT payload with the configured protocol.Decoder and, if the compression
+// is configured, decompresses its value.
+func (client *honoClient) receivedEnvelope(mqttPayload []byte) (*protocol.Envelope, error) {
+	decoder := &protocol.Decoder{}
+	if client.cfg != nil && client.cfg.decoder != nil {
+		decoder = client.cfg.decoder
+	}
+	env, err := getEnvelope(mqttPayload, decoder)
+	if err != nil || client.cfg == nil || client.cfg.compressionThreshold <= 0 {
+		return env, err
+	}
+	return decoder.DecompressValue(env, client.cfg.maxDecompressedSize)
+}
+
+// encode applies the configured creation time stamping and compression to the provided message and encodes it
+// into the provided buffer. On error, the buffer is left unchanged.
+func (client *honoClient) encode(buf *bytes.Buffer, message *protocol.Envelope) error {
+	if client.cfg.stampCreationTime {
+		message = withCreationTime(message)
+	}
+	if client.cfg.compressionThreshold > 0 {
+		compressed, err := protocol.CompressValue(message, client.cfg.compressionThreshold)
+		if err != nil {
+			return err
+		}
+		message = compressed
+	}
+	if client.cfg.cborEncoding {
+		return protocol.EncodeCBOR(buf, message)
+	}
+	return message.EncodeJSON(buf)
+}
+
+func waitPublished(token MQTT.Token, timeout time.Duration) error {
+	if !token.WaitTimeout(timeout) {
 		return ErrAcknowledgeTimeout
 	}
 	return token.Error()
 }
+
+func (client *honoClient) structuredLogger() StructuredLogger {
+	if client.cfg != nil && client.cfg.logger != nil {
+		return client.cfg.logger
+	}
+	return DefaultLogger()
+}
+
+// logEnvelope logs the provided debug message along with the fields identifying the provided Envelope, which are
+// only built if the debug records are written.
+func (client *honoClient) logEnvelope(msg, requestID string, message *protocol.Envelope, fields ...LogField) {
+	logger := client.structuredLogger()
+	if !levelEnabled(logger, LevelDebug) {
+		return
+	}
+	WithFields(logger, EnvelopeFields(requestID, message)...).Log(LevelDebug, msg, fields...)
+}
+
+func (client *honoClient) log(level LogLevel, msg string, fields ...LogField) {
+	client.structuredLogger().Log(level, msg, fields...)
+}
+
+func (client *honoClient) traceWire(topic string, direction Direction, payload []byte) {
+	if client.cfg != nil && client.cfg.wireHook != nil {
+		client.cfg.wireHook(topic, direction, payload)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"sync"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+// sharedSubscriptions manages the MQTT subscriptions of the Clients created via NewClientMQTT, so that multiple Clients
+// could share the same external MQTT client. As the MQTT client routes the messages of a topic to a single handler,
+// each topic is subscribed to once, with its messages transferred to all Clients subscribed to it, and it is
+// unsubscribed from only once the last of them unsubscribes.
+var sharedSubscriptions = &sharedSubscriptionRegistry{
+	subscriptions: make(map[sharedSubscriptionKey]*sharedSubscription),
+}
+
+type sharedSubscriptionKey struct {
+	mqttClient MQTT.Client
+	topic      string
+}
+
+type sharedSubscriptionRegistry struct {
+	// serializes the subscribing and unsubscribing, so that a topic is never subscribed to and unsubscribed from concurrently
+	lock          sync.Mutex
+	subscriptions map[sharedSubscriptionKey]*sharedSubscription
+}
+
+// sharedSubscription transfers the messages received on a topic to the handlers of all Clients subscribed to it.
+type sharedSubscription struct {
+	lock sync.RWMutex
+	// never modified in place, so that the messages are transferred from a snapshot without holding the lock
+	handlers []sharedHandler
+}
+
+type sharedHandler struct {
+	client  *honoClient
+	handler MQTT.MessageHandler
+}
+
+// subscribe adds the provided Client's handler to the subscription for the provided topic of the provided MQTT client.
+// If there is no such subscription yet, it is created via the provided subscribe function, whose error is returned.
+// Subscribing the same Client again replaces its handler.
+func (registry *sharedSubscriptionRegistry) subscribe(mqttClient MQTT.Client, topic string, client *honoClient,
+	handler MQTT.MessageHandler, subscribe func(handler MQTT.MessageHandler) error) error {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	key := sharedSubscriptionKey{mqttClient: mqttClient, topic: topic}
+	subscription, ok := registry.subscriptions[key]
+	if !ok {
+		subscription = &sharedSubscription{}
+		if err := subscribe(subscription.handle); err != nil {
+			return err
+		}
+		registry.subscriptions[key] = subscription
+	}
+	subscription.add(client, handler)
+	return nil
+}
+
+// unsubscribe removes the provided Client's handlers from the subscriptions for the provided topics of the provided
+// MQTT client and unsubscribes via the provided unsubscribe function from the topics no other Clients are subscribed to.
+func (registry *sharedSubscriptionRegistry) unsubscribe(mqttClient MQTT.Client, client *honoClient, topics []string,
+	unsubscribe func(topics ...string) error) error {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	var unused []string
+	for _, topic := range topics {
+		key := sharedSubscriptionKey{mqttClient: mqttClient, topic: topic}
+		subscription, ok := registry.subscriptions[key]
+		if !ok {
+			unused = append(unused, topic)
+			continue
+		}
+		if subscription.remove(client) == 0 {
+			delete(registry.subscriptions, key)
+			unused = append(unused, topic)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	return unsubscribe(unused...)
+}
+
+func (subscription *sharedSubscription) add(client *honoClient, handler MQTT.MessageHandler) {
+	subscription.lock.Lock()
+	defer subscription.lock.Unlock()
+
+	handlers := make([]sharedHandler, 0, len(subscription.handlers)+1)
+	for _, shared := range subscription.handlers {
+		if shared.client != client {
+			handlers = append(handlers, shared)
+		}
+	}
+	subscription.handlers = append(handlers, sharedHandler{client: client, handler: handler})
+}
+
+// remove removes the provided Client's handler and returns the number of the remaining ones.
+func (subscription *sharedSubscription) remove(client *honoClient) int {
+	subscription.lock.Lock()
+	defer subscription.lock.Unlock()
+
+	handlers := make([]sharedHandler, 0, len(subscription.handlers))
+	for _, shared := range subscription.handlers {
+		if shared.client != client {
+			handlers = append(handlers, shared)
+		}
+	}
+	subscription.handlers = handlers
+	return len(handlers)
+}
+
+func (subscription *sharedSubscription) handle(mqttClient MQTT.Client, message MQTT.Message) {
+	subscription.lock.RLock()
+	handlers := subscription.handlers
+	subscription.lock.RUnlock()
+
+	for _, shared := range handlers {
+		shared.handler(mqttClient, message)
+	}
+}
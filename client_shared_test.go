@@ -0,0 +1,149 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/internal/mock"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/golang/mock/gomock"
+)
+
+func resetSharedSubscriptions() {
+	sharedSubscriptions.lock.Lock()
+	defer sharedSubscriptions.lock.Unlock()
+
+	sharedSubscriptions.subscriptions = make(map[sharedSubscriptionKey]*sharedSubscription)
+}
+
+func TestSharedSubscriptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mqttClient := mock.NewMockClient(mockCtrl)
+	registry := &sharedSubscriptionRegistry{subscriptions: make(map[sharedSubscriptionKey]*sharedSubscription)}
+	first, second := &honoClient{}, &honoClient{}
+
+	var (
+		subscribed   MQTT.MessageHandler
+		subscribes   int
+		unsubscribed []string
+		received     []string
+	)
+	subscribe := func(handler MQTT.MessageHandler) error {
+		subscribes++
+		subscribed = handler
+		return nil
+	}
+	unsubscribe := func(topics ...string) error {
+		unsubscribed = append(unsubscribed, topics...)
+		return nil
+	}
+	handler := func(name string) MQTT.MessageHandler {
+		return func(MQTT.Client, MQTT.Message) {
+			received = append(received, name)
+		}
+	}
+
+	internal.AssertNil(t, registry.subscribe(mqttClient, honoMQTTTopicSubscribeCommands, first, handler("first"), subscribe))
+	internal.AssertNil(t, registry.subscribe(mqttClient, honoMQTTTopicSubscribeCommands, second, handler("second"), subscribe))
+	internal.AssertNil(t, registry.subscribe(mqttClient, honoMQTTTopicSubscribeCommands, second, handler("replaced"), subscribe))
+	internal.AssertEqual(t, 1, subscribes)
+
+	subscribed(mqttClient, nil)
+	internal.AssertEqual(t, []string{"first", "replaced"}, received)
+
+	topics := []string{honoMQTTTopicSubscribeCommands, testSubscriptionTopic}
+	internal.AssertNil(t, registry.unsubscribe(mqttClient, first, topics, unsubscribe))
+	internal.AssertEqual(t, []string{testSubscriptionTopic}, unsubscribed)
+
+	received = nil
+	subscribed(mqttClient, nil)
+	internal.AssertEqual(t, []string{"replaced"}, received)
+
+	internal.AssertNil(t, registry.unsubscribe(mqttClient, second, topics, unsubscribe))
+	internal.AssertEqual(t, []string{testSubscriptionTopic, honoMQTTTopicSubscribeCommands, testSubscriptionTopic}, unsubscribed)
+	internal.AssertEqual(t, 0, len(registry.subscriptions))
+}
+
+func TestSharedSubscriptionsSubscribeError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mqttClient := mock.NewMockClient(mockCtrl)
+	registry := &sharedSubscriptionRegistry{subscriptions: make(map[sharedSubscriptionKey]*sharedSubscription)}
+	client := &honoClient{}
+	handler := func(MQTT.Client, MQTT.Message) {}
+
+	subscribes := 0
+	err := registry.subscribe(mqttClient, honoMQTTTopicSubscribeCommands, client, handler, func(MQTT.MessageHandler) error {
+		subscribes++
+		return MQTT.ErrNotConnected
+	})
+	internal.AssertError(t, MQTT.ErrNotConnected, err)
+	internal.AssertEqual(t, 0, len(registry.subscriptions))
+
+	err = registry.subscribe(mqttClient, honoMQTTTopicSubscribeCommands, client, handler, func(MQTT.MessageHandler) error {
+		subscribes++
+		return nil
+	})
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 2, subscribes)
+}
+
+func TestSharedMQTTClientConnectDisconnect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	resetSharedSubscriptions()
+	defer resetSharedSubscriptions()
+
+	mqttClient := mock.NewMockClient(mockCtrl)
+	token := mock.NewMockToken(mockCtrl)
+	first := &honoClient{cfg: NewConfiguration(), pahoClient: mqttClient, externalMQTTClient: true}
+	second := &honoClient{cfg: NewConfiguration(), pahoClient: mqttClient, externalMQTTClient: true}
+
+	mqttClient.EXPECT().Subscribe(honoMQTTTopicSubscribeCommands, byte(1), gomock.Any()).Return(token)
+	token.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	token.EXPECT().Error().Return(nil)
+
+	internal.AssertNil(t, first.Connect())
+	internal.AssertNil(t, second.Connect())
+
+	first.Disconnect()
+
+	mqttClient.EXPECT().Unsubscribe(honoMQTTTopicSubscribeCommands).Return(token)
+	token.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	token.EXPECT().Error().Return(nil)
+
+	second.Disconnect()
+}
+
+func TestSharedMQTTClientDisconnectError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	resetSharedSubscriptions()
+	defer resetSharedSubscriptions()
+
+	mqttClient := mock.NewMockClient(mockCtrl)
+	token := mock.NewMockToken(mockCtrl)
+	client := &honoClient{cfg: NewConfiguration(), pahoClient: mqttClient, externalMQTTClient: true}
+
+	mqttClient.EXPECT().Unsubscribe(honoMQTTTopicSubscribeCommands).Return(token)
+	token.EXPECT().WaitTimeout(gomock.Any()).Return(false)
+
+	err := sharedSubscriptions.unsubscribe(mqttClient, client, []string{honoMQTTTopicSubscribeCommands}, client.unsubscribe)
+	internal.AssertError(t, ErrUnsubscribeTimeout, err)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// the number of the most recent reply latencies the percentiles are calculated from
+	statsLatencySamples = 1024
+	// the maximum number of the received requests awaiting a reply that are tracked for the reply latencies,
+	// the oldest of them are no longer tracked once it's exceeded
+	statsPendingReplies = 1024
+)
+
+// Stats is a snapshot of a Client's statistics accumulated since it was last connected, e.g. for health endpoints.
+type Stats struct {
+	// Sent is the number of the messages sent and acknowledged, including the replies.
+	Sent uint64
+	// Received is the number of the messages received.
+	Received uint64
+	// Dropped is the number of the received messages not transferred to any Handler, e.g. as they are not
+	// Ditto messages or there are no subscribed Handlers.
+	Dropped uint64
+	// Reconnects is the number of the times the connection has been re-established after being lost.
+	// It's always 0 for the Clients using an external MQTT client, as their connection is managed externally.
+	Reconnects uint64
+	// ReplyLatency provides the percentiles of the durations between receiving the requests and sending the replies to them.
+	ReplyLatency LatencyPercentiles
+}
+
+// LatencyPercentiles provides the percentiles of the most recent latencies. All of them are 0 if there are no latencies yet.
+type LatencyPercentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+type statistics struct {
+	lock       sync.Mutex
+	sent       uint64
+	received   uint64
+	dropped    uint64
+	reconnects uint64
+	connected  bool
+	// the requests awaiting a reply by their request IDs, along with the order of receiving them
+	pending      map[string]*list.Element
+	pendingOrder list.List
+	// the ring buffer of the most recent reply latencies
+	latencies []time.Duration
+	next      int
+}
+
+func (stats *statistics) reset() {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.sent, stats.received, stats.dropped, stats.reconnects = 0, 0, 0, 0
+	stats.connected = false
+	stats.pending = nil
+	stats.pendingOrder.Init()
+	stats.latencies = nil
+	stats.next = 0
+}
+
+func (stats *statistics) connectionEstablished() {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	if stats.connected {
+		stats.reconnects++
+	}
+	stats.connected = true
+}
+
+func (stats *statistics) messagesSent(count int) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.sent += uint64(count)
+}
+
+func (stats *statistics) messageReceived() {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.received++
+}
+
+func (stats *statistics) messageDropped() {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	stats.dropped++
+}
+
+// pendingRequest is a received request awaiting a reply.
+type pendingRequest struct {
+	requestID string
+	received  time.Time
+}
+
+// requestReceived tracks the request with the provided ID received at the provided time for the latency of the reply to it.
+// If statsPendingReplies requests are already tracked, the oldest of them is no longer tracked.
+func (stats *statistics) requestReceived(requestID string, at time.Time) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	if stats.pending == nil {
+		stats.pending = make(map[string]*list.Element)
+	}
+	if element, ok := stats.pending[requestID]; ok {
+		stats.pendingOrder.Remove(element)
+	} else if len(stats.pending) >= statsPendingReplies {
+		oldest := stats.pendingOrder.Front()
+		stats.pendingOrder.Remove(oldest)
+		delete(stats.pending, oldest.Value.(pendingRequest).requestID)
+	}
+	stats.pending[requestID] = stats.pendingOrder.PushBack(pendingRequest{requestID: requestID, received: at})
+}
+
+// replySent records the latency of the reply to the request with the provided ID sent at the provided time, if it's tracked.
+func (stats *statistics) replySent(requestID string, at time.Time) {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	element, ok := stats.pending[requestID]
+	if !ok {
+		return
+	}
+	delete(stats.pending, requestID)
+	stats.pendingOrder.Remove(element)
+	received := element.Value.(pendingRequest).received
+
+	if len(stats.latencies) < statsLatencySamples {
+		stats.latencies = append(stats.latencies, at.Sub(received))
+		return
+	}
+	stats.latencies[stats.next] = at.Sub(received)
+	stats.next = (stats.next + 1) % statsLatencySamples
+}
+
+func (stats *statistics) snapshot() Stats {
+	stats.lock.Lock()
+	latencies := make([]time.Duration, len(stats.latencies))
+	copy(latencies, stats.latencies)
+	snapshot := Stats{
+		Sent:       stats.sent,
+		Received:   stats.received,
+		Dropped:    stats.dropped,
+		Reconnects: stats.reconnects,
+	}
+	stats.lock.Unlock()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	snapshot.ReplyLatency = LatencyPercentiles{
+		P50: percentile(latencies, 50),
+		P90: percentile(latencies, 90),
+		P99: percentile(latencies, 99),
+	}
+	return snapshot
+}
+
+// percentile provides the nearest-rank percentile of the provided sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
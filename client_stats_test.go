@@ -0,0 +1,171 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := map[string]struct {
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		"test_no_latencies": {
+			p:    50,
+			want: 0,
+		},
+		"test_single_latency": {
+			sorted: []time.Duration{time.Second},
+			p:      99,
+			want:   time.Second,
+		},
+		"test_p50": {
+			sorted: latencies,
+			p:      50,
+			want:   50 * time.Millisecond,
+		},
+		"test_p99": {
+			sorted: latencies,
+			p:      99,
+			want:   99 * time.Millisecond,
+		},
+		"test_p90_of_few": {
+			sorted: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond},
+			p:      90,
+			want:   3 * time.Millisecond,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, percentile(testCase.sorted, testCase.p))
+		})
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	stats := &statistics{}
+	received := time.Now()
+
+	stats.connectionEstablished()
+	stats.connectionEstablished()
+	stats.messageReceived()
+	stats.messageReceived()
+	stats.messageDropped()
+	stats.messagesSent(2)
+	stats.requestReceived("testRequestID", received)
+	stats.replySent("testRequestID", received.Add(time.Second))
+	stats.replySent("testRequestID", received.Add(2*time.Second))
+
+	want := Stats{
+		Sent:       2,
+		Received:   2,
+		Dropped:    1,
+		Reconnects: 1,
+		ReplyLatency: LatencyPercentiles{
+			P50: time.Second,
+			P90: time.Second,
+			P99: time.Second,
+		},
+	}
+	internal.AssertEqual(t, want, stats.snapshot())
+
+	stats.reset()
+	internal.AssertEqual(t, Stats{}, stats.snapshot())
+	stats.connectionEstablished()
+	internal.AssertEqual(t, uint64(0), stats.snapshot().Reconnects)
+}
+
+func TestStatisticsLimits(t *testing.T) {
+	stats := &statistics{}
+	received := time.Now()
+
+	for i := 0; i <= statsPendingReplies; i++ {
+		stats.requestReceived(fmt.Sprint(i), received)
+	}
+	internal.AssertEqual(t, statsPendingReplies, len(stats.pending))
+	internal.AssertEqual(t, statsPendingReplies, stats.pendingOrder.Len())
+	// the oldest request is no longer tracked
+	stats.replySent("0", received.Add(time.Second))
+	internal.AssertEqual(t, LatencyPercentiles{}, stats.snapshot().ReplyLatency)
+
+	for i := 1; i <= statsLatencySamples; i++ {
+		stats.replySent(fmt.Sprint(i), received.Add(time.Millisecond))
+	}
+	internal.AssertEqual(t, 0, len(stats.pending))
+	internal.AssertEqual(t, 0, stats.pendingOrder.Len())
+	for i := 0; i < statsLatencySamples/2; i++ {
+		stats.requestReceived("testRequestID", received)
+		stats.replySent("testRequestID", received.Add(time.Second))
+	}
+	internal.AssertEqual(t, statsLatencySamples, len(stats.latencies))
+	internal.AssertEqual(t, LatencyPercentiles{P50: time.Millisecond, P90: time.Second, P99: time.Second}, stats.snapshot().ReplyLatency)
+}
+
+func TestStatisticsRequestReceivedAgain(t *testing.T) {
+	stats := &statistics{}
+	received := time.Now()
+
+	stats.requestReceived("testRequestID", received)
+	for i := 1; i < statsPendingReplies; i++ {
+		stats.requestReceived(fmt.Sprint(i), received)
+	}
+	// received again, thus no longer the oldest one
+	stats.requestReceived("testRequestID", received.Add(time.Second))
+	stats.requestReceived("newRequestID", received)
+
+	internal.AssertEqual(t, statsPendingReplies, len(stats.pending))
+	stats.replySent("testRequestID", received.Add(2*time.Second))
+	internal.AssertEqual(t, []time.Duration{time.Second}, stats.latencies)
+}
+
+func TestClientStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        &Configuration{},
+		pahoClient: mockMQTTClient,
+	}
+	cl.stats.requestReceived("testRequestID", time.Now())
+
+	message := &protocol.Envelope{Path: "/outbox/messages/status", Value: "ok", Status: 200}
+	mockMQTTClient.EXPECT().Publish(generateHonoResponseTopic("testRequestID", 200), byte(1), false, gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	internal.AssertNil(t, cl.Reply("testRequestID", message))
+
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(errors.New("publish error"))
+	internal.AssertNotNil(t, cl.Send(message))
+
+	stats := cl.Stats()
+	internal.AssertEqual(t, uint64(1), stats.Sent)
+	internal.AssertTrue(t, stats.ReplyLatency.P50 > 0)
+}
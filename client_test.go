@@ -16,7 +16,9 @@ import (
 	"encoding/json"
 	"errors"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -27,6 +29,13 @@ import (
 	"github.com/golang/mock/gomock"
 )
 
+const (
+	testSubscriptionTopic      = "notification///#"
+	testOtherSubscriptionTopic = "telemetry///#"
+)
+
+func noopRawHandler(topic string, payload []byte) {}
+
 var (
 	mockMQTTClient *mock.MockClient
 	mockToken      *mock.MockToken
@@ -45,8 +54,7 @@ func TestNewClient(t *testing.T) {
 		"test_new_client_empty_configuration": {
 			arg: &Configuration{},
 			want: &honoClient{
-				cfg:      &Configuration{},
-				handlers: map[string]Handler{},
+				cfg: &Configuration{},
 			},
 		},
 		"test_new_client_empty_tls_config": {
@@ -60,7 +68,6 @@ func TestNewClient(t *testing.T) {
 						MinVersion:   tls.VersionTLS12,
 					},
 				},
-				handlers: map[string]Handler{},
 			},
 		},
 		"test_new_client_non_empty_cipher_suites_empty_min_version": {
@@ -82,7 +89,6 @@ func TestNewClient(t *testing.T) {
 						MinVersion: tls.VersionTLS12,
 					},
 				},
-				handlers: map[string]Handler{},
 			},
 		},
 		"test_new_client_empty_cipher_suites_non_empty_min_version": {
@@ -98,7 +104,6 @@ func TestNewClient(t *testing.T) {
 						MinVersion:   tls.VersionTLS13,
 					},
 				},
-				handlers: map[string]Handler{},
 			},
 		},
 		"test_new_client_non_empty_cipher_suites_non_empty_min_version": {
@@ -119,7 +124,6 @@ func TestNewClient(t *testing.T) {
 						MinVersion: tls.VersionTLS13,
 					},
 				},
-				handlers: map[string]Handler{},
 			},
 		},
 	}
@@ -134,6 +138,86 @@ func TestNewClient(t *testing.T) {
 
 type mockExecNewClientMQTT func(mockMQTTClient *mock.MockClient, config *Configuration, message string) (Client, error)
 
+func TestPahoOptions(t *testing.T) {
+	tests := map[string]struct {
+		protocolVersion     MQTTProtocolVersion
+		wantProtocolVersion uint
+	}{
+		"test_protocol_version_default": {
+			protocolVersion:     MQTTProtocolVersionDefault,
+			wantProtocolVersion: 0,
+		},
+		"test_protocol_version_31": {
+			protocolVersion:     MQTTProtocolVersion31,
+			wantProtocolVersion: 3,
+		},
+		"test_protocol_version_311": {
+			protocolVersion:     MQTTProtocolVersion311,
+			wantProtocolVersion: 4,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := NewConfiguration().
+				WithBroker("tcp://localhost:1883").
+				WithCredentials(&Credentials{Username: "user", Password: "pass"}).
+				WithProtocolVersion(testCase.protocolVersion)
+			opts := (&honoClient{cfg: cfg}).pahoOptions()
+
+			internal.AssertEqual(t, testCase.wantProtocolVersion, opts.ProtocolVersion)
+			internal.AssertEqual(t, "tcp://localhost:1883", opts.Servers[0].String())
+			internal.AssertEqual(t, int64(30), opts.KeepAlive)
+			username, password := opts.CredentialsProvider()
+			internal.AssertEqual(t, "user", username)
+			internal.AssertEqual(t, "pass", password)
+		})
+	}
+}
+
+func TestPahoOptionsSession(t *testing.T) {
+	store := MQTT.NewMemoryStore()
+	tests := map[string]struct {
+		cfg              *Configuration
+		wantClientID     string
+		wantStore        MQTT.Store
+		wantCleanSession bool
+	}{
+		"test_default_session": {
+			cfg:              NewConfiguration(),
+			wantCleanSession: true,
+		},
+		"test_client_id": {
+			cfg:              NewConfiguration().WithClientID("device"),
+			wantClientID:     "device",
+			wantCleanSession: true,
+		},
+		"test_persistent_session": {
+			cfg:          NewConfiguration().WithClientID("device").WithStore(store),
+			wantClientID: "device",
+			wantStore:    store,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			opts := (&honoClient{cfg: testCase.cfg}).pahoOptions()
+
+			if testCase.wantClientID == "" {
+				internal.AssertTrue(t, len(opts.ClientID) > 0)
+			} else {
+				internal.AssertEqual(t, testCase.wantClientID, opts.ClientID)
+			}
+			internal.AssertEqual(t, testCase.wantCleanSession, opts.CleanSession)
+			if testCase.wantStore == nil {
+				internal.AssertNil(t, opts.Store)
+			} else {
+				internal.AssertEqual(t, testCase.wantStore, opts.Store)
+			}
+		})
+	}
+}
+
 func TestNewClientMQTT(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -193,6 +277,34 @@ func TestNewClientMQTT(t *testing.T) {
 			mockExecution: mockExecNewClientMQTTConfigurationError,
 			errorMassage:  "TLS configuration is not expected when using external MQTT client",
 		},
+		"test_configuration_protocol_version_error": {
+			arg: &Configuration{
+				protocolVersion: MQTTProtocolVersion31,
+			},
+			mockExecution: mockExecNewClientMQTTConfigurationError,
+			errorMassage:  "protocol version is not expected when using external MQTT client",
+		},
+		"test_configuration_client_id_error": {
+			arg: &Configuration{
+				clientID: "device",
+			},
+			mockExecution: mockExecNewClientMQTTConfigurationError,
+			errorMassage:  "client ID is not expected when using external MQTT client",
+		},
+		"test_configuration_store_error": {
+			arg: &Configuration{
+				store: MQTT.NewMemoryStore(),
+			},
+			mockExecution: mockExecNewClientMQTTConfigurationError,
+			errorMassage:  "store is not expected when using external MQTT client",
+		},
+		"test_configuration_subscription_without_handler_error": {
+			arg: &Configuration{
+				subscriptions: []*Subscription{{Topic: testSubscriptionTopic}},
+			},
+			mockExecution: mockExecNewClientMQTTConfigurationError,
+			errorMassage:  "subscription to 'notification///#' has neither a handler nor a raw handler",
+		},
 	}
 
 	for testName, testCase := range tests {
@@ -232,6 +344,70 @@ func TestConnect(t *testing.T) {
 			},
 			mockExec: mockExecConnectNoError,
 		},
+		"test_external_mqtt_client_command_qos_0_no_error": {
+			client: &honoClient{
+				cfg: &Configuration{
+					commandQoS:    CommandQoSAtMostOnce,
+					commandQoSSet: true,
+					connectHandler: func(client Client) {
+						testWg.Done()
+					},
+				},
+				pahoClient:         mockMQTTClient,
+				externalMQTTClient: true,
+			},
+			mockExec: mockExecConnectQoS0NoError,
+		},
+		"test_external_mqtt_client_without_command_subscription": {
+			client: &honoClient{
+				cfg: &Configuration{
+					noCommandSubscription: true,
+					subscriptions:         []*Subscription{{Topic: testSubscriptionTopic, QoS: 0, RawHandler: noopRawHandler}},
+					connectHandler: func(client Client) {
+						testWg.Done()
+					},
+				},
+				pahoClient:         mockMQTTClient,
+				externalMQTTClient: true,
+			},
+			mockExec: mockExecConnectWithoutCommandSubscription,
+		},
+		"test_external_mqtt_client_share_group": {
+			client: &honoClient{
+				cfg: &Configuration{
+					shareGroup: "consumers",
+					connectHandler: func(client Client) {
+						testWg.Done()
+					},
+				},
+				pahoClient:         mockMQTTClient,
+				externalMQTTClient: true,
+			},
+			mockExec: mockExecConnectShareGroupNoError,
+		},
+		"test_external_mqtt_client_additional_subscription_error": {
+			client: &honoClient{
+				cfg: &Configuration{
+					subscriptions: []*Subscription{{Topic: testSubscriptionTopic, QoS: 0, RawHandler: noopRawHandler}},
+				},
+				pahoClient:         mockMQTTClient,
+				externalMQTTClient: true,
+			},
+			mockExec: mockExecConnectSubscriptionError,
+		},
+		"test_external_mqtt_client_additional_subscription_rollback": {
+			client: &honoClient{
+				cfg: &Configuration{
+					subscriptions: []*Subscription{
+						{Topic: testSubscriptionTopic, QoS: 0, RawHandler: noopRawHandler},
+						{Topic: testOtherSubscriptionTopic, QoS: 0, RawHandler: noopRawHandler},
+					},
+				},
+				pahoClient:         mockMQTTClient,
+				externalMQTTClient: true,
+			},
+			mockExec: mockExecConnectSubscriptionRollback,
+		},
 		"test_external_mqtt_client_error": {
 			client: &honoClient{
 				cfg:                &Configuration{},
@@ -252,6 +428,7 @@ func TestConnect(t *testing.T) {
 
 	for testName, testCase := range tests {
 		t.Run(testName, func(t *testing.T) {
+			resetSharedSubscriptions()
 			expectedError := testCase.mockExec(testWg)
 			actualError := testCase.client.Connect()
 			internal.AssertWithTimeout(t, testWg, 5*time.Second)
@@ -260,6 +437,57 @@ func TestConnect(t *testing.T) {
 	}
 }
 
+func TestConnectInvalidConfiguration(t *testing.T) {
+	client := NewClient(NewConfiguration().WithStore(MQTT.NewMemoryStore()))
+
+	err := client.Connect()
+	internal.AssertNotNil(t, err)
+	internal.AssertEqual(t, "client ID is required when using a store", err.Error())
+	internal.AssertNil(t, client.(*honoClient).pahoClient)
+}
+
+func TestConnectInvalidCommandQoS(t *testing.T) {
+	client := NewClient(NewConfiguration().WithCommandQoS(3))
+
+	err := client.Connect()
+	internal.AssertNotNil(t, err)
+	internal.AssertEqual(t, "invalid command QoS 3, expected 0, 1 or 2", err.Error())
+	internal.AssertNil(t, client.(*honoClient).pahoClient)
+}
+
+func TestConnectSubscriptionWithoutHandler(t *testing.T) {
+	client := NewClient(NewConfiguration().WithSubscriptions(&Subscription{Topic: testSubscriptionTopic}))
+
+	err := client.Connect()
+	internal.AssertNotNil(t, err)
+	internal.AssertEqual(t, "subscription to 'notification///#' has neither a handler nor a raw handler", err.Error())
+	internal.AssertNil(t, client.(*honoClient).pahoClient)
+}
+
+func TestDisconnectInternalClientWithSubscriptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg: &Configuration{
+			disconnectTimeout: defaultDisconnectTimeout,
+			subscriptions:     []*Subscription{{Topic: testSubscriptionTopic, RawHandler: noopRawHandler}},
+		},
+		pahoClient:         mockMQTTClient,
+		externalMQTTClient: false,
+	}
+
+	mockMQTTClient.EXPECT().Unsubscribe(honoMQTTTopicSubscribeCommands, testSubscriptionTopic).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(time.Duration(0)).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	mockMQTTClient.EXPECT().Disconnect(uint(defaultDisconnectTimeout.Milliseconds())).Times(1)
+
+	cl.Disconnect()
+}
+
 func TestDisconnectInternalClient(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -282,6 +510,51 @@ func TestDisconnectInternalClient(t *testing.T) {
 	cl.Disconnect()
 }
 
+func TestDisconnectInternalClientWithoutCommandSubscription(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg: &Configuration{
+			disconnectTimeout:     defaultDisconnectTimeout,
+			noCommandSubscription: true,
+		},
+		pahoClient:         mockMQTTClient,
+		externalMQTTClient: false,
+	}
+
+	mockMQTTClient.EXPECT().Disconnect(uint(defaultDisconnectTimeout.Milliseconds())).Times(1)
+
+	cl.Disconnect()
+}
+
+func TestDisconnectInternalClientWithShareGroup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg: &Configuration{
+			disconnectTimeout: defaultDisconnectTimeout,
+			shareGroup:        "consumers",
+		},
+		pahoClient:         mockMQTTClient,
+		externalMQTTClient: false,
+	}
+
+	mockMQTTClient.EXPECT().Unsubscribe("$share/consumers/" + honoMQTTTopicSubscribeCommands).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(time.Duration(0)).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	mockMQTTClient.EXPECT().Disconnect(uint(defaultDisconnectTimeout.Milliseconds())).Times(1)
+
+	cl.Disconnect()
+}
+
 type mockExecUnsubscribe func()
 
 func TestDisconnectExternalClient(t *testing.T) {
@@ -379,6 +652,65 @@ func TestReply(t *testing.T) {
 	}
 }
 
+func TestDeviceTopics(t *testing.T) {
+	tests := map[string]struct {
+		cfg          *Configuration
+		wantCommands string
+		wantEvents   string
+		wantResponse string
+	}{
+		"test_short_topics": {
+			cfg:          &Configuration{},
+			wantCommands: "command///req/#",
+			wantEvents:   "e",
+			wantResponse: "command///res/req-id/204",
+		},
+		"test_tenant_and_device": {
+			cfg:          &Configuration{tenantID: "my-tenant", deviceID: "my-device"},
+			wantCommands: "command/my-tenant/my-device/req/#",
+			wantEvents:   "event/my-tenant/my-device",
+			wantResponse: "command/my-tenant/my-device/res/req-id/204",
+		},
+		"test_device_only": {
+			cfg:          &Configuration{deviceID: "my-device"},
+			wantCommands: "command//my-device/req/#",
+			wantEvents:   "event//my-device",
+			wantResponse: "command//my-device/res/req-id/204",
+		},
+		"test_shared_tenant_and_device": {
+			cfg:          &Configuration{tenantID: "my-tenant", deviceID: "my-device", shareGroup: "consumers"},
+			wantCommands: "$share/consumers/command/my-tenant/my-device/req/#",
+			wantEvents:   "event/my-tenant/my-device",
+			wantResponse: "command/my-tenant/my-device/res/req-id/204",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			client := &honoClient{cfg: testCase.cfg}
+			internal.AssertEqual(t, testCase.wantCommands, client.commandsTopic())
+			internal.AssertEqual(t, testCase.wantEvents, client.eventsTopic())
+			internal.AssertEqual(t, testCase.wantResponse, client.responseTopic("req-id", 204))
+		})
+	}
+}
+
+func TestSendDeviceTopic(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        (&Configuration{}).WithTenantID("my-tenant").WithDeviceID("my-device"),
+		pahoClient: mockMQTTClient,
+	}
+
+	payload, _ := json.Marshal(&protocol.Envelope{})
+	expectedError := mockExecPublishNoErrors("event/my-tenant/my-device", payload)
+	internal.AssertError(t, expectedError, cl.Send(&protocol.Envelope{}))
+}
+
 func TestSend(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -420,133 +752,713 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestReplyTo(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        &Configuration{},
+		pahoClient: mockMQTTClient,
+	}
+
+	request := &protocol.Envelope{
+		Headers: protocol.NewHeaders(
+			protocol.WithCorrelationID("testCorrelationID"),
+			protocol.WithContentType(protocol.ContentTypeJSON),
+			protocol.WithOriginator("nginx:ditto"),
+			protocol.WithResponseRequired(true),
+		),
+		Path: "/inbox/messages/status",
+	}
+	message := &protocol.Envelope{Path: "/outbox/messages/status", Value: "ok", Status: 200}
+
+	var published []byte
+	mockMQTTClient.EXPECT().Publish(generateHonoResponseTopic("testRequestID", 200), byte(1), false, gomock.Any()).
+		DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+			published = append([]byte(nil), payload.([]byte)...)
+			return mockToken
+		})
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+
+	internal.AssertNil(t, cl.ReplyTo("testRequestID", request, message))
+
+	sent, err := getEnvelope(published, nil)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "testCorrelationID", sent.Headers.CorrelationID())
+	internal.AssertEqual(t, protocol.ContentTypeJSON, sent.Headers.ContentType())
+	internal.AssertEqual(t, "nginx:ditto", sent.Headers.Originator())
+	internal.AssertEqual(t, false, sent.Headers.Values[protocol.HeaderResponseRequired])
+	internal.AssertEqual(t, "ok", sent.Value)
+	internal.AssertNil(t, message.Headers)
+}
+
+func TestSendStampCreationTime(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg:        &Configuration{stampCreationTime: true},
+		pahoClient: mockMQTTClient,
+	}
+
+	message := &protocol.Envelope{Headers: protocol.NewHeaders(protocol.WithCorrelationID("testCorrelationID"))}
+
+	var published []byte
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+		DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+			published = payload.([]byte)
+			return mockToken
+		})
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+
+	internal.AssertNil(t, cl.Send(message))
+
+	sent, err := getEnvelope(published, nil)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "testCorrelationID", sent.Headers.CorrelationID())
+	internal.AssertFalse(t, sent.Headers.CreationTime().IsZero())
+	internal.AssertNil(t, message.Headers.Values[protocol.HeaderCreationTime])
+}
+
+func TestSendDefaults(t *testing.T) {
+	tests := map[string]struct {
+		message                *protocol.Envelope
+		wantCorrelationID      string
+		wantResponseRequired   bool
+		wantOriginalHeadersNil bool
+	}{
+		"test_without_headers": {
+			message:                &protocol.Envelope{Path: "/attributes"},
+			wantOriginalHeadersNil: true,
+		},
+		"test_with_other_headers": {
+			message: &protocol.Envelope{Headers: protocol.NewHeaders(protocol.WithContentType(protocol.ContentTypeJSON))},
+		},
+		"test_with_provided_headers": {
+			message: &protocol.Envelope{Headers: protocol.NewHeaders(
+				protocol.WithCorrelationID("testCorrelationID"), protocol.WithResponseRequired(true))},
+			wantCorrelationID:    "testCorrelationID",
+			wantResponseRequired: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			setup(mockCtrl)
+
+			cl := &honoClient{
+				cfg:        NewConfiguration().WithSendDefaults(true),
+				pahoClient: mockMQTTClient,
+			}
+
+			var published [][]byte
+			mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+				DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+					published = append(published, append([]byte(nil), payload.([]byte)...))
+					return mockToken
+				}).Times(2)
+			mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true).Times(2)
+			mockToken.EXPECT().Error().Return(nil).Times(2)
+
+			internal.AssertNil(t, cl.Send(testCase.message))
+			internal.AssertNil(t, cl.SendBatch([]*protocol.Envelope{testCase.message}))
+
+			for _, payload := range published {
+				sent, err := getEnvelope(payload, nil)
+				internal.AssertNil(t, err)
+				if testCase.wantCorrelationID == "" {
+					internal.AssertTrue(t, sent.Headers.CorrelationID() != "")
+				} else {
+					internal.AssertEqual(t, testCase.wantCorrelationID, sent.Headers.CorrelationID())
+				}
+				internal.AssertEqual(t, testCase.wantResponseRequired, sent.Headers.IsResponseRequired())
+				internal.AssertNotNil(t, sent.Headers.Values[protocol.HeaderResponseRequired])
+			}
+			if testCase.wantOriginalHeadersNil {
+				internal.AssertNil(t, testCase.message.Headers)
+			} else if testCase.wantCorrelationID == "" {
+				internal.AssertEqual(t, "", testCase.message.Headers.CorrelationID())
+			}
+		})
+	}
+}
+
+func TestSendCBOREncoding(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg:        &Configuration{cborEncoding: true},
+		pahoClient: mockMQTTClient,
+	}
+
+	message := &protocol.Envelope{
+		Topic:   &protocol.Topic{Namespace: "ns", EntityName: "thing", Group: protocol.GroupThings, Channel: protocol.ChannelTwin, Criterion: protocol.CriterionEvents, Action: protocol.ActionModified},
+		Headers: protocol.NewHeaders(protocol.WithCorrelationID("testCorrelationID")),
+		Path:    "/attributes/location",
+		Value:   map[string]interface{}{"lat": 42.5},
+	}
+
+	var published []byte
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+		DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+			published = payload.([]byte)
+			return mockToken
+		})
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+
+	internal.AssertNil(t, cl.Send(message))
+	internal.AssertTrue(t, protocol.IsCBOR(published))
+
+	sent, err := getEnvelope(published, nil)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, message.Topic, sent.Topic)
+	internal.AssertEqual(t, "testCorrelationID", sent.Headers.CorrelationID())
+	internal.AssertEqual(t, message.Path, sent.Path)
+	internal.AssertEqual(t, message.Value, sent.Value)
+}
+
+func TestSendWireHook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var traced []string
+	cl := &honoClient{
+		cfg: NewConfiguration().WithWireHook(func(topic string, direction Direction, payload []byte) {
+			traced = append(traced, topic+" "+direction.String()+" "+string(payload))
+		}),
+		pahoClient: mockMQTTClient,
+	}
+	message := &protocol.Envelope{Path: "/attributes", Value: 1}
+
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).Return(mockToken)
+	mockMQTTClient.EXPECT().Publish(generateHonoResponseTopic("req", 204), byte(1), false, gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true).Times(2)
+	mockToken.EXPECT().Error().Return(nil).Times(2)
+
+	internal.AssertNil(t, cl.Send(message))
+	internal.AssertNil(t, cl.Reply("req", message.WithStatus(204)))
+	internal.AssertEqual(t, []string{
+		`e outgoing {"topic":null,"path":"/attributes","value":1}`,
+		`command///res/req/204 outgoing {"topic":null,"path":"/attributes","value":1,"status":204}`,
+	}, traced)
+}
+
+func TestSendPooledEncoding(t *testing.T) {
+	tests := map[string]struct {
+		cfg  *Configuration
+		want []byte
+	}{
+		"test_json_pre_sized": {
+			cfg:  NewConfiguration().WithEncodingBufferSize(1024),
+			want: []byte(`{"topic":null,"path":"/attributes","value":1}`),
+		},
+		"test_cbor": {
+			cfg: NewConfiguration().WithCBOREncoding(true),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			setup(mockCtrl)
+
+			cl := &honoClient{
+				cfg:        testCase.cfg,
+				pahoClient: mockMQTTClient,
+			}
+			message := &protocol.Envelope{Path: "/attributes", Value: 1}
+			want := testCase.want
+			if want == nil {
+				want, _ = protocol.MarshalCBOR(message)
+			}
+
+			var published []byte
+			mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+				DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+					published = append([]byte(nil), payload.([]byte)...)
+					return mockToken
+				})
+			mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+			mockToken.EXPECT().Error().Return(nil)
+
+			internal.AssertNil(t, cl.Send(message))
+			internal.AssertEqual(t, want, published)
+		})
+	}
+}
+
+func TestSendBatchPipelining(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        NewConfiguration().WithMaxInflight(2),
+		pahoClient: mockMQTTClient,
+	}
+	messages := []*protocol.Envelope{
+		{Path: "/attributes/a", Value: 1},
+		{Path: "/attributes/b", Value: 2},
+		{Path: "/attributes/c", Value: 3},
+	}
+
+	var published []string
+	publish := func(token MQTT.Token) *gomock.Call {
+		return mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+			DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+				published = append(published, string(payload.([]byte)))
+				return token
+			})
+	}
+	wait := func(token *mock.MockToken) *gomock.Call {
+		token.EXPECT().Error().Return(nil).AnyTimes()
+		return token.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	}
+	tokens := []*mock.MockToken{mock.NewMockToken(mockCtrl), mock.NewMockToken(mockCtrl), mock.NewMockToken(mockCtrl)}
+	gomock.InOrder(
+		publish(tokens[0]),
+		publish(tokens[1]),
+		// the third message waits for the first one to be acknowledged
+		wait(tokens[0]),
+		publish(tokens[2]),
+		wait(tokens[1]),
+		wait(tokens[2]),
+	)
+
+	internal.AssertNil(t, cl.SendBatch(messages))
+	internal.AssertEqual(t, []string{
+		`{"topic":null,"path":"/attributes/a","value":1}`,
+		`{"topic":null,"path":"/attributes/b","value":2}`,
+		`{"topic":null,"path":"/attributes/c","value":3}`,
+	}, published)
+}
+
+func TestSendBatchErrors(t *testing.T) {
+	tests := map[string]struct {
+		messages []*protocol.Envelope
+		tokenErr error
+		timeout  bool
+		want     string
+	}{
+		"test_encoding_error_stops_sending": {
+			messages: []*protocol.Envelope{{Path: "/a"}, {Path: "/b", Value: make(chan int)}, {Path: "/c"}},
+			want:     "envelope 1: json: unsupported type: chan int",
+		},
+		"test_publish_error": {
+			messages: []*protocol.Envelope{{Path: "/a"}},
+			tokenErr: errors.New("test error"),
+			want:     "envelope 0: test error",
+		},
+		"test_acknowledge_timeout": {
+			messages: []*protocol.Envelope{{Path: "/a"}},
+			timeout:  true,
+			want:     "envelope 0: " + ErrAcknowledgeTimeout.Error(),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			setup(mockCtrl)
+
+			cl := &honoClient{
+				cfg:        NewConfiguration(),
+				pahoClient: mockMQTTClient,
+			}
+			mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).Return(mockToken).MaxTimes(1)
+			mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(!testCase.timeout).MaxTimes(1)
+			mockToken.EXPECT().Error().Return(testCase.tokenErr).AnyTimes()
+
+			err := cl.SendBatch(testCase.messages)
+			internal.AssertNotNil(t, err)
+			internal.AssertEqual(t, testCase.want, err.Error())
+		})
+	}
+}
+
+func TestSendFrozen(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        NewConfiguration().WithCBOREncoding(true).WithCompressionThreshold(1),
+		pahoClient: mockMQTTClient,
+	}
+	frozen, err := (&protocol.Envelope{Path: "/attributes", Value: 1}).Freeze()
+	internal.AssertNil(t, err)
+
+	var published [][]byte
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+		DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+			published = append(published, append([]byte(nil), payload.([]byte)...))
+			return mockToken
+		}).Times(2)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true).Times(2)
+	mockToken.EXPECT().Error().Return(nil).Times(2)
+
+	internal.AssertNil(t, cl.SendFrozen(frozen))
+	internal.AssertNil(t, cl.SendFrozen(frozen))
+
+	correlationIDs := map[string]bool{}
+	for _, payload := range published {
+		envelope := &protocol.Envelope{}
+		// sent as frozen, i.e. as JSON without compression
+		internal.AssertNil(t, json.Unmarshal(payload, envelope))
+		internal.AssertEqual(t, "/attributes", envelope.Path)
+		internal.AssertEqual(t, float64(1), envelope.Value)
+		internal.AssertFalse(t, envelope.Headers.CreationTime().IsZero())
+		correlationIDs[envelope.Headers.CorrelationID()] = true
+	}
+	internal.AssertEqual(t, 2, len(correlationIDs))
+}
+
+func TestSendEncodingError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	cl := &honoClient{
+		cfg:        NewConfiguration(),
+		pahoClient: mockMQTTClient,
+	}
+
+	internal.AssertNotNil(t, cl.Send(&protocol.Envelope{Path: "/attributes", Value: make(chan int)}))
+}
+
+func TestSendCompression(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	setup(mockCtrl)
+
+	var cl Client
+	cl = &honoClient{
+		cfg:        &Configuration{compressionThreshold: 64},
+		pahoClient: mockMQTTClient,
+	}
+
+	value := map[string]interface{}{"description": strings.Repeat("verbose ", 20)}
+	message := &protocol.Envelope{
+		Topic: &protocol.Topic{Namespace: "ns", EntityName: "thing", Group: protocol.GroupThings, Channel: protocol.ChannelTwin, Criterion: protocol.CriterionEvents, Action: protocol.ActionModified},
+		Path:  "/attributes",
+		Value: value,
+	}
+
+	var published []byte
+	mockMQTTClient.EXPECT().Publish(honoMQTTTopicPublishEvents, byte(1), false, gomock.Any()).
+		DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+			published = payload.([]byte)
+			return mockToken
+		})
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+
+	internal.AssertNil(t, cl.Send(message))
+	internal.AssertTrue(t, strings.Contains(string(published), `"content-encoding":"gzip"`))
+	internal.AssertNil(t, message.Headers)
+
+	received, err := cl.(*honoClient).receivedEnvelope(published)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, value, received.Value)
+	internal.AssertEqual(t, "", received.Headers.ContentEncoding())
+}
+
+func TestReceivedEnvelope(t *testing.T) {
+	value := strings.Repeat("verbose ", 20)
+	compressed, err := protocol.CompressValue(&protocol.Envelope{
+		Topic:   &protocol.Topic{Namespace: "ns", EntityName: "thing", Group: protocol.GroupThings, Channel: protocol.ChannelTwin, Criterion: protocol.CriterionEvents, Action: protocol.ActionModified},
+		Headers: protocol.NewHeaders(),
+		Path:    "/attributes/description",
+		Value:   value,
+	}, 0)
+	internal.AssertNil(t, err)
+	payload, err := json.Marshal(compressed)
+	internal.AssertNil(t, err)
+
+	tests := map[string]struct {
+		cfg          *Configuration
+		wantEncoding string
+		wantErr      bool
+	}{
+		"test_compression_disabled": {
+			cfg:          NewConfiguration(),
+			wantEncoding: protocol.ContentEncodingGzip,
+		},
+		"test_compression_enabled": {
+			cfg: NewConfiguration().WithCompressionThreshold(64),
+		},
+		"test_exceeding_max_decompressed_size": {
+			cfg:     NewConfiguration().WithCompressionThreshold(64).WithMaxDecompressedSize(64),
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			client := &honoClient{cfg: testCase.cfg}
+
+			received, err := client.receivedEnvelope(payload)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				return
+			}
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.wantEncoding, received.Headers.ContentEncoding())
+			if testCase.wantEncoding == "" {
+				internal.AssertEqual(t, value, received.Value)
+			}
+		})
+	}
+}
+
+func TestReceivedEnvelopeDecoder(t *testing.T) {
+	payload := []byte(`{"topic":"ns/thing/things/twin/events/modified","path":"/","value":1,"newField":"x"}`)
+
+	client := &honoClient{cfg: NewConfiguration().WithDecoder(protocol.NewDecoder(protocol.WithRetainUnknownFields(true)))}
+	received, err := client.receivedEnvelope(payload)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, map[string]json.RawMessage{"newField": json.RawMessage(`"x"`)}, received.Unknown)
+
+	received, err = getEnvelope(payload, nil)
+	internal.AssertNil(t, err)
+	internal.AssertNil(t, received.Unknown)
+}
+
 func TestSubscribe(t *testing.T) {
 	handler := func(requestID string, message *protocol.Envelope) {}
 	secondHandler := func(requestID string, message *protocol.Envelope) {}
 
 	tests := map[string]struct {
 		arg        []Handler
-		testClient Client
-		want       map[string]Handler
+		testClient *honoClient
+		wantIDs    []HandlerID
+		want       []Handler
 	}{
 		"test_client_handlers_nil": {
-			arg: nil,
-			testClient: &honoClient{
-				handlers: nil,
-			},
-			want: make(map[string]Handler),
+			arg:        nil,
+			testClient: &honoClient{},
+			wantIDs:    []HandlerID{},
 		},
 		"test_client_handlers_empty": {
-			arg: []Handler{
-				handler,
-			},
+			arg:        []Handler{handler},
 			testClient: &honoClient{},
-			want: map[string]Handler{
-				getHandlerName(handler): handler,
-			},
+			wantIDs:    []HandlerID{1},
+			want:       []Handler{handler},
 		},
 		"test_client_not_empty_handler": {
-			arg: []Handler{
-				secondHandler,
-			},
+			arg: []Handler{secondHandler},
 			testClient: &honoClient{
-				handlers: map[string]Handler{
-					getHandlerName(handler): handler,
-				},
+				handlers:      []subscribedHandler{{id: 1, handler: handler, code: handlerCode(handler)}},
+				lastHandlerID: 1,
 			},
-			want: map[string]Handler{
-				getHandlerName(handler):       handler,
-				getHandlerName(secondHandler): secondHandler,
+			wantIDs: []HandlerID{2},
+			want:    []Handler{handler, secondHandler},
+		},
+		"test_same_handler_subscribed_again": {
+			arg: []Handler{handler, handler},
+			testClient: &honoClient{
+				handlers:      []subscribedHandler{{id: 1, handler: handler, code: handlerCode(handler)}},
+				lastHandlerID: 1,
 			},
+			wantIDs: []HandlerID{2, 3},
+			want:    []Handler{handler, handler, handler},
+		},
+		"test_same_handler_twice": {
+			arg:        []Handler{handler, secondHandler, handler},
+			testClient: &honoClient{},
+			wantIDs:    []HandlerID{1, 2, 3},
+			want:       []Handler{handler, secondHandler, handler},
 		},
 	}
 
 	for testName, testCase := range tests {
 		t.Run(testName, func(t *testing.T) {
-			testCase.testClient.Subscribe(testCase.arg...)
-			handlers := testCase.testClient.(*honoClient).handlers
-			internal.AssertEqual(t, len(testCase.want), len(handlers))
-			for key, element := range testCase.want {
-				got := handlers[key]
-				if got == nil || reflect.ValueOf(got).Pointer() != reflect.ValueOf(element).Pointer() {
-					t.Errorf("Client.Subscribe()= %v, want %v", got, element)
-				}
-			}
+			ids := testCase.testClient.Subscribe(testCase.arg...)
+			internal.AssertEqual(t, testCase.wantIDs, ids)
+			assertSubscribedHandlers(t, testCase.want, testCase.testClient.handlers)
 		})
 	}
 }
 
+func TestSubscribeOnce(t *testing.T) {
+	handler := func(requestID string, message *protocol.Envelope) {}
+	testClient := &honoClient{}
+
+	internal.AssertEqual(t, []HandlerID{1, 2}, testClient.Subscribe(handler, handler))
+	internal.AssertEqual(t, []HandlerID{1, 1}, testClient.SubscribeOnce(handler, handler))
+	assertSubscribedHandlers(t, []Handler{handler, handler}, testClient.handlers)
+}
+
+func TestSubscribeClosures(t *testing.T) {
+	newHandler := func(received *int32) Handler {
+		return func(requestID string, message *protocol.Envelope) {
+			atomic.AddInt32(received, 1)
+		}
+	}
+	var first, second int32
+	testClient := &honoClient{cfg: NewConfiguration()}
+
+	internal.AssertEqual(t, []HandlerID{1, 2}, testClient.Subscribe(newHandler(&first), newHandler(&second)))
+	testClient.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertNil(t, testClient.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&first))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&second))
+}
+
 func TestUnsubscribe(t *testing.T) {
 	handler := func(requestID string, message *protocol.Envelope) {}
 	secondHandler := func(requestID string, message *protocol.Envelope) {}
+	subscribed := []subscribedHandler{
+		{id: 1, handler: handler, code: handlerCode(handler)},
+		{id: 2, handler: secondHandler, code: handlerCode(secondHandler)},
+	}
 
 	tests := map[string]struct {
-		arg        []Handler
-		testClient Client
-		want       map[string]Handler
+		arg      []Handler
+		existing []subscribedHandler
+		want     []Handler
 	}{
 		"test_remove_all_handlers": {
-			arg: []Handler{},
-			testClient: &honoClient{
-				handlers: map[string]Handler{
-					getHandlerName(handler):       handler,
-					getHandlerName(secondHandler): secondHandler,
-				},
-			},
-			want: make(map[string]Handler),
+			arg:      []Handler{},
+			existing: subscribed,
 		},
 		"test_remove_nil_argument": {
-			arg: nil,
-			testClient: &honoClient{
-				handlers: map[string]Handler{
-					getHandlerName(handler):       handler,
-					getHandlerName(secondHandler): secondHandler,
-				},
-			},
-			want: make(map[string]Handler),
+			arg:      nil,
+			existing: subscribed,
 		},
 		"test_remove_arg_handler": {
-			arg: []Handler{
-				handler,
-			},
-			testClient: &honoClient{
-				handlers: map[string]Handler{
-					getHandlerName(handler):       handler,
-					getHandlerName(secondHandler): secondHandler,
-				},
-			}, want: map[string]Handler{
-				getHandlerName(secondHandler): secondHandler,
-			},
+			arg:      []Handler{handler},
+			existing: subscribed,
+			want:     []Handler{secondHandler},
 		},
 		"test_remove_not_existing_handler": {
-			arg: []Handler{
-				handler,
-			},
-			testClient: &honoClient{
-				handlers: map[string]Handler{
-					getHandlerName(secondHandler): secondHandler,
-				},
-			}, want: map[string]Handler{
-				getHandlerName(secondHandler): secondHandler,
-			},
+			arg:      []Handler{handler},
+			existing: subscribed[1:],
+			want:     []Handler{secondHandler},
 		},
 	}
 
 	for testName, testCase := range tests {
 		t.Run(testName, func(t *testing.T) {
-			testCase.testClient.Unsubscribe(testCase.arg...)
-			handlers := testCase.testClient.(*honoClient).handlers
-			internal.AssertEqual(t, len(testCase.want), len(handlers))
-			for key, element := range testCase.want {
-				got := handlers[key]
-				internal.AssertNotNil(t, got)
-				if reflect.ValueOf(got).Pointer() != reflect.ValueOf(element).Pointer() {
-					t.Errorf("Client.Unsubscribe()= %v, want %v", got, element)
-				}
-			}
+			testClient := &honoClient{handlers: append([]subscribedHandler(nil), testCase.existing...)}
+			testClient.Unsubscribe(testCase.arg...)
+			assertSubscribedHandlers(t, testCase.want, testClient.handlers)
+		})
+	}
+}
+
+func TestUnsubscribeIDs(t *testing.T) {
+	newHandler := func(id int) Handler {
+		return func(requestID string, message *protocol.Envelope) {}
+	}
+	first, second := newHandler(1), newHandler(2)
+
+	tests := map[string]struct {
+		arg  []HandlerID
+		want []Handler
+	}{
+		"test_remove_closure_by_id": {
+			arg:  []HandlerID{1},
+			want: []Handler{second},
+		},
+		"test_remove_all_by_ids": {
+			arg: []HandlerID{2, 1},
+		},
+		"test_remove_unknown_id": {
+			arg:  []HandlerID{3},
+			want: []Handler{first, second},
+		},
+		"test_remove_no_ids": {
+			want: []Handler{first, second},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testClient := &honoClient{}
+			internal.AssertEqual(t, []HandlerID{1, 2}, testClient.Subscribe(first, second))
+			testClient.UnsubscribeIDs(testCase.arg...)
+			assertSubscribedHandlers(t, testCase.want, testClient.handlers)
 		})
 	}
+}
 
+func TestHandlers(t *testing.T) {
+	closure := func(requestID string, message *protocol.Envelope) {}
+	testClient := &honoClient{}
+	internal.AssertEqual(t, []HandlerInfo{}, testClient.Handlers())
+
+	ids := testClient.Subscribe(testHandler, closure, testHandler)
+	testClient.UnsubscribeIDs(ids[2])
+	internal.AssertEqual(t, []HandlerInfo{
+		{ID: ids[0], Name: "github.com/eclipse/ditto-clients-golang.testHandler"},
+		{ID: ids[1], Name: "github.com/eclipse/ditto-clients-golang.TestHandlers.func1"},
+	}, testClient.Handlers())
+}
+
+func TestWaitForIdle(t *testing.T) {
+	release := make(chan struct{})
+	var handled int32
+	handler := func(requestID string, message *protocol.Envelope) {
+		<-release
+		atomic.AddInt32(&handled, 1)
+	}
+	testClient := &honoClient{cfg: NewConfiguration()}
+	testClient.Subscribe(handler, handler)
+	internal.AssertNil(t, testClient.WaitForIdle(time.Millisecond))
+
+	testClient.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertError(t, ErrIdleTimeout, testClient.WaitForIdle(10*time.Millisecond))
+
+	close(release)
+	internal.AssertNil(t, testClient.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(2), atomic.LoadInt32(&handled))
+}
+
+func TestUnsubscribeSnapshot(t *testing.T) {
+	handler := func(requestID string, message *protocol.Envelope) {}
+	testClient := &honoClient{}
+	ids := testClient.Subscribe(handler, handler)
+
+	snapshot := testClient.handlers
+	testClient.UnsubscribeIDs(ids[0])
+	internal.AssertEqual(t, ids[0], snapshot[0].id)
+	internal.AssertEqual(t, ids[1], snapshot[1].id)
+	internal.AssertEqual(t, 1, len(testClient.handlers))
+}
+
+func assertSubscribedHandlers(t *testing.T, want []Handler, got []subscribedHandler) {
+	internal.AssertEqual(t, len(want), len(got))
+	for i := 0; i < len(want) && i < len(got); i++ {
+		if reflect.ValueOf(got[i].handler).Pointer() != reflect.ValueOf(want[i]).Pointer() {
+			t.Errorf("handler %d = %v, want %v", i, got[i].handler, want[i])
+		}
+	}
 }
 
 // Mock executions -------------------------------------------------------------
@@ -618,6 +1530,30 @@ func mockExecConnectNoError(testWg *sync.WaitGroup) error {
 	return nil
 }
 
+func mockExecConnectQoS0NoError(testWg *sync.WaitGroup) error {
+	testWg.Add(1)
+	mockMQTTClient.EXPECT().Subscribe(honoMQTTTopicSubscribeCommands, byte(0), gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	return nil
+}
+
+func mockExecConnectWithoutCommandSubscription(testWg *sync.WaitGroup) error {
+	testWg.Add(1)
+	mockMQTTClient.EXPECT().Subscribe(testSubscriptionTopic, byte(0), gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	return nil
+}
+
+func mockExecConnectShareGroupNoError(testWg *sync.WaitGroup) error {
+	testWg.Add(1)
+	mockMQTTClient.EXPECT().Subscribe("$share/consumers/"+honoMQTTTopicSubscribeCommands, byte(1), gomock.Any()).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+	mockToken.EXPECT().Error().Return(nil)
+	return nil
+}
+
 func mockExecConnectError(testWg *sync.WaitGroup) error {
 	mockMQTTClient.EXPECT().Subscribe(honoMQTTTopicSubscribeCommands, byte(1), gomock.Any()).Return(mockToken)
 	mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
@@ -631,3 +1567,37 @@ func mockExecConnectTimeoutError(testWg *sync.WaitGroup) error {
 	mockToken.EXPECT().Error().Return(nil)
 	return ErrSubscribeTimeout
 }
+
+func mockExecConnectSubscriptionError(testWg *sync.WaitGroup) error {
+	gomock.InOrder(
+		mockMQTTClient.EXPECT().Subscribe(honoMQTTTopicSubscribeCommands, byte(1), gomock.Any()).Return(mockToken),
+		mockMQTTClient.EXPECT().Subscribe(testSubscriptionTopic, byte(0), gomock.Any()).Return(mockToken),
+	)
+	mockMQTTClient.EXPECT().Unsubscribe(honoMQTTTopicSubscribeCommands).Return(mockToken)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Times(3).Return(true)
+	gomock.InOrder(
+		mockToken.EXPECT().Error().Return(nil),
+		mockToken.EXPECT().Error().Return(MQTT.ErrNotConnected),
+		mockToken.EXPECT().Error().Return(nil),
+	)
+	return MQTT.ErrNotConnected
+}
+
+func mockExecConnectSubscriptionRollback(testWg *sync.WaitGroup) error {
+	gomock.InOrder(
+		mockMQTTClient.EXPECT().Subscribe(honoMQTTTopicSubscribeCommands, byte(1), gomock.Any()).Return(mockToken),
+		mockMQTTClient.EXPECT().Subscribe(testSubscriptionTopic, byte(0), gomock.Any()).Return(mockToken),
+		mockMQTTClient.EXPECT().Subscribe(testOtherSubscriptionTopic, byte(0), gomock.Any()).Return(mockToken),
+		mockMQTTClient.EXPECT().Unsubscribe(testSubscriptionTopic).Return(mockToken),
+		mockMQTTClient.EXPECT().Unsubscribe(honoMQTTTopicSubscribeCommands).Return(mockToken),
+	)
+	mockToken.EXPECT().WaitTimeout(gomock.Any()).Times(5).Return(true)
+	gomock.InOrder(
+		mockToken.EXPECT().Error().Return(nil),
+		mockToken.EXPECT().Error().Return(nil),
+		mockToken.EXPECT().Error().Return(MQTT.ErrNotConnected),
+		mockToken.EXPECT().Error().Return(nil),
+		mockToken.EXPECT().Error().Return(nil),
+	)
+	return MQTT.ErrNotConnected
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/eclipse/ditto-clients-golang/protocol/things"
+)
+
+const (
+	envBroker   = "DITTO_CONFORMANCE_BROKER"
+	envUsername = "DITTO_CONFORMANCE_USERNAME"
+	envPassword = "DITTO_CONFORMANCE_PASSWORD"
+	envThingID  = "DITTO_CONFORMANCE_THING_ID"
+	envLive     = "DITTO_CONFORMANCE_LIVE"
+	envTimeout  = "DITTO_CONFORMANCE_TIMEOUT"
+
+	defaultTimeout = 10 * time.Second
+
+	conformanceAttribute = "dittoClientConformance"
+	conformanceFeature   = "dittoClientConformance"
+	conformanceSubject   = "ditto-client-conformance"
+)
+
+type environment struct {
+	client  ditto.Client
+	thingID *model.NamespacedID
+	timeout time.Duration
+}
+
+func setUp(t *testing.T) *environment {
+	broker := os.Getenv(envBroker)
+	if broker == "" {
+		t.Skipf("conformance tests are skipped, %s is not set", envBroker)
+	}
+	thingID := model.NewNamespacedIDFrom(os.Getenv(envThingID))
+	if thingID == nil {
+		t.Fatalf("invalid %s: %s", envThingID, os.Getenv(envThingID))
+	}
+	timeout := defaultTimeout
+	if value := os.Getenv(envTimeout); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			t.Fatalf("invalid %s: %v", envTimeout, err)
+		}
+	}
+
+	connected := make(chan struct{})
+	var connectedOnce sync.Once
+	cfg := ditto.NewConfiguration().
+		WithBroker(broker).
+		WithAcknowledgeTimeout(timeout).
+		WithSubscribeTimeout(timeout).
+		WithConnectHandler(func(client ditto.Client) {
+			connectedOnce.Do(func() { close(connected) })
+		})
+	if username := os.Getenv(envUsername); username != "" {
+		cfg.WithCredentials(&ditto.Credentials{Username: username, Password: os.Getenv(envPassword)})
+	}
+
+	client := ditto.NewClient(cfg)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("error connecting to %s: %v", broker, err)
+	}
+	t.Cleanup(client.Disconnect)
+	select {
+	case <-connected:
+	case <-time.After(timeout):
+		t.Fatalf("timed out waiting for the connection to %s", broker)
+	}
+	return &environment{client: client, thingID: thingID, timeout: timeout}
+}
+
+func (env *environment) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), env.timeout)
+}
+
+func TestConformanceTwinAttribute(t *testing.T) {
+	env := setUp(t)
+	client := things.NewClient(env.client)
+	defer client.Close()
+
+	ctx, cancel := env.context()
+	defer cancel()
+
+	value := map[string]interface{}{"text": "conformance", "count": float64(time.Now().Unix())}
+	internal.AssertNil(t, client.ModifyAttribute(ctx, env.thingID, conformanceAttribute, value))
+	defer func() {
+		ctx, cancel := env.context()
+		defer cancel()
+		_, err := client.Execute(ctx, things.NewCommand(env.thingID).Twin().Attribute(conformanceAttribute).Delete())
+		internal.AssertNil(t, err)
+	}()
+
+	var got map[string]interface{}
+	internal.AssertNil(t, client.RetrieveAttribute(ctx, env.thingID, conformanceAttribute, &got))
+	internal.AssertEqual(t, value, got)
+
+	thing, err := client.RetrieveThing(ctx, env.thingID)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, env.thingID.String(), thing.ID.String())
+	internal.AssertEqual(t, value, thing.Attributes[conformanceAttribute])
+}
+
+func TestConformanceTwinFeature(t *testing.T) {
+	env := setUp(t)
+	client := things.NewClient(env.client)
+	defer client.Close()
+
+	ctx, cancel := env.context()
+	defer cancel()
+
+	feature := (&model.Feature{}).WithProperty("on", false).WithProperty("level", float64(1))
+	internal.AssertNil(t, client.ModifyFeature(ctx, env.thingID, conformanceFeature, feature))
+	defer func() {
+		ctx, cancel := env.context()
+		defer cancel()
+		internal.AssertNil(t, client.DeleteFeature(ctx, env.thingID, conformanceFeature))
+	}()
+
+	internal.AssertNil(t, client.ModifyFeatureProperty(ctx, env.thingID, conformanceFeature, "on", true))
+	var on bool
+	internal.AssertNil(t, client.RetrieveFeatureProperty(ctx, env.thingID, conformanceFeature, "on", &on))
+	internal.AssertTrue(t, on)
+
+	internal.AssertNil(t, client.DeleteFeatureProperty(ctx, env.thingID, conformanceFeature, "level"))
+	got, err := client.RetrieveFeature(ctx, env.thingID, conformanceFeature)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, (&model.Feature{}).WithProperty("on", true).Equals(got))
+}
+
+func TestConformanceErrorResponse(t *testing.T) {
+	env := setUp(t)
+	client := things.NewClient(env.client)
+	defer client.Close()
+
+	ctx, cancel := env.context()
+	defer cancel()
+
+	var value interface{}
+	err := client.RetrieveAttribute(ctx, env.thingID, conformanceAttribute+"/notExisting", &value)
+	var errorResponse *protocol.ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		t.Fatalf("expected a Ditto error response, got %v", err)
+	}
+	internal.AssertEqual(t, 404, errorResponse.Status)
+	internal.AssertEqual(t, "things:attribute.notfound", errorResponse.ErrorCode)
+}
+
+func TestConformanceLiveMessage(t *testing.T) {
+	env := setUp(t)
+	if os.Getenv(envLive) != "true" {
+		t.Skipf("live conformance tests are skipped, %s is not 'true'", envLive)
+	}
+
+	handlers := things.NewLiveHandlers(env.client).
+		OnMessage(conformanceSubject, func(msg *things.Message) (interface{}, error) {
+			request, ok := msg.Payload.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("unexpected payload")
+			}
+			return map[string]interface{}{"echo": request["text"]}, nil
+		})
+	defer handlers.Close()
+	client := things.NewClient(env.client)
+	defer client.Close()
+
+	ctx, cancel := env.context()
+	defer cancel()
+
+	text := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	msg := things.NewMessage(env.thingID).
+		Inbox(conformanceSubject).
+		WithPayload(map[string]interface{}{"text": text}).
+		WithTimeout(env.timeout)
+	var response map[string]interface{}
+	got, err := client.SendMessage(ctx, msg, &response)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 200, got.Status)
+	internal.AssertEqual(t, map[string]interface{}{"echo": text}, response)
+}
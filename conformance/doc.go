@@ -0,0 +1,26 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package conformance contains the conformance test suite of the Ditto client against a real Ditto and Hono instance,
+// e.g. the Eclipse Ditto sandbox, which catches the protocol drifts between the Ditto releases and the client.
+// The suite is skipped unless it is configured via the following environment variables:
+//
+//	DITTO_CONFORMANCE_BROKER    - the URL of the Hono MQTT adapter, e.g. 'tcp://hono.example.com:1883'
+//	DITTO_CONFORMANCE_USERNAME  - the username of the device, e.g. 'device@tenant'
+//	DITTO_CONFORMANCE_PASSWORD  - the password of the device
+//	DITTO_CONFORMANCE_THING_ID  - the ID of the existing Thing the device has the READ and WRITE permissions on
+//	DITTO_CONFORMANCE_LIVE      - if 'true', the live messages are tested as well, which requires the device
+//	                              to receive the live messages sent to its Thing
+//	DITTO_CONFORMANCE_TIMEOUT   - the timeout of each operation, e.g. '20s', the default is 10 seconds
+//
+// The suite is run via 'go test ./conformance/...'. It modifies and restores a dedicated attribute and feature
+// of the Thing, the rest of the Thing remains unchanged.
+package conformance
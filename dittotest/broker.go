@@ -0,0 +1,579 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package dittotest
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// MQTT control packet types.
+const (
+	packetConnect     = 1
+	packetConnack     = 2
+	packetPublish     = 3
+	packetPuback      = 4
+	packetPubrec      = 5
+	packetPubrel      = 6
+	packetPubcomp     = 7
+	packetSubscribe   = 8
+	packetSuback      = 9
+	packetUnsubscribe = 10
+	packetUnsuback    = 11
+	packetPingreq     = 12
+	packetPingresp    = 13
+	packetDisconnect  = 14
+)
+
+const (
+	honoCommandTopicFormat  = "command///req/%s/%s"
+	honoResponseTopicFormat = "command///res/%s/+"
+	sharePrefix             = "$share/"
+	messagesBufferSize      = 100
+)
+
+// honoEventsFilters are the topic filters of the Hono's events and telemetry topics in their short and long forms,
+// e.g. 'e' and 'event/<tenant>/<device>'.
+var honoEventsFilters = []string{"e/#", "event/#", "t/#", "telemetry/#"}
+
+// Message is an MQTT message published to the Broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Envelope decodes the Message's payload as a JSON or CBOR encoded Ditto message.
+func (msg *Message) Envelope() (*protocol.Envelope, error) {
+	env := &protocol.Envelope{}
+	if protocol.IsCBOR(msg.Payload) {
+		if err := protocol.UnmarshalCBOR(msg.Payload, env); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(msg.Payload, env); err != nil {
+		return nil, err
+	}
+	return protocol.DecompressValue(env, 0)
+}
+
+// Broker is an in-process MQTT 3.1.1 broker for integration tests, so that a Client could be tested end-to-end
+// without external infrastructure. It accepts QoS 0, 1 and 2 publishing, while the subscriptions with wildcards
+// and the shared subscriptions are granted at most QoS 1, and simulates the Hono command and response topics used by the Client.
+// The retained messages, the persistent sessions and the last will messages are not supported.
+type Broker struct {
+	listener net.Listener
+
+	lock        sync.RWMutex
+	conns       map[*brokerConn]bool
+	subscribers map[int]*brokerSubscriber
+	lastID      int
+	closed      bool
+
+	wg sync.WaitGroup
+}
+
+type brokerSubscriber struct {
+	filter   string
+	messages chan Message
+}
+
+type brokerConn struct {
+	conn      net.Conn
+	writeLock sync.Mutex
+
+	lock          sync.Mutex
+	subscriptions map[string]byte
+	lastPacketID  uint16
+}
+
+// NewBroker starts a new Broker listening on a random port of the loopback interface.
+func NewBroker() (*Broker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	broker := &Broker{
+		listener:    listener,
+		conns:       make(map[*brokerConn]bool),
+		subscribers: make(map[int]*brokerSubscriber),
+	}
+	broker.wg.Add(1)
+	go broker.serve()
+	return broker, nil
+}
+
+// URL provides the URL the Client is to be configured to connect to the Broker with, e.g. 'tcp://127.0.0.1:1883'.
+func (broker *Broker) URL() string {
+	return "tcp://" + broker.listener.Addr().String()
+}
+
+// Close stops the Broker closing all of its connections.
+func (broker *Broker) Close() error {
+	broker.lock.Lock()
+	if broker.closed {
+		broker.lock.Unlock()
+		return nil
+	}
+	broker.closed = true
+	for conn := range broker.conns {
+		conn.conn.Close()
+	}
+	for id, subscriber := range broker.subscribers {
+		close(subscriber.messages)
+		delete(broker.subscribers, id)
+	}
+	broker.lock.Unlock()
+
+	err := broker.listener.Close()
+	broker.wg.Wait()
+	return err
+}
+
+// Connect creates a Client with the provided Configuration, if any, connected to the Broker and waits
+// for the Client to be ready, failing the test if it isn't in time. The Client is disconnected on the test's cleanup.
+func (broker *Broker) Connect(t testing.TB, cfg *ditto.Configuration) ditto.Client {
+	t.Helper()
+	if cfg == nil {
+		cfg = ditto.NewConfiguration()
+	}
+	connected := make(chan struct{})
+	var connectedOnce sync.Once
+	connectHandler := cfg.ConnectHandler()
+	cfg.WithBroker(broker.URL()).WithConnectHandler(func(client ditto.Client) {
+		if connectHandler != nil {
+			connectHandler(client)
+		}
+		connectedOnce.Do(func() { close(connected) })
+	})
+
+	client := ditto.NewClient(cfg)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("error connecting to the test broker: %v", err)
+	}
+	select {
+	case <-connected:
+	case <-time.After(cfg.ConnectTimeout()):
+		t.Fatal("timed out waiting for the client to connect to the test broker")
+	}
+	t.Cleanup(client.Disconnect)
+	return client
+}
+
+// Subscribe provides the channel the Messages published to the Broker on the topics matching the provided MQTT topic
+// filter are transferred to, until the returned cancel function is called or the Broker is closed.
+// The channel is buffered, the Messages that don't fit in the buffer are dropped.
+func (broker *Broker) Subscribe(filter string) (<-chan Message, func()) {
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	messages := make(chan Message, messagesBufferSize)
+	if broker.closed {
+		close(messages)
+		return messages, func() {}
+	}
+	broker.lastID++
+	id := broker.lastID
+	broker.subscribers[id] = &brokerSubscriber{filter: filter, messages: messages}
+	return messages, func() {
+		broker.lock.Lock()
+		defer broker.lock.Unlock()
+		if subscriber, ok := broker.subscribers[id]; ok {
+			close(subscriber.messages)
+			delete(broker.subscribers, id)
+		}
+	}
+}
+
+// Events provides the channel the Ditto messages sent by the Clients, i.e. published to the Hono's events
+// and telemetry topics in their short or long forms, e.g. 'e' or 'event/<tenant>/<device>', are transferred to
+// along with the cancel function to stop the transfer.
+func (broker *Broker) Events() (<-chan Message, func()) {
+	merged := make(chan Message, messagesBufferSize)
+	cancels := make([]func(), 0, len(honoEventsFilters))
+	var wg sync.WaitGroup
+	for _, filter := range honoEventsFilters {
+		messages, cancel := broker.Subscribe(filter)
+		cancels = append(cancels, cancel)
+		wg.Add(1)
+		go func(messages <-chan Message) {
+			defer wg.Done()
+			for msg := range messages {
+				merged <- msg
+			}
+		}(messages)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+// Publish publishes the provided payload on the provided topic to all subscribed connections.
+func (broker *Broker) Publish(topic string, payload []byte) {
+	broker.dispatch(topic, payload, 1)
+}
+
+// SendCommand sends the provided Ditto command to the connected Clients as Hono does, i.e. on the command topic
+// with the provided request ID, and waits for the response replied to the request ID.
+// Returns an error if the command cannot be encoded, the response cannot be decoded or the context is done before
+// the response is received.
+func (broker *Broker) SendCommand(ctx context.Context, requestID string, command *protocol.Envelope) (*protocol.Envelope, error) {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+	responses, cancel := broker.Subscribe(fmt.Sprintf(honoResponseTopicFormat, requestID))
+	defer cancel()
+
+	name := "command"
+	if command.Topic != nil && command.Topic.Action != "" {
+		name = string(command.Topic.Action)
+	}
+	broker.Publish(fmt.Sprintf(honoCommandTopicFormat, requestID, name), payload)
+
+	select {
+	case response, ok := <-responses:
+		if !ok {
+			return nil, errors.New("broker closed")
+		}
+		return response.Envelope()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (broker *Broker) serve() {
+	defer broker.wg.Done()
+	for {
+		conn, err := broker.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &brokerConn{conn: conn, subscriptions: make(map[string]byte)}
+		broker.lock.Lock()
+		if broker.closed {
+			broker.lock.Unlock()
+			conn.Close()
+			return
+		}
+		broker.conns[c] = true
+		broker.lock.Unlock()
+
+		broker.wg.Add(1)
+		go func() {
+			defer broker.wg.Done()
+			broker.handle(c)
+			broker.lock.Lock()
+			delete(broker.conns, c)
+			broker.lock.Unlock()
+			c.conn.Close()
+		}()
+	}
+}
+
+func (broker *Broker) handle(c *brokerConn) {
+	reader := bufio.NewReader(c.conn)
+	for {
+		header, body, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		switch header >> 4 {
+		case packetConnect:
+			if err := c.write(packetConnack<<4, []byte{0, 0}); err != nil {
+				return
+			}
+		case packetPublish:
+			if err := broker.handlePublish(c, header, body); err != nil {
+				return
+			}
+		case packetPubrel:
+			if len(body) < 2 || c.write(packetPubcomp<<4, body[:2]) != nil {
+				return
+			}
+		case packetSubscribe:
+			if err := c.handleSubscribe(body); err != nil {
+				return
+			}
+		case packetUnsubscribe:
+			if err := c.handleUnsubscribe(body); err != nil {
+				return
+			}
+		case packetPingreq:
+			if err := c.write(packetPingresp<<4, nil); err != nil {
+				return
+			}
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+func (broker *Broker) handlePublish(c *brokerConn, header byte, body []byte) error {
+	qos := (header >> 1) & 0x03
+	topic, rest, err := readString(body)
+	if err != nil {
+		return err
+	}
+	var packetID []byte
+	if qos > 0 {
+		if len(rest) < 2 {
+			return io.ErrUnexpectedEOF
+		}
+		packetID, rest = rest[:2], rest[2:]
+	}
+	broker.dispatch(topic, append([]byte(nil), rest...), qos)
+
+	switch qos {
+	case 1:
+		return c.write(packetPuback<<4, packetID)
+	case 2:
+		return c.write(packetPubrec<<4, packetID)
+	}
+	return nil
+}
+
+// dispatch transfers the message to the matching subscribers and connections. The connections are written to
+// after releasing the Broker's lock, so that a stalled connection doesn't block closing the Broker or subscribing.
+func (broker *Broker) dispatch(topic string, payload []byte, qos byte) {
+	broker.lock.RLock()
+	for _, subscriber := range broker.subscribers {
+		if topicMatches(subscriber.filter, topic) {
+			select {
+			case subscriber.messages <- Message{Topic: topic, Payload: payload}:
+			default:
+			}
+		}
+	}
+	conns := make([]*brokerConn, 0, len(broker.conns))
+	for c := range broker.conns {
+		conns = append(conns, c)
+	}
+	broker.lock.RUnlock()
+
+	// each message is delivered to a single connection of each shared subscription's group
+	shared := make(map[string]bool)
+	for _, c := range conns {
+		if grantedQoS, ok := c.matches(topic, shared); ok {
+			if grantedQoS > qos {
+				grantedQoS = qos
+			}
+			// the delivery errors are handled by the connection's reading loop
+			c.publish(topic, payload, grantedQoS)
+		}
+	}
+}
+
+func (c *brokerConn) handleSubscribe(body []byte) error {
+	if len(body) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	ack := append([]byte(nil), body[:2]...)
+	rest := body[2:]
+	c.lock.Lock()
+	for len(rest) > 0 {
+		filter, next, err := readString(rest)
+		if err != nil || len(next) < 1 {
+			c.lock.Unlock()
+			return io.ErrUnexpectedEOF
+		}
+		qos := next[0] & 0x03
+		if qos > 1 {
+			qos = 1
+		}
+		c.subscriptions[filter] = qos
+		ack = append(ack, qos)
+		rest = next[1:]
+	}
+	c.lock.Unlock()
+	return c.write(packetSuback<<4, ack)
+}
+
+func (c *brokerConn) handleUnsubscribe(body []byte) error {
+	if len(body) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	rest := body[2:]
+	c.lock.Lock()
+	for len(rest) > 0 {
+		filter, next, err := readString(rest)
+		if err != nil {
+			c.lock.Unlock()
+			return err
+		}
+		delete(c.subscriptions, filter)
+		rest = next
+	}
+	c.lock.Unlock()
+	return c.write(packetUnsuback<<4, body[:2])
+}
+
+// matches provides the highest QoS of the connection's subscriptions matching the provided topic, if any.
+// The shared subscriptions already delivered to are skipped and the matching ones are added to them.
+func (c *brokerConn) matches(topic string, shared map[string]bool) (byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var (
+		qos     byte
+		matched bool
+	)
+	for filter, filterQoS := range c.subscriptions {
+		if topicMatches(filter, topic) {
+			if _, ok := sharedFilter(filter); ok {
+				if shared[filter] {
+					continue
+				}
+				shared[filter] = true
+			}
+			matched = true
+			if filterQoS > qos {
+				qos = filterQoS
+			}
+		}
+	}
+	return qos, matched
+}
+
+func (c *brokerConn) publish(topic string, payload []byte, qos byte) error {
+	body := appendString(nil, topic)
+	if qos > 0 {
+		c.lock.Lock()
+		c.lastPacketID++
+		if c.lastPacketID == 0 {
+			c.lastPacketID++
+		}
+		id := c.lastPacketID
+		c.lock.Unlock()
+		body = append(body, byte(id>>8), byte(id))
+	}
+	return c.write(packetPublish<<4|qos<<1, append(body, payload...))
+}
+
+func (c *brokerConn) write(header byte, body []byte) error {
+	packet := []byte{header}
+	packet = append(packet, encodeLength(len(body))...)
+	packet = append(packet, body...)
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func readPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func encodeLength(length int) []byte {
+	var res []byte
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		res = append(res, b)
+		if length == 0 {
+			return res
+		}
+	}
+}
+
+func readString(data []byte) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	length := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+length {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	return string(data[2 : 2+length]), data[2+length:], nil
+}
+
+func appendString(data []byte, value string) []byte {
+	data = append(data, byte(len(value)>>8), byte(len(value)))
+	return append(data, value...)
+}
+
+// sharedFilter provides the topic filter of the provided MQTT shared subscription filter,
+// i.e. '$share/<group>/<filter>', if it's such.
+func sharedFilter(filter string) (string, bool) {
+	if !strings.HasPrefix(filter, sharePrefix) {
+		return "", false
+	}
+	group := strings.TrimPrefix(filter, sharePrefix)
+	i := strings.Index(group, "/")
+	if i < 0 {
+		return "", false
+	}
+	return group[i+1:], true
+}
+
+// topicMatches checks if the provided topic matches the provided MQTT topic filter with '+' and '#' wildcards.
+// The shared subscription filters are matched by their topic filters.
+func topicMatches(filter, topic string) bool {
+	if shared, ok := sharedFilter(filter); ok {
+		filter = shared
+	}
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) || (level != "+" && level != topicLevels[i]) {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
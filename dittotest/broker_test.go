@@ -0,0 +1,265 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package dittotest
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/eclipse/ditto-clients-golang/protocol/things"
+)
+
+func newTestBroker(t *testing.T) *Broker {
+	broker, err := NewBroker()
+	internal.AssertNil(t, err)
+	t.Cleanup(func() { broker.Close() })
+	return broker
+}
+
+func TestBrokerSendCommand(t *testing.T) {
+	broker := newTestBroker(t)
+	client := broker.Connect(t, nil)
+
+	received := make(chan string, 1)
+	client.Subscribe(func(requestID string, message *protocol.Envelope) {
+		received <- requestID
+		response := things.NewResponseTo(message).Modified().Envelope()
+		internal.AssertNil(t, client.Reply(requestID, response))
+	})
+
+	command := things.NewCommand(model.NewNamespacedIDFrom("test.namespace:test-name")).
+		Twin().Attribute("on").Modify(true).Envelope(protocol.WithCorrelationID("test-correlation"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	response, err := broker.SendCommand(ctx, "test-request", command)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "test-request", <-received)
+	internal.AssertEqual(t, 204, response.Status)
+	internal.AssertEqual(t, "test-correlation", response.Headers.CorrelationID())
+}
+
+func TestBrokerEvents(t *testing.T) {
+	tests := map[string]struct {
+		cfg       *ditto.Configuration
+		wantTopic string
+	}{
+		"test_short_topic": {
+			cfg:       ditto.NewConfiguration().WithCBOREncoding(true),
+			wantTopic: "e",
+		},
+		"test_long_topic": {
+			cfg:       ditto.NewConfiguration().WithTenantID("test-tenant").WithDeviceID("test-device"),
+			wantTopic: "event/test-tenant/test-device",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			broker := newTestBroker(t)
+			client := broker.Connect(t, testCase.cfg)
+
+			events, cancel := broker.Events()
+			defer cancel()
+
+			event := things.NewEvent(model.NewNamespacedIDFrom("test.namespace:test-name")).
+				Twin().Attribute("on").Modified(true).Envelope()
+			internal.AssertNil(t, client.Send(event))
+
+			select {
+			case msg := <-events:
+				internal.AssertEqual(t, testCase.wantTopic, msg.Topic)
+				got, err := msg.Envelope()
+				internal.AssertNil(t, err)
+				AssertEnvelope(t, event, got)
+			case <-time.After(5 * time.Second):
+				t.Fatal("no event received")
+			}
+		})
+	}
+}
+
+func TestBrokerEventsTelemetry(t *testing.T) {
+	broker := newTestBroker(t)
+	events, cancel := broker.Events()
+	defer cancel()
+
+	topics := []string{"t", "telemetry/test-tenant/test-device", "other"}
+	for _, topic := range topics {
+		broker.Publish(topic, []byte(topic))
+	}
+
+	// the messages of the different topics are merged in no particular order
+	received := make(map[string]bool)
+	for len(received) < 2 {
+		select {
+		case msg := <-events:
+			internal.AssertEqual(t, msg.Topic, string(msg.Payload))
+			received[msg.Topic] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no telemetry received, got %v", received)
+		}
+	}
+	internal.AssertEqual(t, map[string]bool{"t": true, "telemetry/test-tenant/test-device": true}, received)
+	select {
+	case msg := <-events:
+		t.Fatalf("unexpected message on %s", msg.Topic)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBrokerSubscriptions(t *testing.T) {
+	broker := newTestBroker(t)
+	raw := make(chan string, 1)
+	broker.Connect(t, ditto.NewConfiguration().WithSubscriptions(&ditto.Subscription{
+		Topic: "test/+/data",
+		QoS:   1,
+		RawHandler: func(topic string, payload []byte) {
+			raw <- topic + ":" + string(payload)
+		},
+	}))
+
+	broker.Publish("test/other/level/data", []byte("ignored"))
+	broker.Publish("test/device/data", []byte("payload"))
+
+	select {
+	case got := <-raw:
+		internal.AssertEqual(t, "test/device/data:payload", got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestBrokerSharedSubscription(t *testing.T) {
+	broker := newTestBroker(t)
+	received := make(chan string, 4)
+	for _, name := range []string{"first", "second"} {
+		name := name
+		client := broker.Connect(t, ditto.NewConfiguration().WithShareGroup("consumers"))
+		client.Subscribe(func(requestID string, message *protocol.Envelope) {
+			received <- name
+			internal.AssertNil(t, client.Reply(requestID, things.NewResponseTo(message).Modified().Envelope()))
+		})
+	}
+
+	command := things.NewCommand(model.NewNamespacedIDFrom("test.namespace:test-name")).
+		Twin().Attribute("on").Modify(true).Envelope(protocol.WithCorrelationID("test-correlation"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	response, err := broker.SendCommand(ctx, "test-request", command)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 204, response.Status)
+
+	// the command is delivered to a single member of the group only
+	select {
+	case name := <-received:
+		internal.AssertTrue(t, name == "first" || name == "second")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no command received")
+	}
+	select {
+	case name := <-received:
+		t.Fatalf("the command is also received by %s", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker, err := NewBroker()
+	internal.AssertNil(t, err)
+	messages, cancel := broker.Subscribe("#")
+	defer cancel()
+
+	internal.AssertNil(t, broker.Close())
+	internal.AssertNil(t, broker.Close())
+	_, ok := <-messages
+	internal.AssertFalse(t, ok)
+
+	_, err = broker.SendCommand(context.Background(), "test-request", &protocol.Envelope{})
+	internal.AssertNotNil(t, err)
+}
+
+func TestBrokerStalledConnection(t *testing.T) {
+	broker, err := NewBroker()
+	internal.AssertNil(t, err)
+
+	conn, err := net.Dial("tcp", broker.listener.Addr().String())
+	internal.AssertNil(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	writePacket := func(header byte, body []byte) {
+		_, err := conn.Write(append(append([]byte{header}, encodeLength(len(body))...), body...))
+		internal.AssertNil(t, err)
+	}
+	writePacket(packetConnect<<4, nil)
+	header, _, err := readPacket(reader)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, byte(packetConnack<<4), header)
+	writePacket(packetSubscribe<<4|2, append(appendString([]byte{0, 1}, "#"), 0))
+	header, _, err = readPacket(reader)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, byte(packetSuback<<4), header)
+
+	// the connection is not read anymore, so that publishing to it blocks once its buffers are full
+	payload := make([]byte, 1024*1024)
+	go func() {
+		for i := 0; i < 64; i++ {
+			broker.Publish("test", payload)
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		_, cancel := broker.Subscribe("test")
+		cancel()
+		broker.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the broker is blocked by a stalled connection")
+	}
+}
+
+func TestTopicMatches(t *testing.T) {
+	tests := map[string]struct {
+		filter string
+		topic  string
+		want   bool
+	}{
+		"test_exact":                 {filter: "e", topic: "e", want: true},
+		"test_different":             {filter: "e", topic: "t"},
+		"test_single_level":          {filter: "command///res/+/+", topic: "command///res/req-1/204", want: true},
+		"test_single_level_missing":  {filter: "a/+", topic: "a"},
+		"test_multi_level":           {filter: "command///req/#", topic: "command///req/req-1/modify", want: true},
+		"test_multi_level_parent":    {filter: "a/#", topic: "a", want: true},
+		"test_longer_topic":          {filter: "a/b", topic: "a/b/c"},
+		"test_shared":                {filter: "$share/group/command///req/#", topic: "command///req/req-1/modify", want: true},
+		"test_shared_different":      {filter: "$share/group/e", topic: "t"},
+		"test_shared_without_filter": {filter: "$share/group", topic: "group"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, topicMatches(testCase.filter, testCase.topic))
+		})
+	}
+}
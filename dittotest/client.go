@@ -0,0 +1,404 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package dittotest provides utilities for testing the code built on top of the Ditto client,
+// e.g. a fake ditto.Client that doesn't require an MQTT broker and helpers comparing Envelopes semantically.
+package dittotest
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// Responder provides the response to the provided request Envelope sent via the fake Client.
+// If it returns nil, no response is delivered.
+type Responder func(request *protocol.Envelope) *protocol.Envelope
+
+// Reply is a reply sent via the fake Client's Reply method.
+type Reply struct {
+	RequestID string
+	Envelope  *protocol.Envelope
+}
+
+// Client is a fake ditto.Client for testing. It records the sent Envelopes and replies, delivers the Envelopes injected
+// via Deliver to the subscribed Handlers and responds to the sent requests with the scripted responses.
+// All of its methods are safe for concurrent use.
+type Client struct {
+	lock       sync.Mutex
+	connected  bool
+	handlers   []subscribedHandler
+	lastID     ditto.HandlerID
+	sent       []*protocol.Envelope
+	replies    []Reply
+	responses  map[string]*protocol.Envelope
+	responders []Responder
+	sendErr    error
+	connectErr error
+	received   uint64
+	dropped    uint64
+	// the number of the responses being delivered asynchronously and the channel closed once there are none
+	delivering int
+	idle       chan struct{}
+}
+
+// NewClient creates a new fake Client.
+func NewClient() *Client {
+	return &Client{
+		responses: make(map[string]*protocol.Envelope),
+	}
+}
+
+// Connect marks the Client as connected or returns the error configured via WithConnectError.
+func (client *Client) Connect() error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	if client.connectErr != nil {
+		return client.connectErr
+	}
+	client.connected = true
+	return nil
+}
+
+// Disconnect marks the Client as disconnected.
+func (client *Client) Disconnect() {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.connected = false
+}
+
+// Reply records the provided reply or returns the error configured via WithSendError.
+func (client *Client) Reply(requestID string, message *protocol.Envelope) error {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	if client.sendErr != nil {
+		return client.sendErr
+	}
+	client.replies = append(client.replies, Reply{RequestID: requestID, Envelope: message})
+	return nil
+}
+
+// ReplyTo records the provided reply with the headers of the provided request as set by the real Client,
+// or returns the error configured via WithSendError.
+func (client *Client) ReplyTo(requestID string, request *protocol.Envelope, message *protocol.Envelope) error {
+	reply := *message
+	reply.Headers = protocol.NewHeadersFrom(message.Headers, protocol.WithReplyHeadersFrom(request.Headers))
+	return client.Reply(requestID, &reply)
+}
+
+// Send records the provided Envelope or returns the error configured via WithSendError.
+// If a response is scripted for the Envelope's 'correlation-id' header, it is delivered (once) to the subscribed Handlers,
+// otherwise the response of the first Responder that provides one is delivered. The responses are delivered asynchronously,
+// as by the real Client, with the request's 'correlation-id' header if they don't have one.
+func (client *Client) Send(message *protocol.Envelope) error {
+	client.lock.Lock()
+	if client.sendErr != nil {
+		client.lock.Unlock()
+		return client.sendErr
+	}
+	client.sent = append(client.sent, message)
+
+	var correlationID string
+	if message.Headers != nil {
+		correlationID = message.Headers.CorrelationID()
+	}
+	response, ok := client.responses[correlationID]
+	if ok {
+		delete(client.responses, correlationID)
+	}
+	responders := append([]Responder(nil), client.responders...)
+	client.lock.Unlock()
+
+	for i := 0; response == nil && i < len(responders); i++ {
+		response = responders[i](message)
+	}
+	if response == nil {
+		return nil
+	}
+	if correlationID != "" && (response.Headers == nil || response.Headers.CorrelationID() == "") {
+		response = response.Clone()
+		response.Headers = protocol.NewHeadersFrom(response.Headers, protocol.WithCorrelationID(correlationID))
+	}
+	client.lock.Lock()
+	if client.delivering == 0 {
+		client.idle = make(chan struct{})
+	}
+	client.delivering++
+	client.lock.Unlock()
+
+	go func() {
+		defer client.delivered()
+		client.Deliver("", response)
+	}()
+	return nil
+}
+
+func (client *Client) delivered() {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.delivering--
+	if client.delivering == 0 {
+		close(client.idle)
+		client.idle = nil
+	}
+}
+
+// SendBatch sends the provided Envelopes one after another via Send, stopping on the first error,
+// which is returned along with the failed Envelope's index.
+func (client *Client) SendBatch(messages []*protocol.Envelope) error {
+	for i, message := range messages {
+		if err := client.Send(message); err != nil {
+			return fmt.Errorf("envelope %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SendFrozen thaws the provided FrozenEnvelope with a newly generated 'correlation-id' and the current
+// 'creation-time' headers and sends it via Send.
+func (client *Client) SendFrozen(message *protocol.FrozenEnvelope) error {
+	env, err := message.Thaw(protocol.NewCorrelationID(), time.Now())
+	if err != nil {
+		return err
+	}
+	return client.Send(env)
+}
+
+// Subscribe adds the provided Handlers to the ones the delivered Envelopes are transferred to and provides the IDs
+// of their subscriptions. As by the real Client, each provided Handler gets a subscription of its own.
+func (client *Client) Subscribe(handlers ...ditto.Handler) []ditto.HandlerID {
+	return client.subscribe(handlers, false)
+}
+
+// SubscribeOnce adds the provided Handlers to the ones the delivered Envelopes are transferred to and provides the IDs
+// of their subscriptions. As by the real Client, the already subscribed Handlers are not subscribed again
+// and the IDs of their existing subscriptions are provided.
+func (client *Client) SubscribeOnce(handlers ...ditto.Handler) []ditto.HandlerID {
+	return client.subscribe(handlers, true)
+}
+
+func (client *Client) subscribe(handlers []ditto.Handler, existing bool) []ditto.HandlerID {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	ids := make([]ditto.HandlerID, 0, len(handlers))
+handlers:
+	for _, handler := range handlers {
+		if existing {
+			for _, subscribed := range client.handlers {
+				if reflect.ValueOf(handler).Pointer() == reflect.ValueOf(subscribed.handler).Pointer() {
+					ids = append(ids, subscribed.id)
+					continue handlers
+				}
+			}
+		}
+		client.lastID++
+		client.handlers = append(client.handlers, subscribedHandler{id: client.lastID, handler: handler})
+		ids = append(ids, client.lastID)
+	}
+	return ids
+}
+
+// Unsubscribe removes the subscriptions of all Handlers with the same code as any of the provided ones
+// or all of them if called without arguments.
+func (client *Client) Unsubscribe(handlers ...ditto.Handler) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	if len(handlers) == 0 {
+		client.handlers = nil
+		return
+	}
+	client.removeHandlers(func(subscribed subscribedHandler) bool {
+		for _, handler := range handlers {
+			if reflect.ValueOf(handler).Pointer() == reflect.ValueOf(subscribed.handler).Pointer() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// UnsubscribeIDs removes the subscriptions with the provided IDs as returned by Subscribe.
+func (client *Client) UnsubscribeIDs(ids ...ditto.HandlerID) {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.removeHandlers(func(subscribed subscribedHandler) bool {
+		for _, id := range ids {
+			if id == subscribed.id {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Handlers provides the descriptions of the current subscriptions of Handlers in the order of their subscribing.
+func (client *Client) Handlers() []ditto.HandlerInfo {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	infos := make([]ditto.HandlerInfo, len(client.handlers))
+	for i, subscribed := range client.handlers {
+		infos[i] = ditto.HandlerInfo{ID: subscribed.id}
+		if fn := runtime.FuncForPC(reflect.ValueOf(subscribed.handler).Pointer()); fn != nil {
+			infos[i].Name = fn.Name()
+		}
+	}
+	return infos
+}
+
+// WaitForIdle waits until the responses being delivered asynchronously to the sent requests by the time of the call
+// are delivered. ditto.ErrIdleTimeout is returned if they are not delivered within the provided timeout.
+func (client *Client) WaitForIdle(timeout time.Duration) error {
+	client.lock.Lock()
+	idle := client.idle
+	client.lock.Unlock()
+
+	if idle == nil {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-time.After(timeout):
+		return ditto.ErrIdleTimeout
+	}
+}
+
+// Stats provides the numbers of the recorded sent Envelopes and replies and of the delivered Envelopes, the ones delivered
+// without subscribed Handlers counted as dropped. Unlike the real Client, the statistics are not reset on Connect
+// and there are neither reconnects nor reply latencies.
+func (client *Client) Stats() ditto.Stats {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return ditto.Stats{
+		Sent:     uint64(len(client.sent) + len(client.replies)),
+		Received: client.received,
+		Dropped:  client.dropped,
+	}
+}
+
+// Deliver transfers the provided Envelope with the provided request ID to all subscribed Handlers as if it is
+// received by the Client. Unlike the real Client, the Handlers are called synchronously, so that the test could
+// check their effects as soon as Deliver returns.
+func (client *Client) Deliver(requestID string, message *protocol.Envelope) {
+	client.lock.Lock()
+	handlers := client.handlers
+	client.received++
+	if len(handlers) == 0 {
+		client.dropped++
+	}
+	client.lock.Unlock()
+
+	for _, subscribed := range handlers {
+		subscribed.handler(requestID, message)
+	}
+}
+
+// RespondTo scripts the provided response to be delivered once a request with the provided 'correlation-id' header is sent.
+func (client *Client) RespondTo(correlationID string, response *protocol.Envelope) *Client {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.responses[correlationID] = response
+	return client
+}
+
+// RespondWith adds the provided Responder to provide the responses to the sent requests without a scripted response.
+func (client *Client) RespondWith(responder Responder) *Client {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.responders = append(client.responders, responder)
+	return client
+}
+
+// WithSendError configures the error to be returned by Send and Reply. Providing nil restores the successful sending.
+func (client *Client) WithSendError(err error) *Client {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.sendErr = err
+	return client
+}
+
+// WithConnectError configures the error to be returned by Connect. Providing nil restores the successful connecting.
+func (client *Client) WithConnectError(err error) *Client {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.connectErr = err
+	return client
+}
+
+// Connected provides if the Client is connected.
+func (client *Client) Connected() bool {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return client.connected
+}
+
+// Sent provides the Envelopes sent via the Client in the order of their sending.
+func (client *Client) Sent() []*protocol.Envelope {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return append([]*protocol.Envelope(nil), client.sent...)
+}
+
+// Replies provides the replies sent via the Client in the order of their sending.
+func (client *Client) Replies() []Reply {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	return append([]Reply(nil), client.replies...)
+}
+
+// Reset clears the recorded Envelopes and replies along with the scripted responses and Responders.
+func (client *Client) Reset() {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+
+	client.sent = nil
+	client.replies = nil
+	client.responses = make(map[string]*protocol.Envelope)
+	client.responders = nil
+}
+
+type subscribedHandler struct {
+	id      ditto.HandlerID
+	handler ditto.Handler
+}
+
+// removeHandlers removes the subscribed Handlers matching the provided predicate.
+// The slice is never modified in place, so that the Handlers could be delivered to from its snapshot.
+func (client *Client) removeHandlers(remove func(subscribed subscribedHandler) bool) {
+	var remaining []subscribedHandler
+	for _, subscribed := range client.handlers {
+		if !remove(subscribed) {
+			remaining = append(remaining, subscribed)
+		}
+	}
+	client.handlers = remaining
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package dittotest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/eclipse/ditto-clients-golang/protocol/things"
+)
+
+func TestClientConnect(t *testing.T) {
+	client := NewClient()
+	internal.AssertFalse(t, client.Connected())
+
+	internal.AssertNil(t, client.Connect())
+	internal.AssertTrue(t, client.Connected())
+
+	client.Disconnect()
+	internal.AssertFalse(t, client.Connected())
+
+	testErr := errors.New("test error")
+	internal.AssertError(t, testErr, client.WithConnectError(testErr).Connect())
+	internal.AssertFalse(t, client.Connected())
+}
+
+func TestClientSendAndReply(t *testing.T) {
+	client := NewClient()
+	message := (&protocol.Envelope{}).WithPath("/")
+
+	internal.AssertNil(t, client.Send(message))
+	internal.AssertNil(t, client.Reply("test-request", message))
+	internal.AssertEqual(t, []*protocol.Envelope{message}, client.Sent())
+	internal.AssertEqual(t, []Reply{{RequestID: "test-request", Envelope: message}}, client.Replies())
+
+	testErr := errors.New("test error")
+	client.WithSendError(testErr)
+	internal.AssertError(t, testErr, client.Send(message))
+	internal.AssertError(t, testErr, client.Reply("test-request", message))
+	internal.AssertEqual(t, 1, len(client.Sent()))
+
+	client.Reset()
+	internal.AssertEqual(t, 0, len(client.Sent()))
+	internal.AssertEqual(t, 0, len(client.Replies()))
+}
+
+func TestClientReplyTo(t *testing.T) {
+	client := NewClient()
+	request := &protocol.Envelope{Headers: protocol.NewHeaders(protocol.WithCorrelationID("test-id"))}
+
+	internal.AssertNil(t, client.ReplyTo("test-request", request, (&protocol.Envelope{}).WithPath("/")))
+	replies := client.Replies()
+	internal.AssertEqual(t, 1, len(replies))
+	internal.AssertEqual(t, "test-request", replies[0].RequestID)
+	internal.AssertEqual(t, "test-id", replies[0].Envelope.Headers.CorrelationID())
+	internal.AssertEqual(t, false, replies[0].Envelope.Headers.Values[protocol.HeaderResponseRequired])
+}
+
+func TestClientDeliver(t *testing.T) {
+	client := NewClient()
+	var received []string
+	handler := func(requestID string, message *protocol.Envelope) {
+		received = append(received, requestID+message.Path)
+	}
+
+	ids := client.Subscribe(handler, handler)
+	internal.AssertEqual(t, []ditto.HandlerID{1, 2}, ids)
+	internal.AssertEqual(t, []ditto.HandlerID{1, 1}, client.SubscribeOnce(handler, handler))
+	internal.AssertEqual(t, 2, len(client.Handlers()))
+
+	client.Deliver("test-request", (&protocol.Envelope{}).WithPath("/attributes"))
+	internal.AssertEqual(t, []string{"test-request/attributes", "test-request/attributes"}, received)
+
+	client.UnsubscribeIDs(ids[0])
+	internal.AssertEqual(t, 1, len(client.Handlers()))
+	internal.AssertEqual(t, ids[1], client.Handlers()[0].ID)
+	client.Deliver("test-request", (&protocol.Envelope{}).WithPath("/thing"))
+	internal.AssertEqual(t, 3, len(received))
+
+	client.Unsubscribe(handler)
+	internal.AssertEqual(t, 0, len(client.Handlers()))
+	client.Deliver("test-request", (&protocol.Envelope{}).WithPath("/features"))
+	internal.AssertEqual(t, 3, len(received))
+}
+
+func TestClientScriptedResponses(t *testing.T) {
+	client := NewClient()
+	responses := make(chan *protocol.Envelope, 2)
+	client.Subscribe(func(requestID string, message *protocol.Envelope) {
+		responses <- message
+	})
+
+	scripted := (&protocol.Envelope{}).WithPath("/scripted").WithStatus(204)
+	client.RespondTo("test-correlation", scripted)
+	client.RespondWith(func(request *protocol.Envelope) *protocol.Envelope {
+		return (&protocol.Envelope{}).WithPath(request.Path).WithStatus(200)
+	})
+
+	request := (&protocol.Envelope{}).
+		WithPath("/attributes").
+		WithHeaders(protocol.NewHeaders(protocol.WithCorrelationID("test-correlation")))
+	internal.AssertNil(t, client.Send(request))
+	response := receive(t, responses)
+	internal.AssertEqual(t, "/scripted", response.Path)
+	internal.AssertEqual(t, "test-correlation", response.Headers.CorrelationID())
+	internal.AssertNil(t, scripted.Headers)
+
+	internal.AssertNil(t, client.Send(request))
+	response = receive(t, responses)
+	internal.AssertEqual(t, "/attributes", response.Path)
+	internal.AssertEqual(t, "test-correlation", response.Headers.CorrelationID())
+}
+
+func TestClientWaitForIdle(t *testing.T) {
+	client := NewClient()
+	internal.AssertNil(t, client.WaitForIdle(time.Millisecond))
+
+	var received int32
+	client.Subscribe(func(requestID string, message *protocol.Envelope) {
+		atomic.AddInt32(&received, 1)
+	})
+	client.RespondWith(func(request *protocol.Envelope) *protocol.Envelope {
+		return (&protocol.Envelope{}).WithPath(request.Path).WithStatus(204)
+	})
+
+	internal.AssertNil(t, client.Send((&protocol.Envelope{}).WithPath("/attributes")))
+	internal.AssertNil(t, client.Send((&protocol.Envelope{}).WithPath("/features")))
+	internal.AssertNil(t, client.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(2), atomic.LoadInt32(&received))
+}
+
+func TestClientStats(t *testing.T) {
+	client := NewClient()
+	message := (&protocol.Envelope{}).WithPath("/attributes")
+
+	client.Deliver("", message)
+	client.Subscribe(func(requestID string, message *protocol.Envelope) {})
+	client.Deliver("testRequestID", message)
+	internal.AssertNil(t, client.Send(message))
+	internal.AssertNil(t, client.Reply("testRequestID", message))
+
+	internal.AssertEqual(t, ditto.Stats{Sent: 2, Received: 2, Dropped: 1}, client.Stats())
+}
+
+func TestClientWithThingsClient(t *testing.T) {
+	thing := (&model.Thing{}).WithIDFrom("test.namespace:test-name").WithAttribute("on", true)
+
+	client := NewClient().RespondWith(func(request *protocol.Envelope) *protocol.Envelope {
+		return things.NewResponseTo(request).Retrieved(thing).Envelope()
+	})
+	thingsClient := things.NewClient(client)
+	defer thingsClient.Close()
+
+	got, err := thingsClient.RetrieveThing(context.Background(), thing.ID)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, thing.Equals(got))
+	internal.AssertEqual(t, 1, len(client.Sent()))
+	internal.AssertEqual(t, protocol.ActionRetrieve, client.Sent()[0].Topic.Action)
+}
+
+func receive(t *testing.T, responses chan *protocol.Envelope) *protocol.Envelope {
+	select {
+	case response := <-responses:
+		return response
+	case <-time.After(time.Second):
+		t.Fatal("no response received")
+		return nil
+	}
+}
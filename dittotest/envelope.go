@@ -0,0 +1,240 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package dittotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/golang/mock/gomock"
+)
+
+// CompareOpt configures the comparison of Envelopes.
+type CompareOpt func(*comparison)
+
+type comparison struct {
+	ignoredHeaders map[string]bool
+}
+
+// IgnoreHeaders configures the headers with the provided case-insensitive names to be ignored on comparison.
+func IgnoreHeaders(names ...string) CompareOpt {
+	return func(c *comparison) {
+		for _, name := range names {
+			c.ignoredHeaders[protocol.CanonicalHeaderName(name)] = true
+		}
+	}
+}
+
+// IgnoreCorrelationID configures the 'correlation-id' header to be ignored on comparison, even if the expected
+// Envelope has one.
+func IgnoreCorrelationID() CompareOpt {
+	return IgnoreHeaders(protocol.HeaderCorrelationID)
+}
+
+// DiffEnvelopes compares the provided Envelopes semantically and provides the differences, one per line in the form
+// of '<JSON pointer>: expected <value>, got <value>', or nil if they are equal.
+// The Envelopes are compared as per their JSON representations, i.e. regardless of the maps' order, the numbers'
+// representation and the headers' names case. The 'correlation-id' header of the actual Envelope is ignored if
+// the expected Envelope doesn't have one, as it is usually generated.
+func DiffEnvelopes(expected, actual *protocol.Envelope, opts ...CompareOpt) []string {
+	if expected == nil || actual == nil {
+		if expected == actual {
+			return nil
+		}
+		return []string{fmt.Sprintf(": expected %s, got %s", formatEnvelope(expected), formatEnvelope(actual))}
+	}
+	c := &comparison{ignoredHeaders: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	expectedValue, err := envelopeValue(expected)
+	if err != nil {
+		return []string{fmt.Sprintf(": cannot marshal the expected envelope: %v", err)}
+	}
+	actualValue, err := envelopeValue(actual)
+	if err != nil {
+		return []string{fmt.Sprintf(": cannot marshal the actual envelope: %v", err)}
+	}
+
+	expectedHeaders := normalizeHeaders(expectedValue, c.ignoredHeaders)
+	actualHeaders := normalizeHeaders(actualValue, c.ignoredHeaders)
+	if _, ok := expectedHeaders[protocol.HeaderCorrelationID]; !ok {
+		delete(actualHeaders, protocol.HeaderCorrelationID)
+	}
+	for _, value := range []map[string]interface{}{expectedValue, actualValue} {
+		if headers, ok := value["headers"].(map[string]interface{}); !ok || len(headers) == 0 {
+			delete(value, "headers")
+		}
+	}
+
+	var diff []string
+	diffValues("", expectedValue, actualValue, &diff)
+	return diff
+}
+
+// EqualEnvelopes returns true if the provided Envelopes are semantically equal as per DiffEnvelopes.
+func EqualEnvelopes(expected, actual *protocol.Envelope, opts ...CompareOpt) bool {
+	return len(DiffEnvelopes(expected, actual, opts...)) == 0
+}
+
+// AssertEnvelope asserts that the provided Envelopes are semantically equal as per DiffEnvelopes,
+// reporting their differences otherwise.
+func AssertEnvelope(t testing.TB, expected, actual *protocol.Envelope, opts ...CompareOpt) {
+	t.Helper()
+	if diff := DiffEnvelopes(expected, actual, opts...); len(diff) > 0 {
+		t.Errorf("envelopes differ:\n%s", strings.Join(diff, "\n"))
+	}
+}
+
+type envelopeMatcher struct {
+	expected *protocol.Envelope
+	opts     []CompareOpt
+}
+
+// MatchEnvelope provides a gomock.Matcher of the Envelopes semantically equal to the provided one as per DiffEnvelopes,
+// e.g. to set the expected calls of the mock.MockClient.
+func MatchEnvelope(expected *protocol.Envelope, opts ...CompareOpt) gomock.Matcher {
+	return &envelopeMatcher{expected: expected, opts: opts}
+}
+
+// Matches returns true if the provided value is an Envelope semantically equal to the expected one.
+func (m *envelopeMatcher) Matches(x interface{}) bool {
+	actual, ok := x.(*protocol.Envelope)
+	return ok && EqualEnvelopes(m.expected, actual, m.opts...)
+}
+
+// String describes the expected Envelope.
+func (m *envelopeMatcher) String() string {
+	return "is semantically equal to " + formatEnvelope(m.expected)
+}
+
+func formatEnvelope(env *protocol.Envelope) string {
+	if env == nil {
+		return "nil envelope"
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Sprintf("%v", env)
+	}
+	return string(data)
+}
+
+func envelopeValue(env *protocol.Envelope) (map[string]interface{}, error) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value map[string]interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func normalizeHeaders(value map[string]interface{}, ignored map[string]bool) map[string]interface{} {
+	headers, ok := value["headers"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	normalized := make(map[string]interface{}, len(headers))
+	for name, header := range headers {
+		if name = protocol.CanonicalHeaderName(name); !ignored[name] {
+			normalized[name] = header
+		}
+	}
+	value["headers"] = normalized
+	return normalized
+}
+
+func diffValues(path string, expected, actual interface{}, diff *[]string) {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(expectedValue)+len(actualValue))
+		for key := range expectedValue {
+			keys = append(keys, key)
+		}
+		for key := range actualValue {
+			if _, ok := expectedValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := path + "/" + escapePointer(key)
+			expectedItem, expectedOK := expectedValue[key]
+			actualItem, actualOK := actualValue[key]
+			switch {
+			case !actualOK:
+				*diff = append(*diff, fmt.Sprintf("%s: expected %s, got none", keyPath, formatValue(expectedItem)))
+			case !expectedOK:
+				*diff = append(*diff, fmt.Sprintf("%s: expected none, got %s", keyPath, formatValue(actualItem)))
+			default:
+				diffValues(keyPath, expectedItem, actualItem, diff)
+			}
+		}
+		return
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok || len(expectedValue) != len(actualValue) {
+			break
+		}
+		for i := range expectedValue {
+			diffValues(fmt.Sprintf("%s/%d", path, i), expectedValue[i], actualValue[i], diff)
+		}
+		return
+	case json.Number:
+		if actualValue, ok := actual.(json.Number); ok && numbersEqual(expectedValue, actualValue) {
+			return
+		}
+	default:
+		if expected == actual {
+			return
+		}
+	}
+	*diff = append(*diff, fmt.Sprintf("%s: expected %s, got %s", path, formatValue(expected), formatValue(actual)))
+}
+
+func numbersEqual(n1, n2 json.Number) bool {
+	if n1 == n2 {
+		return true
+	}
+	r1, ok1 := new(big.Rat).SetString(string(n1))
+	r2, ok2 := new(big.Rat).SetString(string(n2))
+	return ok1 && ok2 && r1.Cmp(r2) == 0
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(key string) string {
+	return pointerEscaper.Replace(key)
+}
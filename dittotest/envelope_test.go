@@ -0,0 +1,121 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package dittotest
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/mock"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/golang/mock/gomock"
+)
+
+func newTestEnvelope(headers map[string]interface{}, value interface{}) *protocol.Envelope {
+	topic := (&protocol.Topic{}).
+		WithNamespace("test.namespace").
+		WithEntityName("test-name").
+		WithGroup(protocol.GroupThings).
+		WithChannel(protocol.ChannelTwin).
+		WithCriterion(protocol.CriterionCommands).
+		WithAction(protocol.ActionModify)
+	return (&protocol.Envelope{}).
+		WithTopic(topic).
+		WithHeaders(&protocol.Headers{Values: headers}).
+		WithPath("/attributes").
+		WithValue(value)
+}
+
+func TestDiffEnvelopes(t *testing.T) {
+	tests := map[string]struct {
+		expected *protocol.Envelope
+		actual   *protocol.Envelope
+		opts     []CompareOpt
+		want     []string
+	}{
+		"test_equal_semantically": {
+			expected: newTestEnvelope(map[string]interface{}{"content-type": "application/json"},
+				map[string]interface{}{"count": 1, "tags": []interface{}{"a", 1.5}}),
+			actual: newTestEnvelope(map[string]interface{}{"Content-Type": "application/json"},
+				map[string]interface{}{"tags": []interface{}{"a", 1.50}, "count": 1.0}),
+		},
+		"test_generated_correlation_id_ignored": {
+			expected: newTestEnvelope(nil, nil),
+			actual:   newTestEnvelope(map[string]interface{}{"correlation-id": "generated"}, nil),
+		},
+		"test_correlation_id_compared": {
+			expected: newTestEnvelope(map[string]interface{}{"correlation-id": "expected"}, nil),
+			actual:   newTestEnvelope(map[string]interface{}{"correlation-id": "generated"}, nil),
+			want:     []string{`/headers/correlation-id: expected "expected", got "generated"`},
+		},
+		"test_correlation_id_ignored_explicitly": {
+			expected: newTestEnvelope(map[string]interface{}{"correlation-id": "expected"}, nil),
+			actual:   newTestEnvelope(map[string]interface{}{"correlation-id": "generated"}, nil),
+			opts:     []CompareOpt{IgnoreCorrelationID()},
+		},
+		"test_ignored_headers": {
+			expected: newTestEnvelope(map[string]interface{}{"creation-time": 1}, nil),
+			actual:   newTestEnvelope(map[string]interface{}{"Creation-Time": 2}, nil),
+			opts:     []CompareOpt{IgnoreHeaders("CREATION-TIME")},
+		},
+		"test_value_differences": {
+			expected: newTestEnvelope(nil, map[string]interface{}{"a/b": 1, "missing": true, "list": []interface{}{1, 2}}),
+			actual:   newTestEnvelope(nil, map[string]interface{}{"a/b": 2, "unexpected": "x", "list": []interface{}{1, 3}}),
+			want: []string{
+				"/value/a~1b: expected 1, got 2",
+				"/value/list/1: expected 2, got 3",
+				"/value/missing: expected true, got none",
+				`/value/unexpected: expected none, got "x"`,
+			},
+		},
+		"test_different_path": {
+			expected: newTestEnvelope(nil, nil),
+			actual:   newTestEnvelope(nil, nil).WithPath("/features"),
+			want:     []string{`/path: expected "/attributes", got "/features"`},
+		},
+		"test_nil_envelopes": {},
+		"test_nil_actual": {
+			expected: (&protocol.Envelope{}).WithPath("/"),
+			want:     []string{`: expected {"topic":null,"path":"/"}, got nil envelope`},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := DiffEnvelopes(testCase.expected, testCase.actual, testCase.opts...)
+			internal.AssertEqual(t, testCase.want, got)
+			internal.AssertEqual(t, len(testCase.want) == 0, EqualEnvelopes(testCase.expected, testCase.actual, testCase.opts...))
+		})
+	}
+}
+
+func TestAssertEnvelope(t *testing.T) {
+	expected := newTestEnvelope(nil, map[string]interface{}{"count": 1})
+	AssertEnvelope(t, expected, newTestEnvelope(map[string]interface{}{"correlation-id": "generated"},
+		map[string]interface{}{"count": 1}))
+}
+
+func TestMatchEnvelope(t *testing.T) {
+	expected := newTestEnvelope(nil, map[string]interface{}{"count": 1})
+	matcher := MatchEnvelope(expected)
+
+	internal.AssertTrue(t, matcher.Matches(newTestEnvelope(map[string]interface{}{"correlation-id": "generated"},
+		map[string]interface{}{"count": 1.0})))
+	internal.AssertFalse(t, matcher.Matches(newTestEnvelope(nil, map[string]interface{}{"count": 2})))
+	internal.AssertFalse(t, matcher.Matches("not an envelope"))
+
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	client.EXPECT().Send(MatchEnvelope(expected)).Return(nil)
+	internal.AssertNil(t, client.Send(newTestEnvelope(nil, map[string]interface{}{"count": 1})))
+	ctrl.Finish()
+}
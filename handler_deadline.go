@@ -0,0 +1,47 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"context"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// ContextHandler represents a Handler that is provided with a context, which is canceled once the Handler's
+// execution deadline is reached, so that it could abort its processing.
+type ContextHandler func(ctx context.Context, requestID string, message *protocol.Envelope)
+
+// HandleWithDeadline adapts the provided ContextHandler into a Handler to be subscribed to the provided Client,
+// whose each invocation is provided with a context canceled after the provided maximum execution duration.
+// The invocations still running at their deadline are logged via the Client's StructuredLogger, along with
+// their execution time once they complete, so that wedged Handlers don't accumulate goroutines silently.
+// As all the adapted Handlers share the same code, they are to be subscribed via Subscribe rather than SubscribeOnce
+// and unsubscribed via UnsubscribeIDs rather than Unsubscribe.
+func HandleWithDeadline(client Client, timeout time.Duration, handler ContextHandler) Handler {
+	return func(requestID string, message *protocol.Envelope) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		start := time.Now()
+		overrun := time.AfterFunc(timeout, func() {
+			LoggerFor(client, requestID, message).
+				Log(LevelWarn, "handler execution deadline exceeded", Field(LogKeyDuration, timeout))
+		})
+		handler(ctx, requestID, message)
+		if !overrun.Stop() {
+			LoggerFor(client, requestID, message).
+				Log(LevelWarn, "handler completed after its execution deadline", Field(LogKeyDuration, time.Since(start)))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// lockedRecordingLogger records the messages logged concurrently.
+type lockedRecordingLogger struct {
+	lock sync.Mutex
+	msgs []string
+}
+
+func (l *lockedRecordingLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.msgs = append(l.msgs, msg)
+}
+
+func (l *lockedRecordingLogger) messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	msgs := append([]string(nil), l.msgs...)
+	sort.Strings(msgs)
+	return msgs
+}
+
+func TestHandleWithDeadline(t *testing.T) {
+	tests := map[string]struct {
+		handler  ContextHandler
+		wantMsgs []string
+	}{
+		"test_completed_within_deadline": {
+			handler: func(ctx context.Context, requestID string, message *protocol.Envelope) {
+				internal.AssertNil(t, ctx.Err())
+			},
+		},
+		"test_completed_after_deadline": {
+			handler: func(ctx context.Context, requestID string, message *protocol.Envelope) {
+				<-ctx.Done()
+				internal.AssertEqual(t, context.DeadlineExceeded, ctx.Err())
+				time.Sleep(50 * time.Millisecond)
+			},
+			wantMsgs: []string{"handler completed after its execution deadline", "handler execution deadline exceeded"},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			logger := &lockedRecordingLogger{}
+			cl := &honoClient{cfg: NewConfiguration().WithLogger(logger)}
+
+			var gotRequestID string
+			handler := HandleWithDeadline(cl, 20*time.Millisecond,
+				func(ctx context.Context, requestID string, message *protocol.Envelope) {
+					gotRequestID = requestID
+					testCase.handler(ctx, requestID, message)
+				})
+			handler("test-request", &protocol.Envelope{})
+
+			internal.AssertEqual(t, "test-request", gotRequestID)
+			internal.AssertEqual(t, testCase.wantMsgs, logger.messages())
+		})
+	}
+}
+
+func TestHandleWithDeadlineSubscribedTwice(t *testing.T) {
+	var first, second int32
+	cl := &honoClient{cfg: NewConfiguration()}
+
+	ids := cl.Subscribe(
+		HandleWithDeadline(cl, time.Second, func(ctx context.Context, requestID string, message *protocol.Envelope) {
+			atomic.AddInt32(&first, 1)
+		}),
+		HandleWithDeadline(cl, time.Second, func(ctx context.Context, requestID string, message *protocol.Envelope) {
+			atomic.AddInt32(&second, 1)
+		}))
+	internal.AssertEqual(t, []HandlerID{1, 2}, ids)
+
+	cl.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertNil(t, cl.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&first))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&second))
+
+	cl.UnsubscribeIDs(ids[1])
+	cl.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertNil(t, cl.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(2), atomic.LoadInt32(&first))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&second))
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// ErrorCodeHandlerFailed is the error code of the error replies sent for the errors returned by ErrorHandlers
+// that are not Ditto errors, i.e. *protocol.ErrorResponse.
+const ErrorCodeHandlerFailed = "handler.failed"
+
+// ErrorHandler represents a Handler that returns an error if the received message could not be handled,
+// which is then processed according to a HandlerErrorPolicy instead of by each Handler on its own.
+type ErrorHandler func(requestID string, message *protocol.Envelope) error
+
+// HandlerErrorPolicy defines how the errors returned by an ErrorHandler are processed.
+// The errors are always logged via the Client's StructuredLogger.
+type HandlerErrorPolicy struct {
+	// Retries is the number of times the ErrorHandler is called again with the same message after returning an error.
+	Retries int
+	// Reply configures if an error reply is to be sent for the failed messages that require a response.
+	// A returned *protocol.ErrorResponse is replied as it is, any other error is replied with the
+	// 500 status and the ErrorCodeHandlerFailed error code.
+	Reply bool
+}
+
+// HandleErrors adapts the provided ErrorHandler into a Handler to be subscribed to the provided Client,
+// processing the returned errors according to the provided HandlerErrorPolicy. A nil policy only logs the errors.
+// As all the adapted Handlers share the same code, they are to be subscribed via Subscribe rather than SubscribeOnce
+// and unsubscribed via UnsubscribeIDs rather than Unsubscribe.
+func HandleErrors(client Client, policy *HandlerErrorPolicy, handler ErrorHandler) Handler {
+	if policy == nil {
+		policy = &HandlerErrorPolicy{}
+	}
+	return func(requestID string, message *protocol.Envelope) {
+		attempts := 0
+		var err error
+		for attempts <= policy.Retries {
+			attempts++
+			if err = handler(requestID, message); err == nil {
+				return
+			}
+		}
+
+		logger := LoggerFor(client, requestID, message)
+		logger.Log(LevelError, "error handling Ditto message", Field(LogKeyError, err), Field("attempts", attempts))
+		if !policy.Reply || !requiresResponse(message) {
+			return
+		}
+		if replyErr := replyError(client, requestID, message, err); replyErr != nil {
+			logger.Log(LevelError, "error replying to Ditto message", Field(LogKeyError, replyErr))
+		}
+	}
+}
+
+func replyError(client Client, requestID string, request *protocol.Envelope, err error) error {
+	var reply *protocol.Envelope
+	var errorResponse *protocol.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		status := errorResponse.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		reply = protocol.NewErrorEnvelope(request, status, errorResponse.ErrorCode, errorResponse.Message)
+	} else {
+		reply = protocol.NewErrorEnvelope(request, http.StatusInternalServerError, ErrorCodeHandlerFailed, err.Error())
+	}
+	if len(requestID) > 0 {
+		return client.ReplyTo(requestID, request, reply)
+	}
+	return client.Send(reply)
+}
+
+// requiresResponse checks if a response is expected for the provided message, i.e. it is not a response itself
+// and does not have a 'response-required' header of false.
+func requiresResponse(message *protocol.Envelope) bool {
+	if message.Status != 0 || protocol.IsError(message) {
+		return false
+	}
+	if message.Headers == nil {
+		return true
+	}
+	responseRequired, ok := message.Headers.Get(protocol.HeaderResponseRequired).(bool)
+	return !ok || responseRequired
+}
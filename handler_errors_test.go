@@ -0,0 +1,201 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleErrors(t *testing.T) {
+	testErr := errors.New("test error")
+	request := &protocol.Envelope{
+		Topic: (&protocol.Topic{}).WithNamespace("test.ns").WithEntityName("thing").WithGroup(protocol.GroupThings).
+			WithChannel(protocol.ChannelLive).WithCriterion(protocol.CriterionMessages).WithAction("ping"),
+		Headers: protocol.NewHeaders(protocol.WithCorrelationID("test-correlation")),
+		Path:    "/inbox/messages/ping",
+	}
+	notRequired := *request
+	notRequired.Headers = protocol.NewHeaders(protocol.WithResponseRequired(false))
+
+	tests := map[string]struct {
+		policy     *HandlerErrorPolicy
+		requestID  string
+		message    *protocol.Envelope
+		errs       []error
+		wantCalls  int
+		wantLogs   int
+		wantTopic  string
+		wantStatus int
+		wantCode   string
+	}{
+		"test_no_error": {
+			policy:    &HandlerErrorPolicy{Retries: 2, Reply: true},
+			message:   request,
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		"test_retried_until_success": {
+			policy:    &HandlerErrorPolicy{Retries: 2, Reply: true},
+			message:   request,
+			errs:      []error{testErr, testErr, nil},
+			wantCalls: 3,
+		},
+		"test_nil_policy_only_logs": {
+			requestID: "test-request",
+			message:   request,
+			errs:      []error{testErr},
+			wantCalls: 1,
+			wantLogs:  1,
+		},
+		"test_retries_exhausted_without_reply": {
+			policy:    &HandlerErrorPolicy{Retries: 1},
+			requestID: "test-request",
+			message:   request,
+			errs:      []error{testErr},
+			wantCalls: 2,
+			wantLogs:  1,
+		},
+		"test_reply_internal_error": {
+			policy:     &HandlerErrorPolicy{Reply: true},
+			requestID:  "test-request",
+			message:    request,
+			errs:       []error{testErr},
+			wantCalls:  1,
+			wantLogs:   1,
+			wantTopic:  generateHonoResponseTopic("test-request", 500),
+			wantStatus: 500,
+			wantCode:   ErrorCodeHandlerFailed,
+		},
+		"test_reply_ditto_error": {
+			policy:    &HandlerErrorPolicy{Reply: true},
+			requestID: "test-request",
+			message:   request,
+			errs: []error{fmt.Errorf("wrapped: %w", &protocol.ErrorResponse{
+				Status: 404, ErrorCode: protocol.ErrorCodeThingNotFound, Message: "not found"})},
+			wantCalls:  1,
+			wantLogs:   1,
+			wantTopic:  generateHonoResponseTopic("test-request", 404),
+			wantStatus: 404,
+			wantCode:   protocol.ErrorCodeThingNotFound,
+		},
+		"test_send_error_without_request_id": {
+			policy:     &HandlerErrorPolicy{Reply: true},
+			message:    request,
+			errs:       []error{testErr},
+			wantCalls:  1,
+			wantLogs:   1,
+			wantTopic:  honoMQTTTopicPublishEvents,
+			wantStatus: 500,
+			wantCode:   ErrorCodeHandlerFailed,
+		},
+		"test_no_reply_if_not_required": {
+			policy:    &HandlerErrorPolicy{Reply: true},
+			requestID: "test-request",
+			message:   &notRequired,
+			errs:      []error{testErr},
+			wantCalls: 1,
+			wantLogs:  1,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			setup(mockCtrl)
+
+			logger := &recordingStructuredLogger{}
+			cl := &honoClient{
+				cfg:        NewConfiguration().WithLogger(logger),
+				pahoClient: mockMQTTClient,
+			}
+
+			var published []byte
+			if testCase.wantTopic != "" {
+				mockMQTTClient.EXPECT().Publish(testCase.wantTopic, byte(1), false, gomock.Any()).
+					DoAndReturn(func(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+						published = append([]byte(nil), payload.([]byte)...)
+						return mockToken
+					})
+				mockToken.EXPECT().WaitTimeout(gomock.Any()).Return(true)
+				mockToken.EXPECT().Error().Return(nil)
+			}
+
+			calls := 0
+			handler := HandleErrors(cl, testCase.policy, func(requestID string, message *protocol.Envelope) error {
+				internal.AssertEqual(t, testCase.requestID, requestID)
+				internal.AssertEqual(t, testCase.message, message)
+				err := testCase.errs[len(testCase.errs)-1]
+				if calls < len(testCase.errs) {
+					err = testCase.errs[calls]
+				}
+				calls++
+				return err
+			})
+			handler(testCase.requestID, testCase.message)
+
+			internal.AssertEqual(t, testCase.wantCalls, calls)
+			internal.AssertEqual(t, testCase.wantLogs, len(logger.logs))
+			if testCase.wantLogs > 0 {
+				internal.AssertEqual(t, LevelError, logger.logs[0].level)
+			}
+			if testCase.wantTopic == "" {
+				return
+			}
+			reply, err := getEnvelope(published, nil)
+			internal.AssertNil(t, err)
+			internal.AssertTrue(t, protocol.IsError(reply))
+			internal.AssertEqual(t, "test-correlation", reply.Headers.CorrelationID())
+			errorResponse, err := protocol.ParseError(reply)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.wantStatus, errorResponse.Status)
+			internal.AssertEqual(t, testCase.wantCode, errorResponse.ErrorCode)
+		})
+	}
+}
+
+func TestHandleErrorsSubscribedTwice(t *testing.T) {
+	var first, second int32
+	cl := &honoClient{cfg: NewConfiguration()}
+
+	ids := cl.Subscribe(
+		HandleErrors(cl, nil, func(requestID string, message *protocol.Envelope) error {
+			atomic.AddInt32(&first, 1)
+			return nil
+		}),
+		HandleErrors(cl, nil, func(requestID string, message *protocol.Envelope) error {
+			atomic.AddInt32(&second, 1)
+			return nil
+		}))
+	internal.AssertEqual(t, []HandlerID{1, 2}, ids)
+
+	cl.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertNil(t, cl.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&first))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&second))
+
+	cl.UnsubscribeIDs(ids[0])
+	cl.dispatch("test-request", &protocol.Envelope{})
+	internal.AssertNil(t, cl.WaitForIdle(5*time.Second))
+	internal.AssertEqual(t, int32(1), atomic.LoadInt32(&first))
+	internal.AssertEqual(t, int32(2), atomic.LoadInt32(&second))
+}
@@ -11,6 +11,13 @@
 
 package ditto
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
 type (
 	// Logger interface allows plugging of a logger implementation that
 	// fits best the needs of the application that is to use the Ditto library.
@@ -30,9 +37,230 @@ func (LoggerStub) Println(v ...interface{}) {}
 func (LoggerStub) Printf(format string, v ...interface{}) {}
 
 // Levels of the library's output that can be configured during package initialization in init().
+// To replace the Loggers at runtime, use SetLogger with NewLevelLogger instead.
 var (
 	INFO  Logger = LoggerStub{}
 	WARN  Logger = LoggerStub{}
 	DEBUG Logger = LoggerStub{}
 	ERROR Logger = LoggerStub{}
 )
+
+// LogLevel is the severity of a structured log record.
+type LogLevel int
+
+// Levels of the structured log records.
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String provides the string representation of the LogLevel, e.g. 'DEBUG'.
+func (level LogLevel) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "LEVEL(" + strconv.Itoa(int(level)) + ")"
+	}
+}
+
+// Keys of the fields attached to the library's structured log records.
+const (
+	LogKeyTopic         = "topic"
+	LogKeyMQTTTopic     = "mqtt-topic"
+	LogKeyCorrelationID = "correlation-id"
+	LogKeyRequestID     = "request-id"
+	LogKeyError         = "error"
+	LogKeyDuration      = "duration"
+)
+
+// LogField is a key-value pair attached to a structured log record, e.g. the topic or the correlation-id
+// of the processed message.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Field creates a LogField with the provided key and value.
+func Field(key string, value interface{}) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// StructuredLogger interface allows plugging of a leveled, structured logger implementation, e.g. a log/slog one via
+// NewSlogLogger, so that the library's output integrates with the application's structured logging pipeline.
+type StructuredLogger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+// levelEnabler is implemented by the StructuredLoggers that are able to tell whether they write the records
+// of a level, so that the records known to be discarded are not built at all.
+type levelEnabler interface {
+	enabled(level LogLevel) bool
+}
+
+// levelEnabled reports whether the provided StructuredLogger writes the records of the provided level.
+// The StructuredLoggers not implementing levelEnabler are assumed to write all of them.
+func levelEnabled(logger StructuredLogger, level LogLevel) bool {
+	if enabler, ok := logger.(levelEnabler); ok {
+		return enabler.enabled(level)
+	}
+	return true
+}
+
+// levelLoggers is a StructuredLogger delegating to a Logger per level. The levels without a Logger are delegated
+// to the INFO, WARN, DEBUG and ERROR Loggers.
+type levelLoggers struct {
+	debug, info, warn, error Logger
+}
+
+// NewLevelLogger creates a StructuredLogger delegating to the provided Loggers per level, each message being followed
+// by its fields as 'key=value' pairs. A nil Logger for a level falls back to the respective level Logger of the package.
+func NewLevelLogger(debug, info, warn, error Logger) StructuredLogger {
+	return levelLoggers{debug: debug, info: info, warn: warn, error: error}
+}
+
+// Log writes the message followed by the fields as 'key=value' pairs to the Logger of the provided level.
+// The record is not formatted at all if the Logger is a LoggerStub.
+func (l levelLoggers) Log(level LogLevel, msg string, fields ...LogField) {
+	logger := l.logger(level)
+	if _, ok := logger.(LoggerStub); ok {
+		return
+	}
+	if len(fields) == 0 {
+		logger.Println(msg)
+		return
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, field := range fields {
+		b.WriteByte(' ')
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		b.WriteString(fmt.Sprint(field.Value))
+	}
+	logger.Println(b.String())
+}
+
+// enabled reports whether the records of the provided level are written, i.e. their Logger is not a LoggerStub.
+func (l levelLoggers) enabled(level LogLevel) bool {
+	_, ok := l.logger(level).(LoggerStub)
+	return !ok
+}
+
+// logger provides the Logger of the provided level, falling back to the respective level Logger of the package.
+func (l levelLoggers) logger(level LogLevel) Logger {
+	var logger Logger
+	switch level {
+	case LevelDebug:
+		logger = l.debug
+		if logger == nil {
+			logger = DEBUG
+		}
+	case LevelInfo:
+		logger = l.info
+		if logger == nil {
+			logger = INFO
+		}
+	case LevelWarn:
+		logger = l.warn
+		if logger == nil {
+			logger = WARN
+		}
+	default:
+		logger = l.error
+		if logger == nil {
+			logger = ERROR
+		}
+	}
+	return logger
+}
+
+// LevelVar is a LogLevel that can be safely changed at runtime, e.g. to re-level a Client's output without reconnecting.
+// Its zero value is LevelDebug.
+type LevelVar struct {
+	level int32
+}
+
+// Level provides the current LogLevel.
+func (v *LevelVar) Level() LogLevel {
+	return LogLevel(atomic.LoadInt32(&v.level))
+}
+
+// Set changes the current LogLevel.
+func (v *LevelVar) Set(level LogLevel) {
+	atomic.StoreInt32(&v.level, int32(level))
+}
+
+type minLevelLogger struct {
+	logger   StructuredLogger
+	minLevel *LevelVar
+}
+
+// WithMinLevel creates a StructuredLogger delegating to the provided one only the records with a level not lower than
+// the current level of the provided LevelVar.
+func WithMinLevel(logger StructuredLogger, minLevel *LevelVar) StructuredLogger {
+	return &minLevelLogger{logger: logger, minLevel: minLevel}
+}
+
+// enabled reports whether the level is not lower than the current minimum level and the delegate writes its records.
+func (l *minLevelLogger) enabled(level LogLevel) bool {
+	return level >= l.minLevel.Level() && levelEnabled(l.logger, level)
+}
+
+// Log delegates the record if its level is not lower than the current minimum level.
+func (l *minLevelLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	if level >= l.minLevel.Level() {
+		l.logger.Log(level, msg, fields...)
+	}
+}
+
+// loggerHolder wraps the StructuredLogger, so that all of them are stored in the atomic.Value with the same type.
+type loggerHolder struct {
+	logger StructuredLogger
+}
+
+// defaultLogger holds the StructuredLogger the output of the Clients without their own one is written to.
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(loggerHolder{logger: levelLoggers{}})
+}
+
+// SetLogger sets the StructuredLogger the library's output is written to, replacing the default one that delegates
+// to the INFO, WARN, DEBUG and ERROR Loggers. Providing nil restores the default one.
+// It is safe to be called at runtime, it applies to all Clients that have no StructuredLogger of their own configured
+// via Configuration.WithLogger.
+func SetLogger(logger StructuredLogger) {
+	if logger == nil {
+		logger = levelLoggers{}
+	}
+	defaultLogger.Store(loggerHolder{logger: logger})
+}
+
+// DefaultLogger provides the StructuredLogger the library's output is written to.
+func DefaultLogger() StructuredLogger {
+	return defaultLogger.Load().(loggerHolder).logger
+}
+
+// structuredLoggerProvider is implemented by the Clients providing their own StructuredLogger.
+type structuredLoggerProvider interface {
+	structuredLogger() StructuredLogger
+}
+
+// LoggerOf provides the StructuredLogger the provided Client's output is written to, i.e. the one configured
+// via Configuration.WithLogger if any or the DefaultLogger otherwise.
+// It allows the packages built on top of the Client to write their output along with the Client's one.
+func LoggerOf(client Client) StructuredLogger {
+	if provider, ok := client.(structuredLoggerProvider); ok {
+		return provider.structuredLogger()
+	}
+	return DefaultLogger()
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+type scopedLogger struct {
+	logger StructuredLogger
+	fields []LogField
+}
+
+// WithFields creates a StructuredLogger delegating to the provided one with the provided fields added to each record
+// before the record's own fields.
+func WithFields(logger StructuredLogger, fields ...LogField) StructuredLogger {
+	if len(fields) == 0 {
+		return logger
+	}
+	if scoped, ok := logger.(*scopedLogger); ok {
+		return &scopedLogger{logger: scoped.logger, fields: appendFields(scoped.fields, fields)}
+	}
+	return &scopedLogger{logger: logger, fields: fields}
+}
+
+// enabled reports whether the delegate StructuredLogger writes the records of the provided level.
+func (l *scopedLogger) enabled(level LogLevel) bool {
+	return levelEnabled(l.logger, level)
+}
+
+// Log delegates the record with the scope's fields added.
+func (l *scopedLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	l.logger.Log(level, msg, appendFields(l.fields, fields)...)
+}
+
+func appendFields(fields, additional []LogField) []LogField {
+	if len(additional) == 0 {
+		return fields
+	}
+	res := make([]LogField, 0, len(fields)+len(additional))
+	res = append(res, fields...)
+	return append(res, additional...)
+}
+
+// EnvelopeFields provides the log fields identifying the provided Envelope, i.e. its topic and 'correlation-id' header,
+// if available, along with the request ID, if provided.
+func EnvelopeFields(requestID string, message *protocol.Envelope) []LogField {
+	var fields []LogField
+	if message != nil && message.Topic != nil {
+		fields = append(fields, Field(LogKeyTopic, message.Topic.String()))
+	}
+	if message != nil && message.Headers != nil {
+		if correlationID := message.Headers.CorrelationID(); correlationID != "" {
+			fields = append(fields, Field(LogKeyCorrelationID, correlationID))
+		}
+	}
+	if requestID != "" {
+		fields = append(fields, Field(LogKeyRequestID, requestID))
+	}
+	return fields
+}
+
+// LoggerFor provides the StructuredLogger of the provided Client scoped to the provided Envelope, i.e. adding its topic,
+// 'correlation-id' header and request ID to each record, so that the output of a Handler processing the Envelope
+// could be correlated with the Client's own output for it.
+func LoggerFor(client Client, requestID string, message *protocol.Envelope) StructuredLogger {
+	return WithFields(LoggerOf(client), EnvelopeFields(requestID, message)...)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func TestWithFields(t *testing.T) {
+	recorder := &recordingStructuredLogger{}
+	logger := WithFields(WithFields(recorder, Field("a", 1)), Field("b", 2))
+
+	logger.Log(LevelInfo, "message", Field("c", 3))
+	logger.Log(LevelDebug, "other")
+
+	internal.AssertEqual(t, []recordedLog{
+		{level: LevelInfo, msg: "message", fields: []LogField{Field("a", 1), Field("b", 2), Field("c", 3)}},
+		{level: LevelDebug, msg: "other", fields: []LogField{Field("a", 1), Field("b", 2)}},
+	}, recorder.logs)
+	internal.AssertEqual(t, StructuredLogger(recorder), WithFields(recorder))
+}
+
+func TestEnvelopeFields(t *testing.T) {
+	topic := (&protocol.Topic{}).
+		WithNamespace("test.namespace").
+		WithEntityName("test-name").
+		WithGroup(protocol.GroupThings).
+		WithChannel(protocol.ChannelTwin).
+		WithCriterion(protocol.CriterionCommands).
+		WithAction(protocol.ActionModify)
+
+	tests := map[string]struct {
+		requestID string
+		message   *protocol.Envelope
+		want      []LogField
+	}{
+		"test_all_fields": {
+			requestID: "test-request",
+			message: (&protocol.Envelope{}).
+				WithTopic(topic).
+				WithHeaders(protocol.NewHeaders(protocol.WithCorrelationID("test-correlation"))),
+			want: []LogField{
+				Field(LogKeyTopic, "test.namespace/test-name/things/twin/commands/modify"),
+				Field(LogKeyCorrelationID, "test-correlation"),
+				Field(LogKeyRequestID, "test-request"),
+			},
+		},
+		"test_non_string_correlation_id": {
+			message: (&protocol.Envelope{}).
+				WithTopic(topic).
+				WithHeaders(&protocol.Headers{Values: map[string]interface{}{protocol.HeaderCorrelationID: 42.0}}),
+			want: []LogField{Field(LogKeyTopic, "test.namespace/test-name/things/twin/commands/modify")},
+		},
+		"test_without_headers": {
+			message: (&protocol.Envelope{}).WithTopic(topic),
+			want:    []LogField{Field(LogKeyTopic, "test.namespace/test-name/things/twin/commands/modify")},
+		},
+		"test_nil_envelope": {
+			requestID: "test-request",
+			want:      []LogField{Field(LogKeyRequestID, "test-request")},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, EnvelopeFields(testCase.requestID, testCase.message))
+		})
+	}
+}
+
+func TestLoggerFor(t *testing.T) {
+	recorder := &recordingStructuredLogger{}
+	client := NewClient(NewConfiguration().WithLogger(recorder))
+	message := (&protocol.Envelope{}).WithHeaders(protocol.NewHeaders(protocol.WithCorrelationID("test-correlation")))
+
+	LoggerFor(client, "test-request", message).Log(LevelInfo, "handled")
+
+	internal.AssertEqual(t, []recordedLog{{level: LevelInfo, msg: "handled", fields: []LogField{
+		Field(LogKeyCorrelationID, "test-correlation"),
+		Field(LogKeyRequestID, "test-request"),
+	}}}, recorder.logs)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"fmt"
+	"strings"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+// LogKeySource is the key of the field identifying the library the bridged records originate from, e.g. 'paho'.
+const LogKeySource = "source"
+
+// pahoLogger bridges a level of the Paho MQTT library's logging to the DefaultLogger.
+type pahoLogger struct {
+	level LogLevel
+}
+
+// Println writes the Paho's record to the DefaultLogger.
+func (l pahoLogger) Println(v ...interface{}) {
+	if logger, ok := l.enabledLogger(); ok {
+		logger.Log(l.level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"), Field(LogKeySource, "paho"))
+	}
+}
+
+// Printf writes the Paho's formatted record to the DefaultLogger.
+func (l pahoLogger) Printf(format string, v ...interface{}) {
+	if logger, ok := l.enabledLogger(); ok {
+		logger.Log(l.level, strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"), Field(LogKeySource, "paho"))
+	}
+}
+
+// enabledLogger provides the DefaultLogger, unless it's known to discard the records of the pahoLogger's level,
+// so that they are not formatted in vain.
+func (l pahoLogger) enabledLogger() (StructuredLogger, bool) {
+	logger := DefaultLogger()
+	if !levelEnabled(logger, l.level) {
+		return nil, false
+	}
+	return logger, true
+}
+
+// BridgePahoLogging wires the Paho MQTT library's ERROR, CRITICAL, WARN and DEBUG loggers, which discard the records
+// by default, to the DefaultLogger with the respective levels, so that the connection diagnostics appear in the same
+// log stream as the library's own output. The CRITICAL records are logged with the LevelError.
+// The Paho's loggers that are already configured by the application are kept. As the Paho's loggers are global,
+// the records are written to the DefaultLogger even for the Clients that have their own StructuredLogger.
+// The bridging is opt-in: as the Paho's loggers are read without synchronization, it must be called by the application
+// only once, before creating any Client or Paho MQTT client.
+func BridgePahoLogging() {
+	if _, ok := MQTT.ERROR.(MQTT.NOOPLogger); ok {
+		MQTT.ERROR = pahoLogger{level: LevelError}
+	}
+	if _, ok := MQTT.CRITICAL.(MQTT.NOOPLogger); ok {
+		MQTT.CRITICAL = pahoLogger{level: LevelError}
+	}
+	if _, ok := MQTT.WARN.(MQTT.NOOPLogger); ok {
+		MQTT.WARN = pahoLogger{level: LevelWarn}
+	}
+	if _, ok := MQTT.DEBUG.(MQTT.NOOPLogger); ok {
+		MQTT.DEBUG = pahoLogger{level: LevelDebug}
+	}
+}
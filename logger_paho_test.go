@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+)
+
+func TestPahoLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	tests := map[string]struct {
+		log  func(logger MQTT.Logger)
+		want string
+	}{
+		"test_println": {
+			log:  func(logger MQTT.Logger) { logger.Println("[client]  ", "connecting") },
+			want: "[client]   connecting",
+		},
+		"test_printf": {
+			log:  func(logger MQTT.Logger) { logger.Printf("[net]      %s: %d\n", "received", 3) },
+			want: "[net]      received: 3",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			recorder := &recordingStructuredLogger{}
+			SetLogger(recorder)
+
+			testCase.log(pahoLogger{level: LevelWarn})
+			internal.AssertEqual(t, []recordedLog{{
+				level:  LevelWarn,
+				msg:    testCase.want,
+				fields: []LogField{Field(LogKeySource, "paho")},
+			}}, recorder.logs)
+		})
+	}
+}
+
+func TestPahoLoggerDisabledLevel(t *testing.T) {
+	defer SetLogger(nil)
+
+	value := &countingStringer{}
+	SetLogger(NewLevelLogger(LoggerStub{}, nil, nil, nil))
+	pahoLogger{level: LevelDebug}.Println(value)
+	pahoLogger{level: LevelDebug}.Printf("%s", value)
+	internal.AssertEqual(t, 0, value.count)
+}
+
+func TestBridgePahoLogging(t *testing.T) {
+	errorLogger, criticalLogger, warnLogger, debugLogger := MQTT.ERROR, MQTT.CRITICAL, MQTT.WARN, MQTT.DEBUG
+	defer func() {
+		MQTT.ERROR, MQTT.CRITICAL, MQTT.WARN, MQTT.DEBUG = errorLogger, criticalLogger, warnLogger, debugLogger
+	}()
+
+	custom := &recordingLogger{}
+	MQTT.ERROR, MQTT.CRITICAL, MQTT.WARN, MQTT.DEBUG = MQTT.NOOPLogger{}, MQTT.NOOPLogger{}, custom, MQTT.NOOPLogger{}
+	BridgePahoLogging()
+
+	internal.AssertEqual(t, pahoLogger{level: LevelError}, MQTT.ERROR)
+	internal.AssertEqual(t, pahoLogger{level: LevelError}, MQTT.CRITICAL)
+	internal.AssertEqual(t, custom, MQTT.WARN)
+	internal.AssertEqual(t, pahoLogger{level: LevelDebug}, MQTT.DEBUG)
+}
+
+func TestNewClientNotBridgingPahoLogging(t *testing.T) {
+	debugLogger := MQTT.DEBUG
+	defer func() { MQTT.DEBUG = debugLogger }()
+
+	MQTT.DEBUG = MQTT.NOOPLogger{}
+	NewClient(NewConfiguration())
+	internal.AssertEqual(t, MQTT.NOOPLogger{}, MQTT.DEBUG)
+}
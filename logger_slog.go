@@ -0,0 +1,65 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+//go:build go1.21
+// +build go1.21
+
+package ditto
+
+import (
+	"context"
+	"log/slog"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a StructuredLogger writing the library's output to the provided slog.Logger,
+// each LogField being added as an attribute of the record. If the provided slog.Logger is nil, slog.Default() is used.
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// enabled reports whether the slog.Logger handles the records of the slog level corresponding to the provided LogLevel.
+func (l *slogLogger) enabled(level LogLevel) bool {
+	return l.logger.Enabled(context.Background(), toSlogLevel(level))
+}
+
+// Log writes the message and the fields with the slog level corresponding to the provided LogLevel.
+func (l *slogLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	ctx := context.Background()
+	slogLevel := toSlogLevel(level)
+	if !l.logger.Enabled(ctx, slogLevel) {
+		return
+	}
+	attrs := make([]slog.Attr, len(fields))
+	for i, field := range fields {
+		attrs[i] = slog.Any(field.Key, field.Value)
+	}
+	l.logger.LogAttrs(ctx, slogLevel, msg, attrs...)
+}
+
+func toSlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
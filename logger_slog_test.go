@@ -0,0 +1,51 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+//go:build go1.21
+// +build go1.21
+
+package ditto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.Log(LevelDebug, "filtered")
+	internal.AssertEqual(t, 0, buf.Len())
+
+	logger.Log(LevelWarn, "test message", Field(LogKeyTopic, "ns/name/things/twin/commands/modify"),
+		Field(LogKeyError, errors.New("test error")))
+
+	var record map[string]interface{}
+	internal.AssertNil(t, json.Unmarshal(buf.Bytes(), &record))
+	internal.AssertEqual(t, "WARN", record[slog.LevelKey])
+	internal.AssertEqual(t, "test message", record[slog.MessageKey])
+	internal.AssertEqual(t, "ns/name/things/twin/commands/modify", record[LogKeyTopic])
+	internal.AssertEqual(t, "test error", record[LogKeyError])
+}
+
+func TestSlogLoggerLevels(t *testing.T) {
+	internal.AssertEqual(t, slog.LevelDebug, toSlogLevel(LevelDebug))
+	internal.AssertEqual(t, slog.LevelInfo, toSlogLevel(LevelInfo))
+	internal.AssertEqual(t, slog.LevelWarn, toSlogLevel(LevelWarn))
+	internal.AssertEqual(t, slog.LevelError, toSlogLevel(LevelError))
+	internal.AssertNotNil(t, NewSlogLogger(nil))
+}
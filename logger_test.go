@@ -0,0 +1,244 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Println(v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(v...))
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+type recordedLog struct {
+	level  LogLevel
+	msg    string
+	fields []LogField
+}
+
+type recordingStructuredLogger struct {
+	logs []recordedLog
+}
+
+func (l *recordingStructuredLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	l.logs = append(l.logs, recordedLog{level: level, msg: msg, fields: fields})
+}
+
+func TestLevelLoggers(t *testing.T) {
+	tests := map[string]struct {
+		level  LogLevel
+		fields []LogField
+		want   string
+	}{
+		"test_debug_without_fields": {
+			level: LevelDebug,
+			want:  "message",
+		},
+		"test_info_with_field": {
+			level:  LevelInfo,
+			fields: []LogField{Field(LogKeyTopic, "ns/name/things/twin/commands/modify")},
+			want:   "message topic=ns/name/things/twin/commands/modify",
+		},
+		"test_warn_with_fields": {
+			level:  LevelWarn,
+			fields: []LogField{Field(LogKeyCorrelationID, "test-id"), Field("count", 2)},
+			want:   "message correlation-id=test-id count=2",
+		},
+		"test_error_with_error": {
+			level:  LevelError,
+			fields: []LogField{Field(LogKeyError, errors.New("test error"))},
+			want:   "message error=test error",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loggers := map[LogLevel]*recordingLogger{
+				LevelDebug: {}, LevelInfo: {}, LevelWarn: {}, LevelError: {},
+			}
+			logger := NewLevelLogger(loggers[LevelDebug], loggers[LevelInfo], loggers[LevelWarn], loggers[LevelError])
+
+			logger.Log(testCase.level, "message", testCase.fields...)
+			for level, recorder := range loggers {
+				if level == testCase.level {
+					internal.AssertEqual(t, []string{testCase.want}, recorder.lines)
+				} else {
+					internal.AssertEqual(t, 0, len(recorder.lines))
+				}
+			}
+		})
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+
+	structuredLogger := &recordingStructuredLogger{}
+	SetLogger(structuredLogger)
+	internal.AssertEqual(t, structuredLogger, DefaultLogger())
+
+	SetLogger(nil)
+	internal.AssertEqual(t, levelLoggers{}, DefaultLogger())
+}
+
+func TestSetLoggerConcurrently(t *testing.T) {
+	defer SetLogger(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetLogger(&recordingStructuredLogger{})
+		}()
+		go func() {
+			defer wg.Done()
+			internal.AssertNotNil(t, DefaultLogger())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLogLevelString(t *testing.T) {
+	internal.AssertEqual(t, "DEBUG", LevelDebug.String())
+	internal.AssertEqual(t, "INFO", LevelInfo.String())
+	internal.AssertEqual(t, "WARN", LevelWarn.String())
+	internal.AssertEqual(t, "ERROR", LevelError.String())
+	internal.AssertEqual(t, "LEVEL(7)", LogLevel(7).String())
+}
+
+func TestNewLevelLoggerFallback(t *testing.T) {
+	info := &recordingLogger{}
+	logger := NewLevelLogger(nil, info, nil, nil)
+
+	logger.Log(LevelInfo, "info message", Field(LogKeyRequestID, "test-request"))
+	// delegated to the package's DEBUG Logger
+	logger.Log(LevelDebug, "debug message")
+
+	internal.AssertEqual(t, []string{"info message request-id=test-request"}, info.lines)
+}
+
+// countingStringer counts how many times it's formatted.
+type countingStringer struct {
+	count int
+}
+
+func (s *countingStringer) String() string {
+	s.count++
+	return "value"
+}
+
+func TestLevelLoggersStubNotFormatting(t *testing.T) {
+	value := &countingStringer{}
+	logger := NewLevelLogger(LoggerStub{}, &recordingLogger{}, nil, nil)
+
+	logger.Log(LevelDebug, "debug message", Field("value", value))
+	// delegated to the package's WARN Logger, which is a LoggerStub by default
+	logger.Log(LevelWarn, "warn message", Field("value", value))
+	internal.AssertEqual(t, 0, value.count)
+
+	logger.Log(LevelInfo, "info message", Field("value", value))
+	internal.AssertEqual(t, 1, value.count)
+}
+
+func TestWithMinLevel(t *testing.T) {
+	recorder := &recordingStructuredLogger{}
+	minLevel := &LevelVar{}
+	logger := WithMinLevel(recorder, minLevel)
+
+	logger.Log(LevelDebug, "debug")
+	minLevel.Set(LevelWarn)
+	logger.Log(LevelInfo, "info")
+	logger.Log(LevelError, "error")
+
+	internal.AssertEqual(t, LevelWarn, minLevel.Level())
+	internal.AssertEqual(t, []recordedLog{
+		{level: LevelDebug, msg: "debug"},
+		{level: LevelError, msg: "error"},
+	}, recorder.logs)
+}
+
+func TestLevelEnabled(t *testing.T) {
+	minLevel := &LevelVar{}
+	minLevel.Set(LevelInfo)
+
+	tests := map[string]struct {
+		logger StructuredLogger
+		level  LogLevel
+		want   bool
+	}{
+		"test_unknown_logger": {
+			logger: &recordingStructuredLogger{},
+			level:  LevelDebug,
+			want:   true,
+		},
+		"test_level_loggers_stub": {
+			logger: NewLevelLogger(LoggerStub{}, nil, nil, nil),
+			level:  LevelDebug,
+		},
+		"test_level_loggers_enabled": {
+			logger: NewLevelLogger(&recordingLogger{}, nil, nil, nil),
+			level:  LevelDebug,
+			want:   true,
+		},
+		"test_scoped_stub": {
+			logger: WithFields(NewLevelLogger(LoggerStub{}, nil, nil, nil), Field("a", 1)),
+			level:  LevelDebug,
+		},
+		"test_min_level_below": {
+			logger: WithMinLevel(&recordingStructuredLogger{}, minLevel),
+			level:  LevelDebug,
+		},
+		"test_min_level_above": {
+			logger: WithMinLevel(&recordingStructuredLogger{}, minLevel),
+			level:  LevelWarn,
+			want:   true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, levelEnabled(testCase.logger, testCase.level))
+		})
+	}
+}
+
+func TestLoggerOf(t *testing.T) {
+	defer SetLogger(nil)
+	defaultRecorder := &recordingStructuredLogger{}
+	SetLogger(defaultRecorder)
+
+	clientRecorder := &recordingStructuredLogger{}
+	withLogger := NewClient(NewConfiguration().WithLogger(clientRecorder))
+	withoutLogger := NewClient(NewConfiguration())
+
+	internal.AssertEqual(t, clientRecorder, LoggerOf(withLogger))
+	internal.AssertEqual(t, defaultRecorder, LoggerOf(withoutLogger))
+
+	withLogger.(*honoClient).log(LevelWarn, "client message")
+	withoutLogger.(*honoClient).log(LevelWarn, "default message")
+	internal.AssertEqual(t, []recordedLog{{level: LevelWarn, msg: "client message"}}, clientRecorder.logs)
+	internal.AssertEqual(t, []recordedLog{{level: LevelWarn, msg: "default message"}}, defaultRecorder.logs)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+// Clone creates a deep copy of the Thing, i.e. its IDs, attributes, features and inline Policy
+// are copied as well, so that changing the copy doesn't affect the original Thing and vice versa.
+// Only the JSON-like attribute values (maps, slices and primitives) are copied deeply, any other values are shared.
+func (thing *Thing) Clone() *Thing {
+	if thing == nil {
+		return nil
+	}
+	res := *thing
+	res.ID = thing.ID.Clone()
+	res.PolicyID = thing.PolicyID.Clone()
+	res.DefinitionID = thing.DefinitionID.Clone()
+	res.Attributes = cloneValues(thing.Attributes)
+	if thing.Features != nil {
+		res.Features = make(map[string]*Feature, len(thing.Features))
+		for id, feature := range thing.Features {
+			res.Features[id] = feature.Clone()
+		}
+	}
+	res.Policy = thing.Policy.Clone()
+	return &res
+}
+
+// Clone creates a deep copy of the Feature, i.e. its definition, properties and desired properties are copied as well.
+// Only the JSON-like property values (maps, slices and primitives) are copied deeply, any other values are shared.
+func (feature *Feature) Clone() *Feature {
+	if feature == nil {
+		return nil
+	}
+	res := &Feature{
+		Properties:        cloneValues(feature.Properties),
+		DesiredProperties: cloneValues(feature.DesiredProperties),
+	}
+	if feature.Definition != nil {
+		res.Definition = make([]*DefinitionID, len(feature.Definition))
+		for i, definitionID := range feature.Definition {
+			res.Definition[i] = definitionID.Clone()
+		}
+	}
+	return res
+}
+
+// Clone creates a copy of the NamespacedID.
+func (nsID *NamespacedID) Clone() *NamespacedID {
+	if nsID == nil {
+		return nil
+	}
+	res := *nsID
+	return &res
+}
+
+// Clone creates a copy of the DefinitionID.
+func (definitionID *DefinitionID) Clone() *DefinitionID {
+	if definitionID == nil {
+		return nil
+	}
+	res := *definitionID
+	return &res
+}
+
+// Clone creates a deep copy of the Policy, i.e. its entries with their subjects and resources are copied as well.
+func (policy *Policy) Clone() *Policy {
+	if policy == nil {
+		return nil
+	}
+	res := &Policy{ID: policy.ID.Clone()}
+	if policy.Entries != nil {
+		res.Entries = make(map[string]*PolicyEntry, len(policy.Entries))
+		for label, entry := range policy.Entries {
+			res.Entries[label] = entry.clone()
+		}
+	}
+	return res
+}
+
+func (entry *PolicyEntry) clone() *PolicyEntry {
+	if entry == nil {
+		return nil
+	}
+	res := &PolicyEntry{}
+	if entry.Subjects != nil {
+		res.Subjects = make(map[string]*PolicySubject, len(entry.Subjects))
+		for id, subject := range entry.Subjects {
+			if subject != nil {
+				subjectCopy := *subject
+				subject = &subjectCopy
+			}
+			res.Subjects[id] = subject
+		}
+	}
+	if entry.Resources != nil {
+		res.Resources = make(map[string]*PolicyResource, len(entry.Resources))
+		for path, resource := range entry.Resources {
+			if resource != nil {
+				resource = &PolicyResource{
+					Grant:  clonePermissions(resource.Grant),
+					Revoke: clonePermissions(resource.Revoke),
+				}
+			}
+			res.Resources[path] = resource
+		}
+	}
+	return res
+}
+
+func clonePermissions(permissions []Permission) []Permission {
+	if permissions == nil {
+		return nil
+	}
+	res := make([]Permission, len(permissions))
+	copy(res, permissions)
+	return res
+}
+
+func cloneValues(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	res := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		res[key] = cloneValue(value)
+	}
+	return res
+}
+
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return cloneValues(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = cloneValue(item)
+		}
+		return res
+	default:
+		return value
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestThingClone(t *testing.T) {
+	original := newTestThing()
+	got := original.Clone()
+	internal.AssertEqual(t, original, got)
+
+	got.ID.Name = "other"
+	got.PolicyID.Name = "other"
+	got.DefinitionID.Version = "2.0.0"
+	got.Attributes["location"].(map[string]interface{})["lat"] = 2.0
+	got.Attributes["location"].(map[string]interface{})["tags"].([]interface{})[1].(map[string]interface{})["b"] = 3
+	got.Features["lamp"].Definition[0].Version = "2.0.0"
+	got.Features["lamp"].Properties["status"].(map[string]interface{})["on"] = false
+	got.Features["lamp"].DesiredProperties["status"].(map[string]interface{})["on"] = true
+	got.Features["other"] = &Feature{}
+	got.Policy.Entries["owner"].Subjects["nginx:ditto"].Type = "other"
+	got.Policy.Entries["owner"].Resources["thing:/"].Grant[0] = PermissionWrite
+
+	internal.AssertEqual(t, newTestThing(), original)
+}
+
+func TestThingCloneNil(t *testing.T) {
+	var thing *Thing
+	internal.AssertNil(t, thing.Clone())
+
+	internal.AssertEqual(t, &Thing{}, (&Thing{}).Clone())
+}
+
+func TestFeatureClone(t *testing.T) {
+	tests := map[string]struct {
+		feature *Feature
+	}{
+		"test_empty_feature": {
+			feature: &Feature{},
+		},
+		"test_feature_with_data": {
+			feature: (&Feature{}).
+				WithDefinitionFrom("test.namespace:lamp:1.0.0").
+				WithProperty("values", []interface{}{1, []interface{}{2}}),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.feature, testCase.feature.Clone())
+		})
+	}
+}
+
+func TestPolicyClone(t *testing.T) {
+	original := NewPolicy().WithIDFrom("test.namespace:test-policy").
+		WithEntry("owner", Subject("nginx:ditto", "generated"), Grant("thing:/", PermissionRead))
+	got := original.Clone()
+	internal.AssertEqual(t, original, got)
+
+	got.ID.Name = "other"
+	got.WithEntry("owner", Revoke("thing:/", PermissionWrite))
+	internal.AssertEqual(t, "test-policy", original.ID.Name)
+	internal.AssertEqual(t, []Permission{}, original.Entries["owner"].Resources["thing:/"].Revoke)
+}
@@ -15,7 +15,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"net/url"
+	"strings"
 )
 
 // DefinitionID represents an ID of a given definition entity.
@@ -23,39 +24,57 @@ import (
 // in the form of 'namespace:name:version'.
 // The DefinitionID is used to declare a Thing's model also it is used
 // in declare the different models a Feature represents via its properties.
+// Alternatively, a DefinitionID can be an HTTP(S) URL, e.g. a link to a WoT Thing Model, in which case
+// only its URL is set.
 type DefinitionID struct {
 	Namespace string
 	Name      string
 	Version   string
+	URL       string
 }
 
-const (
-	definitionIDTemplate     = "%s:%s:%s"
-	definitionElementPattern = "([_a-zA-Z0-9\\-.]+)"
-)
-
-var regexDefinitionID = regexp.MustCompile("^" + fmt.Sprintf(definitionIDTemplate, definitionElementPattern, definitionElementPattern, definitionElementPattern) + "$")
+const definitionIDTemplate = "%s:%s:%s"
 
-// NewDefinitionIDFrom creates a new DefinitionID instance from a provided string in the form of 'namespace:name:version'.
-// Returns nil if the provided string doesn't match the form.
+// NewDefinitionIDFrom creates a new DefinitionID instance from a provided string in the form of 'namespace:name:version'
+// or an HTTP(S) URL.
+// Returns nil if the provided string doesn't match any of the forms.
 func NewDefinitionIDFrom(full string) *DefinitionID {
-	if matches, err := isValidDefinitionID(full); err == nil {
-		return &DefinitionID{Namespace: matches[1], Name: matches[2], Version: matches[3]}
+	definitionID, err := parseDefinitionID(full)
+	if err != nil {
+		return nil
 	}
-	return nil
+	return definitionID
+}
+
+// NewDefinitionIDFromURL creates a new DefinitionID instance from the provided HTTP(S) URL, e.g. a link to a WoT Thing Model.
+// Returns nil if the provided string is not an absolute HTTP(S) URL.
+func NewDefinitionIDFromURL(definitionURL string) *DefinitionID {
+	if !isValidDefinitionURL(definitionURL) {
+		return nil
+	}
+	return &DefinitionID{URL: definitionURL}
 }
 
 // NewDefinitionID creates a new DefinitionID instance with the namespace, name and version provided.
 // Returns nil if the provided string doesn't match the form.
 func NewDefinitionID(namespace string, name string, version string) *DefinitionID {
-	if _, err := isValidDefinitionID(fmt.Sprintf(definitionIDTemplate, namespace, name, version)); err == nil {
+	if _, err := splitDefinitionID(fmt.Sprintf(definitionIDTemplate, namespace, name, version)); err == nil {
 		return &DefinitionID{Namespace: namespace, Name: name, Version: version}
 	}
 	return nil
 }
 
-// String provides the string representation of a DefinitionID in the Ditto's specified form of 'namespace:name:version'.
+// IsURL returns true if the DefinitionID is in the URL form.
+func (definitionID *DefinitionID) IsURL() bool {
+	return len(definitionID.URL) > 0
+}
+
+// String provides the string representation of a DefinitionID in the Ditto's specified form of 'namespace:name:version'
+// or its URL if it is in the URL form.
 func (definitionID *DefinitionID) String() string {
+	if definitionID.IsURL() {
+		return definitionID.URL
+	}
 	return fmt.Sprintf(definitionIDTemplate, definitionID.Namespace, definitionID.Name, definitionID.Version)
 }
 
@@ -72,14 +91,12 @@ func (definitionID *DefinitionID) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	matches, err := isValidDefinitionID(defIDString)
+	parsed, err := parseDefinitionID(defIDString)
 	if err != nil {
 		return err
 	}
 
-	definitionID.Namespace = matches[1]
-	definitionID.Name = matches[2]
-	definitionID.Version = matches[3]
+	*definitionID = *parsed
 	return nil
 }
 
@@ -101,9 +118,41 @@ func (definitionID *DefinitionID) WithVersion(version string) *DefinitionID {
 	return definitionID
 }
 
-func isValidDefinitionID(defIDString string) ([]string, error) {
-	if matches := regexDefinitionID.FindStringSubmatch(defIDString); len(matches) == 4 {
-		return matches, nil
+func parseDefinitionID(defIDString string) (*DefinitionID, error) {
+	if isValidDefinitionURL(defIDString) {
+		return &DefinitionID{URL: defIDString}, nil
+	}
+	elements, err := splitDefinitionID(defIDString)
+	if err != nil {
+		return nil, err
+	}
+	return &DefinitionID{Namespace: elements[0], Name: elements[1], Version: elements[2]}, nil
+}
+
+func isValidDefinitionURL(defIDString string) bool {
+	parsed, err := url.Parse(defIDString)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && len(parsed.Host) > 0
+}
+
+// splitDefinitionID splits the provided string into its valid namespace, name and version without a regular expression.
+func splitDefinitionID(defIDString string) ([]string, error) {
+	if elements := strings.Split(defIDString, ":"); len(elements) == 3 {
+		if isValidDefinitionElement(elements[0]) && isValidDefinitionElement(elements[1]) && isValidDefinitionElement(elements[2]) {
+			return elements, nil
+		}
 	}
 	return nil, errors.New("invalid DefinitionID: " + defIDString)
 }
+
+// isValidDefinitionElement checks that the element is not empty and consists of letters, digits, '_', '-' and '.' only.
+func isValidDefinitionElement(element string) bool {
+	if len(element) == 0 {
+		return false
+	}
+	for i := 0; i < len(element); i++ {
+		if c := element[i]; !isLetter(c) && !isDigit(c) && c != '_' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -32,6 +32,20 @@ func TestDefinitionIDNewDefinitionIDFrom(t *testing.T) {
 				Version:   "1.0.0-qualifier",
 			},
 		},
+		"test_new_definition_id_from_url": {
+			arg: "https://models.example.com/thermostat-1.0.0.tm.jsonld",
+			want: &DefinitionID{
+				URL: "https://models.example.com/thermostat-1.0.0.tm.jsonld",
+			},
+		},
+		"test_new_definition_id_from_url_without_host": {
+			arg:  "https:///thermostat.tm.jsonld",
+			want: nil,
+		},
+		"test_new_definition_id_from_non_http_url": {
+			arg:  "ftp://models.example.com/thermostat.tm.jsonld",
+			want: nil,
+		},
 		"test_new_definition_id_from_without_namespace": {
 			arg:  ":test-name:1.0.0",
 			want: nil,
@@ -155,6 +169,41 @@ func TestDefinitionIDMarshalJSON(t *testing.T) {
 	internal.AssertEqual(t, want, got)
 }
 
+func TestDefinitionIDURL(t *testing.T) {
+	tests := map[string]struct {
+		arg  string
+		want *DefinitionID
+	}{
+		"test_valid_https_url": {
+			arg:  "https://models.example.com/lamp-1.0.0.tm.jsonld",
+			want: &DefinitionID{URL: "https://models.example.com/lamp-1.0.0.tm.jsonld"},
+		},
+		"test_namespaced_form": {
+			arg:  "test.namespace:test-name:1.0.0",
+			want: nil,
+		},
+		"test_relative_url": {
+			arg:  "/lamp-1.0.0.tm.jsonld",
+			want: nil,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewDefinitionIDFromURL(testCase.arg)
+			internal.AssertEqual(t, testCase.want, got)
+			if got != nil {
+				internal.AssertTrue(t, got.IsURL())
+				internal.AssertEqual(t, testCase.arg, got.String())
+				data, err := got.MarshalJSON()
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, "\""+testCase.arg+"\"", string(data))
+			}
+		})
+	}
+	internal.AssertFalse(t, NewDefinitionIDFrom("test.namespace:test-name:1.0.0").IsURL())
+}
+
 func TestDefinitionIDUnmarshalJSON(t *testing.T) {
 	tests := map[string]struct {
 		arg     []byte
@@ -170,6 +219,13 @@ func TestDefinitionIDUnmarshalJSON(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		"test_definition_id_unmarshal_json_url": {
+			arg: []byte("\"http://models.example.com/thermostat.tm.jsonld\""),
+			want: &DefinitionID{
+				URL: "http://models.example.com/thermostat.tm.jsonld",
+			},
+			wantErr: nil,
+		},
 		"test_definition_id_unmarshal_json_invalid_namespace": {
 			arg:     []byte("\"test:namespace:test-name:1.0.0\""),
 			wantErr: errors.New("invalid DefinitionID: test:namespace:test-name:1.0.0"),
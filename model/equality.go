@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+)
+
+// Equals returns true if the Thing has the same content as the provided one, i.e. their JSON representations
+// are semantically equal - regardless of the maps' order and the representation of the numbers, e.g. 1 and 1.0
+// or an int and a float64 after a JSON round-trip, are equal. As per the JSON representations, the empty maps are equal
+// to the absent ones. Two nil Things are equal.
+func (thing *Thing) Equals(other *Thing) bool {
+	if thing == nil || other == nil {
+		return thing == nil && other == nil
+	}
+	return jsonEquals(thing, other)
+}
+
+// Equals returns true if the Feature has the same content as the provided one, i.e. their JSON representations
+// are semantically equal - regardless of the maps' order and the representation of the numbers. Two nil Features are equal.
+func (feature *Feature) Equals(other *Feature) bool {
+	if feature == nil || other == nil {
+		return feature == nil && other == nil
+	}
+	return jsonEquals(feature, other)
+}
+
+func jsonEquals(v1, v2 interface{}) bool {
+	value1, err := toJSONValue(v1)
+	if err != nil {
+		return false
+	}
+	value2, err := toJSONValue(v2)
+	if err != nil {
+		return false
+	}
+	return valuesEqual(value1, value2)
+}
+
+func toJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var res interface{}
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func valuesEqual(v1, v2 interface{}) bool {
+	switch value1 := v1.(type) {
+	case map[string]interface{}:
+		value2, ok := v2.(map[string]interface{})
+		if !ok || len(value1) != len(value2) {
+			return false
+		}
+		for key, item := range value1 {
+			other, ok := value2[key]
+			if !ok || !valuesEqual(item, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		value2, ok := v2.([]interface{})
+		if !ok || len(value1) != len(value2) {
+			return false
+		}
+		for i := range value1 {
+			if !valuesEqual(value1[i], value2[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		value2, ok := v2.(json.Number)
+		if !ok {
+			return false
+		}
+		if value1 == value2 {
+			return true
+		}
+		n1, ok1 := new(big.Rat).SetString(string(value1))
+		n2, ok2 := new(big.Rat).SetString(string(value2))
+		return ok1 && ok2 && n1.Cmp(n2) == 0
+	default:
+		return v1 == v2
+	}
+}
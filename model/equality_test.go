@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestThingEquals(t *testing.T) {
+	newThing := func() *Thing {
+		return (&Thing{}).
+			WithIDFrom("test.namespace:test-name").
+			WithAttribute("count", 1).
+			WithAttribute("location", map[string]interface{}{"lat": 1.5, "tags": []interface{}{"a", 2}}).
+			WithFeature("lamp", (&Feature{}).WithProperty("on", true))
+	}
+
+	tests := map[string]struct {
+		thing *Thing
+		other *Thing
+		want  bool
+	}{
+		"test_same_content": {
+			thing: newThing(),
+			other: newThing(),
+			want:  true,
+		},
+		"test_numeric_representation": {
+			thing: newThing(),
+			other: newThing().
+				WithAttribute("count", 1.0).
+				WithAttribute("location", map[string]interface{}{"lat": json.Number("1.50"), "tags": []interface{}{"a", int64(2)}}),
+			want: true,
+		},
+		"test_json_round_trip": {
+			thing: newThing(),
+			other: func() *Thing {
+				data, _ := json.Marshal(newThing())
+				thing := &Thing{}
+				json.Unmarshal(data, thing)
+				return thing
+			}(),
+			want: true,
+		},
+		"test_different_number": {
+			thing: newThing(),
+			other: newThing().WithAttribute("count", 2),
+		},
+		"test_number_and_string": {
+			thing: newThing(),
+			other: newThing().WithAttribute("count", "1"),
+		},
+		"test_different_array_order": {
+			thing: newThing(),
+			other: newThing().WithAttribute("location", map[string]interface{}{"lat": 1.5, "tags": []interface{}{2, "a"}}),
+		},
+		"test_additional_attribute": {
+			thing: newThing(),
+			other: newThing().WithAttribute("other", nil),
+		},
+		"test_different_feature": {
+			thing: newThing(),
+			other: newThing().WithFeature("lamp", (&Feature{}).WithProperty("on", false)),
+		},
+		"test_nil_things": {
+			want: true,
+		},
+		"test_nil_other": {
+			thing: newThing(),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.thing.Equals(testCase.other))
+			internal.AssertEqual(t, testCase.want, testCase.other.Equals(testCase.thing))
+		})
+	}
+}
+
+func TestFeatureEquals(t *testing.T) {
+	tests := map[string]struct {
+		feature *Feature
+		other   *Feature
+		want    bool
+	}{
+		"test_same_content": {
+			feature: (&Feature{}).WithDefinitionFrom("test.namespace:lamp:1.0.0").WithProperty("brightness", 50),
+			other:   (&Feature{}).WithDefinitionFrom("test.namespace:lamp:1.0.0").WithProperty("brightness", float32(50)),
+			want:    true,
+		},
+		"test_different_definition": {
+			feature: (&Feature{}).WithDefinitionFrom("test.namespace:lamp:1.0.0"),
+			other:   (&Feature{}).WithDefinitionFrom("test.namespace:lamp:2.0.0"),
+		},
+		"test_empty_and_nil_properties": {
+			feature: &Feature{Properties: map[string]interface{}{}},
+			other:   &Feature{},
+			want:    true,
+		},
+		"test_nil_features": {
+			want: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.feature.Equals(testCase.other))
+		})
+	}
+}
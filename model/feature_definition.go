@@ -0,0 +1,147 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateDefinition checks that the Feature's definition entries are valid DefinitionIDs, i.e. none of them is nil
+// as created from an invalid string, or has neither a URL nor a namespace, name and version.
+// If the definition is required, it is also checked that the Feature has at least one definition entry.
+func (feature *Feature) ValidateDefinition(required bool) error {
+	if required && len(feature.Definition) == 0 {
+		return errors.New("feature definition is required")
+	}
+	for i, definitionID := range feature.Definition {
+		if definitionID == nil {
+			return fmt.Errorf("feature definition entry %d is invalid", i)
+		}
+		if definitionID.IsURL() {
+			continue
+		}
+		if _, err := splitDefinitionID(definitionID.String()); err != nil {
+			return fmt.Errorf("feature definition entry %d is invalid: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// HasDefinition returns true if any of the Feature's definition entries has the provided namespace and name
+// and a version within the provided range, e.g. to discover the capabilities of the Features of a received Thing.
+// The version range consists of space separated constraints, all of which are to be satisfied, each being a version
+// optionally prefixed by an operator - '=', '>', '>=', '<' or '<=', e.g. '>=1.0.0 <2.0.0'. An empty range matches any version.
+// The versions are compared by their dot separated numeric segments, a version with a '-qualifier' being lower
+// than the same one without it. Returns false if the version range is invalid.
+func (feature *Feature) HasDefinition(namespace, name, versionRange string) bool {
+	for _, definitionID := range feature.Definition {
+		if definitionID == nil || definitionID.IsURL() ||
+			definitionID.Namespace != namespace || definitionID.Name != name {
+			continue
+		}
+		if matchesVersionRange(definitionID.Version, versionRange) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesVersionRange(version, versionRange string) bool {
+	for _, constraint := range strings.Fields(versionRange) {
+		operator := ""
+		for _, op := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(constraint, op) {
+				operator = op
+				break
+			}
+		}
+		other := constraint[len(operator):]
+		if other == "" {
+			return false
+		}
+		cmp := compareVersions(version, other)
+		var ok bool
+		switch operator {
+		case "", "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares the provided versions by their dot separated segments, numerically if both segments
+// are numbers, and by their qualifiers, a version without a qualifier being greater than the same one with it.
+func compareVersions(v1, v2 string) int {
+	base1, qualifier1 := splitVersionQualifier(v1)
+	base2, qualifier2 := splitVersionQualifier(v2)
+	segments1 := strings.Split(base1, ".")
+	segments2 := strings.Split(base2, ".")
+	for i := 0; i < len(segments1) || i < len(segments2); i++ {
+		s1, s2 := "0", "0"
+		if i < len(segments1) {
+			s1 = segments1[i]
+		}
+		if i < len(segments2) {
+			s2 = segments2[i]
+		}
+		if cmp := compareVersionSegments(s1, s2); cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case qualifier1 == qualifier2:
+		return 0
+	case qualifier1 == "":
+		return 1
+	case qualifier2 == "":
+		return -1
+	default:
+		return strings.Compare(qualifier1, qualifier2)
+	}
+}
+
+func splitVersionQualifier(version string) (string, string) {
+	if i := strings.Index(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+func compareVersionSegments(s1, s2 string) int {
+	n1, err1 := strconv.Atoi(s1)
+	n2, err2 := strconv.Atoi(s2)
+	if err1 != nil || err2 != nil {
+		return strings.Compare(s1, s2)
+	}
+	switch {
+	case n1 < n2:
+		return -1
+	case n1 > n2:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestFeatureValidateDefinition(t *testing.T) {
+	tests := map[string]struct {
+		feature  *Feature
+		required bool
+		wantErr  bool
+	}{
+		"test_valid_definition": {
+			feature:  (&Feature{}).WithDefinitionFrom("test.namespace:lamp:1.0.0", "https://models.example.com/lamp.tm.jsonld"),
+			required: true,
+		},
+		"test_without_definition_not_required": {
+			feature: &Feature{},
+		},
+		"test_without_definition_required": {
+			feature:  &Feature{},
+			required: true,
+			wantErr:  true,
+		},
+		"test_invalid_definition_entry": {
+			feature: (&Feature{}).WithDefinitionFrom("test.namespace:lamp:1.0.0", "invalid"),
+			wantErr: true,
+		},
+		"test_empty_definition_entry": {
+			feature: (&Feature{}).WithDefinition(&DefinitionID{}),
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.feature.ValidateDefinition(testCase.required)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+			}
+		})
+	}
+}
+
+func TestFeatureHasDefinition(t *testing.T) {
+	feature := (&Feature{}).WithDefinitionFrom(
+		"https://models.example.com/lamp.tm.jsonld",
+		"test.namespace:lamp:1.10.2",
+		"test.namespace:switch:2.0.0-beta")
+
+	tests := map[string]struct {
+		namespace    string
+		name         string
+		versionRange string
+		want         bool
+	}{
+		"test_any_version":               {namespace: "test.namespace", name: "lamp", want: true},
+		"test_exact_version":             {namespace: "test.namespace", name: "lamp", versionRange: "1.10.2", want: true},
+		"test_exact_version_with_equals": {namespace: "test.namespace", name: "lamp", versionRange: "=1.10.2", want: true},
+		"test_other_exact_version":       {namespace: "test.namespace", name: "lamp", versionRange: "1.10"},
+		"test_numeric_comparison":        {namespace: "test.namespace", name: "lamp", versionRange: ">1.9", want: true},
+		"test_range":                     {namespace: "test.namespace", name: "lamp", versionRange: ">=1.0.0 <2.0.0", want: true},
+		"test_range_excluded":            {namespace: "test.namespace", name: "lamp", versionRange: ">=1.0.0 <1.10.2"},
+		"test_range_inclusive_upper":     {namespace: "test.namespace", name: "lamp", versionRange: "<=1.10.2", want: true},
+		"test_qualifier_lower":           {namespace: "test.namespace", name: "switch", versionRange: "<2.0.0", want: true},
+		"test_qualifier_excluded":        {namespace: "test.namespace", name: "switch", versionRange: ">=2.0.0"},
+		"test_other_name":                {namespace: "test.namespace", name: "thermostat"},
+		"test_other_namespace":           {namespace: "other.namespace", name: "lamp"},
+		"test_invalid_range":             {namespace: "test.namespace", name: "lamp", versionRange: ">="},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := feature.HasDefinition(testCase.namespace, testCase.name, testCase.versionRange)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// mergePatchIgnoredFields are the Thing's fields that cannot be changed via a merge patch.
+var mergePatchIgnoredFields = []string{"thingId", "_revision", "_modified", "_created", "_namespace", "_policy"}
+
+// NewMergePatch computes the JSON merge patch (https://tools.ietf.org/html/rfc7396) that turns the original Thing
+// into the desired one, to be used as the payload of a Thing merge command. The removed fields are set to nil,
+// i.e. to explicit JSON nulls. The Thing's ID, special fields, e.g. its revision, and inline Policy
+// are not part of the patch.
+// An empty patch is returned if there are no differences.
+// Note: As nulls denote removals in a merge patch, nil values within the desired Thing are removed and not set.
+func NewMergePatch(original, desired *Thing) (map[string]interface{}, error) {
+	originalJSON, err := thingToJSON(original)
+	if err != nil {
+		return nil, err
+	}
+	desiredJSON, err := thingToJSON(desired)
+	if err != nil {
+		return nil, err
+	}
+	return mergePatch(originalJSON, desiredJSON), nil
+}
+
+func thingToJSON(thing *Thing) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	if thing == nil {
+		return res, nil
+	}
+	data, err := json.Marshal(thing)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	for _, field := range mergePatchIgnoredFields {
+		delete(res, field)
+	}
+	return res, nil
+}
+
+func mergePatch(original, desired map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key := range original {
+		if _, ok := desired[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, desiredValue := range desired {
+		originalValue, ok := original[key]
+		if desiredObject, isObject := desiredValue.(map[string]interface{}); isObject && ok {
+			if originalObject, isObject := originalValue.(map[string]interface{}); isObject {
+				if nested := mergePatch(originalObject, desiredObject); len(nested) > 0 {
+					patch[key] = nested
+				}
+				continue
+			}
+		}
+		if !ok || !reflect.DeepEqual(originalValue, desiredValue) {
+			patch[key] = desiredValue
+		}
+	}
+	return patch
+}
+
+// MergeMaps applies the provided JSON merge patch (https://tools.ietf.org/html/rfc7396) to the provided original values
+// the same way Ditto does, i.e. the nested objects are merged, nil values remove the values they refer to and any other
+// values replace the original ones. The original values are not changed - a merged copy of them is returned,
+// sharing only the values that are not changed by the patch.
+func MergeMaps(original, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(original)+len(patch))
+	for key, value := range original {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		switch patchValue := value.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]interface{}:
+			originalObject, _ := merged[key].(map[string]interface{})
+			merged[key] = MergeMaps(originalObject, patchValue)
+		default:
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// MergeAttributes applies the provided JSON merge patch to the current Thing instance's attributes as per MergeMaps,
+// e.g. to apply a received attributes merged event to a locally kept Thing.
+func (thing *Thing) MergeAttributes(patch map[string]interface{}) *Thing {
+	thing.Attributes = MergeMaps(thing.Attributes, patch)
+	return thing
+}
+
+// MergeProperties applies the provided JSON merge patch to the current Feature instance's properties as per MergeMaps.
+func (feature *Feature) MergeProperties(patch map[string]interface{}) *Feature {
+	feature.Properties = MergeMaps(feature.Properties, patch)
+	return feature
+}
+
+// MergeDesiredProperties applies the provided JSON merge patch to the current Feature instance's desired properties as per MergeMaps.
+func (feature *Feature) MergeDesiredProperties(patch map[string]interface{}) *Feature {
+	feature.DesiredProperties = MergeMaps(feature.DesiredProperties, patch)
+	return feature
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestNewMergePatch(t *testing.T) {
+	newThing := func() *Thing {
+		return (&Thing{}).
+			WithIDFrom("test.namespace:test-name").
+			WithPolicyIDFrom("test.namespace:policy").
+			WithAttribute("location", map[string]interface{}{"x": 1, "y": 2}).
+			WithAttribute("serial", "123").
+			WithFeature("lamp", (&Feature{}).WithProperty("on", false).WithProperty("color", "red"))
+	}
+
+	tests := map[string]struct {
+		original *Thing
+		desired  *Thing
+		want     string
+	}{
+		"test_no_changes": {
+			original: newThing(),
+			desired:  newThing(),
+			want:     `{}`,
+		},
+		"test_ignored_fields": {
+			original: newThing(),
+			desired: func() *Thing {
+				thing := newThing().WithIDFrom("other.namespace:other-name")
+				thing.Revision = 5
+				thing.Timestamp = "2022-01-01T00:00:00Z"
+				thing.Created = "2021-01-01T00:00:00Z"
+				thing.Namespace = "other.namespace"
+				thing.Policy = NewPolicy().WithEntry("owner", Subject("nginx:ditto", "generated"))
+				return thing
+			}(),
+			want: `{}`,
+		},
+		"test_nested_changes": {
+			original: newThing(),
+			desired: newThing().
+				WithAttribute("location", map[string]interface{}{"x": 1, "y": 3}).
+				WithFeature("lamp", (&Feature{}).WithProperty("on", true).WithProperty("color", "red")),
+			want: `{"attributes":{"location":{"y":3}},"features":{"lamp":{"properties":{"on":true}}}}`,
+		},
+		"test_removals": {
+			original: newThing(),
+			desired: func() *Thing {
+				thing := newThing()
+				delete(thing.Attributes, "serial")
+				delete(thing.Features["lamp"].Properties, "color")
+				thing.PolicyID = nil
+				return thing
+			}(),
+			want: `{"attributes":{"serial":null},"features":{"lamp":{"properties":{"color":null}}},"policyId":null}`,
+		},
+		"test_additions": {
+			original: newThing(),
+			desired: newThing().
+				WithDefinitionFrom("test.namespace:model:1.0.0").
+				WithFeature("fan", (&Feature{}).WithProperty("speed", 2)),
+			want: `{"definitionId":"test.namespace:model:1.0.0","features":{"fan":{"properties":{"speed":2}}}}`,
+		},
+		"test_replaced_type": {
+			original: newThing(),
+			desired:  newThing().WithAttribute("location", "home"),
+			want:     `{"attributes":{"location":"home"}}`,
+		},
+		"test_nil_original": {
+			original: nil,
+			desired:  (&Thing{}).WithIDFrom("test.namespace:test-name").WithAttribute("a", 1),
+			want:     `{"attributes":{"a":1}}`,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := NewMergePatch(testCase.original, testCase.desired)
+			internal.AssertNil(t, err)
+			data, err := json.Marshal(got)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.want, string(data))
+		})
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	tests := map[string]struct {
+		original map[string]interface{}
+		patch    map[string]interface{}
+		want     map[string]interface{}
+	}{
+		"test_nil_original": {
+			original: nil,
+			patch:    map[string]interface{}{"a": 1, "b": nil},
+			want:     map[string]interface{}{"a": 1},
+		},
+		"test_replace_and_remove": {
+			original: map[string]interface{}{"a": 1, "b": 2},
+			patch:    map[string]interface{}{"a": "x", "b": nil},
+			want:     map[string]interface{}{"a": "x"},
+		},
+		"test_nested_merge": {
+			original: map[string]interface{}{"location": map[string]interface{}{"lat": 1, "lon": 2}},
+			patch:    map[string]interface{}{"location": map[string]interface{}{"lat": 3, "lon": nil, "alt": 4}},
+			want:     map[string]interface{}{"location": map[string]interface{}{"lat": 3, "alt": 4}},
+		},
+		"test_object_replacing_primitive": {
+			original: map[string]interface{}{"location": "home"},
+			patch:    map[string]interface{}{"location": map[string]interface{}{"lat": 1, "lon": nil}},
+			want:     map[string]interface{}{"location": map[string]interface{}{"lat": 1}},
+		},
+		"test_array_replaced": {
+			original: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			patch:    map[string]interface{}{"tags": []interface{}{"c"}},
+			want:     map[string]interface{}{"tags": []interface{}{"c"}},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, MergeMaps(testCase.original, testCase.patch))
+		})
+	}
+}
+
+func TestMergeMapsKeepsOriginal(t *testing.T) {
+	original := map[string]interface{}{"location": map[string]interface{}{"lat": 1}}
+	MergeMaps(original, map[string]interface{}{"location": map[string]interface{}{"lat": nil}})
+	internal.AssertEqual(t, map[string]interface{}{"location": map[string]interface{}{"lat": 1}}, original)
+}
+
+func TestMergeAttributesAndProperties(t *testing.T) {
+	thing := (&Thing{}).
+		WithAttribute("serial", "123").
+		MergeAttributes(map[string]interface{}{"serial": nil, "model": "x"})
+	internal.AssertEqual(t, map[string]interface{}{"model": "x"}, thing.Attributes)
+
+	feature := (&Feature{}).
+		WithProperty("on", true).
+		MergeProperties(map[string]interface{}{"on": false}).
+		MergeDesiredProperties(map[string]interface{}{"on": true})
+	internal.AssertEqual(t, map[string]interface{}{"on": false}, feature.Properties)
+	internal.AssertEqual(t, map[string]interface{}{"on": true}, feature.DesiredProperties)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"errors"
+)
+
+// Namespace represents the namespace of the Ditto entities, e.g. the namespace of a NamespacedID.
+// Compliant with the Ditto specification a namespace is either empty or consists of segments separated by a '.' (dot)
+// or a '-' (dash), each starting with a letter and followed by letters, digits or '_' (underscores), e.g. 'org.eclipse_ditto'.
+// Note: The namespaces of the DefinitionIDs follow the Ditto's less strict definition rules and are not Namespaces.
+type Namespace string
+
+// NewNamespace creates a new Namespace from the provided string.
+// Returns an error if the provided string is not a valid namespace.
+func NewNamespace(namespace string) (Namespace, error) {
+	ns := Namespace(namespace)
+	if err := ns.Validate(); err != nil {
+		return "", err
+	}
+	return ns, nil
+}
+
+// Validate checks that the Namespace complies with the Ditto's namespace rules.
+func (ns Namespace) Validate() error {
+	if !isValidNamespace(string(ns)) {
+		return errors.New("invalid namespace: " + string(ns))
+	}
+	return nil
+}
+
+// String provides the string representation of the Namespace.
+func (ns Namespace) String() string {
+	return string(ns)
+}
+
+// isValidNamespace checks the namespace rules without a regular expression, as it's done for each incoming message.
+func isValidNamespace(ns string) bool {
+	segmentStart := true
+	for i := 0; i < len(ns); i++ {
+		c := ns[i]
+		switch {
+		case segmentStart:
+			if !isLetter(c) {
+				return false
+			}
+			segmentStart = false
+		case c == '.' || c == '-':
+			segmentStart = true
+		case !isLetter(c) && !isDigit(c) && c != '_':
+			return false
+		}
+	}
+	return len(ns) == 0 || !segmentStart
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
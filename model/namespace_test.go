@@ -0,0 +1,58 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestNewNamespace(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		wantErr bool
+	}{
+		"test_empty":                 {arg: ""},
+		"test_single_segment":        {arg: "ditto"},
+		"test_dot_segments":          {arg: "org.eclipse_ditto.test1"},
+		"test_dash_segments":         {arg: "org-eclipse.ditto"},
+		"test_segment_with_digit":    {arg: "org.1eclipse", wantErr: true},
+		"test_segment_leading_under": {arg: "_org", wantErr: true},
+		"test_empty_segment":         {arg: "org..eclipse", wantErr: true},
+		"test_trailing_dot":          {arg: "org.", wantErr: true},
+		"test_colon":                 {arg: "org:eclipse", wantErr: true},
+		"test_slash":                 {arg: "org/eclipse", wantErr: true},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := NewNamespace(testCase.arg)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				internal.AssertEqual(t, Namespace(""), got)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.arg, got.String())
+			}
+		})
+	}
+}
+
+func TestNewNamespacedIDIn(t *testing.T) {
+	got := NewNamespacedIDIn(Namespace("test.namespace"), "test-name")
+	internal.AssertEqual(t, &NamespacedID{Namespace: "test.namespace", Name: "test-name"}, got)
+	internal.AssertEqual(t, Namespace("test.namespace"), got.NamespaceOf())
+
+	internal.AssertNil(t, NewNamespacedIDIn(Namespace("test..namespace"), "test-name"))
+	internal.AssertNil(t, NewNamespacedIDIn(Namespace("test.namespace"), "test/name"))
+}
@@ -15,14 +15,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
 const namespacedIDTemplate = "%s:%s"
 
-var regexNamespacedID = regexp.MustCompile("^(|(?:[a-zA-Z]\\w*)(?:[.\\-][a-zA-Z]\\w*)*):([^\\x00-\\x1F\\x7F-\\xFF/]+)$")
-
 // NamespacedID represents the namespaced ID defined by the Ditto specification.
 // It is a unique identifier representing a Thing compliant with the Ditto requirements:
 // - namespace and name separated by a : (colon)
@@ -35,10 +32,10 @@ type NamespacedID struct {
 // NewNamespacedID creates a new NamespacedID instance using the provided namespace and name.
 // Returns nil if the provided string doesn't match the form.
 func NewNamespacedID(namespace string, name string) *NamespacedID {
-	if strings.Contains(namespace, ":") {
+	if Namespace(namespace).Validate() != nil {
 		return nil
 	}
-	if _, err := isValidNamespacedID(fmt.Sprintf(namespacedIDTemplate, namespace, name)); err == nil {
+	if _, _, err := parseNamespacedID(fmt.Sprintf(namespacedIDTemplate, namespace, name)); err == nil {
 		return &NamespacedID{Namespace: namespace, Name: name}
 	}
 	return nil
@@ -47,12 +44,23 @@ func NewNamespacedID(namespace string, name string) *NamespacedID {
 // NewNamespacedIDFrom creates a new NamespacedID instance using the provided string in the valid form of 'namespace:name'.
 // Returns nil if the provided string doesn't match the form.
 func NewNamespacedIDFrom(full string) *NamespacedID {
-	if matches, err := isValidNamespacedID(full); err == nil {
-		return &NamespacedID{Namespace: matches[1], Name: matches[2]}
+	if namespace, name, err := parseNamespacedID(full); err == nil {
+		return &NamespacedID{Namespace: namespace, Name: name}
 	}
 	return nil
 }
 
+// NewNamespacedIDIn creates a new NamespacedID instance within the provided Namespace using the provided name.
+// Returns nil if the Namespace or the name are not valid.
+func NewNamespacedIDIn(namespace Namespace, name string) *NamespacedID {
+	return NewNamespacedID(namespace.String(), name)
+}
+
+// NamespaceOf provides the Namespace of the NamespacedID.
+func (nsID *NamespacedID) NamespaceOf() Namespace {
+	return Namespace(nsID.Namespace)
+}
+
 // String provides the string representation of the NamespacedID entity in the form of 'namespace:name'.
 func (nsID *NamespacedID) String() string {
 	return fmt.Sprintf(namespacedIDTemplate, nsID.Namespace, nsID.Name)
@@ -69,12 +77,12 @@ func (nsID *NamespacedID) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &nsIDString); err != nil {
 		return err
 	}
-	matches, err := isValidNamespacedID(nsIDString)
+	namespace, name, err := parseNamespacedID(nsIDString)
 	if err != nil {
 		return err
 	}
-	nsID.Namespace = matches[1]
-	nsID.Name = matches[2]
+	nsID.Namespace = namespace
+	nsID.Name = name
 	return nil
 }
 
@@ -90,12 +98,31 @@ func (nsID *NamespacedID) WithName(name string) *NamespacedID {
 	return nsID
 }
 
-func isValidNamespacedID(nsIDString string) ([]string, error) {
+// parseNamespacedID splits the provided string into a valid namespace and name without a regular expression,
+// as it's done for each incoming message.
+func parseNamespacedID(nsIDString string) (string, string, error) {
 	if len(nsIDString) > 256 {
-		return nil, errors.New("length exceeds 256, invalid NamespacedID: " + nsIDString)
+		return "", "", errors.New("length exceeds 256, invalid NamespacedID: " + nsIDString)
+	}
+	// the namespace cannot contain a colon, while the name can
+	if i := strings.IndexByte(nsIDString, ':'); i >= 0 {
+		namespace, name := nsIDString[:i], nsIDString[i+1:]
+		if isValidNamespace(namespace) && isValidEntityName(name) {
+			return namespace, name, nil
+		}
+	}
+	return "", "", errors.New("invalid NamespacedID: " + nsIDString)
+}
+
+// isValidEntityName checks that the name is not empty and contains neither slashes nor control and Latin-1 characters.
+func isValidEntityName(name string) bool {
+	if len(name) == 0 {
+		return false
 	}
-	if matches := regexNamespacedID.FindStringSubmatch(nsIDString); len(matches) == 3 {
-		return matches, nil
+	for _, r := range name {
+		if r <= 0x1F || (0x7F <= r && r <= 0xFF) || r == '/' {
+			return false
+		}
 	}
-	return nil, errors.New("invalid NamespacedID: " + nsIDString)
+	return true
 }
@@ -0,0 +1,40 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// DecodeJSON unmarshals the provided JSON data into the provided target, e.g. a Thing or a Feature, the same way
+// as json.Unmarshal does. If preciseNumbers is true, the numbers within the generic maps, slices and interfaces,
+// e.g. the Things' attributes and the Features' properties and desired properties, are decoded as json.Number
+// instead of float64, so that values which don't fit into a float64 without a loss, e.g. large int64 counters
+// or timestamps, round-trip losslessly. The values are then converted on demand via the json.Number's Int64,
+// Float64 or String methods.
+func DecodeJSON(data []byte, target interface{}, preciseNumbers bool) error {
+	if !preciseNumbers {
+		return json.Unmarshal(data, target)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(target); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid JSON: unexpected data after the top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+const testPreciseThingJSON = `{"thingId":"test.namespace:test-name","attributes":{"counter":9007199254740993,"ratio":0.1},` +
+	`"features":{"meter":{"properties":{"total":{"value":9223372036854775807}},"desiredProperties":{"total":1}}}}`
+
+func TestThingPreciseNumberDecoding(t *testing.T) {
+	thing := &Thing{}
+	internal.AssertNil(t, DecodeJSON([]byte(testPreciseThingJSON), thing, true))
+
+	internal.AssertEqual(t, json.Number("9007199254740993"), thing.Attributes["counter"])
+	internal.AssertEqual(t, json.Number("0.1"), thing.Attributes["ratio"])
+	internal.AssertEqual(t, map[string]interface{}{"value": json.Number("9223372036854775807")},
+		thing.Features["meter"].Properties["total"])
+	internal.AssertEqual(t, json.Number("1"), thing.Features["meter"].DesiredProperties["total"])
+	internal.AssertEqual(t, "test.namespace:test-name", thing.ID.String())
+
+	data, err := json.Marshal(thing)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, testPreciseThingJSON, string(data))
+}
+
+func TestThingImpreciseNumberDecoding(t *testing.T) {
+	thing := &Thing{}
+	internal.AssertNil(t, json.Unmarshal([]byte(testPreciseThingJSON), thing))
+
+	internal.AssertEqual(t, float64(9007199254740992), thing.Attributes["counter"])
+	internal.AssertEqual(t, float64(1), thing.Features["meter"].DesiredProperties["total"])
+}
+
+func TestFeaturePreciseNumberDecoding(t *testing.T) {
+	feature := &Feature{}
+	internal.AssertNil(t, DecodeJSON([]byte(`{"properties":{"timestamp":1648000000123456789}}`), feature, true))
+
+	value, err := feature.Properties["timestamp"].(json.Number).Int64()
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, int64(1648000000123456789), value)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		precise bool
+		want    interface{}
+		wantErr bool
+	}{
+		"test_precise_number": {
+			data:    `[9007199254740993]`,
+			precise: true,
+			want:    []interface{}{json.Number("9007199254740993")},
+		},
+		"test_float_number": {
+			data: `[9007199254740993]`,
+			want: []interface{}{float64(9007199254740992)},
+		},
+		"test_precise_trailing_data": {
+			data:    `[1] [2]`,
+			precise: true,
+			wantErr: true,
+		},
+		"test_precise_invalid_json": {
+			data:    `[1`,
+			precise: true,
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var got interface{}
+			err := DecodeJSON([]byte(testCase.data), &got, testCase.precise)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got)
+			}
+		})
+	}
+}
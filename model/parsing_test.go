@@ -0,0 +1,85 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// The regular expressions formerly used for parsing, kept as the reference the hand-rolled parsers are validated against.
+const namespacePattern = "(|(?:[a-zA-Z]\\w*)(?:[.\\-][a-zA-Z]\\w*)*)"
+
+var (
+	regexNamespace      = regexp.MustCompile("^" + namespacePattern + "$")
+	regexNamespacedID   = regexp.MustCompile("^" + namespacePattern + ":([^\\x00-\\x1F\\x7F-\\xFF/]+)$")
+	regexDefinitionID   = regexp.MustCompile("^([_a-zA-Z0-9\\-.]+):([_a-zA-Z0-9\\-.]+):([_a-zA-Z0-9\\-.]+)$")
+	parsingTestElements = []string{"a", "Z", "0", "_", ".", "-", ":", "/", " ", "\n", "\x1f", "\x7f", "é", "€", "\xff", "org", "eclipse"}
+)
+
+// parsingTestInputs provides the inputs the parsers are validated with, i.e. the provided ones along with
+// deterministically generated random combinations of characters significant for the parsing.
+func parsingTestInputs(inputs ...string) []string {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		var input strings.Builder
+		for j := random.Intn(12); j >= 0; j-- {
+			input.WriteString(parsingTestElements[random.Intn(len(parsingTestElements))])
+		}
+		inputs = append(inputs, input.String())
+	}
+	return inputs
+}
+
+func TestIsValidNamespaceMatchesRegex(t *testing.T) {
+	for _, input := range parsingTestInputs("", "a", "a.b-c_1", "a..b", "a.", "1a", "a.1", "a_") {
+		if got, want := isValidNamespace(input), regexNamespace.MatchString(input); got != want {
+			t.Errorf("namespace %q: expected %v, got %v", input, want, got)
+		}
+	}
+}
+
+func TestParseNamespacedIDMatchesRegex(t *testing.T) {
+	inputs := parsingTestInputs(":", "a:", ":b", "a:b:c", "a.b:c/d", "a:é", "a:€", "a:\xff", "a:b\n",
+		"ns:"+strings.Repeat("n", 253), "ns:"+strings.Repeat("n", 254))
+	for _, input := range inputs {
+		namespace, name, err := parseNamespacedID(input)
+		var got []string
+		if err == nil {
+			got = []string{input, namespace, name}
+		}
+		var want []string
+		if len(input) <= 256 {
+			want = regexNamespacedID.FindStringSubmatch(input)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("namespaced ID %q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestSplitDefinitionIDMatchesRegex(t *testing.T) {
+	for _, input := range parsingTestInputs("a:b:c", "a.b:c-d:1.0_0", "a:b", "a:b:c:d", "a::c", ":b:c", "a:b:") {
+		elements, err := splitDefinitionID(input)
+		var got []string
+		if err == nil {
+			got = append([]string{input}, elements...)
+		}
+		want := regexDefinitionID.FindStringSubmatch(input)
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("definition ID %q: expected %q, got %q", input, want, got)
+		}
+	}
+}
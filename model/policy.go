@@ -0,0 +1,198 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Permission represents a permission granted or revoked on a Policy resource.
+type Permission string
+
+// Policy permissions constants.
+const (
+	PermissionRead  Permission = "READ"
+	PermissionWrite Permission = "WRITE"
+)
+
+// Policy resource types constants.
+const (
+	ResourceTypeThing   = "thing"
+	ResourceTypePolicy  = "policy"
+	ResourceTypeMessage = "message"
+)
+
+var regexSubjectID = regexp.MustCompile("^[^:\\s]+:\\S.*$")
+
+// Policy represents the Policy entity model form the Ditto's specification.
+// A Policy controls the access to the entities it is applied to via its entries, each granting and revoking
+// permissions on resources to a set of subjects.
+type Policy struct {
+	ID      *NamespacedID           `json:"policyId,omitempty"`
+	Entries map[string]*PolicyEntry `json:"entries,omitempty"`
+}
+
+// PolicyEntry represents a single labeled entry of a Policy.
+type PolicyEntry struct {
+	Subjects  map[string]*PolicySubject  `json:"subjects"`
+	Resources map[string]*PolicyResource `json:"resources"`
+}
+
+// PolicySubject represents a subject of a PolicyEntry, e.g. an authenticated user or a connection.
+type PolicySubject struct {
+	Type string `json:"type"`
+}
+
+// PolicyResource represents the permissions granted and revoked on a resource of a PolicyEntry.
+type PolicyResource struct {
+	Grant  []Permission `json:"grant"`
+	Revoke []Permission `json:"revoke"`
+}
+
+// PolicyEntryOpt represents a configuration of a PolicyEntry, e.g. a subject or granted permissions.
+type PolicyEntryOpt func(entry *PolicyEntry)
+
+// NewPolicy creates a new empty Policy instance.
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// WithID sets the provided NamespacedID as the current Policy's instance ID value.
+func (policy *Policy) WithID(id *NamespacedID) *Policy {
+	policy.ID = id
+	return policy
+}
+
+// WithIDFrom is an auxiliary method that sets the ID value of the current Policy instance based on the provided string in the form of 'namespace:name'.
+func (policy *Policy) WithIDFrom(id string) *Policy {
+	policy.ID = NewNamespacedIDFrom(id)
+	return policy
+}
+
+// WithEntry sets/adds an entry with the provided label configured with all provided options to the current Policy instance.
+// If an entry with the same label is already present, the options are applied to it.
+func (policy *Policy) WithEntry(label string, opts ...PolicyEntryOpt) *Policy {
+	if policy.Entries == nil {
+		policy.Entries = make(map[string]*PolicyEntry)
+	}
+	entry, ok := policy.Entries[label]
+	if !ok {
+		entry = &PolicyEntry{
+			Subjects:  make(map[string]*PolicySubject),
+			Resources: make(map[string]*PolicyResource),
+		}
+		policy.Entries[label] = entry
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	return policy
+}
+
+// Subject configures a PolicyEntry with the subject with the provided ID in the form of 'issuer:subject' and type.
+func Subject(subjectID, subjectType string) PolicyEntryOpt {
+	return func(entry *PolicyEntry) {
+		entry.Subjects[subjectID] = &PolicySubject{Type: subjectType}
+	}
+}
+
+// Grant configures a PolicyEntry to grant the provided permissions on the resource with the provided path
+// in the form of 'type:/pointer', e.g. 'thing:/features'.
+func Grant(resourcePath string, permissions ...Permission) PolicyEntryOpt {
+	return func(entry *PolicyEntry) {
+		resource := entry.resource(resourcePath)
+		resource.Grant = appendPermissions(resource.Grant, permissions)
+	}
+}
+
+// Revoke configures a PolicyEntry to revoke the provided permissions on the resource with the provided path
+// in the form of 'type:/pointer', e.g. 'thing:/features'.
+func Revoke(resourcePath string, permissions ...Permission) PolicyEntryOpt {
+	return func(entry *PolicyEntry) {
+		resource := entry.resource(resourcePath)
+		resource.Revoke = appendPermissions(resource.Revoke, permissions)
+	}
+}
+
+func (entry *PolicyEntry) resource(resourcePath string) *PolicyResource {
+	resource, ok := entry.Resources[resourcePath]
+	if !ok {
+		resource = &PolicyResource{Grant: []Permission{}, Revoke: []Permission{}}
+		entry.Resources[resourcePath] = resource
+	}
+	return resource
+}
+
+func appendPermissions(current, permissions []Permission) []Permission {
+	for _, permission := range permissions {
+		if !containsPermission(current, permission) {
+			current = append(current, permission)
+		}
+	}
+	return current
+}
+
+func containsPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the Policy's entries have non-empty labels, subject IDs in the form of 'issuer:subject',
+// resource paths in the form of 'type:/pointer' with a 'thing', 'policy' or 'message' type and only known permissions.
+func (policy *Policy) Validate() error {
+	for label, entry := range policy.Entries {
+		if label == "" {
+			return fmt.Errorf("empty policy entry label")
+		}
+		if entry == nil {
+			return fmt.Errorf("policy entry '%s' is nil", label)
+		}
+		for subjectID := range entry.Subjects {
+			if !regexSubjectID.MatchString(subjectID) {
+				return fmt.Errorf("invalid subject ID '%s' of policy entry '%s', it must be in the form of 'issuer:subject'", subjectID, label)
+			}
+		}
+		for resourcePath, resource := range entry.Resources {
+			if err := validateResourcePath(resourcePath); err != nil {
+				return fmt.Errorf("invalid resource of policy entry '%s': %v", label, err)
+			}
+			if resource == nil {
+				continue
+			}
+			for _, permission := range append(append([]Permission{}, resource.Grant...), resource.Revoke...) {
+				if permission != PermissionRead && permission != PermissionWrite {
+					return fmt.Errorf("invalid permission '%s' on resource '%s' of policy entry '%s'", permission, resourcePath, label)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateResourcePath(resourcePath string) error {
+	parts := strings.SplitN(resourcePath, ":", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], "/") {
+		return fmt.Errorf("resource path '%s' must be in the form of 'type:/pointer'", resourcePath)
+	}
+	switch parts[0] {
+	case ResourceTypeThing, ResourceTypePolicy, ResourceTypeMessage:
+		return nil
+	default:
+		return fmt.Errorf("unknown resource type '%s' of resource path '%s'", parts[0], resourcePath)
+	}
+}
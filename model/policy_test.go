@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestPolicyBuilder(t *testing.T) {
+	policy := NewPolicy().
+		WithIDFrom("test.namespace:test-policy").
+		WithEntry("owner",
+			Subject("nginx:ditto", "generated"),
+			Grant("thing:/", PermissionRead, PermissionWrite),
+			Grant("policy:/", PermissionRead),
+			Grant("thing:/", PermissionWrite),
+			Revoke("thing:/attributes/secret", PermissionRead)).
+		WithEntry("observer", Subject("integration:reader", "connection")).
+		WithEntry("observer", Grant("thing:/features", PermissionRead))
+
+	internal.AssertNil(t, policy.Validate())
+
+	data, err := json.Marshal(policy)
+	internal.AssertNil(t, err)
+	want := `{"policyId":"test.namespace:test-policy","entries":{` +
+		`"observer":{"subjects":{"integration:reader":{"type":"connection"}},` +
+		`"resources":{"thing:/features":{"grant":["READ"],"revoke":[]}}},` +
+		`"owner":{"subjects":{"nginx:ditto":{"type":"generated"}},"resources":{` +
+		`"policy:/":{"grant":["READ"],"revoke":[]},` +
+		`"thing:/":{"grant":["READ","WRITE"],"revoke":[]},` +
+		`"thing:/attributes/secret":{"grant":[],"revoke":["READ"]}}}}}`
+	internal.AssertEqual(t, want, string(data))
+}
+
+func TestPolicyUnmarshalJSON(t *testing.T) {
+	data := `{"policyId":"test.namespace:test-policy","entries":{"owner":{` +
+		`"subjects":{"nginx:ditto":{"type":"generated"}},"resources":{"thing:/":{"grant":["READ","WRITE"],"revoke":[]}}}}}`
+
+	got := &Policy{}
+	internal.AssertNil(t, json.Unmarshal([]byte(data), got))
+
+	want := NewPolicy().
+		WithIDFrom("test.namespace:test-policy").
+		WithEntry("owner", Subject("nginx:ditto", "generated"), Grant("thing:/", PermissionRead, PermissionWrite))
+	internal.AssertEqual(t, want, got)
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy  *Policy
+		wantErr bool
+	}{
+		"test_empty_policy": {
+			policy: NewPolicy(),
+		},
+		"test_valid_resource_types": {
+			policy: NewPolicy().WithEntry("owner",
+				Grant("thing:/", PermissionRead),
+				Grant("policy:/entries", PermissionRead),
+				Grant("message:/inbox", PermissionWrite)),
+		},
+		"test_empty_label": {
+			policy:  NewPolicy().WithEntry("", Subject("nginx:ditto", "generated")),
+			wantErr: true,
+		},
+		"test_subject_id_without_issuer": {
+			policy:  NewPolicy().WithEntry("owner", Subject("ditto", "generated")),
+			wantErr: true,
+		},
+		"test_subject_id_with_empty_issuer": {
+			policy:  NewPolicy().WithEntry("owner", Subject(":ditto", "generated")),
+			wantErr: true,
+		},
+		"test_subject_id_with_empty_subject": {
+			policy:  NewPolicy().WithEntry("owner", Subject("nginx:", "generated")),
+			wantErr: true,
+		},
+		"test_resource_without_type": {
+			policy:  NewPolicy().WithEntry("owner", Grant("/features", PermissionRead)),
+			wantErr: true,
+		},
+		"test_resource_with_unknown_type": {
+			policy:  NewPolicy().WithEntry("owner", Grant("device:/", PermissionRead)),
+			wantErr: true,
+		},
+		"test_resource_without_pointer": {
+			policy:  NewPolicy().WithEntry("owner", Grant("thing:features", PermissionRead)),
+			wantErr: true,
+		},
+		"test_unknown_permission": {
+			policy:  NewPolicy().WithEntry("owner", Grant("thing:/", "EXECUTE")),
+			wantErr: true,
+		},
+		"test_nil_entry": {
+			policy:  &Policy{Entries: map[string]*PolicyEntry{"owner": nil}},
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.policy.Validate()
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+			}
+		})
+	}
+}
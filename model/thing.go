@@ -13,14 +13,20 @@ package model
 
 // Thing represents the Thing entity model form the Ditto's specification.
 // Things are very generic entities and are mostly used as a “handle” for multiple features belonging to this Thing.
+// The Revision, Timestamp (the last modification), Created and Namespace are the Ditto's special fields,
+// which are provided only if explicitly requested via a field selector, e.g. '_revision,_modified'.
+// The Policy is the inline Policy to be created along with the Thing, i.e. it is applicable on the Thing's creation only.
 type Thing struct {
 	ID           *NamespacedID          `json:"thingId"`
 	PolicyID     *NamespacedID          `json:"policyId,omitempty"`
 	DefinitionID *DefinitionID          `json:"definitionId,omitempty"`
 	Attributes   map[string]interface{} `json:"attributes,omitempty"`
 	Features     map[string]*Feature    `json:"features,omitempty"`
-	Revision     int64                  `json:"revision,omitempty"`
-	Timestamp    string                 `json:"timestamp,omitempty"`
+	Revision     int64                  `json:"_revision,omitempty"`
+	Timestamp    string                 `json:"_modified,omitempty"`
+	Created      string                 `json:"_created,omitempty"`
+	Namespace    string                 `json:"_namespace,omitempty"`
+	Policy       *Policy                `json:"_policy,omitempty"`
 }
 
 // WithID sets the provided NamespacedID as the current Thing's instance ID value.
@@ -41,7 +47,7 @@ func (thing *Thing) WithDefinition(definitionID *DefinitionID) *Thing {
 	return thing
 }
 
-// WithDefinitionFrom is an auxiliary method to set the current Thing instance's definition to the provided one in the form of 'namespace:name:version'.
+// WithDefinitionFrom is an auxiliary method to set the current Thing instance's definition to the provided one in the form of 'namespace:name:version' or an HTTP(S) URL.
 func (thing *Thing) WithDefinitionFrom(definitionID string) *Thing {
 	thing.DefinitionID = NewDefinitionIDFrom(definitionID)
 	return thing
@@ -60,6 +66,12 @@ func (thing *Thing) WithPolicyIDFrom(policyID string) *Thing {
 	return thing
 }
 
+// WithPolicy sets the provided Policy as the inline Policy to be created along with the current Thing instance.
+func (thing *Thing) WithPolicy(policy *Policy) *Thing {
+	thing.Policy = policy
+	return thing
+}
+
 // WithAttributes sets all attributes to the current Thing instance.
 func (thing *Thing) WithAttributes(attrs map[string]interface{}) *Thing {
 	thing.Attributes = attrs
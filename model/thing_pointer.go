@@ -0,0 +1,318 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	pathPolicyID          = "policyId"
+	pathDefinition        = "definition"
+	pathAttributes        = "attributes"
+	pathFeatures          = "features"
+	pathProperties        = "properties"
+	pathDesiredProperties = "desiredProperties"
+)
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// Get returns the value within the Thing referenced by the provided Ditto path, the same as the paths of the Thing commands,
+// e.g. '/attributes/location' or '/features/Temp/properties/value', and whether such value is present.
+// The Thing's entities are returned with their model types, e.g. a *Feature for '/features/Temp',
+// while the attributes and the properties are returned as they are.
+func (thing *Thing) Get(path string) (interface{}, bool) {
+	tokens, err := thingPathTokens(path)
+	if err != nil || thing == nil {
+		return nil, false
+	}
+	if len(tokens) == 0 {
+		return thing, true
+	}
+	switch tokens[0] {
+	case pathPolicyID:
+		return thing.PolicyID, thing.PolicyID != nil && len(tokens) == 1
+	case pathDefinition:
+		return thing.DefinitionID, thing.DefinitionID != nil && len(tokens) == 1
+	case pathAttributes:
+		if thing.Attributes == nil {
+			return nil, false
+		}
+		return lookupValue(thing.Attributes, tokens[1:])
+	case pathFeatures:
+		if thing.Features == nil {
+			return nil, false
+		}
+		if len(tokens) == 1 {
+			return thing.Features, true
+		}
+		feature, ok := thing.Features[tokens[1]]
+		if !ok {
+			return nil, false
+		}
+		if len(tokens) == 2 {
+			return feature, true
+		}
+		if feature == nil {
+			return nil, false
+		}
+		switch tokens[2] {
+		case pathDefinition:
+			return feature.Definition, feature.Definition != nil && len(tokens) == 3
+		case pathProperties:
+			if feature.Properties == nil {
+				return nil, false
+			}
+			return lookupValue(feature.Properties, tokens[3:])
+		case pathDesiredProperties:
+			if feature.DesiredProperties == nil {
+				return nil, false
+			}
+			return lookupValue(feature.DesiredProperties, tokens[3:])
+		}
+	}
+	return nil, false
+}
+
+// Set sets the provided value within the Thing at the provided Ditto path, the same as the paths of the Thing commands,
+// e.g. '/features/Temp/properties/value', creating the missing features and the intermediate objects on the way.
+// The values of the Thing's entities are either of their model types, e.g. a *Feature for '/features/Temp',
+// or are converted to them via their JSON representation, e.g. a map[string]interface{} received within an event.
+// Returns an error if the path doesn't refer to a Thing's entity or the value cannot be converted.
+func (thing *Thing) Set(path string, value interface{}) error {
+	tokens, err := thingPathTokens(path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		res := &Thing{}
+		if err := convertValue(value, &res); err != nil {
+			return err
+		}
+		if res == nil {
+			return fmt.Errorf("cannot set a nil thing at path '%s'", path)
+		}
+		*thing = *res
+		return nil
+	}
+	switch tokens[0] {
+	case pathPolicyID:
+		if len(tokens) == 1 {
+			return convertValue(value, &thing.PolicyID)
+		}
+	case pathDefinition:
+		if len(tokens) == 1 {
+			return convertValue(value, &thing.DefinitionID)
+		}
+	case pathAttributes:
+		return setValue(&thing.Attributes, tokens[1:], value)
+	case pathFeatures:
+		if len(tokens) == 1 {
+			return convertValue(value, &thing.Features)
+		}
+		if thing.Features == nil {
+			thing.Features = make(map[string]*Feature)
+		}
+		if len(tokens) == 2 {
+			var feature *Feature
+			if err := convertValue(value, &feature); err != nil {
+				return err
+			}
+			thing.Features[tokens[1]] = feature
+			return nil
+		}
+		feature := thing.Features[tokens[1]]
+		if feature == nil {
+			feature = &Feature{}
+			thing.Features[tokens[1]] = feature
+		}
+		switch tokens[2] {
+		case pathDefinition:
+			if len(tokens) == 3 {
+				return convertValue(value, &feature.Definition)
+			}
+		case pathProperties:
+			return setValue(&feature.Properties, tokens[3:], value)
+		case pathDesiredProperties:
+			return setValue(&feature.DesiredProperties, tokens[3:], value)
+		}
+	}
+	return fmt.Errorf("path '%s' doesn't refer to a thing's entity", path)
+}
+
+// Delete removes the value within the Thing referenced by the provided Ditto path, the same as the paths of the Thing commands,
+// e.g. '/features/Temp/properties/value'. Nothing is changed if there is no such value.
+// Returns an error if the path doesn't refer to a Thing's entity or refers to the whole Thing.
+func (thing *Thing) Delete(path string) error {
+	tokens, err := thingPathTokens(path)
+	if err != nil {
+		return err
+	}
+	if len(tokens) > 0 {
+		switch tokens[0] {
+		case pathPolicyID:
+			if len(tokens) == 1 {
+				thing.PolicyID = nil
+				return nil
+			}
+		case pathDefinition:
+			if len(tokens) == 1 {
+				thing.DefinitionID = nil
+				return nil
+			}
+		case pathAttributes:
+			deleteValue(&thing.Attributes, tokens[1:])
+			return nil
+		case pathFeatures:
+			if len(tokens) <= 2 {
+				if len(tokens) == 1 {
+					thing.Features = nil
+				} else {
+					delete(thing.Features, tokens[1])
+				}
+				return nil
+			}
+			feature := thing.Features[tokens[1]]
+			switch tokens[2] {
+			case pathDefinition:
+				if len(tokens) == 3 {
+					if feature != nil {
+						feature.Definition = nil
+					}
+					return nil
+				}
+			case pathProperties:
+				if feature != nil {
+					deleteValue(&feature.Properties, tokens[3:])
+				}
+				return nil
+			case pathDesiredProperties:
+				if feature != nil {
+					deleteValue(&feature.DesiredProperties, tokens[3:])
+				}
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("path '%s' doesn't refer to a deletable thing's entity", path)
+}
+
+func thingPathTokens(path string) ([]string, error) {
+	if path == "" || path == "/" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path '%s' must start with '/'", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		if token == "" {
+			return nil, fmt.Errorf("path '%s' has an empty segment", path)
+		}
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+func lookupValue(values map[string]interface{}, tokens []string) (interface{}, bool) {
+	var value interface{} = values
+	for _, token := range tokens {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[token]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func setValue(values *map[string]interface{}, tokens []string, value interface{}) error {
+	if len(tokens) == 0 {
+		return convertValue(value, values)
+	}
+	if *values == nil {
+		*values = make(map[string]interface{})
+	}
+	object := *values
+	for _, token := range tokens[:len(tokens)-1] {
+		nested, ok := object[token].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			object[token] = nested
+		}
+		object = nested
+	}
+	object[tokens[len(tokens)-1]] = value
+	return nil
+}
+
+func deleteValue(values *map[string]interface{}, tokens []string) {
+	if len(tokens) == 0 {
+		*values = nil
+		return
+	}
+	parent, ok := lookupValue(*values, tokens[:len(tokens)-1])
+	if object, isObject := parent.(map[string]interface{}); ok && isObject {
+		delete(object, tokens[len(tokens)-1])
+	}
+}
+
+// convertValue sets the provided value to the target pointer if it's of the target's type
+// or converts it via its JSON representation otherwise.
+func convertValue(value interface{}, target interface{}) error {
+	switch t := target.(type) {
+	case **Thing:
+		if v, ok := value.(*Thing); ok {
+			*t = v
+			return nil
+		}
+	case **Feature:
+		if v, ok := value.(*Feature); ok {
+			*t = v
+			return nil
+		}
+	case *map[string]*Feature:
+		if v, ok := value.(map[string]*Feature); ok {
+			*t = v
+			return nil
+		}
+	case **NamespacedID:
+		if v, ok := value.(*NamespacedID); ok {
+			*t = v
+			return nil
+		}
+	case **DefinitionID:
+		if v, ok := value.(*DefinitionID); ok {
+			*t = v
+			return nil
+		}
+	case *[]*DefinitionID:
+		if v, ok := value.([]*DefinitionID); ok {
+			*t = v
+			return nil
+		}
+	case *map[string]interface{}:
+		if v, ok := value.(map[string]interface{}); ok {
+			*t = v
+			return nil
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
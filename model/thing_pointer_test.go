@@ -0,0 +1,230 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestThingGet(t *testing.T) {
+	thing := newTestThing().WithAttribute("a/b", "escaped")
+
+	tests := map[string]struct {
+		path   string
+		want   interface{}
+		wantOk bool
+	}{
+		"test_thing":                  {path: "/", want: thing, wantOk: true},
+		"test_policy_id":              {path: "/policyId", want: thing.PolicyID, wantOk: true},
+		"test_definition":             {path: "/definition", want: thing.DefinitionID, wantOk: true},
+		"test_attributes":             {path: "/attributes", want: thing.Attributes, wantOk: true},
+		"test_nested_attribute":       {path: "/attributes/location/lat", want: 1.0, wantOk: true},
+		"test_escaped_attribute":      {path: "/attributes/a~1b", want: "escaped", wantOk: true},
+		"test_missing_attribute":      {path: "/attributes/location/lon"},
+		"test_attribute_in_primitive": {path: "/attributes/location/lat/x"},
+		"test_features":               {path: "/features", want: thing.Features, wantOk: true},
+		"test_feature":                {path: "/features/lamp", want: thing.Features["lamp"], wantOk: true},
+		"test_missing_feature":        {path: "/features/Humidity"},
+		"test_feature_definition": {
+			path:   "/features/lamp/definition",
+			want:   thing.Features["lamp"].Definition,
+			wantOk: true,
+		},
+		"test_feature_property":         {path: "/features/lamp/properties/status/on", want: true, wantOk: true},
+		"test_feature_desired_property": {path: "/features/lamp/desiredProperties/status/on", want: false, wantOk: true},
+		"test_invalid_feature_path":     {path: "/features/lamp/other"},
+		"test_invalid_path":             {path: "/other"},
+		"test_relative_path":            {path: "attributes"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, ok := thing.Get(testCase.path)
+			internal.AssertEqual(t, testCase.wantOk, ok)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestThingSet(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		value   interface{}
+		want    func(thing *Thing)
+		wantErr bool
+	}{
+		"test_nested_attribute": {
+			path:  "/attributes/location/lon",
+			value: 2.0,
+			want: func(thing *Thing) {
+				thing.Attributes["location"].(map[string]interface{})["lon"] = 2.0
+			},
+		},
+		"test_new_nested_attribute": {
+			path:  "/attributes/model/version",
+			value: "1",
+			want: func(thing *Thing) {
+				thing.Attributes["model"] = map[string]interface{}{"version": "1"}
+			},
+		},
+		"test_attributes": {
+			path:  "/attributes",
+			value: map[string]interface{}{"a": 1},
+			want: func(thing *Thing) {
+				thing.Attributes = map[string]interface{}{"a": 1}
+			},
+		},
+		"test_policy_id_from_string": {
+			path:  "/policyId",
+			value: "test.namespace:other-policy",
+			want: func(thing *Thing) {
+				thing.WithPolicyIDFrom("test.namespace:other-policy")
+			},
+		},
+		"test_invalid_policy_id": {
+			path:    "/policyId",
+			value:   "invalid",
+			wantErr: true,
+		},
+		"test_definition": {
+			path:  "/definition",
+			value: NewDefinitionIDFrom("test.namespace:model:2.0.0"),
+			want: func(thing *Thing) {
+				thing.WithDefinitionFrom("test.namespace:model:2.0.0")
+			},
+		},
+		"test_feature_from_json": {
+			path:  "/features/Humidity",
+			value: map[string]interface{}{"properties": map[string]interface{}{"value": 50.0}},
+			want: func(thing *Thing) {
+				thing.WithFeature("Humidity", (&Feature{}).WithProperty("value", 50.0))
+			},
+		},
+		"test_property_of_missing_feature": {
+			path:  "/features/Humidity/properties/value",
+			value: 50.0,
+			want: func(thing *Thing) {
+				thing.WithFeature("Humidity", (&Feature{}).WithProperty("value", 50.0))
+			},
+		},
+		"test_feature_definition": {
+			path:  "/features/lamp/definition",
+			value: []interface{}{"test.namespace:lamp:2.0.0"},
+			want: func(thing *Thing) {
+				thing.Features["lamp"].WithDefinitionFrom("test.namespace:lamp:2.0.0")
+			},
+		},
+		"test_feature_desired_property": {
+			path:  "/features/lamp/desiredProperties/status/on",
+			value: true,
+			want: func(thing *Thing) {
+				thing.Features["lamp"].WithDesiredProperty("status", map[string]interface{}{"on": true})
+			},
+		},
+		"test_thing": {
+			path:  "/",
+			value: map[string]interface{}{"thingId": "test.namespace:other"},
+			want: func(thing *Thing) {
+				*thing = Thing{ID: NewNamespacedIDFrom("test.namespace:other")}
+			},
+		},
+		"test_invalid_path": {
+			path:    "/features/lamp/other",
+			value:   1,
+			wantErr: true,
+		},
+		"test_empty_segment": {
+			path:    "/attributes//a",
+			value:   1,
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := newTestThing()
+			err := got.Set(testCase.path, testCase.value)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				return
+			}
+			internal.AssertNil(t, err)
+			want := newTestThing()
+			testCase.want(want)
+			internal.AssertEqual(t, want, got)
+		})
+	}
+}
+
+func TestThingDelete(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		want    func(thing *Thing)
+		wantErr bool
+	}{
+		"test_nested_attribute": {
+			path: "/attributes/location/lat",
+			want: func(thing *Thing) {
+				delete(thing.Attributes["location"].(map[string]interface{}), "lat")
+			},
+		},
+		"test_missing_attribute": {
+			path: "/attributes/missing/value",
+			want: func(thing *Thing) {},
+		},
+		"test_feature": {
+			path: "/features/lamp",
+			want: func(thing *Thing) {
+				thing.Features = map[string]*Feature{}
+			},
+		},
+		"test_feature_property": {
+			path: "/features/lamp/properties/status",
+			want: func(thing *Thing) {
+				thing.Features["lamp"].Properties = map[string]interface{}{}
+			},
+		},
+		"test_feature_desired_properties": {
+			path: "/features/lamp/desiredProperties",
+			want: func(thing *Thing) {
+				thing.Features["lamp"].DesiredProperties = nil
+			},
+		},
+		"test_policy_id": {
+			path: "/policyId",
+			want: func(thing *Thing) {
+				thing.PolicyID = nil
+			},
+		},
+		"test_thing": {
+			path:    "/",
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := newTestThing()
+			err := got.Delete(testCase.path)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				return
+			}
+			internal.AssertNil(t, err)
+			want := newTestThing()
+			testCase.want(want)
+			internal.AssertEqual(t, want, got)
+		})
+	}
+}
@@ -12,11 +12,27 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
 )
 
+// newTestThing provides a fully populated Thing shared by the model tests. Each call returns a new instance
+// so that the tests are free to modify it.
+func newTestThing() *Thing {
+	return (&Thing{}).
+		WithIDFrom("test.namespace:test-name").
+		WithPolicyIDFrom("test.namespace:test-policy").
+		WithDefinitionFrom("test.namespace:model:1.0.0").
+		WithAttribute("location", map[string]interface{}{"lat": 1.0, "tags": []interface{}{"a", map[string]interface{}{"b": 2}}}).
+		WithFeature("lamp", (&Feature{}).
+			WithDefinitionFrom("test.namespace:lamp:1.0.0").
+			WithProperty("status", map[string]interface{}{"on": true}).
+			WithDesiredProperty("status", map[string]interface{}{"on": false})).
+		WithPolicy(NewPolicy().WithEntry("owner", Subject("nginx:ditto", "generated"), Grant("thing:/", PermissionRead)))
+}
+
 func TestThingWithID(t *testing.T) {
 	arg := &NamespacedID{
 		Namespace: "test.namespace",
@@ -72,6 +88,15 @@ func TestThingWithPolicyID(t *testing.T) {
 	internal.AssertEqual(t, arg, got.PolicyID)
 }
 
+func TestThingWithPolicy(t *testing.T) {
+	arg := NewPolicy().WithEntry("owner", Subject("nginx:ditto", "generated"))
+
+	testThing := &Thing{}
+
+	got := testThing.WithPolicy(arg)
+	internal.AssertEqual(t, arg, got.Policy)
+}
+
 func TestThingPolicyIDFrom(t *testing.T) {
 	arg := "test.namespace:test-name"
 
@@ -213,3 +238,40 @@ func TestThingWithFeature(t *testing.T) {
 		})
 	}
 }
+
+func TestThingSpecialFieldsJSON(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want *Thing
+	}{
+		"test_with_special_fields": {
+			data: `{"thingId":"test.namespace:test-name","_revision":3,"_modified":"2022-01-02T00:00:00Z",` +
+				`"_created":"2022-01-01T00:00:00Z","_namespace":"test.namespace"}`,
+			want: &Thing{
+				ID:        NewNamespacedID("test.namespace", "test-name"),
+				Revision:  3,
+				Timestamp: "2022-01-02T00:00:00Z",
+				Created:   "2022-01-01T00:00:00Z",
+				Namespace: "test.namespace",
+			},
+		},
+		"test_without_special_fields": {
+			data: `{"thingId":"test.namespace:test-name"}`,
+			want: &Thing{
+				ID: NewNamespacedID("test.namespace", "test-name"),
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := &Thing{}
+			internal.AssertNil(t, json.Unmarshal([]byte(testCase.data), got))
+			internal.AssertEqual(t, testCase.want, got)
+
+			data, err := json.Marshal(got)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.data, string(data))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError represents a violation of the Ditto's requirements by a Thing's field,
+// referenced by its JSON pointer, e.g. '/attributes/location'.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+// Error provides the string representation of the ValidationError.
+func (err *ValidationError) Error() string {
+	if err.Field == "" {
+		return fmt.Sprintf("invalid thing: %s", err.Reason)
+	}
+	return fmt.Sprintf("invalid thing field '%s': %s", err.Field, err.Reason)
+}
+
+// ValidateThing unmarshals the provided Thing's JSON representation and validates the result via Thing.Validate.
+// Unlike json.Unmarshal, which silently produces a partially populated Thing, a *ValidationError referencing
+// the violating field is returned on a missing or invalid 'thingId', a malformed 'policyId' or 'definitionId',
+// as well as on illegal attribute, feature or property keys.
+func ValidateThing(data []byte) (*Thing, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, &ValidationError{Reason: err.Error()}
+	}
+	thing := &Thing{}
+	for _, field := range []struct {
+		name   string
+		target interface{}
+	}{
+		{"thingId", &thing.ID},
+		{"policyId", &thing.PolicyID},
+		{"definitionId", &thing.DefinitionID},
+		{"attributes", &thing.Attributes},
+		{"features", &thing.Features},
+	} {
+		if value, ok := fields[field.name]; ok {
+			if err := json.Unmarshal(value, field.target); err != nil {
+				return nil, &ValidationError{Field: "/" + field.name, Reason: err.Error()}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, thing); err != nil {
+		return nil, &ValidationError{Reason: err.Error()}
+	}
+	if err := thing.Validate(); err != nil {
+		return nil, err
+	}
+	return thing, nil
+}
+
+// Validate checks that the Thing has an ID and that its attribute keys, feature IDs, feature definitions
+// and property keys comply with the Ditto's requirements, i.e. the keys are not empty and contain
+// neither slashes nor control characters. Returns a *ValidationError on the first violation found,
+// the keys and feature IDs being checked in lexical order.
+func (thing *Thing) Validate() error {
+	if thing.ID == nil {
+		return &ValidationError{Field: "/thingId", Reason: "missing thing ID"}
+	}
+	if err := validateKeys("/attributes", thing.Attributes); err != nil {
+		return err
+	}
+	featureIDs := make([]string, 0, len(thing.Features))
+	for featureID := range thing.Features {
+		featureIDs = append(featureIDs, featureID)
+	}
+	sort.Strings(featureIDs)
+	for _, featureID := range featureIDs {
+		feature := thing.Features[featureID]
+		path := "/features/" + featureID
+		if reason := invalidKeyReason(featureID); reason != "" {
+			return &ValidationError{Field: path, Reason: reason}
+		}
+		if feature == nil {
+			continue
+		}
+		for _, definitionID := range feature.Definition {
+			if definitionID == nil {
+				return &ValidationError{Field: path + "/definition", Reason: "invalid definition ID"}
+			}
+		}
+		if err := validateKeys(path+"/properties", feature.Properties); err != nil {
+			return err
+		}
+		if err := validateKeys(path+"/desiredProperties", feature.DesiredProperties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateKeys(path string, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		if reason := invalidKeyReason(key); reason != "" {
+			return &ValidationError{Field: path + "/" + key, Reason: reason}
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			if err := validateKeys(path+"/"+key, nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func invalidKeyReason(key string) string {
+	if key == "" {
+		return "empty key"
+	}
+	if strings.Contains(key, "/") {
+		return fmt.Sprintf("key '%s' contains a slash", key)
+	}
+	for _, r := range key {
+		if r < 0x20 || r == 0x7F {
+			return fmt.Sprintf("key '%s' contains a control character", key)
+		}
+	}
+	return ""
+}
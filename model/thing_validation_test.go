@@ -0,0 +1,152 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestValidateThing(t *testing.T) {
+	validData, err := json.Marshal(newTestThing())
+	internal.AssertNil(t, err)
+
+	tests := map[string]struct {
+		data      string
+		wantField string
+		wantErr   bool
+	}{
+		"test_valid_thing": {
+			data: string(validData),
+		},
+		"test_not_json_object": {
+			data:    `[]`,
+			wantErr: true,
+		},
+		"test_missing_thing_id": {
+			data:      `{"attributes":{"a":1}}`,
+			wantField: "/thingId",
+			wantErr:   true,
+		},
+		"test_invalid_thing_id": {
+			data:      `{"thingId":"invalid"}`,
+			wantField: "/thingId",
+			wantErr:   true,
+		},
+		"test_invalid_policy_id": {
+			data:      `{"thingId":"test.namespace:test-name","policyId":"invalid"}`,
+			wantField: "/policyId",
+			wantErr:   true,
+		},
+		"test_invalid_definition_id": {
+			data:      `{"thingId":"test.namespace:test-name","definitionId":"invalid"}`,
+			wantField: "/definitionId",
+			wantErr:   true,
+		},
+		"test_invalid_attributes_type": {
+			data:      `{"thingId":"test.namespace:test-name","attributes":1}`,
+			wantField: "/attributes",
+			wantErr:   true,
+		},
+		"test_attribute_key_with_slash": {
+			data:      `{"thingId":"test.namespace:test-name","attributes":{"a/b":1}}`,
+			wantField: "/attributes/a/b",
+			wantErr:   true,
+		},
+		"test_nested_empty_attribute_key": {
+			data:      `{"thingId":"test.namespace:test-name","attributes":{"location":{"":1}}}`,
+			wantField: "/attributes/location/",
+			wantErr:   true,
+		},
+		"test_attribute_key_with_control_character": {
+			data:      `{"thingId":"test.namespace:test-name","attributes":{"a\u0001":1}}`,
+			wantField: "/attributes/a\u0001",
+			wantErr:   true,
+		},
+		"test_invalid_feature_definition": {
+			data:      `{"thingId":"test.namespace:test-name","features":{"lamp":{"definition":["invalid"]}}}`,
+			wantField: "/features",
+			wantErr:   true,
+		},
+		"test_feature_property_key_with_slash": {
+			data:      `{"thingId":"test.namespace:test-name","features":{"lamp":{"properties":{"a/b":1}}}}`,
+			wantField: "/features/lamp/properties/a/b",
+			wantErr:   true,
+		},
+		"test_feature_desired_property_empty_key": {
+			data:      `{"thingId":"test.namespace:test-name","features":{"lamp":{"desiredProperties":{"":1}}}}`,
+			wantField: "/features/lamp/desiredProperties/",
+			wantErr:   true,
+		},
+		"test_multiple_invalid_attribute_keys": {
+			data:      `{"thingId":"test.namespace:test-name","attributes":{"z/z":1,"b/b":1,"m/m":1,"a":{"y/y":1,"c/c":1}}}`,
+			wantField: "/attributes/a/c/c",
+			wantErr:   true,
+		},
+		"test_multiple_invalid_features": {
+			data:      `{"thingId":"test.namespace:test-name","features":{"z":{"properties":{"a/b":1}},"b/c":{},"m":{"properties":{"":1}}}}`,
+			wantField: "/features/b/c",
+			wantErr:   true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ValidateThing([]byte(testCase.data))
+			if !testCase.wantErr {
+				internal.AssertNil(t, err)
+				internal.AssertNotNil(t, got)
+				return
+			}
+			internal.AssertNil(t, got)
+			validationErr, ok := err.(*ValidationError)
+			internal.AssertTrue(t, ok)
+			internal.AssertEqual(t, testCase.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestThingValidate(t *testing.T) {
+	tests := map[string]struct {
+		thing   *Thing
+		wantErr bool
+	}{
+		"test_valid_thing": {
+			thing: newTestThing(),
+		},
+		"test_without_id": {
+			thing:   newTestThing().WithID(nil),
+			wantErr: true,
+		},
+		"test_empty_feature_id": {
+			thing:   newTestThing().WithFeature("", &Feature{}),
+			wantErr: true,
+		},
+		"test_nil_feature_definition": {
+			thing:   newTestThing().WithFeature("lamp", (&Feature{}).WithDefinitionFrom("invalid")),
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.thing.Validate()
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// CBOR (RFC 8949) major types.
+const (
+	cborUnsigned byte = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const (
+	cborFalse      = cborSimple | 20
+	cborTrue       = cborSimple | 21
+	cborNull       = cborSimple | 22
+	cborUndefined  = cborSimple | 23
+	cborFloat16    = cborSimple | 25
+	cborFloat32    = cborSimple | 26
+	cborFloat64    = cborSimple | 27
+	cborBreak      = cborSimple | 31
+	cborIndefinite = 31
+
+	cborMaxDepth = 1000
+)
+
+var errCBORUnexpectedEnd = errors.New("unexpected end of CBOR data")
+
+// MarshalCBOR provides the CBOR (RFC 8949) representation of the provided value, e.g. an Envelope.
+// The value is encoded as the data model of its JSON representation, i.e. any JSON marshaling customizations
+// are applied and the result could be decoded into the same types via UnmarshalCBOR.
+func MarshalCBOR(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeCBOR(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeCBOR appends the CBOR (RFC 8949) representation of the provided value, the same as provided by MarshalCBOR,
+// to the provided buffer. The intermediate JSON representation is encoded into a pooled buffer.
+// On error, the buffer is left unchanged.
+func EncodeCBOR(buf *bytes.Buffer, v interface{}) error {
+	data := GetBuffer()
+	defer PutBuffer(data)
+
+	var err error
+	if env, ok := v.(*Envelope); ok && env != nil {
+		err = env.EncodeJSON(data)
+	} else {
+		err = json.NewEncoder(data).Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(data)
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+
+	start := buf.Len()
+	if err := encodeCBOR(buf, value); err != nil {
+		buf.Truncate(start)
+		return err
+	}
+	return nil
+}
+
+// UnmarshalCBOR decodes the provided CBOR (RFC 8949) data into the provided target, e.g. an Envelope.
+// The decoded data is applied to the target as its JSON representation, so all JSON unmarshaling customizations are respected.
+// CBOR byte strings are represented as base64 encoded strings and the map keys are expected to be text strings.
+func UnmarshalCBOR(data []byte, v interface{}) error {
+	jsonData, err := cborToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonData, v)
+}
+
+// cborToJSON provides the JSON representation of the provided CBOR data.
+func cborToJSON(data []byte) ([]byte, error) {
+	dec := &cborDecoder{data: data}
+	value, err := dec.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if dec.pos != len(data) {
+		return nil, errors.New("unexpected trailing CBOR data")
+	}
+	return json.Marshal(value)
+}
+
+// IsCBOR reports whether the provided data looks like a CBOR encoded map (e.g. an Envelope) rather than a JSON object.
+func IsCBOR(data []byte) bool {
+	return len(data) > 0 && data[0]&0xe0 == cborMap
+}
+
+func encodeCBOR(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(cborNull)
+	case bool:
+		if v {
+			buf.WriteByte(cborTrue)
+		} else {
+			buf.WriteByte(cborFalse)
+		}
+	case string:
+		writeCBORHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case json.Number:
+		return encodeCBORNumber(buf, v)
+	case []interface{}:
+		writeCBORHead(buf, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if err := encodeCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		writeCBORHead(buf, cborMap, uint64(len(v)))
+		for _, key := range keys {
+			writeCBORHead(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := encodeCBOR(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported CBOR value type %T", value)
+	}
+	return nil
+}
+
+func encodeCBORNumber(buf *bytes.Buffer, number json.Number) error {
+	if i, err := number.Int64(); err == nil {
+		if i >= 0 {
+			writeCBORHead(buf, cborUnsigned, uint64(i))
+		} else {
+			writeCBORHead(buf, cborNegative, uint64(-(i + 1)))
+		}
+		return nil
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return err
+	}
+	if f32 := float32(f); float64(f32) == f {
+		buf.WriteByte(cborFloat32)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(f32))
+		buf.Write(b[:])
+		return nil
+	}
+	buf.WriteByte(cborFloat64)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(f))
+	buf.Write(b[:])
+	return nil
+}
+
+func writeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 24)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		buf.Write(b[:])
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf.Write(b[:])
+	default:
+		buf.WriteByte(major | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		buf.Write(b[:])
+	}
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (dec *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("maximum CBOR nesting depth exceeded")
+	}
+	if dec.pos >= len(dec.data) {
+		return nil, errCBORUnexpectedEnd
+	}
+	initial := dec.data[dec.pos]
+	dec.pos++
+	major, info := initial&0xe0, initial&0x1f
+
+	if major == cborSimple {
+		return dec.decodeSimple(initial)
+	}
+	if info == cborIndefinite {
+		return dec.decodeIndefinite(major, depth)
+	}
+	n, err := dec.readArgument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		if n > math.MaxInt64 {
+			return json.Number(fmt.Sprintf("%d", n)), nil
+		}
+		return int64(n), nil
+	case cborNegative:
+		if n > math.MaxInt64 {
+			return json.Number(fmt.Sprintf("-%d", n)), nil
+		}
+		return -int64(n) - 1, nil
+	case cborBytes:
+		return dec.readBytes(n)
+	case cborText:
+		return dec.readText(n)
+	case cborArray:
+		if n > uint64(len(dec.data)-dec.pos) {
+			return nil, errCBORUnexpectedEnd
+		}
+		res := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := dec.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, item)
+		}
+		return res, nil
+	case cborMap:
+		if n > uint64(len(dec.data)-dec.pos) {
+			return nil, errCBORUnexpectedEnd
+		}
+		res := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			if err := dec.decodeMapEntry(res, depth); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default: // tags are skipped and only their content is decoded
+		return dec.decode(depth + 1)
+	}
+}
+
+func (dec *cborDecoder) decodeIndefinite(major byte, depth int) (interface{}, error) {
+	switch major {
+	case cborBytes, cborText:
+		var buf bytes.Buffer
+		for !dec.isBreak() {
+			chunk, err := dec.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch c := chunk.(type) {
+			case []byte:
+				if major != cborBytes {
+					return nil, errors.New("invalid CBOR indefinite length string chunk")
+				}
+				buf.Write(c)
+			case string:
+				if major != cborText {
+					return nil, errors.New("invalid CBOR indefinite length string chunk")
+				}
+				buf.WriteString(c)
+			default:
+				return nil, errors.New("invalid CBOR indefinite length string chunk")
+			}
+		}
+		if major == cborBytes {
+			return buf.Bytes(), nil
+		}
+		return buf.String(), nil
+	case cborArray:
+		res := []interface{}{}
+		for !dec.isBreak() {
+			item, err := dec.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, item)
+		}
+		return res, nil
+	case cborMap:
+		res := map[string]interface{}{}
+		for !dec.isBreak() {
+			if err := dec.decodeMapEntry(res, depth); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("invalid CBOR indefinite length for major type %d", major>>5)
+	}
+}
+
+func (dec *cborDecoder) decodeMapEntry(res map[string]interface{}, depth int) error {
+	key, err := dec.decode(depth + 1)
+	if err != nil {
+		return err
+	}
+	name, ok := key.(string)
+	if !ok {
+		return fmt.Errorf("unsupported CBOR map key type %T", key)
+	}
+	value, err := dec.decode(depth + 1)
+	if err != nil {
+		return err
+	}
+	res[name] = value
+	return nil
+}
+
+func (dec *cborDecoder) decodeSimple(initial byte) (interface{}, error) {
+	switch initial {
+	case cborFalse:
+		return false, nil
+	case cborTrue:
+		return true, nil
+	case cborNull, cborUndefined:
+		return nil, nil
+	case cborFloat16:
+		b, err := dec.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return float16ToFloat64(binary.BigEndian.Uint16(b))
+	case cborFloat32:
+		b, err := dec.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return checkFinite(float64(math.Float32frombits(binary.BigEndian.Uint32(b))))
+	case cborFloat64:
+		b, err := dec.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return checkFinite(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	default:
+		return nil, fmt.Errorf("unsupported CBOR simple value 0x%x", initial)
+	}
+}
+
+func (dec *cborDecoder) isBreak() bool {
+	if dec.pos < len(dec.data) && dec.data[dec.pos] == cborBreak {
+		dec.pos++
+		return true
+	}
+	return false
+}
+
+func (dec *cborDecoder) readArgument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		b, err := dec.read(1)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(b[0]), nil
+	case info == 25:
+		b, err := dec.read(2)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case info == 26:
+		b, err := dec.read(4)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	case info == 27:
+		b, err := dec.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b), nil
+	default:
+		return 0, fmt.Errorf("invalid CBOR additional information %d", info)
+	}
+}
+
+func (dec *cborDecoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(len(dec.data)-dec.pos) {
+		return nil, errCBORUnexpectedEnd
+	}
+	b, err := dec.read(int(n))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func (dec *cborDecoder) readText(n uint64) (string, error) {
+	if n > uint64(len(dec.data)-dec.pos) {
+		return "", errCBORUnexpectedEnd
+	}
+	b, err := dec.read(int(n))
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("invalid UTF-8 CBOR text string")
+	}
+	return string(b), nil
+}
+
+func (dec *cborDecoder) read(n int) ([]byte, error) {
+	if n > len(dec.data)-dec.pos {
+		return nil, errCBORUnexpectedEnd
+	}
+	b := dec.data[dec.pos : dec.pos+n]
+	dec.pos += n
+	return b, nil
+}
+
+func float16ToFloat64(h uint16) (interface{}, error) {
+	exp := (h >> 10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		return nil, errors.New("unsupported non-finite CBOR float")
+	default:
+		f = math.Ldexp(mant+1024, int(exp)-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f, nil
+}
+
+func checkFinite(f float64) (interface{}, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, errors.New("unsupported non-finite CBOR float")
+	}
+	return f, nil
+}
+
+// cborCodec represents the CBOR encoded values as base64 encoded strings within the JSON Envelope.
+// Values provided as []byte are considered already CBOR encoded.
+type cborCodec struct{}
+
+func (cborCodec) Marshal(value interface{}) ([]byte, error) {
+	if data, ok := value.([]byte); ok {
+		return json.Marshal(data)
+	}
+	data, err := MarshalCBOR(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+func (cborCodec) Unmarshal(data []byte, target interface{}) error {
+	var encoded []byte
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	return UnmarshalCBOR(encoded, target)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestMarshalCBOR(t *testing.T) {
+	// the expected encodings are taken from the RFC 8949 Appendix A examples
+	tests := map[string]struct {
+		arg  interface{}
+		want string
+	}{
+		"test_zero":            {arg: 0, want: "00"},
+		"test_small_unsigned":  {arg: 23, want: "17"},
+		"test_one_byte":        {arg: 24, want: "1818"},
+		"test_two_bytes":       {arg: 1000, want: "1903e8"},
+		"test_four_bytes":      {arg: 1000000, want: "1a000f4240"},
+		"test_eight_bytes":     {arg: uint64(1000000000000), want: "1b000000e8d4a51000"},
+		"test_negative":        {arg: -1, want: "20"},
+		"test_negative_big":    {arg: -1000, want: "3903e7"},
+		"test_float32":         {arg: 100000.0, want: "1a000186a0"},
+		"test_float_fraction":  {arg: 1.5, want: "fa3fc00000"},
+		"test_float64":         {arg: 1.1, want: "fb3ff199999999999a"},
+		"test_false":           {arg: false, want: "f4"},
+		"test_true":            {arg: true, want: "f5"},
+		"test_null":            {arg: nil, want: "f6"},
+		"test_empty_string":    {arg: "", want: "60"},
+		"test_string":          {arg: "IETF", want: "6449455446"},
+		"test_unicode_string":  {arg: "ü", want: "62c3bc"},
+		"test_empty_array":     {arg: []interface{}{}, want: "80"},
+		"test_array":           {arg: []int{1, 2, 3}, want: "83010203"},
+		"test_nested_array":    {arg: []interface{}{1, []int{2, 3}}, want: "8201820203"},
+		"test_empty_map":       {arg: map[string]interface{}{}, want: "a0"},
+		"test_map_sorted":      {arg: map[string]interface{}{"b": []int{2, 3}, "a": 1}, want: "a26161016162820203"},
+		"test_bytes_as_base64": {arg: []byte{0x01}, want: "6441513d3d"},
+		"test_struct_json_tags": {arg: struct {
+			Key string `json:"key"`
+		}{Key: "a"}, want: "a1636b65796161"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := MarshalCBOR(testCase.arg)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.want, hex.EncodeToString(got))
+		})
+	}
+}
+
+func TestMarshalCBORError(t *testing.T) {
+	_, err := MarshalCBOR(make(chan int))
+	internal.AssertNotNil(t, err)
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	tests := map[string]struct {
+		arg  string
+		want interface{}
+	}{
+		"test_unsigned":            {arg: "1903e8", want: float64(1000)},
+		"test_negative":            {arg: "3903e7", want: float64(-1000)},
+		"test_float16":             {arg: "f93e00", want: 1.5},
+		"test_float16_subnormal":   {arg: "f90001", want: 5.960464477539063e-08},
+		"test_float16_negative":    {arg: "f9c400", want: -4.0},
+		"test_float32":             {arg: "fa47c35000", want: 100000.0},
+		"test_float64":             {arg: "fb3ff199999999999a", want: 1.1},
+		"test_simple_values":       {arg: "83f4f5f6", want: []interface{}{false, true, nil}},
+		"test_undefined":           {arg: "f7", want: nil},
+		"test_string":              {arg: "6449455446", want: "IETF"},
+		"test_bytes":               {arg: "4401020304", want: "AQIDBA=="},
+		"test_map":                 {arg: "a26161016162820203", want: map[string]interface{}{"a": float64(1), "b": []interface{}{float64(2), float64(3)}}},
+		"test_tag":                 {arg: "c074323031332d30332d32315432303a30343a30305a", want: "2013-03-21T20:04:00Z"},
+		"test_indefinite_bytes":    {arg: "5f42010243030405ff", want: "AQIDBAU="},
+		"test_indefinite_string":   {arg: "7f657374726561646d696e67ff", want: "streaming"},
+		"test_indefinite_array":    {arg: "9f018202039f0405ffff", want: []interface{}{float64(1), []interface{}{float64(2), float64(3)}, []interface{}{float64(4), float64(5)}}},
+		"test_indefinite_map":      {arg: "bf61610161629f0203ffff", want: map[string]interface{}{"a": float64(1), "b": []interface{}{float64(2), float64(3)}}},
+		"test_max_unsigned_number": {arg: "1bffffffffffffffff", want: 18446744073709551615.0},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			data, err := hex.DecodeString(testCase.arg)
+			internal.AssertNil(t, err)
+
+			var got interface{}
+			internal.AssertNil(t, UnmarshalCBOR(data, &got))
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestUnmarshalCBORInvalid(t *testing.T) {
+	tests := map[string]string{
+		"test_empty":                    "",
+		"test_truncated_argument":       "19",
+		"test_truncated_string":         "6449",
+		"test_truncated_array":          "8301",
+		"test_truncated_map":            "a161",
+		"test_huge_length":              "5bffffffffffffffff",
+		"test_reserved_information":     "1c",
+		"test_trailing_data":            "0000",
+		"test_non_string_key":           "a10102",
+		"test_invalid_utf8":             "62c328",
+		"test_unsupported_simple_value": "f0",
+		"test_infinity":                 "f97c00",
+		"test_nan":                      "fb7ff8000000000000",
+		"test_mixed_indefinite_chunks":  "5f6161ff",
+		"test_indefinite_integer":       "1f",
+		"test_unterminated_indefinite":  "9f01",
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			data, err := hex.DecodeString(testCase)
+			internal.AssertNil(t, err)
+
+			var got interface{}
+			internal.AssertNotNil(t, UnmarshalCBOR(data, &got))
+		})
+	}
+}
+
+func TestUnmarshalCBORMaxDepth(t *testing.T) {
+	data := make([]byte, cborMaxDepth+2)
+	for i := range data {
+		data[i] = 0x81
+	}
+	var got interface{}
+	internal.AssertNotNil(t, UnmarshalCBOR(data, &got))
+}
+
+func TestCBOREnvelopeRoundTrip(t *testing.T) {
+	msg := &Envelope{
+		Topic:   &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionCommands, Action: ActionModify},
+		Headers: NewHeaders(WithCorrelationID("id"), WithResponseRequired(true), WithRequestedAcks("twin-persisted")),
+		Path:    "/features/meter/properties",
+		Value:   map[string]interface{}{"value": 12.25, "unit": "kWh", "samples": []interface{}{float64(1), float64(2)}},
+		Status:  204,
+	}
+
+	data, err := MarshalCBOR(msg)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, IsCBOR(data))
+
+	jsonData, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+	internal.AssertFalse(t, IsCBOR(jsonData))
+	internal.AssertTrue(t, len(data) < len(jsonData))
+
+	got := &Envelope{}
+	internal.AssertNil(t, UnmarshalCBOR(data, got))
+	internal.AssertEqual(t, msg.Topic, got.Topic)
+	internal.AssertEqual(t, "id", got.Headers.CorrelationID())
+	internal.AssertTrue(t, got.Headers.IsResponseRequired())
+	internal.AssertEqual(t, []string{"twin-persisted"}, got.Headers.RequestedAcks())
+	internal.AssertEqual(t, msg.Path, got.Path)
+	internal.AssertEqual(t, msg.Value, got.Value)
+	internal.AssertEqual(t, msg.Status, got.Status)
+}
+
+func TestCBORCodec(t *testing.T) {
+	msg := &Envelope{
+		Topic:   &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelLive, Criterion: CriterionMessages, Action: "data"},
+		Headers: NewHeaders(WithContentType(ContentTypeCBOR)),
+		Path:    "/outbox/messages/data",
+		Value:   map[string]interface{}{"a": 1},
+	}
+
+	data, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+
+	got := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal(data, got))
+	internal.AssertEqual(t, "oWFhAQ==", got.Value)
+
+	var value map[string]int
+	internal.AssertNil(t, got.ValueAs(&value))
+	internal.AssertEqual(t, map[string]int{"a": 1}, value)
+
+	encoded, err := cborCodec{}.Marshal([]byte{0xa0})
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, `"oA=="`, string(encoded))
+}
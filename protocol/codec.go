@@ -0,0 +1,165 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Content types constants, applicable as 'content-type' header values.
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeText        = "text/plain"
+	ContentTypeOctetStream = "application/octet-stream"
+	ContentTypeCBOR        = "application/cbor"
+	// ContentTypeMergePatch is the content type required for the merge commands.
+	ContentTypeMergePatch = "application/merge-patch+json"
+)
+
+// Codec converts the Envelope values of a specific content type from and to their JSON representation within the Envelope.
+type Codec interface {
+	// Marshal provides the JSON representation of the value to be set as the Envelope's value.
+	Marshal(value interface{}) ([]byte, error)
+	// Unmarshal decodes the JSON representation of the Envelope's value into the target.
+	Unmarshal(data []byte, target interface{}) error
+}
+
+var (
+	codecs     = map[string]Codec{}
+	codecsLock sync.RWMutex
+)
+
+func init() {
+	RegisterCodec(ContentTypeJSON, jsonCodec{})
+	RegisterCodec(ContentTypeText, textCodec{})
+	// the binary values are base64 encoded strings, which is the JSON representation of []byte
+	RegisterCodec(ContentTypeOctetStream, jsonCodec{})
+	RegisterCodec(ContentTypeCBOR, cborCodec{})
+}
+
+// RegisterCodec registers the provided Codec for the provided content type, replacing any previously registered one.
+// The content type parameters (e.g. charset) are ignored.
+func RegisterCodec(contentType string, codec Codec) {
+	codecsLock.Lock()
+	defer codecsLock.Unlock()
+	codecs[mediaType(contentType)] = codec
+}
+
+// CodecFor returns the Codec registered for the provided content type.
+// If there is no such, the JSON Codec is returned.
+func CodecFor(contentType string) Codec {
+	if codec := registeredCodec(contentType); codec != nil {
+		return codec
+	}
+	return jsonCodec{}
+}
+
+// ValueAs decodes the Envelope's value into the provided target using the Codec for the Envelope's content type.
+// Gzip compressed values are decompressed beforehand, up to DefaultMaxDecompressedSize bytes,
+// and raw values (see WithLazyValueDecoding) are decoded directly. Other content encodings are not supported.
+// The numbers are decoded as by json.Unmarshal, see the Decoder's ValueAs for their lossless decoding.
+func (msg *Envelope) ValueAs(target interface{}) error {
+	return (&Decoder{}).ValueAs(msg, target)
+}
+
+// encodedValue provides the Envelope's value as encoded by the Codec registered for the Envelope's content type.
+// Values for content types without a Codec, JSON, raw and compressed values are provided as they are.
+func (msg *Envelope) encodedValue() (interface{}, error) {
+	if msg.Value == nil || (msg.Headers != nil && len(msg.Headers.ContentEncoding()) > 0) {
+		return msg.Value, nil
+	}
+	if _, ok := msg.Value.(json.RawMessage); ok {
+		return msg.Value, nil
+	}
+	codec := registeredCodec(msg.contentType())
+	if codec == nil {
+		return msg.Value, nil
+	}
+	if _, ok := codec.(jsonCodec); ok {
+		return msg.Value, nil
+	}
+	data, err := codec.Marshal(msg.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+func (msg *Envelope) contentType() string {
+	if msg.Headers == nil {
+		return ""
+	}
+	contentType, _ := msg.Headers.Get(HeaderContentType).(string)
+	return contentType
+}
+
+func registeredCodec(contentType string) Codec {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+	return codecs[mediaType(contentType)]
+}
+
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Unmarshal(data []byte, target interface{}) error {
+	return json.Unmarshal(data, target)
+}
+
+// textCodec represents the text values as JSON strings, supporting string and []byte values and targets.
+// Other values are JSON encoded as they are.
+type textCodec struct{}
+
+func (textCodec) Marshal(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return json.Marshal(v)
+	case []byte:
+		return json.Marshal(string(v))
+	case fmt.Stringer:
+		return json.Marshal(v.String())
+	default:
+		return json.Marshal(value)
+	}
+}
+
+func (textCodec) Unmarshal(data []byte, target interface{}) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	switch t := target.(type) {
+	case *string:
+		*t = text
+	case *[]byte:
+		*t = []byte(text)
+	case *interface{}:
+		*t = text
+	default:
+		return fmt.Errorf("unsupported text target type %T", target)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+// upperCodec is a test codec representing string values as upper-cased JSON strings.
+type upperCodec struct{}
+
+func (upperCodec) Marshal(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("string expected")
+	}
+	return json.Marshal(strings.ToUpper(s))
+}
+
+func (upperCodec) Unmarshal(data []byte, target interface{}) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*(target.(*string)) = strings.ToLower(s)
+	return nil
+}
+
+func newCodecTestEnvelope(contentType string, value interface{}) *Envelope {
+	msg := &Envelope{
+		Topic: &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelLive, Criterion: CriterionMessages, Action: "subject"},
+		Path:  "/inbox/messages/subject",
+		Value: value,
+	}
+	if len(contentType) > 0 {
+		msg.Headers = NewHeaders(WithContentType(contentType))
+	}
+	return msg
+}
+
+func TestCodecFor(t *testing.T) {
+	tests := map[string]struct {
+		arg  string
+		want Codec
+	}{
+		"test_json": {
+			arg:  ContentTypeJSON,
+			want: jsonCodec{},
+		},
+		"test_text_with_parameters": {
+			arg:  "Text/Plain; charset=utf-8",
+			want: textCodec{},
+		},
+		"test_octet_stream": {
+			arg:  ContentTypeOctetStream,
+			want: jsonCodec{},
+		},
+		"test_unknown": {
+			arg:  "application/vnd.unknown",
+			want: jsonCodec{},
+		},
+		"test_empty": {
+			arg:  "",
+			want: jsonCodec{},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, CodecFor(testCase.arg))
+		})
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	const contentType = "application/vnd.upper"
+	defer func() {
+		codecsLock.Lock()
+		delete(codecs, contentType)
+		codecsLock.Unlock()
+	}()
+
+	RegisterCodec(contentType, upperCodec{})
+	internal.AssertEqual(t, upperCodec{}, CodecFor(contentType+"; version=1"))
+
+	msg := newCodecTestEnvelope(contentType, "value")
+	data, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, strings.Contains(string(data), `"value":"VALUE"`))
+
+	got := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal(data, got))
+	var value string
+	internal.AssertNil(t, got.ValueAs(&value))
+	internal.AssertEqual(t, "value", value)
+
+	msg.Value = 42
+	_, err = json.Marshal(msg)
+	internal.AssertNotNil(t, err)
+}
+
+func TestTextCodec(t *testing.T) {
+	t.Run("TestTextCodecBytes", func(t *testing.T) {
+		msg := newCodecTestEnvelope(ContentTypeText, []byte("hello"))
+		data, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertTrue(t, strings.Contains(string(data), `"value":"hello"`))
+
+		got := &Envelope{}
+		internal.AssertNil(t, json.Unmarshal(data, got))
+		var value []byte
+		internal.AssertNil(t, got.ValueAs(&value))
+		internal.AssertEqual(t, []byte("hello"), value)
+	})
+
+	t.Run("TestTextCodecNonString", func(t *testing.T) {
+		data, err := textCodec{}.Marshal(map[string]interface{}{"temperature": 42})
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"temperature":42}`, string(data))
+	})
+
+	t.Run("TestTextCodecUnsupported", func(t *testing.T) {
+		codec := textCodec{}
+		var target int
+		internal.AssertNotNil(t, codec.Unmarshal([]byte(`"42"`), &target))
+		internal.AssertNotNil(t, codec.Unmarshal([]byte(`42`), new(string)))
+	})
+}
+
+func TestOctetStreamCodec(t *testing.T) {
+	msg := newCodecTestEnvelope(ContentTypeOctetStream, []byte{0x01, 0x02})
+	data, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, strings.Contains(string(data), `"value":"AQI="`))
+
+	got := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal(data, got))
+	var value []byte
+	internal.AssertNil(t, got.ValueAs(&value))
+	internal.AssertEqual(t, []byte{0x01, 0x02}, value)
+}
+
+func TestEnvelopeValueAsJSON(t *testing.T) {
+	msg := newCodecTestEnvelope("", map[string]interface{}{"temperature": 23.5})
+
+	var value struct {
+		Temperature float64 `json:"temperature"`
+	}
+	internal.AssertNil(t, msg.ValueAs(&value))
+	internal.AssertEqual(t, 23.5, value.Temperature)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+const (
+	// ContentEncodingGzip is the 'content-encoding' header value of the Envelopes with gzip compressed values.
+	ContentEncodingGzip = "gzip"
+
+	// DefaultMaxDecompressedSize is the maximum size in bytes of a decompressed value, if no other is provided.
+	DefaultMaxDecompressedSize = 16 * 1024 * 1024
+)
+
+// CompressValue provides a copy of the Envelope with its value gzip compressed, if the JSON representation of the value
+// is at least threshold bytes long. The compressed value is set as a base64 encoded string and the 'content-encoding'
+// header is set to 'gzip'. The Envelope is returned as it is if its value is smaller or already encoded.
+func CompressValue(msg *Envelope, threshold int) (*Envelope, error) {
+	if msg.Value == nil || (msg.Headers != nil && len(msg.Headers.ContentEncoding()) > 0) {
+		return msg, nil
+	}
+	value, err := msg.encodedValue()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < threshold {
+		return msg, nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	compressed := *msg
+	compressed.Headers = NewHeadersFrom(msg.Headers, WithContentEncoding(ContentEncodingGzip))
+	compressed.Value = buf.Bytes()
+	return &compressed, nil
+}
+
+// DecompressValue provides a copy of the Envelope with its gzip compressed value decompressed,
+// if its 'content-encoding' header is set to 'gzip'. The 'content-encoding' header is removed from the copy.
+// An error is returned if the decompressed value exceeds maxSize bytes, DefaultMaxDecompressedSize if maxSize is 0 or less.
+// The decompressed value is decoded as by the zero Decoder, see Decoder's DecompressValue.
+// The Envelope is returned as it is if its value is not compressed or is encoded with another content encoding.
+// Note that Ditto itself does not decode compressed values, i.e. they are only meaningful between peers that both
+// compress and decompress them.
+func DecompressValue(msg *Envelope, maxSize int) (*Envelope, error) {
+	return (&Decoder{}).DecompressValue(msg, maxSize)
+}
+
+// DecompressValue provides a copy of the Envelope with its gzip compressed value decompressed, the same way as
+// the package's DecompressValue does, with the decompressed value decoded as configured for the Decoder,
+// e.g. kept raw if it's configured via WithLazyValueDecoding or with its numbers decoded as json.Number
+// if it's configured via WithPreciseNumberDecoding.
+func (dec *Decoder) DecompressValue(msg *Envelope, maxSize int) (*Envelope, error) {
+	if msg.Headers == nil || msg.Headers.ContentEncoding() != ContentEncodingGzip {
+		return msg, nil
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	var compressed []byte
+	switch value := msg.Value.(type) {
+	case []byte:
+		compressed = value
+	case string:
+		var err error
+		if compressed, err = base64.StdEncoding.DecodeString(value); err != nil {
+			return nil, err
+		}
+	case json.RawMessage:
+		if err := json.Unmarshal(value, &compressed); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("base64 encoded compressed value expected")
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("decompressed value exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	var value interface{} = json.RawMessage(data)
+	if !dec.lazyValueDecoding {
+		if err := model.DecodeJSON(data, &value, dec.preciseNumberDecoding); err != nil {
+			return nil, err
+		}
+	}
+
+	decompressed := *msg
+	decompressed.Headers = NewHeadersFrom(msg.Headers)
+	decompressed.Headers.Del(HeaderContentEncoding)
+	decompressed.Value = value
+	return &decompressed, nil
+}
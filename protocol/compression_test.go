@@ -0,0 +1,198 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func newCompressionTestEnvelope(value interface{}, headerOpts ...HeaderOpt) *Envelope {
+	return &Envelope{
+		Topic:   &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionCommands, Action: ActionModify},
+		Headers: NewHeaders(headerOpts...),
+		Path:    "/attributes",
+		Value:   value,
+	}
+}
+
+func TestCompressValue(t *testing.T) {
+	value := map[string]interface{}{"description": strings.Repeat("verbose thing state ", 50)}
+
+	t.Run("TestCompressValueRoundTrip", func(t *testing.T) {
+		msg := newCompressionTestEnvelope(value, WithCorrelationID("id"))
+
+		compressed, err := CompressValue(msg, 100)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, ContentEncodingGzip, compressed.Headers.ContentEncoding())
+		internal.AssertEqual(t, "", msg.Headers.ContentEncoding())
+		internal.AssertEqual(t, value, msg.Value)
+
+		data, err := json.Marshal(compressed)
+		internal.AssertNil(t, err)
+		plain, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertTrue(t, len(data) < len(plain))
+
+		received := &Envelope{}
+		internal.AssertNil(t, json.Unmarshal(data, received))
+
+		var got map[string]interface{}
+		internal.AssertNil(t, received.ValueAs(&got))
+		internal.AssertEqual(t, value, got)
+
+		decompressed, err := DecompressValue(received, 0)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, value, decompressed.Value)
+		internal.AssertEqual(t, "", decompressed.Headers.ContentEncoding())
+		internal.AssertEqual(t, "id", decompressed.Headers.CorrelationID())
+		internal.AssertEqual(t, ContentEncodingGzip, received.Headers.ContentEncoding())
+	})
+
+	t.Run("TestCompressValueLazyRoundTrip", func(t *testing.T) {
+		decoder := NewDecoder(WithLazyValueDecoding(true))
+
+		compressed, err := CompressValue(newCompressionTestEnvelope(value), 100)
+		internal.AssertNil(t, err)
+		data, err := json.Marshal(compressed)
+		internal.AssertNil(t, err)
+
+		received := &Envelope{}
+		internal.AssertNil(t, decoder.Decode(data, received))
+		decompressed, err := decoder.DecompressValue(received, 0)
+		internal.AssertNil(t, err)
+		raw, ok := decompressed.Value.(json.RawMessage)
+		internal.AssertTrue(t, ok)
+
+		var got map[string]interface{}
+		internal.AssertNil(t, json.Unmarshal(raw, &got))
+		internal.AssertEqual(t, value, got)
+	})
+
+	t.Run("TestCompressValuePreciseRoundTrip", func(t *testing.T) {
+		decoder := NewDecoder(WithPreciseNumberDecoding(true))
+
+		compressed, err := CompressValue(newCompressionTestEnvelope(json.RawMessage(`{"total":9007199254740993}`)), 0)
+		internal.AssertNil(t, err)
+
+		decompressed, err := decoder.DecompressValue(compressed, 0)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, map[string]interface{}{"total": json.Number("9007199254740993")}, decompressed.Value)
+	})
+
+	t.Run("TestCompressValueBelowThreshold", func(t *testing.T) {
+		msg := newCompressionTestEnvelope(value)
+
+		got, err := CompressValue(msg, 10000)
+		internal.AssertNil(t, err)
+		internal.AssertTrue(t, msg == got)
+	})
+
+	t.Run("TestCompressValueWithoutValue", func(t *testing.T) {
+		msg := newCompressionTestEnvelope(nil)
+
+		got, err := CompressValue(msg, 0)
+		internal.AssertNil(t, err)
+		internal.AssertTrue(t, msg == got)
+	})
+
+	t.Run("TestCompressValueAlreadyEncoded", func(t *testing.T) {
+		msg := newCompressionTestEnvelope(value, WithContentEncoding(ContentEncodingGzip))
+
+		got, err := CompressValue(msg, 0)
+		internal.AssertNil(t, err)
+		internal.AssertTrue(t, msg == got)
+	})
+
+	t.Run("TestCompressValueText", func(t *testing.T) {
+		msg := newCompressionTestEnvelope(strings.Repeat("text ", 100), WithContentType(ContentTypeText))
+
+		compressed, err := CompressValue(msg, 0)
+		internal.AssertNil(t, err)
+
+		data, err := json.Marshal(compressed)
+		internal.AssertNil(t, err)
+		received := &Envelope{}
+		internal.AssertNil(t, json.Unmarshal(data, received))
+
+		var got string
+		internal.AssertNil(t, received.ValueAs(&got))
+		internal.AssertEqual(t, msg.Value, got)
+	})
+}
+
+func TestDecompressValue(t *testing.T) {
+	compressed, err := CompressValue(newCompressionTestEnvelope(strings.Repeat("a", 100)), 0)
+	internal.AssertNil(t, err)
+
+	tests := map[string]struct {
+		arg     *Envelope
+		maxSize int
+		wantErr bool
+	}{
+		"test_without_headers": {
+			arg: &Envelope{Value: "value"},
+		},
+		"test_not_compressed": {
+			arg: newCompressionTestEnvelope("value"),
+		},
+		"test_unknown_encoding": {
+			arg: newCompressionTestEnvelope("value", WithContentEncoding("br")),
+		},
+		"test_exceeding_max_size": {
+			arg:     compressed,
+			maxSize: 50,
+			wantErr: true,
+		},
+		"test_invalid_base64": {
+			arg:     newCompressionTestEnvelope("not base64!", WithContentEncoding(ContentEncodingGzip)),
+			wantErr: true,
+		},
+		"test_not_gzip": {
+			arg:     newCompressionTestEnvelope("dmFsdWU=", WithContentEncoding(ContentEncodingGzip)),
+			wantErr: true,
+		},
+		"test_invalid_value_type": {
+			arg:     newCompressionTestEnvelope(42, WithContentEncoding(ContentEncodingGzip)),
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := DecompressValue(testCase.arg, testCase.maxSize)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertTrue(t, testCase.arg == got)
+			}
+		})
+	}
+}
+
+func TestDecompressValueMaxSize(t *testing.T) {
+	compressed, err := CompressValue(newCompressionTestEnvelope(strings.Repeat("a", 100)), 0)
+	internal.AssertNil(t, err)
+
+	decompressed, err := DecompressValue(compressed, 102)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, strings.Repeat("a", 100), decompressed.Value)
+}
+
+func TestValueAsUnknownEncoding(t *testing.T) {
+	var got string
+	internal.AssertNotNil(t, newCompressionTestEnvelope("value", WithContentEncoding("br")).ValueAs(&got))
+}
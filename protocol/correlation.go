@@ -0,0 +1,64 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// CorrelationIDGenerator generates new correlation IDs, used as 'correlation-id' header values.
+// The generators are required to be safe for concurrent use.
+type CorrelationIDGenerator func() string
+
+var (
+	correlationIDGenerator     CorrelationIDGenerator = UUIDCorrelationIDs()
+	correlationIDGeneratorLock sync.RWMutex
+)
+
+// UUIDCorrelationIDs provides a CorrelationIDGenerator of random UUIDs. This is the default generator.
+func UUIDCorrelationIDs() CorrelationIDGenerator {
+	return func() string {
+		return uuid.New().String()
+	}
+}
+
+// PrefixedCorrelationIDs provides a CorrelationIDGenerator of the provided prefix followed by an increasing counter,
+// e.g. 'device-1234-1', 'device-1234-2', etc. when the device serial number is used as prefix.
+func PrefixedCorrelationIDs(prefix string) CorrelationIDGenerator {
+	var counter uint64
+	return func() string {
+		return prefix + strconv.FormatUint(atomic.AddUint64(&counter, 1), 10)
+	}
+}
+
+// SetCorrelationIDGenerator configures the CorrelationIDGenerator used by NewCorrelationID.
+// If nil is provided the default UUID generator is restored.
+func SetCorrelationIDGenerator(generator CorrelationIDGenerator) {
+	if generator == nil {
+		generator = UUIDCorrelationIDs()
+	}
+	correlationIDGeneratorLock.Lock()
+	defer correlationIDGeneratorLock.Unlock()
+	correlationIDGenerator = generator
+}
+
+// NewCorrelationID generates a new correlation ID using the configured CorrelationIDGenerator.
+func NewCorrelationID() string {
+	correlationIDGeneratorLock.RLock()
+	generator := correlationIDGenerator
+	correlationIDGeneratorLock.RUnlock()
+	return generator()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/google/uuid"
+)
+
+func TestNewCorrelationIDDefault(t *testing.T) {
+	first := NewCorrelationID()
+	_, err := uuid.Parse(first)
+	internal.AssertNil(t, err)
+	internal.AssertFalse(t, first == NewCorrelationID())
+}
+
+func TestSetCorrelationIDGenerator(t *testing.T) {
+	defer SetCorrelationIDGenerator(nil)
+
+	SetCorrelationIDGenerator(PrefixedCorrelationIDs("device-1234-"))
+	internal.AssertEqual(t, "device-1234-1", NewCorrelationID())
+	internal.AssertEqual(t, "device-1234-2", NewCorrelationID())
+
+	SetCorrelationIDGenerator(func() string { return "fixed" })
+	internal.AssertEqual(t, "fixed", NewCorrelationID())
+
+	SetCorrelationIDGenerator(nil)
+	_, err := uuid.Parse(NewCorrelationID())
+	internal.AssertNil(t, err)
+}
+
+func TestPrefixedCorrelationIDsConcurrent(t *testing.T) {
+	generator := PrefixedCorrelationIDs("id-")
+
+	const count = 100
+	ids := make(chan string, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids <- generator()
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	unique := make(map[string]bool)
+	for id := range ids {
+		unique[id] = true
+	}
+	internal.AssertEqual(t, count, len(unique))
+}
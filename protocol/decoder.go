@@ -0,0 +1,140 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+// Decoder decodes the JSON and CBOR representations of Envelopes as configured by its DecoderOpts, so that e.g.
+// each Client could decode the received Envelopes differently. The zero Decoder decodes the Envelopes the same way
+// as json.Unmarshal does.
+type Decoder struct {
+	retainUnknownFields   bool
+	lazyValueDecoding     bool
+	preciseNumberDecoding bool
+}
+
+// DecoderOpt represents a configuration option of a Decoder.
+type DecoderOpt func(dec *Decoder)
+
+// NewDecoder creates a new Decoder configured with the provided DecoderOpts.
+func NewDecoder(opts ...DecoderOpt) *Decoder {
+	dec := &Decoder{}
+	for _, opt := range opts {
+		opt(dec)
+	}
+	return dec
+}
+
+// WithRetainUnknownFields configures the retention of the unknown top-level JSON members of the decoded Envelopes
+// in their Unknown field, so that forwarding an Envelope doesn't drop any fields introduced by newer Ditto protocol
+// versions. It's disabled by default.
+func WithRetainUnknownFields(retain bool) DecoderOpt {
+	return func(dec *Decoder) {
+		dec.retainUnknownFields = retain
+	}
+}
+
+// WithLazyValueDecoding configures the decoded Envelopes' values to be kept as json.RawMessage instead of being decoded
+// into the generic maps, slices and primitives, so that handlers which inspect only the topic and path don't pay for
+// decoding the whole payload. The raw value is then decoded on demand via the Envelope's ValueAs. It's disabled by default.
+func WithLazyValueDecoding(lazy bool) DecoderOpt {
+	return func(dec *Decoder) {
+		dec.lazyValueDecoding = lazy
+	}
+}
+
+// WithPreciseNumberDecoding configures the numbers within the decoded Envelopes' values to be decoded as json.Number
+// instead of float64, so that values which don't fit into a float64 without a loss, e.g. large int64 counters
+// or timestamps, round-trip losslessly. The values decoded via the Decoder's ValueAs, e.g. into a model.Thing,
+// keep their numbers as json.Number as well. It's disabled by default.
+func WithPreciseNumberDecoding(precise bool) DecoderOpt {
+	return func(dec *Decoder) {
+		dec.preciseNumberDecoding = precise
+	}
+}
+
+// Decode unmarshals the provided JSON data into the provided Envelope.
+func (dec *Decoder) Decode(data []byte, msg *Envelope) error {
+	if dec.lazyValueDecoding {
+		lazy := lazyEnvelopeJSON{envelopeJSON: (*envelopeJSON)(msg)}
+		if err := json.Unmarshal(data, &lazy); err != nil {
+			return err
+		}
+		msg.Value = nil
+		if len(lazy.Value) > 0 && string(lazy.Value) != "null" {
+			msg.Value = lazy.Value
+		}
+	} else if err := model.DecodeJSON(data, (*envelopeJSON)(msg), dec.preciseNumberDecoding); err != nil {
+		return err
+	}
+	msg.Unknown = nil
+	if !dec.retainUnknownFields {
+		return nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for name := range members {
+		if isEnvelopeField(name) {
+			delete(members, name)
+		}
+	}
+	if len(members) > 0 {
+		msg.Unknown = members
+	}
+	return nil
+}
+
+// DecodeCBOR decodes the provided CBOR (RFC 8949) data into the provided Envelope, the same way as its JSON
+// representation is decoded by Decode.
+func (dec *Decoder) DecodeCBOR(data []byte, msg *Envelope) error {
+	jsonData, err := cborToJSON(data)
+	if err != nil {
+		return err
+	}
+	return dec.Decode(jsonData, msg)
+}
+
+// ValueAs decodes the provided Envelope's value into the provided target, the same way as the Envelope's ValueAs does,
+// with the numbers decoded as json.Number if it's configured via WithPreciseNumberDecoding and the Envelope's content
+// type is decoded as JSON.
+func (dec *Decoder) ValueAs(msg *Envelope, target interface{}) error {
+	if msg.Headers != nil && len(msg.Headers.ContentEncoding()) > 0 {
+		if encoding := msg.Headers.ContentEncoding(); encoding != ContentEncodingGzip {
+			return fmt.Errorf("unsupported content encoding '%s'", encoding)
+		}
+		decompressed, err := dec.DecompressValue(msg, DefaultMaxDecompressedSize)
+		if err != nil {
+			return err
+		}
+		msg = decompressed
+	}
+	data, ok := msg.Value.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(msg.Value); err != nil {
+			return err
+		}
+	}
+	codec := CodecFor(msg.contentType())
+	if _, ok := codec.(jsonCodec); ok {
+		return model.DecodeJSON(data, target, dec.preciseNumberDecoding)
+	}
+	return codec.Unmarshal(data, target)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestDecoderDecodeCBOR(t *testing.T) {
+	msg := &Envelope{
+		Topic:   &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionEvents, Action: ActionModified},
+		Path:    "/attributes",
+		Value:   map[string]interface{}{"a": "b"},
+		Unknown: map[string]json.RawMessage{"newField": json.RawMessage(`"x"`)},
+	}
+	data, err := MarshalCBOR(msg)
+	internal.AssertNil(t, err)
+
+	tests := map[string]struct {
+		decoder     *Decoder
+		wantUnknown map[string]json.RawMessage
+	}{
+		"test_zero_decoder": {
+			decoder: &Decoder{},
+		},
+		"test_retaining_decoder": {
+			decoder:     NewDecoder(WithRetainUnknownFields(true)),
+			wantUnknown: map[string]json.RawMessage{"newField": json.RawMessage(`"x"`)},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := &Envelope{}
+			internal.AssertNil(t, testCase.decoder.DecodeCBOR(data, got))
+			internal.AssertEqual(t, msg.Topic, got.Topic)
+			internal.AssertEqual(t, msg.Value, got.Value)
+			internal.AssertEqual(t, testCase.wantUnknown, got.Unknown)
+		})
+	}
+}
+
+func TestDecoderDecodeInvalidCBOR(t *testing.T) {
+	internal.AssertNotNil(t, NewDecoder().DecodeCBOR([]byte{0xa1}, &Envelope{}))
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// maxPooledBufferSize is the maximum capacity of the buffers kept in the pool, so that a single huge message
+// doesn't keep its memory allocated for the process' lifetime.
+const maxPooledBufferSize = 64 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// GetBuffer provides an empty buffer from the pool of encoding buffers, e.g. to be used with EncodeJSON or EncodeCBOR.
+// The buffer is to be returned to the pool via PutBuffer once its content is no longer referenced.
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer resets the provided buffer and returns it to the pool of encoding buffers.
+// Buffers grown beyond 64 KiB are dropped instead.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// EncodeJSON appends the JSON representation of the Envelope, the same as provided by MarshalJSON, to the provided buffer,
+// without the intermediate allocations of json.Marshal. On error, the buffer is left unchanged.
+func (msg *Envelope) EncodeJSON(buf *bytes.Buffer) error {
+	value, err := msg.encodedValue()
+	if err != nil {
+		return err
+	}
+	encoded := envelopeJSON(*msg)
+	encoded.Value = value
+
+	start := buf.Len()
+	if err := json.NewEncoder(buf).Encode(&encoded); err != nil {
+		buf.Truncate(start)
+		return err
+	}
+	// drop the new line added by the encoder
+	buf.Truncate(buf.Len() - 1)
+	if len(msg.Unknown) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(msg.Unknown))
+	for name := range msg.Unknown {
+		if !isEnvelopeField(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf.Truncate(buf.Len() - 1)
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			buf.Truncate(start)
+			return err
+		}
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(msg.Unknown[name])
+	}
+	buf.WriteByte('}')
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestEnvelopeEncodeJSON(t *testing.T) {
+	tests := map[string]struct {
+		envelope *Envelope
+	}{
+		"test_plain_envelope": {
+			envelope: &Envelope{
+				Topic: &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin,
+					Criterion: CriterionCommands, Action: ActionModify},
+				Headers: NewHeaders(WithCorrelationID("test-id")),
+				Path:    "/attributes/<html>",
+				Value:   map[string]interface{}{"a": 1, "b": []interface{}{"x", true}},
+			},
+		},
+		"test_envelope_with_unknown": {
+			envelope: &Envelope{
+				Path: "/",
+				Unknown: map[string]json.RawMessage{
+					"b":    json.RawMessage(`{"x":1}`),
+					"a":    json.RawMessage(`"y"`),
+					"path": json.RawMessage(`"ignored"`),
+				},
+			},
+		},
+		"test_envelope_with_codec_value": {
+			envelope: &Envelope{
+				Headers: NewHeaders(WithContentType(ContentTypeText)),
+				Path:    "/",
+				Value:   "text",
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			want, err := json.Marshal(testCase.envelope)
+			internal.AssertNil(t, err)
+
+			buf := bytes.NewBufferString("prefix")
+			internal.AssertNil(t, testCase.envelope.EncodeJSON(buf))
+			internal.AssertEqual(t, "prefix"+string(want), buf.String())
+		})
+	}
+}
+
+func TestEnvelopeEncodeJSONError(t *testing.T) {
+	buf := bytes.NewBufferString("prefix")
+	internal.AssertNotNil(t, (&Envelope{Value: make(chan int)}).EncodeJSON(buf))
+	internal.AssertEqual(t, "prefix", buf.String())
+}
+
+func TestEncodeCBOR(t *testing.T) {
+	envelope := &Envelope{Path: "/attributes", Value: map[string]interface{}{"count": 1}}
+	want, err := MarshalCBOR(envelope)
+	internal.AssertNil(t, err)
+
+	buf := bytes.NewBufferString("prefix")
+	internal.AssertNil(t, EncodeCBOR(buf, envelope))
+	internal.AssertEqual(t, append([]byte("prefix"), want...), buf.Bytes())
+
+	internal.AssertNotNil(t, EncodeCBOR(buf, make(chan int)))
+	internal.AssertEqual(t, append([]byte("prefix"), want...), buf.Bytes())
+}
+
+func TestBufferPool(t *testing.T) {
+	buf := GetBuffer()
+	buf.WriteString("content")
+	PutBuffer(buf)
+
+	internal.AssertEqual(t, 0, GetBuffer().Len())
+
+	PutBuffer(bytes.NewBuffer(make([]byte, 0, maxPooledBufferSize+1)))
+	PutBuffer(nil)
+}
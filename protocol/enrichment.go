@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+// Enrichment represents the extra data of a Thing an Envelope is enriched with, as requested via the extra fields
+// (declared with a FieldSelector) of a subscription for events or messages. Only the requested subsets
+// of the Thing's data are present.
+type Enrichment struct {
+	ThingID    string                    `json:"thingId,omitempty"`
+	PolicyID   string                    `json:"policyId,omitempty"`
+	Definition string                    `json:"definition,omitempty"`
+	Attributes map[string]interface{}    `json:"attributes,omitempty"`
+	Features   map[string]*model.Feature `json:"features,omitempty"`
+}
+
+// Attribute returns the enriched attribute value referenced by the provided JSON pointer, e.g. 'location/latitude',
+// and true, or nil and false if the attribute is not present.
+func (enrichment *Enrichment) Attribute(pointer string) (interface{}, bool) {
+	return lookupPointer(enrichment.Attributes, pointer)
+}
+
+// FeatureProperty returns the enriched property value of the feature with the provided ID referenced by the provided
+// JSON pointer, e.g. 'status/value', and true, or nil and false if the feature or the property is not present.
+func (enrichment *Enrichment) FeatureProperty(featureID string, pointer string) (interface{}, bool) {
+	feature, ok := enrichment.Features[featureID]
+	if !ok || feature == nil {
+		return nil, false
+	}
+	return lookupPointer(feature.Properties, pointer)
+}
+
+// Enrichment decodes the extra data the Envelope is enriched with. Returns nil if the Envelope is not enriched.
+func (msg *Envelope) Enrichment() (*Enrichment, error) {
+	if msg.Extra == nil {
+		return nil, nil
+	}
+	if enrichment, ok := msg.Extra.(*Enrichment); ok {
+		return enrichment, nil
+	}
+	data, err := json.Marshal(msg.Extra)
+	if err != nil {
+		return nil, err
+	}
+	enrichment := &Enrichment{}
+	if err := json.Unmarshal(data, enrichment); err != nil {
+		return nil, err
+	}
+	return enrichment, nil
+}
+
+// WithEnrichment sets the extra data of the Envelope to the provided Enrichment.
+func (msg *Envelope) WithEnrichment(enrichment *Enrichment) *Envelope {
+	msg.Extra = enrichment
+	return msg
+}
+
+// lookupPointer resolves the provided JSON pointer (RFC 6901) relative to the provided JSON object.
+func lookupPointer(object map[string]interface{}, pointer string) (interface{}, bool) {
+	var current interface{} = object
+	for _, token := range strings.Split(strings.Trim(pointer, "/"), "/") {
+		members, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = members[UnescapePointerToken(token)]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+func TestEnvelopeEnrichment(t *testing.T) {
+	data := `{"topic":"ns/thing/things/twin/events/modified","path":"/features/lamp/properties/on","value":true,
+		"extra":{"thingId":"ns:thing","attributes":{"location":{"room":"kitchen"},"a/b":{"~c":1}},
+		"features":{"meter":{"properties":{"status":{"value":42}}}}}}`
+
+	msg := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal([]byte(data), msg))
+
+	got, err := msg.Enrichment()
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "ns:thing", got.ThingID)
+
+	tests := map[string]struct {
+		lookup func() (interface{}, bool)
+		want   interface{}
+		wantOk bool
+	}{
+		"test_attribute": {
+			lookup: func() (interface{}, bool) { return got.Attribute("location/room") },
+			want:   "kitchen",
+			wantOk: true,
+		},
+		"test_attribute_object": {
+			lookup: func() (interface{}, bool) { return got.Attribute("/location") },
+			want:   map[string]interface{}{"room": "kitchen"},
+			wantOk: true,
+		},
+		"test_attribute_escaped": {
+			lookup: func() (interface{}, bool) { return got.Attribute("a~1b/~0c") },
+			want:   float64(1),
+			wantOk: true,
+		},
+		"test_missing_attribute": {
+			lookup: func() (interface{}, bool) { return got.Attribute("location/floor") },
+		},
+		"test_attribute_below_value": {
+			lookup: func() (interface{}, bool) { return got.Attribute("location/room/name") },
+		},
+		"test_feature_property": {
+			lookup: func() (interface{}, bool) { return got.FeatureProperty("meter", "status/value") },
+			want:   float64(42),
+			wantOk: true,
+		},
+		"test_missing_feature": {
+			lookup: func() (interface{}, bool) { return got.FeatureProperty("lamp", "on") },
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			value, ok := testCase.lookup()
+			internal.AssertEqual(t, testCase.wantOk, ok)
+			internal.AssertEqual(t, testCase.want, value)
+		})
+	}
+}
+
+func TestEnvelopeWithEnrichment(t *testing.T) {
+	enrichment := &Enrichment{
+		Attributes: map[string]interface{}{"location": "kitchen"},
+		Features:   map[string]*model.Feature{"meter": (&model.Feature{}).WithProperty("value", 1)},
+	}
+
+	msg := (&Envelope{Path: "/"}).WithEnrichment(enrichment)
+	got, err := msg.Enrichment()
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, enrichment == got)
+
+	data, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, `{"topic":null,"path":"/","extra":{"attributes":{"location":"kitchen"},`+
+		`"features":{"meter":{"properties":{"value":1}}}}}`, string(data))
+}
+
+func TestEnvelopeEnrichmentEmpty(t *testing.T) {
+	got, err := (&Envelope{}).Enrichment()
+	internal.AssertNil(t, err)
+	internal.AssertNil(t, got)
+
+	got, err = (&Envelope{Extra: "invalid"}).Enrichment()
+	internal.AssertNotNil(t, err)
+	internal.AssertNil(t, got)
+}
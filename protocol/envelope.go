@@ -11,6 +11,43 @@
 
 package protocol
 
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	pathInboxMessages  = "/inbox/messages/"
+	pathOutboxMessages = "/outbox/messages/"
+)
+
+// NewResponseEnvelope creates a response Envelope to the provided request Envelope with the provided status and value.
+// The response has the topic of the request and the reply headers as per WithReplyHeadersFrom, i.e. the request's
+// 'correlation-id', 'content-type' and 'ditto-originator' headers with the 'response-required' header set to false.
+// For messages the request's inbox path is turned into the corresponding outbox path, for all other requests
+// the request's path is kept.
+func NewResponseEnvelope(request *Envelope, status int, value interface{}) *Envelope {
+	var topic *Topic
+	if request.Topic != nil {
+		t := *request.Topic
+		topic = &t
+	}
+
+	path := request.Path
+	if topic != nil && topic.Criterion == CriterionMessages {
+		path = strings.Replace(path, pathInboxMessages, pathOutboxMessages, 1)
+	}
+
+	return &Envelope{
+		Topic:   topic,
+		Headers: NewHeaders(WithReplyHeadersFrom(request.Headers)),
+		Path:    path,
+		Value:   value,
+		Status:  status,
+	}
+}
+
 // Envelope represents the Ditto's Envelope specification. As a Ditto's message consists of an envelope along with a Ditto-compliant
 // payload, the structure is to be used as a ready to use Ditto message.
 type Envelope struct {
@@ -23,6 +60,46 @@ type Envelope struct {
 	Status    int         `json:"status,omitempty"`
 	Revision  int64       `json:"revision,omitempty"`
 	Timestamp string      `json:"timestamp,omitempty"`
+
+	// Unknown holds the top-level JSON members of an unmarshaled Envelope that are not modeled by the Envelope structure.
+	// It's populated only by a Decoder configured via WithRetainUnknownFields and all of its members are marshaled back along with the Envelope.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+var envelopeFields = []string{"topic", "headers", "path", "value", "fields", "extra", "status", "revision", "timestamp"}
+
+// envelopeJSON is used to (un)marshal the modeled Envelope fields without recursing into the Envelope's JSON methods.
+type envelopeJSON Envelope
+
+// lazyEnvelopeJSON shadows the Envelope's value, so that it's unmarshaled as json.RawMessage.
+type lazyEnvelopeJSON struct {
+	*envelopeJSON
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON marshals Envelope along with all of its unknown members.
+// The value is encoded by the Codec registered for the Envelope's content type.
+func (msg *Envelope) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := msg.EncodeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON unmarshals Envelope as the zero Decoder does, i.e. without retaining its unknown members
+// and decoding its value eagerly with its numbers as float64.
+func (msg *Envelope) UnmarshalJSON(data []byte) error {
+	return (&Decoder{}).Decode(data, msg)
+}
+
+func isEnvelopeField(name string) bool {
+	for _, field := range envelopeFields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
 }
 
 // WithTopic sets the topic of the Envelope.
@@ -55,6 +132,12 @@ func (msg *Envelope) WithFields(fields string) *Envelope {
 	return msg
 }
 
+// WithFieldSelector sets the fields of the Envelope as rendered by the provided FieldSelector.
+func (msg *Envelope) WithFieldSelector(fields *FieldSelector) *Envelope {
+	msg.Fields = fields.String()
+	return msg
+}
+
 // WithExtra sets any extra Envelope configurations as defined by the Ditto protocol specification.
 func (msg *Envelope) WithExtra(extra interface{}) *Envelope {
 	msg.Extra = extra
@@ -78,3 +161,73 @@ func (msg *Envelope) WithTimestamp(timestamp string) *Envelope {
 	msg.Timestamp = timestamp
 	return msg
 }
+
+// Clone returns a deep copy of the Envelope, so that the copy could be modified without affecting the original one.
+// The Topic and Headers are always copied. The Value and Extra are deeply copied when they consist of
+// the JSON-compatible maps, slices and primitives or json.RawMessage (as provided on unmarshal), any other types are shared with the original.
+func (msg *Envelope) Clone() *Envelope {
+	if msg == nil {
+		return nil
+	}
+	clone := *msg
+	if msg.Topic != nil {
+		topic := *msg.Topic
+		clone.Topic = &topic
+	}
+	clone.Headers = msg.Headers.Clone()
+	clone.Value = deepCopyValue(msg.Value)
+	clone.Extra = deepCopyValue(msg.Extra)
+	if msg.Unknown != nil {
+		clone.Unknown = make(map[string]json.RawMessage, len(msg.Unknown))
+		for name, value := range msg.Unknown {
+			clone.Unknown[name] = append(json.RawMessage(nil), value...)
+		}
+	}
+	return &clone
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[key] = deepCopyValue(item)
+		}
+		return res
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = deepCopyValue(item)
+		}
+		return res
+	case json.RawMessage:
+		if v == nil {
+			return v
+		}
+		return append(json.RawMessage(nil), v...)
+	case []string:
+		if v == nil {
+			return v
+		}
+		res := make([]string, len(v))
+		copy(res, v)
+		return res
+	case []MetadataEntry:
+		if v == nil {
+			return v
+		}
+		res := make([]MetadataEntry, len(v))
+		for i, entry := range v {
+			res[i] = MetadataEntry{Key: entry.Key, Value: deepCopyValue(entry.Value)}
+		}
+		return res
+	default:
+		return v
+	}
+}
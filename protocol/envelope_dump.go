@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const redactedValue = "***"
+
+// Redaction configures the Envelope data masked when dumping an Envelope.
+type Redaction struct {
+	// Headers are the names of the headers, which values are masked.
+	Headers []string
+	// ValueKeys are the names of the JSON object members (at any depth of the Envelope's value and extra data), which values are masked.
+	ValueKeys []string
+}
+
+// DefaultRedaction is the Redaction applied by Envelope.String, masking the common credential-bearing headers and value members.
+var DefaultRedaction = &Redaction{
+	Headers:   []string{"authorization", "proxy-authorization", "cookie", "x-access-token", "x-api-key"},
+	ValueKeys: []string{"password", "secret", "token", "clientSecret", "apiKey"},
+}
+
+// String provides a stable, human-readable single-line representation of the Envelope, applying the DefaultRedaction.
+func (msg *Envelope) String() string {
+	return msg.Dump(DefaultRedaction)
+}
+
+// Dump provides a stable, human-readable single-line representation of the Envelope masking the data configured
+// by the provided Redaction. The headers and all JSON objects are rendered with sorted keys. A nil Redaction masks nothing.
+func (msg *Envelope) Dump(redaction *Redaction) string {
+	if msg == nil {
+		return "<nil>"
+	}
+	if redaction == nil {
+		redaction = &Redaction{}
+	}
+
+	var buf bytes.Buffer
+	topic := ""
+	if msg.Topic != nil {
+		topic = msg.Topic.String()
+	}
+	fmt.Fprintf(&buf, "topic=%s path=%s", topic, msg.Path)
+	if msg.Status != 0 {
+		fmt.Fprintf(&buf, " status=%d", msg.Status)
+	}
+	if msg.Revision != 0 {
+		fmt.Fprintf(&buf, " revision=%d", msg.Revision)
+	}
+	if len(msg.Timestamp) > 0 {
+		fmt.Fprintf(&buf, " timestamp=%s", msg.Timestamp)
+	}
+	if len(msg.Fields) > 0 {
+		fmt.Fprintf(&buf, " fields=%s", msg.Fields)
+	}
+	if msg.Headers != nil && len(msg.Headers.Values) > 0 {
+		headers := make(map[string]interface{}, len(msg.Headers.Values))
+		for name, value := range msg.Headers.Values {
+			if containsFold(redaction.Headers, name) {
+				value = redactedValue
+			}
+			headers[name] = value
+		}
+		fmt.Fprintf(&buf, " headers=%s", dumpJSON(headers, nil))
+	}
+	if msg.Value != nil {
+		fmt.Fprintf(&buf, " value=%s", dumpJSON(msg.Value, redaction.ValueKeys))
+	}
+	if msg.Extra != nil {
+		fmt.Fprintf(&buf, " extra=%s", dumpJSON(msg.Extra, redaction.ValueKeys))
+	}
+	return buf.String()
+}
+
+func dumpJSON(value interface{}, redactedKeys []string) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	if len(redactedKeys) == 0 {
+		return string(data)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	if data, err = json.Marshal(redactJSON(generic, redactedKeys)); err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(data)
+}
+
+func redactJSON(value interface{}, redactedKeys []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if containsFold(redactedKeys, key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(item, redactedKeys)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item, redactedKeys)
+		}
+	}
+	return value
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func newDumpTestEnvelope() *Envelope {
+	return &Envelope{
+		Topic: &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionCommands, Action: ActionModify},
+		Headers: NewHeaders(
+			WithCorrelationID("id"),
+			WithResponseRequired(true),
+			WithGeneric("Authorization", "Bearer abc"),
+		),
+		Path: "/attributes",
+		Value: map[string]interface{}{
+			"location": "office",
+			"credentials": map[string]interface{}{
+				"user":     "admin",
+				"Password": "secret",
+			},
+			"tokens": []interface{}{map[string]interface{}{"token": "t1"}},
+		},
+	}
+}
+
+func TestEnvelopeString(t *testing.T) {
+	want := `topic=ns/thing/things/twin/commands/modify path=/attributes ` +
+		`headers={"authorization":"***","correlation-id":"id","response-required":true} ` +
+		`value={"credentials":{"Password":"***","user":"admin"},"location":"office","tokens":[{"token":"***"}]}`
+
+	msg := newDumpTestEnvelope()
+	internal.AssertEqual(t, want, msg.String())
+	internal.AssertEqual(t, want, fmt.Sprint(msg))
+	internal.AssertEqual(t, "secret", msg.Value.(map[string]interface{})["credentials"].(map[string]interface{})["Password"])
+	internal.AssertEqual(t, "Bearer abc", msg.Headers.Get("authorization"))
+}
+
+func TestEnvelopeDump(t *testing.T) {
+	tests := map[string]struct {
+		msg       *Envelope
+		redaction *Redaction
+		want      string
+	}{
+		"test_nil_envelope": {
+			msg:  nil,
+			want: "<nil>",
+		},
+		"test_empty_envelope": {
+			msg:  &Envelope{},
+			want: "topic= path=",
+		},
+		"test_without_redaction": {
+			msg: newDumpTestEnvelope(),
+			want: `topic=ns/thing/things/twin/commands/modify path=/attributes ` +
+				`headers={"authorization":"Bearer abc","correlation-id":"id","response-required":true} ` +
+				`value={"credentials":{"Password":"secret","user":"admin"},"location":"office","tokens":[{"token":"t1"}]}`,
+		},
+		"test_custom_redaction": {
+			msg:       newDumpTestEnvelope(),
+			redaction: &Redaction{Headers: []string{"correlation-id"}, ValueKeys: []string{"location"}},
+			want: `topic=ns/thing/things/twin/commands/modify path=/attributes ` +
+				`headers={"authorization":"Bearer abc","correlation-id":"***","response-required":true} ` +
+				`value={"credentials":{"Password":"secret","user":"admin"},"location":"***","tokens":[{"token":"t1"}]}`,
+		},
+		"test_all_fields": {
+			msg: &Envelope{
+				Topic:     &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionEvents, Action: ActionModified},
+				Path:      "/",
+				Value:     struct{ Secret string }{Secret: "s"},
+				Fields:    "thingId",
+				Extra:     map[string]interface{}{"apiKey": "k"},
+				Status:    200,
+				Revision:  3,
+				Timestamp: "2022-01-01T00:00:00Z",
+			},
+			redaction: DefaultRedaction,
+			want: `topic=ns/thing/things/twin/events/modified path=/ status=200 revision=3 timestamp=2022-01-01T00:00:00Z ` +
+				`fields=thingId value={"Secret":"***"} extra={"apiKey":"***"}`,
+		},
+		"test_unmarshalable_value": {
+			msg:  &Envelope{Path: "/", Value: make(chan int)},
+			want: "topic= path=/ value=<json: unsupported type: chan int>",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.msg.Dump(testCase.redaction))
+		})
+	}
+}
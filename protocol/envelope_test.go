@@ -12,9 +12,11 @@
 package protocol
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
 )
 
 func TestEnvelopeWithTopic(t *testing.T) {
@@ -74,6 +76,15 @@ func TestEnvelopeWithFields(t *testing.T) {
 	})
 }
 
+func TestEnvelopeWithFieldSelector(t *testing.T) {
+	t.Run("TestEnvelopeWithFieldSelector", func(t *testing.T) {
+		msg := &Envelope{}
+
+		got := msg.WithFieldSelector(Fields().ThingID().Attributes("location", "model"))
+		internal.AssertEqual(t, "thingId,attributes(location,model)", got.Fields)
+	})
+}
+
 func TestEnvelopeWithExtra(t *testing.T) {
 	t.Run("TestEnvelopeWithExtra", func(t *testing.T) {
 		arg := "Extra"
@@ -113,3 +124,269 @@ func TestEnvelopeWithTimestamp(t *testing.T) {
 		internal.AssertEqual(t, arg, got.Timestamp)
 	})
 }
+
+func TestEnvelopeClone(t *testing.T) {
+	t.Run("TestEnvelopeClone", func(t *testing.T) {
+		orig := &Envelope{
+			Topic: &Topic{
+				Namespace:  "namespace",
+				EntityName: "entity_name",
+				Group:      GroupThings,
+				Channel:    ChannelTwin,
+				Criterion:  CriterionCommands,
+				Action:     ActionModify,
+			},
+			Headers: NewHeaders(WithCorrelationID("correlation-id"), WithRequestedAcks("twin-persisted")),
+			Path:    "/attributes",
+			Value: map[string]interface{}{
+				"location": map[string]interface{}{"lat": 42.0},
+				"tags":     []interface{}{"a", "b"},
+			},
+			Extra:    map[string]interface{}{"attributes": "extra"},
+			Status:   200,
+			Revision: 3,
+		}
+
+		got := orig.Clone()
+		internal.AssertEqual(t, orig, got)
+
+		got.Topic.WithAction(ActionDelete)
+		got.Headers.Set(HeaderCorrelationID, "other")
+		got.Headers.Values[HeaderRequestedAcks].([]string)[0] = "other"
+		got.Value.(map[string]interface{})["location"].(map[string]interface{})["lat"] = 0
+		got.Value.(map[string]interface{})["tags"].([]interface{})[0] = "c"
+		got.Extra.(map[string]interface{})["attributes"] = "other"
+
+		internal.AssertEqual(t, ActionModify, orig.Topic.Action)
+		internal.AssertEqual(t, "correlation-id", orig.Headers.CorrelationID())
+		internal.AssertEqual(t, []string{"twin-persisted"}, orig.Headers.RequestedAcks())
+		internal.AssertEqual(t, 42.0, orig.Value.(map[string]interface{})["location"].(map[string]interface{})["lat"])
+		internal.AssertEqual(t, "a", orig.Value.(map[string]interface{})["tags"].([]interface{})[0])
+		internal.AssertEqual(t, "extra", orig.Extra.(map[string]interface{})["attributes"])
+	})
+}
+
+func TestEnvelopeCloneEmpty(t *testing.T) {
+	t.Run("TestEnvelopeCloneEmpty", func(t *testing.T) {
+		var nilMsg *Envelope
+		internal.AssertNil(t, nilMsg.Clone())
+
+		got := (&Envelope{Path: "/"}).Clone()
+		internal.AssertEqual(t, &Envelope{Path: "/"}, got)
+	})
+}
+
+func TestNewResponseEnvelope(t *testing.T) {
+	topic := func(criterion TopicCriterion, action TopicAction) *Topic {
+		return &Topic{
+			Namespace:  "namespace",
+			EntityName: "entity_name",
+			Group:      GroupThings,
+			Channel:    ChannelLive,
+			Criterion:  criterion,
+			Action:     action,
+		}
+	}
+
+	tests := map[string]struct {
+		request *Envelope
+		want    *Envelope
+	}{
+		"test_thing_message": {
+			request: &Envelope{
+				Topic:   topic(CriterionMessages, "reboot"),
+				Headers: NewHeaders(WithCorrelationID("id"), WithContentType("text/plain"), WithResponseRequired(true)),
+				Path:    "/inbox/messages/reboot",
+				Value:   "now",
+			},
+			want: &Envelope{
+				Topic:   topic(CriterionMessages, "reboot"),
+				Headers: NewHeaders(WithResponseRequired(false), WithCorrelationID("id"), WithContentType("text/plain")),
+				Path:    "/outbox/messages/reboot",
+				Value:   "done",
+				Status:  200,
+			},
+		},
+		"test_feature_message": {
+			request: &Envelope{
+				Topic:   topic(CriterionMessages, "inbox"),
+				Headers: NewHeaders(WithCorrelationID("id")),
+				Path:    "/features/feature/inbox/messages/inbox",
+			},
+			want: &Envelope{
+				Topic:   topic(CriterionMessages, "inbox"),
+				Headers: NewHeaders(WithResponseRequired(false), WithCorrelationID("id")),
+				Path:    "/features/feature/outbox/messages/inbox",
+				Value:   "done",
+				Status:  200,
+			},
+		},
+		"test_originator": {
+			request: &Envelope{
+				Topic:   topic(CriterionCommands, ActionRetrieve),
+				Headers: NewHeaders(WithCorrelationID("id"), WithOriginator("nginx:ditto"), WithChannel("live")),
+				Path:    "/attributes",
+			},
+			want: &Envelope{
+				Topic:   topic(CriterionCommands, ActionRetrieve),
+				Headers: NewHeaders(WithResponseRequired(false), WithCorrelationID("id"), WithOriginator("nginx:ditto")),
+				Path:    "/attributes",
+				Value:   "done",
+				Status:  200,
+			},
+		},
+		"test_command": {
+			request: &Envelope{
+				Topic: topic(CriterionCommands, ActionModify),
+				Path:  "/features/feature/inbox/messages/inbox",
+			},
+			want: &Envelope{
+				Topic:   topic(CriterionCommands, ActionModify),
+				Headers: NewHeaders(WithResponseRequired(false)),
+				Path:    "/features/feature/inbox/messages/inbox",
+				Value:   "done",
+				Status:  200,
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewResponseEnvelope(testCase.request, 200, "done")
+			internal.AssertEqual(t, testCase.want, got)
+			internal.AssertFalse(t, got.Topic == testCase.request.Topic)
+			internal.AssertEqual(t, NewHeaders(WithReplyHeadersFrom(testCase.request.Headers)), got.Headers)
+		})
+	}
+}
+
+func TestEnvelopeUnknownFields(t *testing.T) {
+	data := `{"topic":"namespace/entity_name/things/twin/events/modified","path":"/attributes","value":1,` +
+		`"newField":{"a":[1,2]},"anotherField":"x"}`
+
+	t.Run("TestEnvelopeUnknownFieldsDropped", func(t *testing.T) {
+		msg := &Envelope{}
+		internal.AssertNil(t, json.Unmarshal([]byte(data), msg))
+		internal.AssertNil(t, msg.Unknown)
+
+		got, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"topic":"namespace/entity_name/things/twin/events/modified","path":"/attributes","value":1}`, string(got))
+	})
+
+	t.Run("TestEnvelopeUnknownFieldsRetained", func(t *testing.T) {
+		msg := &Envelope{}
+		internal.AssertNil(t, NewDecoder(WithRetainUnknownFields(true)).Decode([]byte(data), msg))
+		internal.AssertEqual(t, map[string]json.RawMessage{
+			"newField":     json.RawMessage(`{"a":[1,2]}`),
+			"anotherField": json.RawMessage(`"x"`),
+		}, msg.Unknown)
+		internal.AssertEqual(t, "/attributes", msg.Path)
+
+		got, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"topic":"namespace/entity_name/things/twin/events/modified","path":"/attributes","value":1,`+
+			`"anotherField":"x","newField":{"a":[1,2]}}`, string(got))
+	})
+
+	t.Run("TestEnvelopeUnknownFieldsIgnoreKnown", func(t *testing.T) {
+		msg := &Envelope{
+			Topic:   &Topic{Namespace: "ns", EntityName: "name", Group: GroupPolicies, Criterion: CriterionErrors},
+			Path:    "/",
+			Unknown: map[string]json.RawMessage{"Path": json.RawMessage(`"/other"`)},
+		}
+
+		got, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"topic":"ns/name/policies/errors","path":"/"}`, string(got))
+	})
+}
+
+func TestEnvelopeLazyValueDecoding(t *testing.T) {
+	decoder := NewDecoder(WithLazyValueDecoding(true))
+
+	data := `{"topic":"namespace/entity_name/things/twin/events/modified","path":"/attributes","value":{"a":[1,2]},"revision":2}`
+
+	t.Run("TestEnvelopeLazyValueRaw", func(t *testing.T) {
+		msg := &Envelope{}
+		internal.AssertNil(t, decoder.Decode([]byte(data), msg))
+		internal.AssertEqual(t, json.RawMessage(`{"a":[1,2]}`), msg.Value)
+		internal.AssertEqual(t, "/attributes", msg.Path)
+		internal.AssertEqual(t, int64(2), msg.Revision)
+
+		var target struct {
+			A []int `json:"a"`
+		}
+		internal.AssertNil(t, msg.ValueAs(&target))
+		internal.AssertEqual(t, []int{1, 2}, target.A)
+
+		got, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, data, string(got))
+	})
+
+	t.Run("TestEnvelopeEagerValueByDefault", func(t *testing.T) {
+		msg := &Envelope{}
+		internal.AssertNil(t, json.Unmarshal([]byte(data), msg))
+		internal.AssertEqual(t, map[string]interface{}{"a": []interface{}{float64(1), float64(2)}}, msg.Value)
+	})
+
+	t.Run("TestEnvelopeLazyValueNull", func(t *testing.T) {
+		msg := &Envelope{Value: "old"}
+		internal.AssertNil(t, decoder.Decode([]byte(`{"path":"/","value":null}`), msg))
+		internal.AssertNil(t, msg.Value)
+	})
+
+	t.Run("TestEnvelopeLazyValueClone", func(t *testing.T) {
+		msg := &Envelope{}
+		internal.AssertNil(t, decoder.Decode([]byte(data), msg))
+		clone := msg.Clone()
+		clone.Value.(json.RawMessage)[0] = '['
+		internal.AssertEqual(t, json.RawMessage(`{"a":[1,2]}`), msg.Value)
+	})
+}
+
+func TestEnvelopePreciseNumberDecoding(t *testing.T) {
+	decoder := NewDecoder(WithPreciseNumberDecoding(true))
+
+	data := `{"topic":"namespace/entity_name/things/twin/events/modified","path":"/features/meter","value":{"properties":{"total":9007199254740993}},"revision":2}`
+
+	msg := &Envelope{}
+	internal.AssertNil(t, decoder.Decode([]byte(data), msg))
+	internal.AssertEqual(t, map[string]interface{}{"properties": map[string]interface{}{"total": json.Number("9007199254740993")}}, msg.Value)
+	internal.AssertEqual(t, int64(2), msg.Revision)
+
+	feature := &model.Feature{}
+	internal.AssertNil(t, decoder.ValueAs(msg, feature))
+	internal.AssertEqual(t, json.Number("9007199254740993"), feature.Properties["total"])
+
+	got, err := json.Marshal(msg)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, data, string(got))
+
+	imprecise := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal([]byte(data), imprecise))
+	internal.AssertEqual(t, map[string]interface{}{"properties": map[string]interface{}{"total": float64(9007199254740992)}}, imprecise.Value)
+	internal.AssertNil(t, msg.ValueAs(feature))
+	internal.AssertEqual(t, float64(9007199254740992), feature.Properties["total"])
+}
+
+func TestEnvelopeMarshalJSONStable(t *testing.T) {
+	msg := &Envelope{
+		Topic: &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin,
+			Criterion: CriterionCommands, Action: ActionModify},
+		Headers: NewHeaders(WithCorrelationID("id"), WithResponseRequired(true), WithTimeout("10s"),
+			WithContentType(ContentTypeJSON), WithReplyTo("reply")),
+		Path:  "/attributes",
+		Value: map[string]interface{}{"b": 1, "a": map[string]interface{}{"y": true, "x": false}},
+	}
+	want := `{"topic":"ns/thing/things/twin/commands/modify","headers":{"content-type":"application/json",` +
+		`"correlation-id":"id","reply-to":"reply","response-required":true,"timeout":"10s"},` +
+		`"path":"/attributes","value":{"a":{"x":false,"y":true},"b":1}}`
+
+	for i := 0; i < 10; i++ {
+		got, err := json.Marshal(msg)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, want, string(got))
+	}
+}
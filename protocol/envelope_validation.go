@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Envelope fields constants, used to reference the invalid field of an EnvelopeError.
+const (
+	EnvelopeFieldTopic   = "topic"
+	EnvelopeFieldHeaders = "headers"
+	EnvelopeFieldPath    = "path"
+	EnvelopeFieldStatus  = "status"
+)
+
+// EnvelopeError represents an Envelope validation error of a specific field.
+type EnvelopeError struct {
+	Field  string
+	Reason string
+}
+
+// Error provides the string representation of the EnvelopeError.
+func (err *EnvelopeError) Error() string {
+	return fmt.Sprintf("invalid envelope %s: %s", err.Field, err.Reason)
+}
+
+// Validate checks that the required Envelope fields are set and that its topic, path, headers and status are mutually consistent
+// as defined by the Ditto protocol specification, e.g. merge commands require the merge patch content type,
+// messages are only allowed on the live channel and events are not allowed to have a status.
+// An *EnvelopeError is returned for the first inconsistency found, or a *HeaderError if the Envelope's Headers are invalid.
+func (msg *Envelope) Validate() error {
+	if err := validateEnvelopeTopic(msg.Topic); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(msg.Path, "/") {
+		return &EnvelopeError{Field: EnvelopeFieldPath, Reason: "path must start with '/'"}
+	}
+	if msg.Headers != nil {
+		if err := msg.Headers.Validate(); err != nil {
+			return err
+		}
+	}
+	if msg.Topic.Criterion == CriterionCommands && msg.Topic.Action == ActionMerge && msg.Status == 0 {
+		if msg.Headers == nil || msg.Headers.ContentType() != ContentTypeMergePatch {
+			return &EnvelopeError{
+				Field:  EnvelopeFieldHeaders,
+				Reason: fmt.Sprintf("merge commands require the '%s' content type", ContentTypeMergePatch),
+			}
+		}
+	}
+	return validateEnvelopeStatus(msg.Topic, msg.Status)
+}
+
+func validateEnvelopeTopic(topic *Topic) error {
+	if topic == nil {
+		return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "topic must be set"}
+	}
+	if len(topic.Namespace) == 0 || len(topic.EntityName) == 0 {
+		return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "namespace and entity name must be set"}
+	}
+	if len(topic.Criterion) == 0 {
+		return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "criterion must be set"}
+	}
+
+	switch topic.Group {
+	case GroupThings:
+		if topic.Channel != ChannelTwin && topic.Channel != ChannelLive {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "things topics require the twin or live channel"}
+		}
+		if topic.Criterion == CriterionMessages && topic.Channel != ChannelLive {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "messages are only allowed on the live channel"}
+		}
+	case GroupPolicies:
+		if len(topic.Channel) > 0 {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "policies topics do not support channels"}
+		}
+		if topic.Criterion == CriterionMessages || topic.Criterion == CriterionSearch || topic.Criterion == CriterionStreaming {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: fmt.Sprintf("%s are not supported for policies", topic.Criterion)}
+		}
+	default:
+		return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: fmt.Sprintf("unsupported group '%s'", topic.Group)}
+	}
+
+	switch topic.Criterion {
+	case CriterionCommands, CriterionEvents, CriterionSearch, CriterionStreaming:
+		if len(topic.Action) == 0 {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: fmt.Sprintf("%s require an action", topic.Criterion)}
+		}
+	case CriterionErrors:
+		if len(topic.Action) > 0 {
+			return &EnvelopeError{Field: EnvelopeFieldTopic, Reason: "errors do not have an action"}
+		}
+	}
+	return nil
+}
+
+func validateEnvelopeStatus(topic *Topic, status int) error {
+	if status != 0 && (status < 100 || status > 599) {
+		return &EnvelopeError{Field: EnvelopeFieldStatus, Reason: fmt.Sprintf("invalid HTTP status %d", status)}
+	}
+	switch topic.Criterion {
+	case CriterionEvents, CriterionSearch, CriterionStreaming:
+		if status != 0 {
+			return &EnvelopeError{Field: EnvelopeFieldStatus, Reason: fmt.Sprintf("status is not allowed for %s", topic.Criterion)}
+		}
+	case CriterionErrors, CriterionAcks:
+		if status == 0 {
+			return &EnvelopeError{Field: EnvelopeFieldStatus, Reason: fmt.Sprintf("status is required for %s", topic.Criterion)}
+		}
+	}
+	return nil
+}
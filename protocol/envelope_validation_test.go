@@ -0,0 +1,167 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestEnvelopeValidate(t *testing.T) {
+	thingsTopic := func(channel TopicChannel, criterion TopicCriterion, action TopicAction) *Topic {
+		return &Topic{
+			Namespace:  "org.eclipse.ditto",
+			EntityName: "thing",
+			Group:      GroupThings,
+			Channel:    channel,
+			Criterion:  criterion,
+			Action:     action,
+		}
+	}
+	policiesTopic := func(criterion TopicCriterion, action TopicAction) *Topic {
+		return &Topic{
+			Namespace:  "org.eclipse.ditto",
+			EntityName: "policy",
+			Group:      GroupPolicies,
+			Criterion:  criterion,
+			Action:     action,
+		}
+	}
+
+	tests := map[string]struct {
+		arg       *Envelope
+		wantField string
+	}{
+		"test_valid_command": {
+			arg: &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionModify), Path: "/attributes"},
+		},
+		"test_valid_merge_command": {
+			arg: &Envelope{
+				Topic:   thingsTopic(ChannelTwin, CriterionCommands, ActionMerge),
+				Path:    "/",
+				Headers: NewHeaders(WithContentType(ContentTypeMergePatch)),
+			},
+		},
+		"test_valid_merge_command_response": {
+			arg: &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionMerge), Path: "/", Status: 204},
+		},
+		"test_valid_live_message": {
+			arg: &Envelope{Topic: thingsTopic(ChannelLive, CriterionMessages, "subject"), Path: "/inbox/messages/subject"},
+		},
+		"test_valid_error": {
+			arg: &Envelope{Topic: thingsTopic(ChannelTwin, CriterionErrors, ""), Path: "/", Status: 404},
+		},
+		"test_valid_policy_command": {
+			arg: &Envelope{Topic: policiesTopic(CriterionCommands, ActionCreate), Path: "/"},
+		},
+		"test_without_topic": {
+			arg:       &Envelope{Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_without_namespace": {
+			arg:       &Envelope{Topic: &Topic{EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionEvents}, Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_without_criterion": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, "", ActionModify), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_things_without_channel": {
+			arg:       &Envelope{Topic: thingsTopic("", CriterionCommands, ActionModify), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_twin_message": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionMessages, "subject"), Path: "/inbox/messages/subject"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_policies_with_channel": {
+			arg:       &Envelope{Topic: policiesTopic(CriterionCommands, ActionCreate).WithChannel(ChannelTwin), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_policies_messages": {
+			arg:       &Envelope{Topic: policiesTopic(CriterionMessages, "subject"), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_unsupported_group": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionModify).WithGroup("connections"), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_command_without_action": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ""), Path: "/"},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_error_with_action": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionErrors, ActionModify), Path: "/", Status: 400},
+			wantField: EnvelopeFieldTopic,
+		},
+		"test_without_path": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionModify)},
+			wantField: EnvelopeFieldPath,
+		},
+		"test_merge_without_content_type": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionMerge), Path: "/"},
+			wantField: EnvelopeFieldHeaders,
+		},
+		"test_merge_with_wrong_content_type": {
+			arg: &Envelope{
+				Topic:   thingsTopic(ChannelTwin, CriterionCommands, ActionMerge),
+				Path:    "/",
+				Headers: NewHeaders(WithContentType("application/json")),
+			},
+			wantField: EnvelopeFieldHeaders,
+		},
+		"test_event_with_status": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionEvents, ActionModified), Path: "/", Status: 200},
+			wantField: EnvelopeFieldStatus,
+		},
+		"test_error_without_status": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionErrors, ""), Path: "/"},
+			wantField: EnvelopeFieldStatus,
+		},
+		"test_invalid_status": {
+			arg:       &Envelope{Topic: thingsTopic(ChannelTwin, CriterionCommands, ActionModify), Path: "/", Status: 42},
+			wantField: EnvelopeFieldStatus,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.arg.Validate()
+			if len(testCase.wantField) == 0 {
+				internal.AssertNil(t, err)
+				return
+			}
+			envelopeErr, ok := err.(*EnvelopeError)
+			internal.AssertTrue(t, ok)
+			internal.AssertEqual(t, testCase.wantField, envelopeErr.Field)
+		})
+	}
+}
+
+func TestEnvelopeValidateInvalidHeaders(t *testing.T) {
+	msg := &Envelope{
+		Topic:   &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin, Criterion: CriterionCommands, Action: ActionModify},
+		Path:    "/",
+		Headers: &Headers{Values: map[string]interface{}{HeaderResponseRequired: "yes"}},
+	}
+
+	err := msg.Validate()
+	headerErr, ok := err.(*HeaderError)
+	internal.AssertTrue(t, ok)
+	internal.AssertEqual(t, HeaderResponseRequired, headerErr.Header)
+}
+
+func TestEnvelopeErrorError(t *testing.T) {
+	err := &EnvelopeError{Field: EnvelopeFieldPath, Reason: "path must start with '/'"}
+	internal.AssertEqual(t, "invalid envelope path: path must start with '/'", err.Error())
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Common Ditto error codes constants.
+const (
+	ErrorCodeThingNotFound                = "things:thing.notfound"
+	ErrorCodeThingConflict                = "things:thing.conflict"
+	ErrorCodeThingNotCreatable            = "things:thing.notcreatable"
+	ErrorCodeThingNotModifiable           = "things:thing.notmodifiable"
+	ErrorCodeThingNotDeletable            = "things:thing.notdeletable"
+	ErrorCodeThingIDInvalid               = "things:id.invalid"
+	ErrorCodeThingPreconditionFailed      = "things:precondition.failed"
+	ErrorCodeThingPreconditionNotModified = "things:precondition.notmodified"
+	ErrorCodeAttributesNotFound           = "things:attributes.notfound"
+	ErrorCodeAttributeNotFound            = "things:attribute.notfound"
+	ErrorCodeFeaturesNotFound             = "things:features.notfound"
+	ErrorCodeFeatureNotFound              = "things:feature.notfound"
+	ErrorCodeFeaturePropertiesNotFound    = "things:feature.properties.notfound"
+	ErrorCodeFeaturePropertyNotFound      = "things:feature.property.notfound"
+	ErrorCodePolicyNotFound               = "policies:policy.notfound"
+	ErrorCodePolicyConflict               = "policies:policy.conflict"
+	ErrorCodePolicyNotModifiable          = "policies:policy.notmodifiable"
+	ErrorCodeMessageSubjectBlocked        = "messages:subject.blocked"
+)
+
+// ErrorResponse represents the payload of a Ditto error as defined by the Ditto protocol specification.
+// See https://www.eclipse.org/ditto/protocol-specification-errors.html
+type ErrorResponse struct {
+	Status      int    `json:"status"`
+	ErrorCode   string `json:"error"`
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+	Href        string `json:"href,omitempty"`
+}
+
+// Error provides the string representation of the ErrorResponse so that it can be used as an error.
+func (resp *ErrorResponse) Error() string {
+	return fmt.Sprintf("%s [%d]: %s", resp.ErrorCode, resp.Status, resp.Message)
+}
+
+// IsError returns true if the provided Envelope is a Ditto error, i.e. its topic criterion is the errors one.
+func IsError(env *Envelope) bool {
+	return env != nil && env.Topic != nil && env.Topic.Criterion == CriterionErrors
+}
+
+// ParseError decodes the value of the provided error Envelope into an ErrorResponse instance.
+// If the value does not provide the status, the Envelope's one is used.
+// Returns an error if the Envelope is not a Ditto error or its value cannot be decoded.
+func ParseError(env *Envelope) (*ErrorResponse, error) {
+	if !IsError(env) {
+		return nil, errors.New("envelope is not a Ditto error")
+	}
+	resp := &ErrorResponse{}
+	data, err := json.Marshal(env.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == 0 {
+		resp.Status = env.Status
+	}
+	return resp, nil
+}
+
+// NewErrorEnvelope creates a new Envelope for a Ditto error with the provided status, error code and message
+// as a response to the provided request Envelope. The error is bound to the same entity and channel as the request
+// and the request's correlation-id header is preserved.
+func NewErrorEnvelope(request *Envelope, status int, errorCode string, message string) *Envelope {
+	topic := &Topic{Criterion: CriterionErrors}
+	if request.Topic != nil {
+		topic.Namespace = request.Topic.Namespace
+		topic.EntityName = request.Topic.EntityName
+		topic.Group = request.Topic.Group
+		topic.Channel = request.Topic.Channel
+	}
+
+	headerOpts := []HeaderOpt{WithResponseRequired(false)}
+	if request.Headers != nil && len(request.Headers.CorrelationID()) > 0 {
+		headerOpts = append(headerOpts, WithCorrelationID(request.Headers.CorrelationID()))
+	}
+
+	return &Envelope{
+		Topic:   topic,
+		Headers: NewHeaders(headerOpts...),
+		Path:    request.Path,
+		Value: &ErrorResponse{
+			Status:    status,
+			ErrorCode: errorCode,
+			Message:   message,
+		},
+		Status: status,
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestIsError(t *testing.T) {
+	tests := map[string]struct {
+		arg  *Envelope
+		want bool
+	}{
+		"test_nil_envelope": {
+			arg:  nil,
+			want: false,
+		},
+		"test_without_topic": {
+			arg:  &Envelope{},
+			want: false,
+		},
+		"test_commands_criterion": {
+			arg:  &Envelope{Topic: &Topic{Criterion: CriterionCommands}},
+			want: false,
+		},
+		"test_errors_criterion": {
+			arg:  &Envelope{Topic: &Topic{Criterion: CriterionErrors}},
+			want: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, IsError(testCase.arg))
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	data := `{"topic":"org.eclipse.ditto/thing/things/twin/errors","path":"/","status":404,
+		"value":{"status":404,"error":"things:thing.notfound","message":"The Thing was not found.",
+		"description":"Check the Thing ID.","href":"https://www.eclipse.org/ditto"}}`
+	env := &Envelope{}
+	internal.AssertNil(t, json.Unmarshal([]byte(data), env))
+
+	got, err := ParseError(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, &ErrorResponse{
+		Status:      404,
+		ErrorCode:   "things:thing.notfound",
+		Message:     "The Thing was not found.",
+		Description: "Check the Thing ID.",
+		Href:        "https://www.eclipse.org/ditto",
+	}, got)
+	internal.AssertEqual(t, "things:thing.notfound [404]: The Thing was not found.", got.Error())
+}
+
+func TestParseErrorStatusFromEnvelope(t *testing.T) {
+	env := &Envelope{
+		Topic:  &Topic{Criterion: CriterionErrors},
+		Status: 409,
+		Value:  map[string]interface{}{"error": "things:thing.conflict"},
+	}
+
+	got, err := ParseError(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 409, got.Status)
+}
+
+func TestParseErrorInvalid(t *testing.T) {
+	tests := map[string]struct {
+		arg *Envelope
+	}{
+		"test_not_error": {
+			arg: &Envelope{Topic: &Topic{Criterion: CriterionEvents}},
+		},
+		"test_invalid_value": {
+			arg: &Envelope{Topic: &Topic{Criterion: CriterionErrors}, Value: "invalid"},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseError(testCase.arg)
+			internal.AssertNil(t, got)
+			internal.AssertNotNil(t, err)
+		})
+	}
+}
+
+func TestNewErrorEnvelope(t *testing.T) {
+	request := &Envelope{
+		Topic: &Topic{
+			Namespace:  "org.eclipse.ditto",
+			EntityName: "thing",
+			Group:      GroupThings,
+			Channel:    ChannelTwin,
+			Criterion:  CriterionCommands,
+			Action:     ActionRetrieve,
+		},
+		Headers: NewHeaders(WithCorrelationID("testCorrelationID"), WithResponseRequired(true)),
+		Path:    "/attributes/location",
+	}
+
+	got := NewErrorEnvelope(request, 404, ErrorCodeAttributeNotFound, "The attribute was not found.")
+
+	data, err := json.Marshal(got)
+	internal.AssertNil(t, err)
+	want := `{"topic":"org.eclipse.ditto/thing/things/twin/errors",` +
+		`"headers":{"correlation-id":"testCorrelationID","response-required":false},"path":"/attributes/location",` +
+		`"value":{"status":404,"error":"things:attribute.notfound","message":"The attribute was not found."},"status":404}`
+	internal.AssertEqual(t, want, string(data))
+
+	parsed, err := ParseError(got)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, ErrorCodeAttributeNotFound, parsed.ErrorCode)
+}
+
+func TestNewErrorEnvelopePolicies(t *testing.T) {
+	request := &Envelope{
+		Topic: &Topic{
+			Namespace:  "org.eclipse.ditto",
+			EntityName: "policy",
+			Group:      GroupPolicies,
+			Criterion:  CriterionCommands,
+			Action:     ActionRetrieve,
+		},
+		Path: "/",
+	}
+
+	got := NewErrorEnvelope(request, 404, ErrorCodePolicyNotFound, "The Policy was not found.")
+	internal.AssertEqual(t, "org.eclipse.ditto/policy/policies/errors", got.Topic.String())
+	internal.AssertEqual(t, "", got.Headers.CorrelationID())
+}
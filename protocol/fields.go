@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import "strings"
+
+const (
+	fieldThingID    = "thingId"
+	fieldPolicyID   = "policyId"
+	fieldDefinition = "definition"
+	fieldAttributes = "attributes"
+	fieldFeatures   = "features"
+
+	fieldRevision  = "_revision"
+	fieldModified  = "_modified"
+	fieldCreated   = "_created"
+	fieldNamespace = "_namespace"
+)
+
+var fieldEscaper = strings.NewReplacer("%", "%25", ",", "%2C", "(", "%28", ")", "%29")
+
+// FieldSelector is a builder of the Ditto field selectors, used to restrict the data of the retrieved entities
+// to the selected fields only, e.g. 'thingId,attributes(location,model),features/Temp/properties/value'.
+// Each selected JSON pointer is escaped, so that any commas and parentheses in it are not treated as part of the syntax.
+type FieldSelector struct {
+	selectors []string
+}
+
+// Fields creates a new empty FieldSelector.
+func Fields() *FieldSelector {
+	return &FieldSelector{}
+}
+
+// FieldsFrom creates a new FieldSelector from the provided already rendered field selector, e.g. the fields of a received Envelope.
+// The provided selector is kept as it is and further fields can be selected in addition to it.
+func FieldsFrom(selector string) *FieldSelector {
+	fields := Fields()
+	if len(selector) > 0 {
+		fields.selectors = append(fields.selectors, selector)
+	}
+	return fields
+}
+
+// Field selects the field referenced by the provided JSON pointer, e.g. 'attributes/location'.
+// If nested JSON pointers are provided, only they are selected from the referenced field, e.g. 'attributes(location,model)'.
+func (fields *FieldSelector) Field(pointer string, nested ...string) *FieldSelector {
+	return fields.add(escapeField(pointer), nested)
+}
+
+func (fields *FieldSelector) add(selector string, nested []string) *FieldSelector {
+	if len(nested) > 0 {
+		escaped := make([]string, len(nested))
+		for i, field := range nested {
+			escaped[i] = escapeField(field)
+		}
+		if len(nested) == 1 {
+			selector = selector + "/" + escaped[0]
+		} else {
+			selector = selector + "(" + strings.Join(escaped, ",") + ")"
+		}
+	}
+	fields.selectors = append(fields.selectors, selector)
+	return fields
+}
+
+// ThingID selects the Thing's ID.
+func (fields *FieldSelector) ThingID() *FieldSelector {
+	return fields.Field(fieldThingID)
+}
+
+// PolicyID selects the Thing's Policy ID.
+func (fields *FieldSelector) PolicyID() *FieldSelector {
+	return fields.Field(fieldPolicyID)
+}
+
+// Definition selects the Thing's definition.
+func (fields *FieldSelector) Definition() *FieldSelector {
+	return fields.Field(fieldDefinition)
+}
+
+// Attributes selects the Thing's attributes referenced by the provided JSON pointers or all attributes if none are provided.
+func (fields *FieldSelector) Attributes(pointers ...string) *FieldSelector {
+	return fields.Field(fieldAttributes, pointers...)
+}
+
+// Features selects the Thing's features with the provided IDs or all features if none are provided.
+func (fields *FieldSelector) Features(featureIDs ...string) *FieldSelector {
+	return fields.Field(fieldFeatures, featureIDs...)
+}
+
+// Feature selects the data of the Thing's feature with the provided ID referenced by the provided JSON pointers,
+// e.g. 'properties/value', or the whole feature if none are provided.
+func (fields *FieldSelector) Feature(featureID string, pointers ...string) *FieldSelector {
+	return fields.add(fieldFeatures+"/"+escapeField(featureID), pointers)
+}
+
+// Revision selects the Thing's '_revision' special field.
+func (fields *FieldSelector) Revision() *FieldSelector {
+	return fields.Field(fieldRevision)
+}
+
+// Modified selects the Thing's '_modified' special field, i.e. the timestamp of its last modification.
+func (fields *FieldSelector) Modified() *FieldSelector {
+	return fields.Field(fieldModified)
+}
+
+// Created selects the Thing's '_created' special field, i.e. the timestamp of its creation.
+func (fields *FieldSelector) Created() *FieldSelector {
+	return fields.Field(fieldCreated)
+}
+
+// Namespace selects the Thing's '_namespace' special field.
+func (fields *FieldSelector) Namespace() *FieldSelector {
+	return fields.Field(fieldNamespace)
+}
+
+// String provides the Ditto field selector representation of the FieldSelector.
+func (fields *FieldSelector) String() string {
+	return strings.Join(fields.selectors, ",")
+}
+
+func escapeField(field string) string {
+	return fieldEscaper.Replace(strings.Trim(field, "/"))
+}
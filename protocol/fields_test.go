@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestFieldSelector(t *testing.T) {
+	tests := map[string]struct {
+		arg  *FieldSelector
+		want string
+	}{
+		"test_empty": {
+			arg:  Fields(),
+			want: "",
+		},
+		"test_thing_fields": {
+			arg:  Fields().ThingID().PolicyID().Definition(),
+			want: "thingId,policyId,definition",
+		},
+		"test_special_fields": {
+			arg:  Fields().Revision().Modified().Created().Namespace(),
+			want: "_revision,_modified,_created,_namespace",
+		},
+		"test_all_attributes": {
+			arg:  Fields().Attributes(),
+			want: "attributes",
+		},
+		"test_single_attribute": {
+			arg:  Fields().Attributes("location"),
+			want: "attributes/location",
+		},
+		"test_multiple_attributes": {
+			arg:  Fields().Attributes("location", "/model/"),
+			want: "attributes(location,model)",
+		},
+		"test_features": {
+			arg:  Fields().Features("Temp", "Humidity"),
+			want: "features(Temp,Humidity)",
+		},
+		"test_feature": {
+			arg:  Fields().Feature("Temp"),
+			want: "features/Temp",
+		},
+		"test_feature_property": {
+			arg:  Fields().Attributes("location").Feature("Temp", "properties/value"),
+			want: "attributes/location,features/Temp/properties/value",
+		},
+		"test_feature_multiple_pointers": {
+			arg:  Fields().Feature("Temp", "properties/value", "definition"),
+			want: "features/Temp(properties/value,definition)",
+		},
+		"test_generic_field": {
+			arg:  Fields().Field("_metadata", "attributes", "features"),
+			want: "_metadata(attributes,features)",
+		},
+		"test_escaping": {
+			arg:  Fields().Attributes("a,b", "c(d)").Feature("100%", "e)"),
+			want: "attributes(a%2Cb,c%28d%29),features/100%25/e%29",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.arg.String())
+		})
+	}
+}
+
+func TestFieldsFrom(t *testing.T) {
+	internal.AssertEqual(t, "", FieldsFrom("").String())
+	internal.AssertEqual(t, "thingId,attributes(a,b)", FieldsFrom("thingId,attributes(a,b)").String())
+	internal.AssertEqual(t, "thingId,_revision", FieldsFrom("thingId").Field("_revision").String())
+}
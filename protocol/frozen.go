@@ -0,0 +1,176 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FrozenEnvelope is an Envelope encoded once, so that it could be sent repeatedly, e.g. as a high-frequency periodic
+// status event, without encoding it again. Only its 'correlation-id' and 'creation-time' headers are patched each time
+// it is encoded. FrozenEnvelope is immutable and safe for concurrent use.
+type FrozenEnvelope struct {
+	// the encoded Envelope split around the patched header values in the order of their appearance
+	prefix, middle, suffix []byte
+	correlationIDFirst     bool
+	cbor                   bool
+}
+
+// Freeze encodes the Envelope as JSON into a FrozenEnvelope with the 'correlation-id' and 'creation-time' headers
+// to be patched on each encoding. Further modifications of the Envelope don't affect the FrozenEnvelope.
+func (msg *Envelope) Freeze() (*FrozenEnvelope, error) {
+	return freeze(msg, false)
+}
+
+// FreezeCBOR encodes the Envelope as CBOR (RFC 8949) into a FrozenEnvelope with the 'correlation-id'
+// and 'creation-time' headers to be patched on each encoding.
+// Further modifications of the Envelope don't affect the FrozenEnvelope.
+func (msg *Envelope) FreezeCBOR() (*FrozenEnvelope, error) {
+	return freeze(msg, true)
+}
+
+func freeze(msg *Envelope, cbor bool) (*FrozenEnvelope, error) {
+	// the patched header values are located via unique placeholders
+	placeholder := uuid.New().String()
+	correlationIDPlaceholder := "frozen-correlation-id-" + placeholder
+	creationTimePlaceholder := "frozen-creation-time-" + placeholder
+
+	env := *msg
+	env.Headers = NewHeadersFrom(msg.Headers)
+	env.Headers.Set(HeaderCorrelationID, correlationIDPlaceholder)
+	env.Headers.Set(HeaderCreationTime, creationTimePlaceholder)
+
+	var buf, encodedPlaceholder bytes.Buffer
+	encode := func(value string) []byte {
+		encodedPlaceholder.Reset()
+		if cbor {
+			writeCBORHead(&encodedPlaceholder, cborText, uint64(len(value)))
+			encodedPlaceholder.WriteString(value)
+		} else {
+			appendJSONString(&encodedPlaceholder, value)
+		}
+		return append([]byte(nil), encodedPlaceholder.Bytes()...)
+	}
+	var err error
+	if cbor {
+		err = EncodeCBOR(&buf, &env)
+	} else {
+		err = env.EncodeJSON(&buf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	correlationID := encode(correlationIDPlaceholder)
+	creationTime := encode(creationTimePlaceholder)
+	correlationIDIndex := bytes.Index(data, correlationID)
+	creationTimeIndex := bytes.Index(data, creationTime)
+	if correlationIDIndex < 0 || creationTimeIndex < 0 {
+		return nil, errors.New("cannot freeze the envelope: the patched headers are not found")
+	}
+
+	frozen := &FrozenEnvelope{correlationIDFirst: correlationIDIndex < creationTimeIndex, cbor: cbor}
+	first, second := correlationID, creationTime
+	firstIndex, secondIndex := correlationIDIndex, creationTimeIndex
+	if !frozen.correlationIDFirst {
+		first, second = second, first
+		firstIndex, secondIndex = secondIndex, firstIndex
+	}
+	frozen.prefix = data[:firstIndex]
+	frozen.middle = data[firstIndex+len(first) : secondIndex]
+	frozen.suffix = data[secondIndex+len(second):]
+	return frozen, nil
+}
+
+// IsCBOR returns true if the FrozenEnvelope is CBOR encoded, i.e. created via FreezeCBOR.
+func (frozen *FrozenEnvelope) IsCBOR() bool {
+	return frozen.cbor
+}
+
+// EncodeTo appends the frozen encoded Envelope with the provided 'correlation-id' and 'creation-time' header values
+// to the provided buffer.
+func (frozen *FrozenEnvelope) EncodeTo(buf *bytes.Buffer, correlationID string, creationTime time.Time) {
+	buf.Write(frozen.prefix)
+	if frozen.correlationIDFirst {
+		frozen.writeCorrelationID(buf, correlationID)
+		buf.Write(frozen.middle)
+		frozen.writeCreationTime(buf, creationTime)
+	} else {
+		frozen.writeCreationTime(buf, creationTime)
+		buf.Write(frozen.middle)
+		frozen.writeCorrelationID(buf, correlationID)
+	}
+	buf.Write(frozen.suffix)
+}
+
+func (frozen *FrozenEnvelope) writeCorrelationID(buf *bytes.Buffer, correlationID string) {
+	if frozen.cbor {
+		writeCBORHead(buf, cborText, uint64(len(correlationID)))
+		buf.WriteString(correlationID)
+	} else {
+		appendJSONString(buf, correlationID)
+	}
+}
+
+func (frozen *FrozenEnvelope) writeCreationTime(buf *bytes.Buffer, creationTime time.Time) {
+	millis := creationTime.UnixNano() / int64(time.Millisecond)
+	if frozen.cbor {
+		if millis >= 0 {
+			writeCBORHead(buf, cborUnsigned, uint64(millis))
+		} else {
+			writeCBORHead(buf, cborNegative, uint64(-(millis + 1)))
+		}
+		return
+	}
+	var number [20]byte
+	buf.Write(strconv.AppendInt(number[:0], millis, 10))
+}
+
+// appendJSONString appends the provided string as a JSON string, the same as json.Marshal does,
+// without allocating for the strings that require no escaping.
+func appendJSONString(buf *bytes.Buffer, value string) {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			data, _ := json.Marshal(value)
+			buf.Write(data)
+			return
+		}
+	}
+	buf.WriteByte('"')
+	buf.WriteString(value)
+	buf.WriteByte('"')
+}
+
+// Thaw decodes the frozen encoded Envelope with the provided 'correlation-id' and 'creation-time' header values
+// into a new Envelope.
+func (frozen *FrozenEnvelope) Thaw(correlationID string, creationTime time.Time) (*Envelope, error) {
+	var buf bytes.Buffer
+	frozen.EncodeTo(&buf, correlationID, creationTime)
+	env := &Envelope{}
+	if frozen.cbor {
+		if err := UnmarshalCBOR(buf.Bytes(), env); err != nil {
+			return nil, err
+		}
+		return env, nil
+	}
+	if err := json.Unmarshal(buf.Bytes(), env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestFrozenEnvelopeEncodeTo(t *testing.T) {
+	creationTime := time.Unix(1650000000, 123000000)
+	envelope := &Envelope{
+		Topic: &Topic{Namespace: "ns", EntityName: "thing", Group: GroupThings, Channel: ChannelTwin,
+			Criterion: CriterionEvents, Action: ActionModified},
+		Headers: NewHeaders(WithCorrelationID("frozen"), WithContentType(ContentTypeJSON)),
+		Path:    "/features/status/properties",
+		Value:   map[string]interface{}{"battery": 87, "online": true},
+	}
+
+	tests := map[string]struct {
+		cbor          bool
+		correlationID string
+		creationTime  time.Time
+	}{
+		"test_json": {
+			correlationID: "test-id-1",
+			creationTime:  creationTime,
+		},
+		"test_json_escaped_correlation_id": {
+			correlationID: "test-\"id\"-<2>",
+			creationTime:  creationTime,
+		},
+		"test_json_before_epoch": {
+			correlationID: "test-id-3",
+			creationTime:  time.Unix(-1, 0),
+		},
+		"test_cbor": {
+			cbor:          true,
+			correlationID: "test-id-4",
+			creationTime:  creationTime,
+		},
+		"test_cbor_before_epoch": {
+			cbor:          true,
+			correlationID: "test-id-5",
+			creationTime:  time.Unix(-1, 0),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var (
+				frozen *FrozenEnvelope
+				err    error
+			)
+			if testCase.cbor {
+				frozen, err = envelope.FreezeCBOR()
+			} else {
+				frozen, err = envelope.Freeze()
+			}
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.cbor, frozen.IsCBOR())
+
+			expected := *envelope
+			expected.Headers = NewHeadersFrom(envelope.Headers,
+				WithCorrelationID(testCase.correlationID), WithCreationTime(testCase.creationTime))
+			var want bytes.Buffer
+			if testCase.cbor {
+				internal.AssertNil(t, EncodeCBOR(&want, &expected))
+			} else {
+				internal.AssertNil(t, expected.EncodeJSON(&want))
+			}
+
+			buf := bytes.NewBufferString("prefix")
+			frozen.EncodeTo(buf, testCase.correlationID, testCase.creationTime)
+			internal.AssertEqual(t, append([]byte("prefix"), want.Bytes()...), buf.Bytes())
+
+			thawed, err := frozen.Thaw(testCase.correlationID, testCase.creationTime)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.correlationID, thawed.Headers.CorrelationID())
+			internal.AssertEqual(t, testCase.creationTime.UnixNano()/int64(time.Millisecond),
+				thawed.Headers.CreationTime().UnixNano()/int64(time.Millisecond))
+			internal.AssertEqual(t, envelope.Path, thawed.Path)
+		})
+	}
+}
+
+func TestFreezeIsolated(t *testing.T) {
+	envelope := &Envelope{Headers: NewHeaders(WithCorrelationID("frozen")), Path: "/attributes", Value: 1}
+	frozen, err := envelope.Freeze()
+	internal.AssertNil(t, err)
+
+	internal.AssertEqual(t, "frozen", envelope.Headers.CorrelationID())
+	internal.AssertNil(t, envelope.Headers.Values[HeaderCreationTime])
+	envelope.Path = "/features"
+
+	thawed, err := frozen.Thaw("test-id", time.Now())
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "/attributes", thawed.Path)
+}
+
+func TestFreezeError(t *testing.T) {
+	_, err := (&Envelope{Value: make(chan int)}).Freeze()
+	internal.AssertNotNil(t, err)
+	_, err = (&Envelope{Value: make(chan int)}).FreezeCBOR()
+	internal.AssertNotNil(t, err)
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, value := range []string{"", "plain", "quoted\"", "back\\slash", "<tag>&", "new\nline", "ünïcödé"} {
+		want, _ := json.Marshal(value)
+		var buf bytes.Buffer
+		appendJSONString(&buf, value)
+		internal.AssertEqual(t, string(want), buf.String())
+	}
+}
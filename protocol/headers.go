@@ -12,7 +12,14 @@
 package protocol
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Ditto-specific headers constants.
@@ -23,29 +30,76 @@ const (
 	HeaderDryRun           = "ditto-dry-run"
 	HeaderOrigin           = "origin"
 	HeaderOriginator       = "ditto-originator"
-	HeaderETag             = "ETag"
-	HeaderIfMatch          = "If-Match"
-	HeaderIfNoneMatch      = "If-None-Match"
+	HeaderETag             = "etag"
+	HeaderIfMatch          = "if-match"
+	HeaderIfNoneMatch      = "if-none-match"
 	HeaderReplyTarget      = "ditto-reply-target"
 	HeaderReplyTo          = "reply-to"
 	HeaderTimeout          = "timeout"
 	HeaderSchemaVersion    = "version"
 	HeaderContentType      = "content-type"
+
+	HeaderLiveChannelCondition       = "live-channel-condition"
+	HeaderLiveChannelTimeoutStrategy = "live-channel-timeout-strategy"
+	HeaderRequestedAcks              = "requested-acks"
+	HeaderPutMetadata                = "put-metadata"
+	HeaderGetMetadata                = "get-metadata"
+	HeaderDeleteMetadata             = "delete-metadata"
+
+	HeaderMessageSubject   = "ditto-message-subject"
+	HeaderMessageDirection = "ditto-message-direction"
+	HeaderMessageThingID   = "ditto-message-thing-id"
+	HeaderMessageFeatureID = "ditto-message-feature-id"
+
+	HeaderTTL          = "ttl"
+	HeaderCreationTime = "creation-time"
+
+	HeaderContentEncoding = "content-encoding"
+
+	HeaderAtHistoricalRevision  = "at-historical-revision"
+	HeaderAtHistoricalTimestamp = "at-historical-timestamp"
 )
 
+// Live channel timeout strategies constants, applicable as 'live-channel-timeout-strategy' header values.
+const (
+	// LiveChannelTimeoutStrategyFail defines that the request fails with a timeout error if no live response is received in time.
+	LiveChannelTimeoutStrategyFail = "fail"
+	// LiveChannelTimeoutStrategyUseTwin defines that the twin's data is responded if no live response is received in time.
+	LiveChannelTimeoutStrategyUseTwin = "use-twin"
+)
+
+// Message directions constants, applicable as 'ditto-message-direction' header values.
+const (
+	// MessageDirectionTo defines a message sent to the inbox of a Thing or a Feature.
+	MessageDirectionTo = "TO"
+	// MessageDirectionFrom defines a message sent from the outbox of a Thing or a Feature.
+	MessageDirectionFrom = "FROM"
+)
+
+// MetadataEntry represents a single entry of the 'put-metadata' header value.
+// The Key is the JSON pointer path of the metadata to be set, relative to the path of the modified entity,
+// e.g. '/issuedBy' or '*/issuedAt' for all leafs.
+type MetadataEntry struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
 // Headers represents all Ditto-specific headers along with additional HTTP/etc. headers
 // that can be applied depending on the transport used.
+// As header names are case-insensitive, they are kept in their canonical lower-cased form as Values keys,
+// so that looking a header up is a single map access regardless of the number of headers. Values populated
+// directly are thus expected to use the canonical names, otherwise Set or NewHeadersFrom are to be used.
 // See https://www.eclipse.org/ditto/protocol-specification.html
 type Headers struct {
 	Values map[string]interface{}
 }
 
 // CorrelationID returns the 'correlation-id' header value or empty string if not set.
+// The Headers are never modified, use WithGeneratedCorrelationID to set a generated one.
+// As the header is read from every received message, a non-string value is reported as not set instead of panicking.
 func (h *Headers) CorrelationID() string {
-	if h.Values[HeaderCorrelationID] == nil {
-		return ""
-	}
-	return h.Values[HeaderCorrelationID].(string)
+	correlationID, _ := h.Values[HeaderCorrelationID].(string)
+	return correlationID
 }
 
 // Timeout returns the 'timeout' header value or empty string if not set.
@@ -56,6 +110,18 @@ func (h *Headers) Timeout() string {
 	return h.Values[HeaderTimeout].(string)
 }
 
+// TimeoutDuration returns the 'timeout' header value as a time.Duration.
+// A zero duration with no error means the sender does not wait for a response at all (fire-and-forget).
+// ErrTimeoutNotSet is returned if the header is not set and an error if its value is invalid.
+// No maximum is applied, see Headers.ValidateMaxTimeout.
+func (h *Headers) TimeoutDuration() (time.Duration, error) {
+	value, ok := h.Values[HeaderTimeout]
+	if !ok || value == nil {
+		return 0, ErrTimeoutNotSet
+	}
+	return parseTimeout(value)
+}
+
 // IsResponseRequired returns the 'response-required' header value or empty string if not set.
 func (h *Headers) IsResponseRequired() bool {
 	if h.Values[HeaderResponseRequired] == nil {
@@ -96,7 +162,7 @@ func (h *Headers) Originator() string {
 	return h.Values[HeaderOriginator].(string)
 }
 
-// ETag returns the 'ETag' header value or empty string if not set.
+// ETag returns the 'etag' header value or empty string if not set.
 func (h *Headers) ETag() string {
 	if h.Values[HeaderETag] == nil {
 		return ""
@@ -104,7 +170,7 @@ func (h *Headers) ETag() string {
 	return h.Values[HeaderETag].(string)
 }
 
-// IfMatch returns the 'If-Match' header value or empty string if not set.
+// IfMatch returns the 'if-match' header value or empty string if not set.
 func (h *Headers) IfMatch() string {
 	if h.Values[HeaderIfMatch] == nil {
 		return ""
@@ -112,7 +178,7 @@ func (h *Headers) IfMatch() string {
 	return h.Values[HeaderIfMatch].(string)
 }
 
-// IfNoneMatch returns the 'If-None-Match' header value or empty string if not set.
+// IfNoneMatch returns the 'if-none-match' header value or empty string if not set.
 func (h *Headers) IfNoneMatch() string {
 	if h.Values[HeaderIfNoneMatch] == nil {
 		return ""
@@ -120,12 +186,13 @@ func (h *Headers) IfNoneMatch() string {
 	return h.Values[HeaderIfNoneMatch].(string)
 }
 
-// ReplyTarget returns the 'ditto-reply-target' header value or empty string if not set.
+// ReplyTarget returns the 'ditto-reply-target' header value or 0 if not set.
+// Integer, JSON number and numeric string values are supported.
 func (h *Headers) ReplyTarget() int64 {
-	if h.Values[HeaderReplyTarget] == nil {
-		return 0
+	if value, ok := toInt64(h.Values[HeaderReplyTarget]); ok {
+		return value
 	}
-	return h.Values[HeaderReplyTarget].(int64)
+	return 0
 }
 
 // ReplyTo returns the 'reply-to' header value or empty string if not set.
@@ -136,12 +203,13 @@ func (h *Headers) ReplyTo() string {
 	return h.Values[HeaderReplyTo].(string)
 }
 
-// Version returns the 'version' header value or empty string if not set.
+// Version returns the 'version' header value or 0 if not set.
+// Integer, JSON number and numeric string values are supported.
 func (h *Headers) Version() int64 {
-	if h.Values[HeaderSchemaVersion] == nil {
-		return 0
+	if value, ok := toInt64(h.Values[HeaderSchemaVersion]); ok {
+		return value
 	}
-	return h.Values[HeaderSchemaVersion].(int64)
+	return 0
 }
 
 // ContentType returns the 'content-type' header value or empty string if not set.
@@ -152,22 +220,297 @@ func (h *Headers) ContentType() string {
 	return h.Values[HeaderContentType].(string)
 }
 
+// ContentEncoding returns the 'content-encoding' header value or empty string if not set.
+func (h *Headers) ContentEncoding() string {
+	if h.Values[HeaderContentEncoding] == nil {
+		return ""
+	}
+	return h.Values[HeaderContentEncoding].(string)
+}
+
+// LiveChannelCondition returns the 'live-channel-condition' header value or empty string if not set.
+func (h *Headers) LiveChannelCondition() string {
+	if h.Values[HeaderLiveChannelCondition] == nil {
+		return ""
+	}
+	return h.Values[HeaderLiveChannelCondition].(string)
+}
+
+// LiveChannelTimeoutStrategy returns the 'live-channel-timeout-strategy' header value or empty string if not set.
+func (h *Headers) LiveChannelTimeoutStrategy() string {
+	if h.Values[HeaderLiveChannelTimeoutStrategy] == nil {
+		return ""
+	}
+	return h.Values[HeaderLiveChannelTimeoutStrategy].(string)
+}
+
+// RequestedAcks returns the 'requested-acks' header value or nil if not set.
+// Both the JSON array and the comma-separated string representations of the header are supported.
+func (h *Headers) RequestedAcks() []string {
+	switch value := h.Values[HeaderRequestedAcks].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		acks := make([]string, 0, len(value))
+		for _, ack := range value {
+			if label, ok := ack.(string); ok {
+				acks = append(acks, label)
+			}
+		}
+		return acks
+	default:
+		return splitListHeader(value)
+	}
+}
+
+// PutMetadata returns the 'put-metadata' header value or nil if not set.
+func (h *Headers) PutMetadata() []MetadataEntry {
+	switch value := h.Values[HeaderPutMetadata].(type) {
+	case []MetadataEntry:
+		return value
+	case []interface{}:
+		entries := make([]MetadataEntry, 0, len(value))
+		for _, item := range value {
+			if entry, ok := item.(map[string]interface{}); ok {
+				key, _ := entry["key"].(string)
+				entries = append(entries, MetadataEntry{Key: key, Value: entry["value"]})
+			}
+		}
+		return entries
+	default:
+		return nil
+	}
+}
+
+// GetMetadata returns the 'get-metadata' header value as a list of metadata keys or nil if not set.
+func (h *Headers) GetMetadata() []string {
+	return splitListHeader(h.Values[HeaderGetMetadata])
+}
+
+// DeleteMetadata returns the 'delete-metadata' header value as a list of metadata keys or nil if not set.
+func (h *Headers) DeleteMetadata() []string {
+	return splitListHeader(h.Values[HeaderDeleteMetadata])
+}
+
+// MessageSubject returns the 'ditto-message-subject' header value or empty string if not set.
+func (h *Headers) MessageSubject() string {
+	if h.Values[HeaderMessageSubject] == nil {
+		return ""
+	}
+	return h.Values[HeaderMessageSubject].(string)
+}
+
+// MessageDirection returns the 'ditto-message-direction' header value or empty string if not set.
+func (h *Headers) MessageDirection() string {
+	if h.Values[HeaderMessageDirection] == nil {
+		return ""
+	}
+	return h.Values[HeaderMessageDirection].(string)
+}
+
+// MessageThingID returns the 'ditto-message-thing-id' header value or empty string if not set.
+func (h *Headers) MessageThingID() string {
+	if h.Values[HeaderMessageThingID] == nil {
+		return ""
+	}
+	return h.Values[HeaderMessageThingID].(string)
+}
+
+// MessageFeatureID returns the 'ditto-message-feature-id' header value or empty string if not set.
+func (h *Headers) MessageFeatureID() string {
+	if h.Values[HeaderMessageFeatureID] == nil {
+		return ""
+	}
+	return h.Values[HeaderMessageFeatureID].(string)
+}
+
+// TTL returns the 'ttl' header value, i.e. the time-to-live of the message in seconds, or 0 if not set.
+func (h *Headers) TTL() time.Duration {
+	if ttl, ok := toInt64(h.Values[HeaderTTL]); ok {
+		return time.Duration(ttl) * time.Second
+	}
+	return 0
+}
+
+// CreationTime returns the 'creation-time' header value, i.e. the time the message was created
+// in milliseconds since the Unix epoch, or the zero time if not set.
+func (h *Headers) CreationTime() time.Time {
+	if millis, ok := toInt64(h.Values[HeaderCreationTime]); ok {
+		return time.Unix(0, millis*int64(time.Millisecond))
+	}
+	return time.Time{}
+}
+
+// AtHistoricalRevision returns the 'at-historical-revision' header value, i.e. the revision of the entity
+// to be retrieved from its history, or 0 if not set.
+func (h *Headers) AtHistoricalRevision() int64 {
+	if revision, ok := toInt64(h.Values[HeaderAtHistoricalRevision]); ok {
+		return revision
+	}
+	return 0
+}
+
+// AtHistoricalTimestamp returns the 'at-historical-timestamp' header value, i.e. the point in time
+// of the entity's history to be retrieved, or the zero time if not set or invalid.
+func (h *Headers) AtHistoricalTimestamp() time.Time {
+	if value, ok := h.Values[HeaderAtHistoricalTimestamp].(string); ok {
+		if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return timestamp
+		}
+	}
+	return time.Time{}
+}
+
+// IsExpired returns true if both 'creation-time' and 'ttl' headers are set and the time-to-live of the message has elapsed.
+func (h *Headers) IsExpired() bool {
+	ttl := h.TTL()
+	created := h.CreationTime()
+	if ttl <= 0 || created.IsZero() {
+		return false
+	}
+	return time.Now().After(created.Add(ttl))
+}
+
 // Generic returns the value of the provided key header and if a header with such key is present.
+// The key is case-insensitive.
 func (h *Headers) Generic(id string) interface{} {
-	return h.Values[id]
+	return h.Get(id)
+}
+
+// Get returns the value of the header with the provided case-insensitive name or nil if not set.
+func (h *Headers) Get(name string) interface{} {
+	return h.Values[CanonicalHeaderName(name)]
+}
+
+// Set sets the value of the header with the provided case-insensitive name.
+func (h *Headers) Set(name string, value interface{}) {
+	if h.Values == nil {
+		h.Values = make(map[string]interface{})
+	}
+	h.Values[CanonicalHeaderName(name)] = value
+}
+
+// Del removes the header with the provided case-insensitive name.
+func (h *Headers) Del(name string) {
+	delete(h.Values, CanonicalHeaderName(name))
+}
+
+// CanonicalHeaderName returns the canonical form of the provided header name, i.e. its lower-cased form.
+func CanonicalHeaderName(name string) string {
+	return strings.ToLower(name)
+}
+
+// Clone returns a deep copy of the Headers, so that modifying the copy's values doesn't affect the original ones.
+func (h *Headers) Clone() *Headers {
+	if h == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(h.Values))
+	for name, value := range h.Values {
+		values[name] = deepCopyValue(value)
+	}
+	return &Headers{Values: values}
 }
 
 // MarshalJSON marshels Headers.
+// The headers, as well as the members of any nested JSON object values, are always marshaled sorted by their names,
+// so that the same Headers result in the same bytes, e.g. for signing, deduplication or comparing payloads.
 func (h *Headers) MarshalJSON() ([]byte, error) {
 	return json.Marshal(h.Values)
 }
 
 // UnmarshalJSON unmarshels Headers.
+// The header names are canonicalized and if the same header is provided multiple times
+// with differently-cased names, the last occurrence wins.
 func (h *Headers) UnmarshalJSON(data []byte) error {
-	var v map[string]interface{}
-	if err := json.Unmarshal(data, &v); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
 		return err
 	}
-	h.Values = v
+	if tok == nil {
+		h.Values = make(map[string]interface{})
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.New("invalid headers, a JSON object is expected")
+	}
+
+	values := make(map[string]interface{})
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		values[CanonicalHeaderName(tok.(string))] = value
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	h.Values = values
 	return nil
 }
+
+func splitListHeader(value interface{}) []string {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	list := []string{}
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true
+		}
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Copy the provided values with canonical header names. If the same header is present multiple times
+// with differently-cased names, the already canonical one wins, otherwise - the last one in sort order.
+func canonicalHeaderValues(values map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(values))
+	var nonCanonical []string
+	for key, value := range values {
+		if canonical := CanonicalHeaderName(key); canonical == key {
+			res[key] = value
+		} else {
+			nonCanonical = append(nonCanonical, key)
+		}
+	}
+	sort.Strings(nonCanonical)
+	for _, key := range nonCanonical {
+		canonical := CanonicalHeaderName(key)
+		if _, ok := values[canonical]; !ok {
+			res[canonical] = values[key]
+		}
+	}
+	return res
+}
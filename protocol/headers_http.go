@@ -0,0 +1,92 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewHeadersFromHTTP creates new Headers from the provided HTTP headers, e.g. of a request to the Ditto HTTP API.
+// The header names are canonicalized and multiple values of the same header are joined with commas.
+// The boolean headers (e.g. 'response-required') and the 'put-metadata' JSON header are converted
+// to their Ditto protocol representation, all other headers are kept as strings.
+func NewHeadersFromHTTP(header http.Header) *Headers {
+	res := NewHeaders()
+	for name, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		name = CanonicalHeaderName(name)
+		value := strings.Join(values, ",")
+		switch name {
+		case HeaderResponseRequired, HeaderDryRun:
+			if b, err := strconv.ParseBool(value); err == nil {
+				res.Values[name] = b
+				continue
+			}
+		case HeaderPutMetadata:
+			var entries []interface{}
+			if err := json.Unmarshal([]byte(value), &entries); err == nil {
+				res.Values[name] = entries
+				continue
+			}
+		}
+		res.Values[name] = value
+	}
+	return res
+}
+
+// ToHTTP converts the Headers into HTTP headers, e.g. for a request to the Ditto HTTP API.
+// String values are kept as they are, list values (e.g. 'requested-acks') are joined with commas,
+// and all other values are set as their JSON representation.
+func (h *Headers) ToHTTP() http.Header {
+	res := make(http.Header, len(h.Values))
+	for name, value := range h.Values {
+		if value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			res.Set(name, v)
+		case []string:
+			res.Set(name, strings.Join(v, ","))
+		case []interface{}:
+			if items, ok := stringItems(v); ok {
+				res.Set(name, strings.Join(items, ","))
+				continue
+			}
+			if data, err := json.Marshal(v); err == nil {
+				res.Set(name, string(data))
+			}
+		default:
+			if data, err := json.Marshal(v); err == nil {
+				res.Set(name, string(data))
+			}
+		}
+	}
+	return res
+}
+
+func stringItems(values []interface{}) ([]string, bool) {
+	items := make([]string, len(values))
+	for i, value := range values {
+		item, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		items[i] = item
+	}
+	return items, true
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestNewHeadersFromHTTP(t *testing.T) {
+	header := http.Header{}
+	header.Set("Correlation-Id", "id")
+	header.Set("Response-Required", "false")
+	header.Set("Ditto-Dry-Run", "not-a-bool")
+	header.Add("Requested-Acks", "twin-persisted")
+	header.Add("Requested-Acks", "custom")
+	header.Set("Put-Metadata", `[{"key":"/issuedBy","value":"me"}]`)
+	header.Set("Version", "2")
+	header["empty"] = []string{}
+
+	got := NewHeadersFromHTTP(header)
+	internal.AssertEqual(t, "id", got.CorrelationID())
+	internal.AssertFalse(t, got.IsResponseRequired())
+	internal.AssertEqual(t, false, got.Values[HeaderResponseRequired])
+	internal.AssertEqual(t, "not-a-bool", got.Values[HeaderDryRun])
+	internal.AssertEqual(t, []string{"twin-persisted", "custom"}, got.RequestedAcks())
+	internal.AssertEqual(t, []MetadataEntry{{Key: "/issuedBy", Value: "me"}}, got.PutMetadata())
+	internal.AssertEqual(t, int64(2), got.Version())
+	internal.AssertNil(t, got.Values["empty"])
+	internal.AssertEqual(t, 6, len(got.Values))
+}
+
+func TestNewHeadersFromHTTPValidate(t *testing.T) {
+	header := http.Header{}
+	header.Set("Correlation-Id", "id")
+	header.Set("Response-Required", "true")
+	header.Set("Requested-Acks", "twin-persisted,custom")
+	header.Set("Timeout", "10s")
+
+	internal.AssertNil(t, NewHeadersFromHTTP(header).Validate())
+
+	header.Set("Requested-Acks", "twin-persisted,")
+	internal.AssertNotNil(t, NewHeadersFromHTTP(header).Validate())
+}
+
+func TestHeadersToHTTP(t *testing.T) {
+	headers := NewHeaders(
+		WithCorrelationID("id"),
+		WithResponseRequired(true),
+		WithRequestedAcks("twin-persisted", "custom"),
+		WithPutMetadata(MetadataEntry{Key: "/issuedBy", Value: "me"}),
+		WithTTL(10*time.Second),
+		WithGeneric("x-list", []interface{}{"a", "b"}),
+		WithGeneric("x-mixed", []interface{}{"a", 1}),
+		WithGeneric("x-nil", nil),
+	)
+
+	got := headers.ToHTTP()
+	internal.AssertEqual(t, "id", got.Get("correlation-id"))
+	internal.AssertEqual(t, "true", got.Get("response-required"))
+	internal.AssertEqual(t, "twin-persisted,custom", got.Get("requested-acks"))
+	internal.AssertEqual(t, `[{"key":"/issuedBy","value":"me"}]`, got.Get("put-metadata"))
+	internal.AssertEqual(t, "10", got.Get("ttl"))
+	internal.AssertEqual(t, "a,b", got.Get("x-list"))
+	internal.AssertEqual(t, `["a",1]`, got.Get("x-mixed"))
+	internal.AssertEqual(t, 7, len(got))
+
+	roundTrip := NewHeadersFromHTTP(got)
+	internal.AssertEqual(t, "id", roundTrip.CorrelationID())
+	internal.AssertTrue(t, roundTrip.IsResponseRequired())
+	internal.AssertEqual(t, headers.RequestedAcks(), roundTrip.RequestedAcks())
+	internal.AssertEqual(t, headers.PutMetadata(), roundTrip.PutMetadata())
+	internal.AssertEqual(t, 10*time.Second, roundTrip.TTL())
+}
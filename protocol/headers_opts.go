@@ -11,6 +11,12 @@
 
 package protocol
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // HeaderOpt represents a specific Headers option that can be applied to the Headers instance
 // resulting in changing the value of a specific header of a set of headers.
 type HeaderOpt func(headers *Headers) error
@@ -35,15 +41,13 @@ func NewHeaders(opts ...HeaderOpt) *Headers {
 }
 
 // NewHeadersFrom returns a new Headers instance using the provided header.
+// The header names of the new instance are canonicalized.
 func NewHeadersFrom(orig *Headers, opts ...HeaderOpt) *Headers {
 	if orig == nil {
 		return NewHeaders(opts...)
 	}
 	res := &Headers{
-		Values: make(map[string]interface{}),
-	}
-	for key, value := range orig.Values {
-		res.Values[key] = value
+		Values: canonicalHeaderValues(orig.Values),
 	}
 	if err := applyOptsHeader(res, opts...); err != nil {
 		return nil
@@ -59,6 +63,17 @@ func WithCorrelationID(correlationID string) HeaderOpt {
 	}
 }
 
+// WithGeneratedCorrelationID sets the 'correlation-id' header value to a new correlation ID
+// generated by the configured CorrelationIDGenerator, if the header is not already set.
+func WithGeneratedCorrelationID() HeaderOpt {
+	return func(headers *Headers) error {
+		if headers.Values[HeaderCorrelationID] == nil {
+			headers.Values[HeaderCorrelationID] = NewCorrelationID()
+		}
+		return nil
+	}
+}
+
 // WithReplyTo sets the 'reply-to' header value.
 func WithReplyTo(replyTo string) HeaderOpt {
 	return func(headers *Headers) error {
@@ -115,7 +130,7 @@ func WithDryRun(isDryRun bool) HeaderOpt {
 	}
 }
 
-// WithETag sets the 'ETag' header value.
+// WithETag sets the 'etag' header value.
 func WithETag(eTag string) HeaderOpt {
 	return func(headers *Headers) error {
 		headers.Values[HeaderETag] = eTag
@@ -123,7 +138,7 @@ func WithETag(eTag string) HeaderOpt {
 	}
 }
 
-// WithIfMatch sets the 'If-Match' header value.
+// WithIfMatch sets the 'if-match' header value.
 func WithIfMatch(ifMatch string) HeaderOpt {
 	return func(headers *Headers) error {
 		headers.Values[HeaderIfMatch] = ifMatch
@@ -131,7 +146,7 @@ func WithIfMatch(ifMatch string) HeaderOpt {
 	}
 }
 
-// WithIfNoneMatch sets the 'If-None-Match' header value.
+// WithIfNoneMatch sets the 'if-none-match' header value.
 func WithIfNoneMatch(ifNoneMatch string) HeaderOpt {
 	return func(headers *Headers) error {
 		headers.Values[HeaderIfNoneMatch] = ifNoneMatch
@@ -147,6 +162,27 @@ func WithTimeout(timeout string) HeaderOpt {
 	}
 }
 
+// WithTimeoutDuration sets the 'timeout' header value from a time.Duration.
+// A zero duration is rendered as "0" meaning that no response is to be waited for. Durations with a sub-millisecond
+// precision are rounded up to whole milliseconds. Negative durations and durations exceeding DefaultMaxTimeout
+// are reported by Headers.Validate, see Headers.ValidateMaxTimeout for a different maximum.
+func WithTimeoutDuration(timeout time.Duration) HeaderOpt {
+	return func(headers *Headers) error {
+		if timeout > 0 && timeout%time.Millisecond != 0 {
+			timeout += time.Millisecond - timeout%time.Millisecond
+		}
+		switch {
+		case timeout == 0:
+			headers.Values[HeaderTimeout] = "0"
+		case timeout%time.Second == 0:
+			headers.Values[HeaderTimeout] = fmt.Sprintf("%ds", timeout/time.Second)
+		default:
+			headers.Values[HeaderTimeout] = fmt.Sprintf("%dms", timeout/time.Millisecond)
+		}
+		return nil
+	}
+}
+
 // WithSchemaVersion sets the 'version' header value.
 func WithSchemaVersion(schemaVersion string) HeaderOpt {
 	return func(headers *Headers) error {
@@ -163,10 +199,165 @@ func WithContentType(contentType string) HeaderOpt {
 	}
 }
 
-// WithGeneric sets the value of the provided key header.
+// WithContentEncoding sets the 'content-encoding' header value.
+func WithContentEncoding(contentEncoding string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderContentEncoding] = contentEncoding
+		return nil
+	}
+}
+
+// WithLiveChannelCondition sets the 'live-channel-condition' header value.
+// The condition is an RQL expression which determines if a twin command is to be routed to the live channel.
+// An empty condition is reported by Headers.Validate.
+func WithLiveChannelCondition(condition string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderLiveChannelCondition] = condition
+		return nil
+	}
+}
+
+// WithLiveChannelTimeoutStrategy sets the 'live-channel-timeout-strategy' header value.
+// The strategy must be one of LiveChannelTimeoutStrategyFail or LiveChannelTimeoutStrategyUseTwin,
+// any other one is reported by Headers.Validate.
+func WithLiveChannelTimeoutStrategy(strategy string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderLiveChannelTimeoutStrategy] = strategy
+		return nil
+	}
+}
+
+// WithRequestedAcks sets the 'requested-acks' header value to the provided acknowledgement labels.
+// The header is serialized as a JSON array. If no labels are provided, no acknowledgements are requested.
+func WithRequestedAcks(labels ...string) HeaderOpt {
+	return func(headers *Headers) error {
+		acks := make([]string, len(labels))
+		copy(acks, labels)
+		headers.Values[HeaderRequestedAcks] = acks
+		return nil
+	}
+}
+
+// WithPutMetadata sets the 'put-metadata' header value to the provided metadata entries
+// to be applied along with a modify command. Entries with an empty key are reported by Headers.Validate.
+func WithPutMetadata(entries ...MetadataEntry) HeaderOpt {
+	return func(headers *Headers) error {
+		metadata := make([]MetadataEntry, len(entries))
+		copy(metadata, entries)
+		headers.Values[HeaderPutMetadata] = metadata
+		return nil
+	}
+}
+
+// WithGetMetadata sets the 'get-metadata' header value to the provided metadata keys to be retrieved.
+func WithGetMetadata(keys ...string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderGetMetadata] = strings.Join(keys, ",")
+		return nil
+	}
+}
+
+// WithDeleteMetadata sets the 'delete-metadata' header value to the provided metadata keys to be deleted.
+func WithDeleteMetadata(keys ...string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderDeleteMetadata] = strings.Join(keys, ",")
+		return nil
+	}
+}
+
+// WithMessageSubject sets the 'ditto-message-subject' header value.
+func WithMessageSubject(subject string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderMessageSubject] = subject
+		return nil
+	}
+}
+
+// WithMessageDirection sets the 'ditto-message-direction' header value.
+// The direction must be one of MessageDirectionTo or MessageDirectionFrom, any other one is reported by Headers.Validate.
+func WithMessageDirection(direction string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderMessageDirection] = direction
+		return nil
+	}
+}
+
+// WithMessageThingID sets the 'ditto-message-thing-id' header value.
+func WithMessageThingID(thingID string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderMessageThingID] = thingID
+		return nil
+	}
+}
+
+// WithMessageFeatureID sets the 'ditto-message-feature-id' header value.
+func WithMessageFeatureID(featureID string) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderMessageFeatureID] = featureID
+		return nil
+	}
+}
+
+// WithTTL sets the 'ttl' header value, i.e. the time-to-live of the message, in whole seconds.
+// A time-to-live of less than a second is reported by Headers.Validate.
+func WithTTL(ttl time.Duration) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderTTL] = int64(ttl / time.Second)
+		return nil
+	}
+}
+
+// WithCreationTime sets the 'creation-time' header value in milliseconds since the Unix epoch.
+func WithCreationTime(creationTime time.Time) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderCreationTime] = creationTime.UnixNano() / int64(time.Millisecond)
+		return nil
+	}
+}
+
+// WithGeneric sets the value of the provided case-insensitive key header.
 func WithGeneric(headerID string, value interface{}) HeaderOpt {
 	return func(headers *Headers) error {
-		headers.Values[headerID] = value
+		headers.Set(headerID, value)
+		return nil
+	}
+}
+
+// WithAtHistoricalRevision sets the 'at-historical-revision' header value to retrieve the entity at the provided revision of its history.
+// A revision less than 1 is reported by Headers.Validate.
+func WithAtHistoricalRevision(revision int64) HeaderOpt {
+	return func(headers *Headers) error {
+		headers.Values[HeaderAtHistoricalRevision] = revision
+		return nil
+	}
+}
+
+// WithAtHistoricalTimestamp sets the 'at-historical-timestamp' header value to retrieve the entity
+// at the provided point in time of its history. A zero timestamp is set as an empty value, which is reported by Headers.Validate.
+func WithAtHistoricalTimestamp(timestamp time.Time) HeaderOpt {
+	return func(headers *Headers) error {
+		if timestamp.IsZero() {
+			headers.Values[HeaderAtHistoricalTimestamp] = ""
+		} else {
+			headers.Values[HeaderAtHistoricalTimestamp] = timestamp.UTC().Format(time.RFC3339Nano)
+		}
+		return nil
+	}
+}
+
+// WithReplyHeadersFrom sets the 'correlation-id', 'content-type' and 'ditto-originator' header values to the ones
+// of the provided request Headers, unless they are already set, and the 'response-required' header value to false,
+// as expected for a reply to the request.
+func WithReplyHeadersFrom(request *Headers) HeaderOpt {
+	return func(headers *Headers) error {
+		if request != nil {
+			for _, header := range []string{HeaderCorrelationID, HeaderContentType, HeaderOriginator} {
+				if value := request.Get(header); value != nil && headers.Values[header] == nil {
+					headers.Values[header] = value
+				}
+			}
+		}
+		headers.Values[HeaderResponseRequired] = false
 		return nil
 	}
 }
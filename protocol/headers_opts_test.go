@@ -14,6 +14,7 @@ package protocol
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
 )
@@ -179,6 +180,21 @@ func TestWithCorrelationID(t *testing.T) {
 	})
 }
 
+func TestWithGeneratedCorrelationID(t *testing.T) {
+	defer SetCorrelationIDGenerator(nil)
+	SetCorrelationIDGenerator(PrefixedCorrelationIDs("test-"))
+
+	t.Run("TestWithGeneratedCorrelationID", func(t *testing.T) {
+		got := NewHeaders(WithGeneratedCorrelationID())
+		internal.AssertEqual(t, "test-1", got.CorrelationID())
+	})
+
+	t.Run("TestWithGeneratedCorrelationIDAlreadySet", func(t *testing.T) {
+		got := NewHeaders(WithCorrelationID("existing"), WithGeneratedCorrelationID())
+		internal.AssertEqual(t, "existing", got.CorrelationID())
+	})
+}
+
 func TestWithReplyTo(t *testing.T) {
 	t.Run("TestWithReplyTo", func(t *testing.T) {
 		rto := "replyto"
@@ -278,6 +294,58 @@ func TestWithTimeout(t *testing.T) {
 	})
 }
 
+func TestWithTimeoutDuration(t *testing.T) {
+	tests := map[string]struct {
+		arg     time.Duration
+		want    string
+		wantErr bool
+	}{
+		"test_zero": {
+			arg:  0,
+			want: "0",
+		},
+		"test_seconds": {
+			arg:  30 * time.Second,
+			want: "30s",
+		},
+		"test_milliseconds": {
+			arg:  1500 * time.Millisecond,
+			want: "1500ms",
+		},
+		"test_negative": {
+			arg:     -time.Second,
+			want:    "-1s",
+			wantErr: true,
+		},
+		"test_exceeding_max": {
+			arg:     2 * time.Minute,
+			want:    "120s",
+			wantErr: true,
+		},
+		"test_sub_millisecond": {
+			arg:  time.Microsecond,
+			want: "1ms",
+		},
+		"test_sub_millisecond_precision": {
+			arg:  1500*time.Millisecond + time.Microsecond,
+			want: "1501ms",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			headers := NewHeaders(WithCorrelationID("correlation-id"), WithTimeoutDuration(testCase.arg))
+			internal.AssertEqual(t, "correlation-id", headers.CorrelationID())
+			internal.AssertEqual(t, testCase.want, headers.Timeout())
+			if testCase.wantErr {
+				internal.AssertNotNil(t, headers.Validate())
+			} else {
+				internal.AssertNil(t, headers.Validate())
+			}
+		})
+	}
+}
+
 func TestWithSchemaVersion(t *testing.T) {
 	t.Run("TestWithSchemaVersion", func(t *testing.T) {
 		sv := "123456789"
@@ -296,6 +364,13 @@ func TestWithContentType(t *testing.T) {
 	})
 }
 
+func TestWithContentEncoding(t *testing.T) {
+	t.Run("TestWithContentEncoding", func(t *testing.T) {
+		got := NewHeaders(WithContentEncoding(ContentEncodingGzip))
+		internal.AssertEqual(t, ContentEncodingGzip, got.ContentEncoding())
+	})
+}
+
 func TestWithGeneric(t *testing.T) {
 	t.Run("TestWithGeneric", func(t *testing.T) {
 		hct := "contentType"
@@ -304,3 +379,263 @@ func TestWithGeneric(t *testing.T) {
 		internal.AssertEqual(t, hct, got.ContentType())
 	})
 }
+
+func TestWithLiveChannelCondition(t *testing.T) {
+	t.Run("TestWithLiveChannelCondition", func(t *testing.T) {
+		condition := "eq(attributes/online,true)"
+
+		got := NewHeaders(WithLiveChannelCondition(condition))
+		internal.AssertEqual(t, condition, got.LiveChannelCondition())
+
+		got = NewHeaders(WithCorrelationID("correlation-id"), WithLiveChannelCondition(""))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithLiveChannelTimeoutStrategy(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		wantErr bool
+	}{
+		"test_strategy_fail": {
+			arg: LiveChannelTimeoutStrategyFail,
+		},
+		"test_strategy_use_twin": {
+			arg: LiveChannelTimeoutStrategyUseTwin,
+		},
+		"test_strategy_invalid": {
+			arg:     "retry",
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			headers := NewHeaders(WithCorrelationID("correlation-id"), WithLiveChannelTimeoutStrategy(testCase.arg))
+			internal.AssertEqual(t, "correlation-id", headers.CorrelationID())
+			internal.AssertEqual(t, testCase.arg, headers.LiveChannelTimeoutStrategy())
+			if testCase.wantErr {
+				internal.AssertNotNil(t, headers.Validate())
+			} else {
+				internal.AssertNil(t, headers.Validate())
+			}
+		})
+	}
+}
+
+func TestWithRequestedAcks(t *testing.T) {
+	t.Run("TestWithRequestedAcks", func(t *testing.T) {
+		got := NewHeaders(WithRequestedAcks("twin-persisted", "custom"))
+		internal.AssertEqual(t, []string{"twin-persisted", "custom"}, got.RequestedAcks())
+
+		data, err := got.MarshalJSON()
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"requested-acks":["twin-persisted","custom"]}`, string(data))
+
+		unmarshaled := NewHeaders()
+		internal.AssertNil(t, unmarshaled.UnmarshalJSON(data))
+		internal.AssertEqual(t, []string{"twin-persisted", "custom"}, unmarshaled.RequestedAcks())
+	})
+
+	t.Run("TestWithRequestedAcksEmpty", func(t *testing.T) {
+		got := NewHeaders(WithRequestedAcks())
+
+		data, err := got.MarshalJSON()
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"requested-acks":[]}`, string(data))
+	})
+}
+
+func TestWithPutMetadata(t *testing.T) {
+	t.Run("TestWithPutMetadata", func(t *testing.T) {
+		got := NewHeaders(WithPutMetadata(MetadataEntry{Key: "*/issuedAt", Value: "2022-01-01T00:00:00Z"}))
+
+		data, err := got.MarshalJSON()
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, `{"put-metadata":[{"key":"*/issuedAt","value":"2022-01-01T00:00:00Z"}]}`, string(data))
+
+		unmarshaled := NewHeaders()
+		internal.AssertNil(t, unmarshaled.UnmarshalJSON(data))
+		internal.AssertEqual(t, got.PutMetadata(), unmarshaled.PutMetadata())
+	})
+
+	t.Run("TestWithPutMetadataEmptyKey", func(t *testing.T) {
+		got := NewHeaders(WithCorrelationID("correlation-id"), WithPutMetadata(MetadataEntry{Value: 1}))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithGetMetadata(t *testing.T) {
+	t.Run("TestWithGetMetadata", func(t *testing.T) {
+		got := NewHeaders(WithGetMetadata("attributes/location", "features/lamp"))
+		internal.AssertEqual(t, "attributes/location,features/lamp", got.Values[HeaderGetMetadata])
+		internal.AssertEqual(t, []string{"attributes/location", "features/lamp"}, got.GetMetadata())
+	})
+}
+
+func TestWithDeleteMetadata(t *testing.T) {
+	t.Run("TestWithDeleteMetadata", func(t *testing.T) {
+		got := NewHeaders(WithDeleteMetadata("attributes/location"))
+		internal.AssertEqual(t, []string{"attributes/location"}, got.DeleteMetadata())
+	})
+}
+
+func TestWithMessageHeaders(t *testing.T) {
+	t.Run("TestWithMessageHeaders", func(t *testing.T) {
+		got := NewHeaders(
+			WithMessageSubject("turnOn"),
+			WithMessageDirection(MessageDirectionFrom),
+			WithMessageThingID("org.eclipse.ditto:thing"),
+			WithMessageFeatureID("lamp"),
+		)
+		internal.AssertEqual(t, "turnOn", got.MessageSubject())
+		internal.AssertEqual(t, MessageDirectionFrom, got.MessageDirection())
+		internal.AssertEqual(t, "org.eclipse.ditto:thing", got.MessageThingID())
+		internal.AssertEqual(t, "lamp", got.MessageFeatureID())
+	})
+
+	t.Run("TestWithMessageDirectionInvalid", func(t *testing.T) {
+		got := NewHeaders(WithCorrelationID("correlation-id"), WithMessageDirection("to"))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithTTL(t *testing.T) {
+	t.Run("TestWithTTL", func(t *testing.T) {
+		got := NewHeaders(WithTTL(90 * time.Second))
+		internal.AssertEqual(t, int64(90), got.Values[HeaderTTL])
+		internal.AssertEqual(t, 90*time.Second, got.TTL())
+
+		got = NewHeaders(WithCorrelationID("correlation-id"), WithTTL(time.Millisecond))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithCreationTime(t *testing.T) {
+	t.Run("TestWithCreationTime", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		got := NewHeaders(WithCreationTime(created))
+		internal.AssertEqual(t, int64(1640995200000), got.Values[HeaderCreationTime])
+		internal.AssertTrue(t, created.Equal(got.CreationTime()))
+	})
+}
+
+func TestNewHeadersFromCanonical(t *testing.T) {
+	tests := map[string]struct {
+		orig map[string]interface{}
+		want map[string]interface{}
+	}{
+		"test_non_canonical_names": {
+			orig: map[string]interface{}{"Content-Type": "text/plain"},
+			want: map[string]interface{}{HeaderContentType: "text/plain"},
+		},
+		"test_canonical_name_wins": {
+			orig: map[string]interface{}{
+				"Content-Type": "text/plain",
+				"content-type": "application/json",
+				"CONTENT-TYPE": "application/xml",
+			},
+			want: map[string]interface{}{HeaderContentType: "application/json"},
+		},
+		"test_last_in_sort_order_wins": {
+			orig: map[string]interface{}{
+				"Content-Type": "text/plain",
+				"CONTENT-TYPE": "application/xml",
+			},
+			want: map[string]interface{}{HeaderContentType: "text/plain"},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewHeadersFrom(&Headers{Values: testCase.orig})
+			internal.AssertEqual(t, testCase.want, got.Values)
+		})
+	}
+}
+
+func TestWithGenericCaseInsensitive(t *testing.T) {
+	t.Run("TestWithGenericCaseInsensitive", func(t *testing.T) {
+		got := NewHeaders(WithGeneric("Content-Type", "text/plain"))
+		internal.AssertEqual(t, "text/plain", got.ContentType())
+	})
+}
+
+func TestWithAtHistoricalRevision(t *testing.T) {
+	t.Run("TestWithAtHistoricalRevision", func(t *testing.T) {
+		got := NewHeaders(WithAtHistoricalRevision(42))
+		internal.AssertEqual(t, int64(42), got.AtHistoricalRevision())
+
+		got = NewHeaders(WithCorrelationID("correlation-id"), WithAtHistoricalRevision(0))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithAtHistoricalTimestamp(t *testing.T) {
+	t.Run("TestWithAtHistoricalTimestamp", func(t *testing.T) {
+		timestamp := time.Date(2022, 1, 1, 11, 0, 0, 0, time.FixedZone("CET", 3600))
+
+		got := NewHeaders(WithAtHistoricalTimestamp(timestamp))
+		internal.AssertEqual(t, "2022-01-01T10:00:00Z", got.Values[HeaderAtHistoricalTimestamp])
+		internal.AssertTrue(t, timestamp.Equal(got.AtHistoricalTimestamp()))
+		internal.AssertNil(t, got.Validate())
+
+		got = NewHeaders(WithCorrelationID("correlation-id"), WithAtHistoricalTimestamp(time.Time{}))
+		internal.AssertEqual(t, "correlation-id", got.CorrelationID())
+		internal.AssertNotNil(t, got.Validate())
+	})
+}
+
+func TestWithReplyHeadersFrom(t *testing.T) {
+	request := NewHeaders(
+		WithCorrelationID("request-id"),
+		WithContentType(ContentTypeJSON),
+		WithOriginator("nginx:ditto"),
+		WithResponseRequired(true),
+		WithChannel("live"),
+	)
+
+	tests := map[string]struct {
+		request *Headers
+		reply   *Headers
+		want    map[string]interface{}
+	}{
+		"test_reply_without_headers": {
+			request: request,
+			want: map[string]interface{}{
+				HeaderCorrelationID:    "request-id",
+				HeaderContentType:      ContentTypeJSON,
+				HeaderOriginator:       "nginx:ditto",
+				HeaderResponseRequired: false,
+			},
+		},
+		"test_reply_with_headers": {
+			request: request,
+			reply:   NewHeaders(WithCorrelationID("reply-id"), WithContentType(ContentTypeText), WithResponseRequired(true)),
+			want: map[string]interface{}{
+				HeaderCorrelationID:    "reply-id",
+				HeaderContentType:      ContentTypeText,
+				HeaderOriginator:       "nginx:ditto",
+				HeaderResponseRequired: false,
+			},
+		},
+		"test_request_without_headers": {
+			want: map[string]interface{}{
+				HeaderResponseRequired: false,
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewHeadersFrom(testCase.reply, WithReplyHeadersFrom(testCase.request))
+			internal.AssertEqual(t, testCase.want, got.Values)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// SyncHeaders is a concurrency-safe variant of Headers, e.g. for headers shared and modified across goroutines.
+// While the Headers getters never modify the Headers, the plain Headers are not safe for concurrent use if any
+// of the goroutines modifies them. The zero value of SyncHeaders is empty headers ready to use.
+type SyncHeaders struct {
+	lock    sync.RWMutex
+	headers Headers
+}
+
+// NewSyncHeaders creates a new SyncHeaders instance with a deep copy of the provided Headers,
+// so that the provided ones could be further modified independently.
+func NewSyncHeaders(headers *Headers) *SyncHeaders {
+	res := &SyncHeaders{}
+	if copied := NewHeadersFrom(headers.Clone()); copied != nil {
+		res.headers.Values = copied.Values
+	}
+	return res
+}
+
+// Get returns the value of the header with the provided case-insensitive name or nil if not set.
+// Composite values, e.g. maps and slices, are shared and must not be modified.
+func (h *SyncHeaders) Get(name string) interface{} {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.headers.Get(name)
+}
+
+// Set sets the value of the header with the provided case-insensitive name.
+func (h *SyncHeaders) Set(name string, value interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.headers.Set(name, value)
+}
+
+// Del removes the header with the provided case-insensitive name.
+func (h *SyncHeaders) Del(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.headers.Del(name)
+}
+
+// Headers provides a deep copy of the current headers, e.g. to use the typed getters or to be set to an Envelope.
+func (h *SyncHeaders) Headers() *Headers {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.headers.Clone()
+}
+
+// Read calls the provided function with the current headers while holding the read lock, so that multiple headers
+// could be read consistently without copying them. The function must neither modify nor retain the Headers.
+func (h *SyncHeaders) Read(read func(headers *Headers)) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	read(&h.headers)
+}
+
+// Update calls the provided function with the current headers while holding the write lock, so that multiple headers
+// could be modified atomically. The function must not retain the Headers.
+func (h *SyncHeaders) Update(update func(headers *Headers)) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	update(&h.headers)
+}
+
+// MarshalJSON marshals SyncHeaders the same way as Headers.
+func (h *SyncHeaders) MarshalJSON() ([]byte, error) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return json.Marshal(&h.headers)
+}
+
+// UnmarshalJSON unmarshals SyncHeaders the same way as Headers.
+func (h *SyncHeaders) UnmarshalJSON(data []byte) error {
+	var headers Headers
+	if err := json.Unmarshal(data, &headers); err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.headers = headers
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestNewSyncHeaders(t *testing.T) {
+	tests := map[string]struct {
+		arg  *Headers
+		want *Headers
+	}{
+		"test_nil_headers": {
+			want: &Headers{Values: map[string]interface{}{}},
+		},
+		"test_non_canonical_headers": {
+			arg:  &Headers{Values: map[string]interface{}{"Correlation-Id": "test-id"}},
+			want: NewHeaders(WithCorrelationID("test-id")),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewSyncHeaders(testCase.arg)
+			internal.AssertEqual(t, testCase.want, got.Headers())
+		})
+	}
+}
+
+func TestSyncHeadersCopies(t *testing.T) {
+	orig := NewHeaders(WithCorrelationID("test-id"))
+	headers := NewSyncHeaders(orig)
+
+	orig.Set(HeaderCorrelationID, "modified")
+	internal.AssertEqual(t, "test-id", headers.Get(HeaderCorrelationID))
+
+	snapshot := headers.Headers()
+	snapshot.Set(HeaderCorrelationID, "modified")
+	internal.AssertEqual(t, "test-id", headers.Get("Correlation-ID"))
+}
+
+func TestSyncHeadersModification(t *testing.T) {
+	var headers SyncHeaders
+	headers.Set("Content-Type", ContentTypeJSON)
+	headers.Update(func(h *Headers) {
+		h.Set(HeaderCorrelationID, "test-id")
+		h.Set(HeaderResponseRequired, true)
+	})
+	headers.Del(HeaderResponseRequired)
+
+	headers.Read(func(h *Headers) {
+		internal.AssertEqual(t, "test-id", h.CorrelationID())
+		internal.AssertEqual(t, ContentTypeJSON, h.ContentType())
+		internal.AssertFalse(t, h.IsResponseRequired())
+	})
+}
+
+func TestSyncHeadersJSON(t *testing.T) {
+	headers := NewSyncHeaders(NewHeaders(WithCorrelationID("test-id")))
+	data, err := json.Marshal(headers)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, `{"correlation-id":"test-id"}`, string(data))
+
+	got := &SyncHeaders{}
+	internal.AssertNil(t, json.Unmarshal([]byte(`{"Content-Type":"text/plain"}`), got))
+	internal.AssertEqual(t, ContentTypeText, got.Get(HeaderContentType))
+	internal.AssertNotNil(t, json.Unmarshal([]byte(`[]`), got))
+	internal.AssertEqual(t, ContentTypeText, got.Get(HeaderContentType))
+}
+
+func TestSyncHeadersConcurrently(t *testing.T) {
+	headers := NewSyncHeaders(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			headers.Set(fmt.Sprintf("header-%d", i), i)
+		}(i)
+		go func() {
+			defer wg.Done()
+			headers.Headers().CorrelationID()
+		}()
+	}
+	wg.Wait()
+
+	internal.AssertEqual(t, 10, len(headers.Headers().Values))
+}
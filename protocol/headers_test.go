@@ -12,7 +12,9 @@
 package protocol
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
 )
@@ -32,6 +34,11 @@ func TestHeadersCorrelationID(t *testing.T) {
 
 		got = h.CorrelationID()
 		internal.AssertEqual(t, "", got)
+
+		arg[HeaderCorrelationID] = 42.0
+
+		got = h.CorrelationID()
+		internal.AssertEqual(t, "", got)
 	})
 }
 
@@ -256,6 +263,23 @@ func TestHeadersContentType(t *testing.T) {
 	})
 }
 
+func TestHeadersContentEncoding(t *testing.T) {
+	t.Run("TestHeadersContentEncoding", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderContentEncoding] = ContentEncodingGzip
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.ContentEncoding()
+		internal.AssertEqual(t, ContentEncodingGzip, got)
+
+		arg[HeaderContentEncoding] = nil
+		got = h.ContentEncoding()
+		internal.AssertEqual(t, "", got)
+	})
+}
+
 func TestHeadersGeneric(t *testing.T) {
 	t.Run("TestHeadersGeneric", func(t *testing.T) {
 		arg := make(map[string]interface{})
@@ -272,6 +296,12 @@ func TestHeadersGeneric(t *testing.T) {
 func TestHeadersMarshalJSON(t *testing.T) {
 	argOk := make(map[string]interface{})
 	argOk[HeaderContentType] = "application/json"
+	argSorted := map[string]interface{}{
+		HeaderTimeout:       "10s",
+		HeaderCorrelationID: "id",
+		"x-custom":          map[string]interface{}{"z": 1, "a": 2},
+		HeaderContentType:   "application/json",
+	}
 	argErr := make(map[string]interface{})
 	someChannel := make(chan int)
 	argErr["Channel"] = someChannel
@@ -286,6 +316,11 @@ func TestHeadersMarshalJSON(t *testing.T) {
 			want:    "{\"content-type\":\"application/json\"}",
 			wantErr: false,
 		},
+		"test_headers_marshal_JSON_sorted": {
+			data:    argSorted,
+			want:    `{"content-type":"application/json","correlation-id":"id","timeout":"10s","x-custom":{"a":2,"z":1}}`,
+			wantErr: false,
+		},
 		"test_headers_marshal_JSON_error": {
 			data:    argErr,
 			wantErr: true,
@@ -341,3 +376,460 @@ func TestHeadersUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadersLiveChannelCondition(t *testing.T) {
+	t.Run("TestHeadersLiveChannelCondition", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderLiveChannelCondition] = "exists(attributes/live)"
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.LiveChannelCondition()
+		internal.AssertEqual(t, "exists(attributes/live)", got)
+
+		arg[HeaderLiveChannelCondition] = nil
+		got = h.LiveChannelCondition()
+		internal.AssertEqual(t, "", got)
+	})
+}
+
+func TestHeadersLiveChannelTimeoutStrategy(t *testing.T) {
+	t.Run("TestHeadersLiveChannelTimeoutStrategy", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderLiveChannelTimeoutStrategy] = LiveChannelTimeoutStrategyUseTwin
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.LiveChannelTimeoutStrategy()
+		internal.AssertEqual(t, LiveChannelTimeoutStrategyUseTwin, got)
+
+		arg[HeaderLiveChannelTimeoutStrategy] = nil
+		got = h.LiveChannelTimeoutStrategy()
+		internal.AssertEqual(t, "", got)
+	})
+}
+
+func TestHeadersRequestedAcks(t *testing.T) {
+	tests := map[string]struct {
+		arg  interface{}
+		want []string
+	}{
+		"test_requested_acks_not_set": {
+			arg:  nil,
+			want: nil,
+		},
+		"test_requested_acks_strings": {
+			arg:  []string{"twin-persisted", "custom"},
+			want: []string{"twin-persisted", "custom"},
+		},
+		"test_requested_acks_json_array": {
+			arg:  []interface{}{"twin-persisted", "custom"},
+			want: []string{"twin-persisted", "custom"},
+		},
+		"test_requested_acks_comma_separated": {
+			arg:  "twin-persisted, custom",
+			want: []string{"twin-persisted", "custom"},
+		},
+		"test_requested_acks_empty_string": {
+			arg:  "",
+			want: []string{},
+		},
+		"test_requested_acks_invalid_type": {
+			arg:  true,
+			want: nil,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			h := &Headers{
+				Values: map[string]interface{}{HeaderRequestedAcks: testCase.arg},
+			}
+			internal.AssertEqual(t, testCase.want, h.RequestedAcks())
+		})
+	}
+}
+
+func TestHeadersPutMetadata(t *testing.T) {
+	entries := []MetadataEntry{{Key: "/issuedBy", Value: "ditto"}}
+
+	tests := map[string]struct {
+		arg  interface{}
+		want []MetadataEntry
+	}{
+		"test_put_metadata_not_set": {
+			arg:  nil,
+			want: nil,
+		},
+		"test_put_metadata_entries": {
+			arg:  entries,
+			want: entries,
+		},
+		"test_put_metadata_json_array": {
+			arg: []interface{}{
+				map[string]interface{}{"key": "/issuedBy", "value": "ditto"},
+			},
+			want: entries,
+		},
+		"test_put_metadata_invalid_type": {
+			arg:  "/issuedBy",
+			want: nil,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			h := &Headers{
+				Values: map[string]interface{}{HeaderPutMetadata: testCase.arg},
+			}
+			internal.AssertEqual(t, testCase.want, h.PutMetadata())
+		})
+	}
+}
+
+func TestHeadersGetMetadata(t *testing.T) {
+	t.Run("TestHeadersGetMetadata", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderGetMetadata] = "features/lamp/properties/on/issuedAt,attributes/location"
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.GetMetadata()
+		internal.AssertEqual(t, []string{"features/lamp/properties/on/issuedAt", "attributes/location"}, got)
+
+		arg[HeaderGetMetadata] = nil
+		internal.AssertNil(t, h.GetMetadata())
+	})
+}
+
+func TestHeadersDeleteMetadata(t *testing.T) {
+	t.Run("TestHeadersDeleteMetadata", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderDeleteMetadata] = "attributes/location"
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.DeleteMetadata()
+		internal.AssertEqual(t, []string{"attributes/location"}, got)
+
+		arg[HeaderDeleteMetadata] = nil
+		internal.AssertNil(t, h.DeleteMetadata())
+	})
+}
+
+func TestHeadersMessageHeaders(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		value  string
+		getter func(h *Headers) string
+	}{
+		"test_message_subject": {
+			header: HeaderMessageSubject,
+			value:  "turnOn",
+			getter: (*Headers).MessageSubject,
+		},
+		"test_message_direction": {
+			header: HeaderMessageDirection,
+			value:  MessageDirectionTo,
+			getter: (*Headers).MessageDirection,
+		},
+		"test_message_thing_id": {
+			header: HeaderMessageThingID,
+			value:  "org.eclipse.ditto:thing",
+			getter: (*Headers).MessageThingID,
+		},
+		"test_message_feature_id": {
+			header: HeaderMessageFeatureID,
+			value:  "lamp",
+			getter: (*Headers).MessageFeatureID,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			arg := make(map[string]interface{})
+			arg[testCase.header] = testCase.value
+			h := &Headers{
+				Values: arg,
+			}
+			internal.AssertEqual(t, testCase.value, testCase.getter(h))
+
+			arg[testCase.header] = nil
+			internal.AssertEqual(t, "", testCase.getter(h))
+		})
+	}
+}
+
+func TestHeadersTTL(t *testing.T) {
+	tests := map[string]struct {
+		arg  interface{}
+		want time.Duration
+	}{
+		"test_ttl_not_set": {
+			arg:  nil,
+			want: 0,
+		},
+		"test_ttl_int64": {
+			arg:  int64(30),
+			want: 30 * time.Second,
+		},
+		"test_ttl_json_number": {
+			arg:  float64(30),
+			want: 30 * time.Second,
+		},
+		"test_ttl_string": {
+			arg:  "30",
+			want: 30 * time.Second,
+		},
+		"test_ttl_invalid": {
+			arg:  "thirty",
+			want: 0,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			h := &Headers{
+				Values: map[string]interface{}{HeaderTTL: testCase.arg},
+			}
+			internal.AssertEqual(t, testCase.want, h.TTL())
+		})
+	}
+}
+
+func TestHeadersCreationTime(t *testing.T) {
+	t.Run("TestHeadersCreationTime", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderCreationTime] = float64(1640995200000)
+		h := &Headers{
+			Values: arg,
+		}
+
+		got := h.CreationTime()
+		internal.AssertTrue(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Equal(got))
+
+		arg[HeaderCreationTime] = nil
+		internal.AssertTrue(t, h.CreationTime().IsZero())
+	})
+}
+
+func TestHeadersIsExpired(t *testing.T) {
+	tests := map[string]struct {
+		opts []HeaderOpt
+		want bool
+	}{
+		"test_without_headers": {
+			opts: nil,
+			want: false,
+		},
+		"test_without_ttl": {
+			opts: []HeaderOpt{WithCreationTime(time.Now().Add(-time.Hour))},
+			want: false,
+		},
+		"test_without_creation_time": {
+			opts: []HeaderOpt{WithTTL(time.Minute)},
+			want: false,
+		},
+		"test_not_expired": {
+			opts: []HeaderOpt{WithCreationTime(time.Now()), WithTTL(time.Minute)},
+			want: false,
+		},
+		"test_expired": {
+			opts: []HeaderOpt{WithCreationTime(time.Now().Add(-time.Hour)), WithTTL(time.Minute)},
+			want: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, NewHeaders(testCase.opts...).IsExpired())
+		})
+	}
+}
+
+func TestHeadersUnmarshalJSONCanonical(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		"test_headers_unmarshal_JSON_mixed_case": {
+			data: `{"Content-Type":"application/json","ETag":"\"rev:1\""}`,
+			want: map[string]interface{}{
+				HeaderContentType: "application/json",
+				HeaderETag:        `"rev:1"`,
+			},
+		},
+		"test_headers_unmarshal_JSON_duplicates_last_wins": {
+			data: `{"correlation-id":"first","Correlation-ID":"second"}`,
+			want: map[string]interface{}{
+				HeaderCorrelationID: "second",
+			},
+		},
+		"test_headers_unmarshal_JSON_null": {
+			data: `null`,
+			want: map[string]interface{}{},
+		},
+		"test_headers_unmarshal_JSON_not_object": {
+			data:    `["correlation-id"]`,
+			wantErr: true,
+		},
+		"test_headers_unmarshal_JSON_invalid_value": {
+			data:    `{"correlation-id":}`,
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewHeaders()
+			err := got.UnmarshalJSON([]byte(testCase.data))
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got.Values)
+			}
+		})
+	}
+}
+
+func TestHeadersCaseInsensitiveAccess(t *testing.T) {
+	t.Run("TestHeadersCaseInsensitiveAccess", func(t *testing.T) {
+		h := NewHeaders()
+		h.Set("X-Custom-Header", "value")
+
+		internal.AssertEqual(t, map[string]interface{}{"x-custom-header": "value"}, h.Values)
+		internal.AssertEqual(t, "value", h.Get("x-CUSTOM-header"))
+		internal.AssertEqual(t, "value", h.Generic("X-Custom-Header"))
+
+		h.Del("X-CUSTOM-HEADER")
+		internal.AssertNil(t, h.Get("x-custom-header"))
+	})
+
+	t.Run("TestHeadersSetWithoutValues", func(t *testing.T) {
+		h := &Headers{}
+		h.Set(HeaderCorrelationID, "id")
+		internal.AssertEqual(t, "id", h.CorrelationID())
+	})
+}
+
+func TestHeadersIntegerValues(t *testing.T) {
+	tests := map[string]struct {
+		arg  interface{}
+		want int64
+	}{
+		"test_int64": {
+			arg:  int64(2),
+			want: 2,
+		},
+		"test_int": {
+			arg:  2,
+			want: 2,
+		},
+		"test_float64": {
+			arg:  float64(2),
+			want: 2,
+		},
+		"test_float64_not_integral": {
+			arg:  2.5,
+			want: 0,
+		},
+		"test_json_number": {
+			arg:  json.Number("2"),
+			want: 2,
+		},
+		"test_numeric_string": {
+			arg:  "2",
+			want: 2,
+		},
+		"test_invalid_string": {
+			arg:  "two",
+			want: 0,
+		},
+		"test_invalid_type": {
+			arg:  true,
+			want: 0,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			h := &Headers{
+				Values: map[string]interface{}{
+					HeaderSchemaVersion: testCase.arg,
+					HeaderReplyTarget:   testCase.arg,
+				},
+			}
+			internal.AssertEqual(t, testCase.want, h.Version())
+			internal.AssertEqual(t, testCase.want, h.ReplyTarget())
+		})
+	}
+}
+
+func TestHeadersIntegerValuesUnmarshalJSON(t *testing.T) {
+	t.Run("TestHeadersIntegerValuesUnmarshalJSON", func(t *testing.T) {
+		h := NewHeaders()
+		internal.AssertNil(t, h.UnmarshalJSON([]byte(`{"version":2,"ditto-reply-target":0}`)))
+		internal.AssertEqual(t, int64(2), h.Version())
+		internal.AssertEqual(t, int64(0), h.ReplyTarget())
+
+		h = NewHeaders(WithSchemaVersion("2"), WithReplyTarget("1"))
+		internal.AssertEqual(t, int64(2), h.Version())
+		internal.AssertEqual(t, int64(1), h.ReplyTarget())
+	})
+}
+
+func TestHeadersClone(t *testing.T) {
+	t.Run("TestHeadersClone", func(t *testing.T) {
+		orig := NewHeaders(WithCorrelationID("correlation-id"), WithPutMetadata(MetadataEntry{Key: "/issuedBy", Value: "me"}))
+
+		got := orig.Clone()
+		internal.AssertEqual(t, orig, got)
+
+		got.Set(HeaderCorrelationID, "other")
+		got.Values[HeaderPutMetadata].([]MetadataEntry)[0].Key = "/other"
+		internal.AssertEqual(t, "correlation-id", orig.CorrelationID())
+		internal.AssertEqual(t, "/issuedBy", orig.PutMetadata()[0].Key)
+
+		var nilHeaders *Headers
+		internal.AssertNil(t, nilHeaders.Clone())
+	})
+}
+
+func TestHeadersAtHistoricalRevision(t *testing.T) {
+	t.Run("TestHeadersAtHistoricalRevision", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderAtHistoricalRevision] = float64(42)
+		h := &Headers{
+			Values: arg,
+		}
+
+		internal.AssertEqual(t, int64(42), h.AtHistoricalRevision())
+
+		arg[HeaderAtHistoricalRevision] = nil
+		internal.AssertEqual(t, int64(0), h.AtHistoricalRevision())
+	})
+}
+
+func TestHeadersAtHistoricalTimestamp(t *testing.T) {
+	t.Run("TestHeadersAtHistoricalTimestamp", func(t *testing.T) {
+		arg := make(map[string]interface{})
+		arg[HeaderAtHistoricalTimestamp] = "2022-01-01T10:00:00.5Z"
+		h := &Headers{
+			Values: arg,
+		}
+
+		internal.AssertTrue(t, time.Date(2022, 1, 1, 10, 0, 0, 500000000, time.UTC).Equal(h.AtHistoricalTimestamp()))
+
+		arg[HeaderAtHistoricalTimestamp] = "invalid"
+		internal.AssertTrue(t, h.AtHistoricalTimestamp().IsZero())
+
+		arg[HeaderAtHistoricalTimestamp] = nil
+		internal.AssertTrue(t, h.AtHistoricalTimestamp().IsZero())
+	})
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxTimeout is the maximum 'timeout' header value accepted by the Headers validation, as allowed by Ditto
+// by default. See Headers.ValidateMaxTimeout for the Ditto installations configured to allow more.
+const DefaultMaxTimeout = 60 * time.Second
+
+// ErrTimeoutNotSet is returned by Headers.TimeoutDuration if the 'timeout' header is not set.
+var ErrTimeoutNotSet = errors.New("timeout header not set")
+
+var regexTimeout = regexp.MustCompile("^([0-9]+)(ms|s|m)?$")
+
+// HeaderError represents a Headers validation error of a specific header.
+type HeaderError struct {
+	Header string
+	Value  interface{}
+	Reason string
+}
+
+// Error provides the string representation of the HeaderError.
+func (err *HeaderError) Error() string {
+	return fmt.Sprintf("invalid header '%s' with value '%v': %s", err.Header, err.Value, err.Reason)
+}
+
+type headerValidator func(value interface{}) string
+
+var headerValidators = []struct {
+	header    string
+	validator headerValidator
+}{
+	{HeaderCorrelationID, validateString},
+	{HeaderResponseRequired, validateBool},
+	{HeaderChannel, validateChannel},
+	{HeaderDryRun, validateBool},
+	{HeaderOrigin, validateString},
+	{HeaderOriginator, validateString},
+	{HeaderETag, validateString},
+	{HeaderIfMatch, validateString},
+	{HeaderIfNoneMatch, validateString},
+	{HeaderReplyTarget, validateInteger},
+	{HeaderReplyTo, validateString},
+	{HeaderTimeout, nil}, // validated against the maximum timeout, see validateTimeout
+	{HeaderSchemaVersion, validateVersion},
+	{HeaderContentType, validateString},
+	{HeaderLiveChannelCondition, validateNonEmptyString},
+	{HeaderLiveChannelTimeoutStrategy, validateLiveChannelTimeoutStrategy},
+	{HeaderRequestedAcks, validateRequestedAcks},
+	{HeaderPutMetadata, validatePutMetadata},
+	{HeaderGetMetadata, validateString},
+	{HeaderDeleteMetadata, validateString},
+	{HeaderMessageSubject, validateString},
+	{HeaderMessageDirection, validateMessageDirection},
+	{HeaderMessageThingID, validateString},
+	{HeaderMessageFeatureID, validateString},
+	{HeaderTTL, validatePositiveInteger},
+	{HeaderCreationTime, validateInteger},
+	{HeaderContentEncoding, validateString},
+	{HeaderAtHistoricalRevision, validatePositiveInteger},
+	{HeaderAtHistoricalTimestamp, validateTimestamp},
+}
+
+// Validate checks the known Ditto headers for correct value types and ranges as defined by the Ditto specification.
+// The headers are validated in a fixed order and a *HeaderError is returned for the first invalid one.
+// Unknown headers are not validated. The 'timeout' header value must not exceed DefaultMaxTimeout.
+func (h *Headers) Validate() error {
+	return h.ValidateMaxTimeout(DefaultMaxTimeout)
+}
+
+// ValidateMaxTimeout checks the known Ditto headers the same way as Validate does, except that the 'timeout' header
+// value must not exceed the provided maximum, e.g. for Ditto installations configured to allow more than
+// DefaultMaxTimeout.
+func (h *Headers) ValidateMaxTimeout(maxTimeout time.Duration) error {
+	for _, v := range headerValidators {
+		value, ok := h.Values[v.header]
+		if !ok || value == nil {
+			continue
+		}
+		var reason string
+		if v.validator == nil {
+			reason = validateTimeout(value, maxTimeout)
+		} else {
+			reason = v.validator(value)
+		}
+		if len(reason) > 0 {
+			return &HeaderError{Header: v.header, Value: value, Reason: reason}
+		}
+	}
+	return nil
+}
+
+func validateString(value interface{}) string {
+	if _, ok := value.(string); !ok {
+		return "string value expected"
+	}
+	return ""
+}
+
+func validateNonEmptyString(value interface{}) string {
+	if s, ok := value.(string); !ok || len(s) == 0 {
+		return "non-empty string value expected"
+	}
+	return ""
+}
+
+func validateBool(value interface{}) string {
+	if _, ok := value.(bool); !ok {
+		return "boolean value expected"
+	}
+	return ""
+}
+
+func validateInteger(value interface{}) string {
+	if _, ok := toInt64(value); !ok {
+		return "integer value expected"
+	}
+	return ""
+}
+
+func validatePositiveInteger(value interface{}) string {
+	if i, ok := toInt64(value); !ok || i < 1 {
+		return "positive integer value expected"
+	}
+	return ""
+}
+
+func validateTimestamp(value interface{}) string {
+	if timestamp, ok := value.(string); ok {
+		if _, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			return ""
+		}
+	}
+	return "ISO-8601 timestamp expected"
+}
+
+func validateChannel(value interface{}) string {
+	if channel, ok := value.(string); !ok || (channel != string(ChannelTwin) && channel != string(ChannelLive)) {
+		return "one of 'twin' or 'live' expected"
+	}
+	return ""
+}
+
+func validateVersion(value interface{}) string {
+	if version, ok := toInt64(value); !ok || (version != 1 && version != 2) {
+		return "one of 1 or 2 expected"
+	}
+	return ""
+}
+
+func validateTimeout(value interface{}, maxTimeout time.Duration) string {
+	timeout, err := parseTimeout(value)
+	if err != nil {
+		return err.Error()
+	}
+	if timeout > maxTimeout {
+		return fmt.Sprintf("timeout must not exceed %v", maxTimeout)
+	}
+	return ""
+}
+
+func validateLiveChannelTimeoutStrategy(value interface{}) string {
+	if strategy, ok := value.(string); !ok ||
+		(strategy != LiveChannelTimeoutStrategyFail && strategy != LiveChannelTimeoutStrategyUseTwin) {
+		return fmt.Sprintf("one of '%s' or '%s' expected", LiveChannelTimeoutStrategyFail, LiveChannelTimeoutStrategyUseTwin)
+	}
+	return ""
+}
+
+func validateMessageDirection(value interface{}) string {
+	if direction, ok := value.(string); !ok || (direction != MessageDirectionTo && direction != MessageDirectionFrom) {
+		return fmt.Sprintf("one of '%s' or '%s' expected", MessageDirectionTo, MessageDirectionFrom)
+	}
+	return ""
+}
+
+func validateRequestedAcks(value interface{}) string {
+	switch acks := value.(type) {
+	case []string:
+		for _, ack := range acks {
+			if !isValidAckLabel(ack) {
+				return "non-empty acknowledgement labels expected"
+			}
+		}
+		return ""
+	case []interface{}:
+		for _, ack := range acks {
+			label, ok := ack.(string)
+			if !ok {
+				return "array of strings expected"
+			}
+			if !isValidAckLabel(label) {
+				return "non-empty acknowledgement labels expected"
+			}
+		}
+		return ""
+	case string:
+		if len(strings.TrimSpace(acks)) == 0 {
+			return ""
+		}
+		for _, ack := range strings.Split(acks, ",") {
+			if !isValidAckLabel(strings.TrimSpace(ack)) {
+				return "comma-separated non-empty acknowledgement labels expected"
+			}
+		}
+		return ""
+	default:
+		return "array of strings expected"
+	}
+}
+
+// isValidAckLabel reports whether the provided acknowledgement label is non-empty and representable
+// in both the array and the comma-separated forms of the 'requested-acks' header.
+func isValidAckLabel(label string) bool {
+	return len(label) > 0 && !strings.ContainsAny(label, ", ")
+}
+
+func validatePutMetadata(value interface{}) string {
+	switch entries := value.(type) {
+	case []MetadataEntry:
+		for _, entry := range entries {
+			if len(entry.Key) == 0 {
+				return "metadata entry key expected"
+			}
+		}
+		return ""
+	case []interface{}:
+		for _, item := range entries {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return "array of metadata entries expected"
+			}
+			if key, ok := entry["key"].(string); !ok || len(key) == 0 {
+				return "metadata entry key expected"
+			}
+		}
+		return ""
+	default:
+		return "array of metadata entries expected"
+	}
+}
+
+func parseTimeout(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case string:
+		matches := regexTimeout.FindStringSubmatch(v)
+		if matches == nil {
+			return 0, fmt.Errorf("invalid timeout format: %s", v)
+		}
+		amount, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		switch matches[2] {
+		case "ms":
+			return timeoutDuration(amount, time.Millisecond)
+		case "m":
+			return timeoutDuration(amount, time.Minute)
+		default:
+			return timeoutDuration(amount, time.Second)
+		}
+	default:
+		if seconds, ok := toInt64(v); ok && seconds >= 0 {
+			return timeoutDuration(seconds, time.Second)
+		}
+		return 0, fmt.Errorf("invalid timeout: %v", v)
+	}
+}
+
+// timeoutDuration provides the non-negative amount of the unit as a time.Duration or an error if it overflows.
+func timeoutDuration(amount int64, unit time.Duration) (time.Duration, error) {
+	if amount > math.MaxInt64/int64(unit) {
+		return 0, fmt.Errorf("timeout out of range: %d%s", amount, unit)
+	}
+	return time.Duration(amount) * unit, nil
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestHeadersValidate(t *testing.T) {
+	tests := map[string]struct {
+		values     map[string]interface{}
+		wantHeader string
+	}{
+		"test_empty_headers": {
+			values: map[string]interface{}{},
+		},
+		"test_valid_headers": {
+			values: map[string]interface{}{
+				HeaderCorrelationID:    "id",
+				HeaderResponseRequired: true,
+				HeaderChannel:          "live",
+				HeaderTimeout:          "500ms",
+				HeaderSchemaVersion:    float64(2),
+				HeaderRequestedAcks:    []interface{}{"twin-persisted"},
+				HeaderPutMetadata:      []interface{}{map[string]interface{}{"key": "/issuedBy", "value": "me"}},
+				HeaderTTL:              float64(10),
+				"x-custom":             42,
+			},
+		},
+		"test_invalid_correlation_id": {
+			values:     map[string]interface{}{HeaderCorrelationID: 1},
+			wantHeader: HeaderCorrelationID,
+		},
+		"test_invalid_response_required": {
+			values:     map[string]interface{}{HeaderResponseRequired: "true"},
+			wantHeader: HeaderResponseRequired,
+		},
+		"test_invalid_channel": {
+			values:     map[string]interface{}{HeaderChannel: "other"},
+			wantHeader: HeaderChannel,
+		},
+		"test_invalid_timeout_format": {
+			values:     map[string]interface{}{HeaderTimeout: "10h"},
+			wantHeader: HeaderTimeout,
+		},
+		"test_invalid_timeout_range": {
+			values:     map[string]interface{}{HeaderTimeout: "2m"},
+			wantHeader: HeaderTimeout,
+		},
+		"test_empty_live_channel_condition": {
+			values:     map[string]interface{}{HeaderLiveChannelCondition: ""},
+			wantHeader: HeaderLiveChannelCondition,
+		},
+		"test_invalid_live_channel_timeout_strategy": {
+			values:     map[string]interface{}{HeaderLiveChannelTimeoutStrategy: "retry"},
+			wantHeader: HeaderLiveChannelTimeoutStrategy,
+		},
+		"test_invalid_version": {
+			values:     map[string]interface{}{HeaderSchemaVersion: "3"},
+			wantHeader: HeaderSchemaVersion,
+		},
+		"test_valid_requested_acks_string": {
+			values: map[string]interface{}{HeaderRequestedAcks: "twin-persisted, custom"},
+		},
+		"test_invalid_requested_acks": {
+			values:     map[string]interface{}{HeaderRequestedAcks: 1},
+			wantHeader: HeaderRequestedAcks,
+		},
+		"test_invalid_requested_acks_string": {
+			values:     map[string]interface{}{HeaderRequestedAcks: "twin-persisted,,custom"},
+			wantHeader: HeaderRequestedAcks,
+		},
+		"test_invalid_requested_acks_label": {
+			values:     map[string]interface{}{HeaderRequestedAcks: []string{"twin-persisted", ""}},
+			wantHeader: HeaderRequestedAcks,
+		},
+		"test_invalid_requested_acks_item": {
+			values:     map[string]interface{}{HeaderRequestedAcks: []interface{}{1}},
+			wantHeader: HeaderRequestedAcks,
+		},
+		"test_invalid_put_metadata": {
+			values:     map[string]interface{}{HeaderPutMetadata: []interface{}{map[string]interface{}{"value": 1}}},
+			wantHeader: HeaderPutMetadata,
+		},
+		"test_put_metadata_entry_empty_key": {
+			values:     map[string]interface{}{HeaderPutMetadata: []MetadataEntry{{Key: "/issuedBy"}, {Value: 1}}},
+			wantHeader: HeaderPutMetadata,
+		},
+		"test_invalid_message_direction": {
+			values:     map[string]interface{}{HeaderMessageDirection: "to"},
+			wantHeader: HeaderMessageDirection,
+		},
+		"test_invalid_historical_revision": {
+			values:     map[string]interface{}{HeaderAtHistoricalRevision: int64(0)},
+			wantHeader: HeaderAtHistoricalRevision,
+		},
+		"test_invalid_historical_timestamp": {
+			values:     map[string]interface{}{HeaderAtHistoricalTimestamp: "yesterday"},
+			wantHeader: HeaderAtHistoricalTimestamp,
+		},
+		"test_invalid_ttl": {
+			values:     map[string]interface{}{HeaderTTL: int64(0)},
+			wantHeader: HeaderTTL,
+		},
+		"test_first_invalid_in_order": {
+			values: map[string]interface{}{
+				HeaderTTL:           "ten",
+				HeaderCorrelationID: false,
+			},
+			wantHeader: HeaderCorrelationID,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := (&Headers{Values: testCase.values}).Validate()
+			if len(testCase.wantHeader) == 0 {
+				internal.AssertNil(t, err)
+				return
+			}
+			headerErr, ok := err.(*HeaderError)
+			internal.AssertTrue(t, ok)
+			internal.AssertEqual(t, testCase.wantHeader, headerErr.Header)
+			internal.AssertEqual(t, testCase.values[testCase.wantHeader], headerErr.Value)
+		})
+	}
+}
+
+func TestHeaderErrorError(t *testing.T) {
+	err := &HeaderError{Header: HeaderChannel, Value: "other", Reason: "one of 'twin' or 'live' expected"}
+	internal.AssertEqual(t, "invalid header 'ditto-channel' with value 'other': one of 'twin' or 'live' expected", err.Error())
+}
+
+func TestHeadersValidateMaxTimeout(t *testing.T) {
+	h := &Headers{Values: map[string]interface{}{HeaderTimeout: "2m"}}
+	internal.AssertNotNil(t, h.Validate())
+	internal.AssertNotNil(t, h.ValidateMaxTimeout(time.Minute))
+	internal.AssertNil(t, h.ValidateMaxTimeout(5*time.Minute))
+
+	h.Values[HeaderTimeout] = "10h"
+	internal.AssertNotNil(t, h.ValidateMaxTimeout(5*time.Minute))
+
+	h.Values[HeaderTimeout] = "153722868m"
+	internal.AssertNotNil(t, h.ValidateMaxTimeout(5*time.Minute))
+}
+
+func TestHeadersTimeoutDuration(t *testing.T) {
+	tests := map[string]struct {
+		values  map[string]interface{}
+		want    time.Duration
+		wantErr error
+	}{
+		"test_absent": {
+			values:  map[string]interface{}{},
+			wantErr: ErrTimeoutNotSet,
+		},
+		"test_nil": {
+			values:  map[string]interface{}{HeaderTimeout: nil},
+			wantErr: ErrTimeoutNotSet,
+		},
+		"test_zero": {
+			values: map[string]interface{}{HeaderTimeout: "0"},
+			want:   0,
+		},
+		"test_valid": {
+			values: map[string]interface{}{HeaderTimeout: "1m"},
+			want:   time.Minute,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := (&Headers{Values: testCase.values}).TimeoutDuration()
+			internal.AssertEqual(t, testCase.wantErr, err)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestHeadersTimeoutDurationInvalid(t *testing.T) {
+	h := &Headers{Values: map[string]interface{}{HeaderTimeout: "10h"}}
+	_, err := h.TimeoutDuration()
+	internal.AssertNotNil(t, err)
+	internal.AssertFalse(t, err == ErrTimeoutNotSet)
+
+	h.Values[HeaderTimeout] = "2m"
+	got, err := h.TimeoutDuration()
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 2*time.Minute, got)
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := map[string]struct {
+		arg     interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		"test_seconds_without_unit": {
+			arg:  "10",
+			want: 10 * time.Second,
+		},
+		"test_milliseconds": {
+			arg:  "250ms",
+			want: 250 * time.Millisecond,
+		},
+		"test_seconds": {
+			arg:  "30s",
+			want: 30 * time.Second,
+		},
+		"test_minutes": {
+			arg:  "1m",
+			want: time.Minute,
+		},
+		"test_number": {
+			arg:  float64(5),
+			want: 5 * time.Second,
+		},
+		"test_invalid_unit": {
+			arg:     "1h",
+			wantErr: true,
+		},
+		"test_negative": {
+			arg:     "-1",
+			wantErr: true,
+		},
+		"test_invalid_type": {
+			arg:     true,
+			wantErr: true,
+		},
+		"test_minutes_overflow": {
+			arg:     "153722868m",
+			wantErr: true,
+		},
+		"test_seconds_overflow": {
+			arg:     "9223372037s",
+			wantErr: true,
+		},
+		"test_milliseconds_overflow": {
+			arg:     "9223372036855ms",
+			wantErr: true,
+		},
+		"test_number_overflow": {
+			arg:     float64(1e10),
+			wantErr: true,
+		},
+		"test_minutes_max": {
+			arg:  "153722867m",
+			want: 153722867 * time.Minute,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := parseTimeout(testCase.arg)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got)
+			}
+		})
+	}
+}
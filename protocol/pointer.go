@@ -0,0 +1,40 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import "strings"
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// EscapePointerToken escapes the provided key as a single JSON pointer (https://tools.ietf.org/html/rfc6901) reference token,
+// i.e. '~' is escaped as '~0' and '/' as '~1'.
+func EscapePointerToken(key string) string {
+	return pointerEscaper.Replace(key)
+}
+
+// UnescapePointerToken provides the key referenced by the provided JSON pointer reference token.
+func UnescapePointerToken(token string) string {
+	return pointerUnescaper.Replace(token)
+}
+
+// NewPointer creates a JSON pointer referencing the provided keys, each of them escaped as a single reference token.
+func NewPointer(keys ...string) string {
+	var buf strings.Builder
+	for _, key := range keys {
+		buf.WriteByte('/')
+		buf.WriteString(EscapePointerToken(key))
+	}
+	return buf.String()
+}
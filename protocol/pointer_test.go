@@ -0,0 +1,43 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestPointerTokenEscaping(t *testing.T) {
+	tests := map[string]struct {
+		key   string
+		token string
+	}{
+		"test_plain":     {key: "temperature", token: "temperature"},
+		"test_slash":     {key: "a/b", token: "a~1b"},
+		"test_tilde":     {key: "m~n", token: "m~0n"},
+		"test_tilde_one": {key: "~1", token: "~01"},
+		"test_empty":     {key: "", token: ""},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.token, EscapePointerToken(testCase.key))
+			internal.AssertEqual(t, testCase.key, UnescapePointerToken(testCase.token))
+		})
+	}
+}
+
+func TestNewPointer(t *testing.T) {
+	internal.AssertEqual(t, "", NewPointer())
+	internal.AssertEqual(t, "/location/a~1b/m~0n", NewPointer("location", "a/b", "m~n"))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package policies
+
+import (
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+const (
+	pathPolicy                     = "/"
+	pathPolicyEntries              = "/entries"
+	pathPolicyEntryFormat          = pathPolicyEntries + "/%s"
+	pathPolicyEntrySubjectsFormat  = pathPolicyEntryFormat + "/subjects"
+	pathPolicyEntrySubjectFormat   = pathPolicyEntrySubjectsFormat + "/%s"
+	pathPolicyEntryResourcesFormat = pathPolicyEntryFormat + "/resources"
+	pathPolicyEntryResourceFormat  = pathPolicyEntryResourcesFormat + "/%s"
+)
+
+// Command represents a message entity defined by the Ditto protocol for the Policies group that defines the execution of a certain action.
+// This is a special Message that is always bound to a specific Policy instance along with providing the capabilities to configure:
+// - the type of the action it will signal for execution - Create, Modify, Retrieve, Delete
+// - the entity it will affect - the whole Policy (the default), all entries of the Policy (Entries),
+//                               a single entry of the Policy (Entry), all subjects of an entry (Subjects) or
+//                               a single subject of an entry (Subject), all resources of an entry (Resources)
+//                               or a single resource of an entry (Resource).
+// Note: Only one action can be configured to the command - if using the methods for configuring it - only the last one applies.
+// Note: Only one entity that will be affected by the command can be configured - if using the methods for configuring it - only the last one applies.
+type Command struct {
+	Topic   *protocol.Topic
+	Path    string
+	Payload interface{}
+}
+
+// NewCommand creates a new Command instance for the defined by the provided NamespacedID Policy.
+func NewCommand(policyID *model.NamespacedID) *Command {
+	return &Command{
+		Topic: (&protocol.Topic{}).
+			WithNamespace(policyID.Namespace).
+			WithEntityName(policyID.Name).
+			WithGroup(protocol.GroupPolicies).
+			WithCriterion(protocol.CriterionCommands),
+		Path: pathPolicy,
+	}
+}
+
+// Create creates a new Policy entity based on the provided payload, which must be the Policy's JSON representation, e.g. a *model.Policy.
+func (cmd *Command) Create(policy interface{}) *Command {
+	cmd.Topic.WithAction(protocol.ActionCreate)
+	cmd.Payload = policy
+	return cmd
+}
+
+// Modify sets the action of the command instance accordingly.
+// The provided payload must be the new value to be used for modification
+// compliant with the (part of) the Policy it is to be applied to.
+func (cmd *Command) Modify(payload interface{}) *Command {
+	cmd.Topic.WithAction(protocol.ActionModify)
+	cmd.Payload = payload
+	return cmd
+}
+
+// Retrieve sets the action of the command instance accordingly.
+func (cmd *Command) Retrieve() *Command {
+	cmd.Topic.WithAction(protocol.ActionRetrieve)
+	return cmd
+}
+
+// Delete sets the action of the command instance accordingly.
+func (cmd *Command) Delete() *Command {
+	cmd.Topic.WithAction(protocol.ActionDelete)
+	return cmd
+}
+
+// Entries configures the command to affect all entries of the Policy.
+func (cmd *Command) Entries() *Command {
+	cmd.Path = pathPolicyEntries
+	return cmd
+}
+
+// Entry configures the command to affect a specified by the provided label entry of the Policy.
+func (cmd *Command) Entry(label string) *Command {
+	cmd.Path = fmt.Sprintf(pathPolicyEntryFormat, label)
+	return cmd
+}
+
+// Subjects configures the command to affect all subjects of a specified by the provided label entry of the Policy.
+func (cmd *Command) Subjects(label string) *Command {
+	cmd.Path = fmt.Sprintf(pathPolicyEntrySubjectsFormat, label)
+	return cmd
+}
+
+// Subject configures the command to affect a specified by the provided subjectID subject
+// of a specified by the provided label entry of the Policy, e.g. 'nginx:ditto'.
+func (cmd *Command) Subject(label, subjectID string) *Command {
+	cmd.Path = fmt.Sprintf(pathPolicyEntrySubjectFormat, label, subjectID)
+	return cmd
+}
+
+// Resources configures the command to affect all resources of a specified by the provided label entry of the Policy.
+func (cmd *Command) Resources(label string) *Command {
+	cmd.Path = fmt.Sprintf(pathPolicyEntryResourcesFormat, label)
+	return cmd
+}
+
+// Resource configures the command to affect a specified by the provided resourcePath resource
+// of a specified by the provided label entry of the Policy, e.g. 'thing:/features'.
+func (cmd *Command) Resource(label, resourcePath string) *Command {
+	cmd.Path = fmt.Sprintf(pathPolicyEntryResourceFormat, label, resourcePath)
+	return cmd
+}
+
+// Envelope generates the Ditto envelope with command's data applying all configurations and optionally all Headers provided.
+func (cmd *Command) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
+	msg := &protocol.Envelope{
+		Topic: cmd.Topic,
+		Path:  cmd.Path,
+		Value: cmd.Payload,
+	}
+	if headerOpts != nil {
+		msg.Headers = protocol.NewHeaders(headerOpts...)
+	}
+	return msg
+}
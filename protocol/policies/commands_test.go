@@ -0,0 +1,180 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package policies
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+var (
+	testPolicyID = &model.NamespacedID{
+		Namespace: "testNamespace",
+		Name:      "testName",
+	}
+	testLabel     = "testLabel"
+	testSubjectID = "nginx:ditto"
+	testResource  = "thing:/features"
+)
+
+func TestNewCommand(t *testing.T) {
+	want := &Command{
+		Topic: &protocol.Topic{
+			Namespace:  testPolicyID.Namespace,
+			EntityName: testPolicyID.Name,
+			Group:      protocol.GroupPolicies,
+			Criterion:  protocol.CriterionCommands,
+		},
+		Path: pathPolicy,
+	}
+
+	got := NewCommand(testPolicyID)
+	internal.AssertEqual(t, want, got)
+}
+
+func TestActions(t *testing.T) {
+	payload := map[string]interface{}{"entries": map[string]interface{}{}}
+
+	tests := map[string]struct {
+		exec func(cmd *Command) *Command
+		want *Command
+	}{
+		"test_create": {
+			exec: func(cmd *Command) *Command { return cmd.Create(payload) },
+			want: &Command{
+				Topic:   &protocol.Topic{Action: protocol.ActionCreate},
+				Payload: payload,
+			},
+		},
+		"test_modify": {
+			exec: func(cmd *Command) *Command { return cmd.Modify(payload) },
+			want: &Command{
+				Topic:   &protocol.Topic{Action: protocol.ActionModify},
+				Payload: payload,
+			},
+		},
+		"test_retrieve": {
+			exec: func(cmd *Command) *Command { return cmd.Retrieve() },
+			want: &Command{
+				Topic: &protocol.Topic{Action: protocol.ActionRetrieve},
+			},
+		},
+		"test_delete": {
+			exec: func(cmd *Command) *Command { return cmd.Delete() },
+			want: &Command{
+				Topic: &protocol.Topic{Action: protocol.ActionDelete},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := testCase.exec(&Command{Topic: &protocol.Topic{}})
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestPaths(t *testing.T) {
+	tests := map[string]struct {
+		exec func(cmd *Command) *Command
+		want string
+	}{
+		"test_entries": {
+			exec: func(cmd *Command) *Command { return cmd.Entries() },
+			want: pathPolicyEntries,
+		},
+		"test_entry": {
+			exec: func(cmd *Command) *Command { return cmd.Entry(testLabel) },
+			want: fmt.Sprintf(pathPolicyEntryFormat, testLabel),
+		},
+		"test_subjects": {
+			exec: func(cmd *Command) *Command { return cmd.Subjects(testLabel) },
+			want: fmt.Sprintf(pathPolicyEntrySubjectsFormat, testLabel),
+		},
+		"test_subject": {
+			exec: func(cmd *Command) *Command { return cmd.Subject(testLabel, testSubjectID) },
+			want: "/entries/testLabel/subjects/nginx:ditto",
+		},
+		"test_resources": {
+			exec: func(cmd *Command) *Command { return cmd.Resources(testLabel) },
+			want: fmt.Sprintf(pathPolicyEntryResourcesFormat, testLabel),
+		},
+		"test_resource": {
+			exec: func(cmd *Command) *Command { return cmd.Resource(testLabel, testResource) },
+			want: "/entries/testLabel/resources/thing:/features",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := testCase.exec(&Command{})
+			internal.AssertEqual(t, &Command{Path: testCase.want}, got)
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	cmd := NewCommand(testPolicyID).Entry(testLabel).Delete()
+
+	tests := map[string]struct {
+		arg  []protocol.HeaderOpt
+		want *protocol.Envelope
+	}{
+		"test_without_header": {
+			arg: nil,
+			want: &protocol.Envelope{
+				Topic: cmd.Topic,
+				Path:  cmd.Path,
+				Value: cmd.Payload,
+			},
+		},
+		"test_with_any_headers": {
+			arg: []protocol.HeaderOpt{
+				protocol.WithCorrelationID("testCorrelationID"),
+			},
+			want: &protocol.Envelope{
+				Topic: cmd.Topic,
+				Path:  cmd.Path,
+				Value: cmd.Payload,
+				Headers: &protocol.Headers{
+					Values: map[string]interface{}{
+						protocol.HeaderCorrelationID: "testCorrelationID",
+					},
+				},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := cmd.Envelope(testCase.arg...)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestEnvelopeTopic(t *testing.T) {
+	env := NewCommand(testPolicyID).Retrieve().Envelope()
+
+	data, err := json.Marshal(env)
+	internal.AssertNil(t, err)
+
+	var got map[string]interface{}
+	internal.AssertNil(t, json.Unmarshal(data, &got))
+	internal.AssertEqual(t, "testNamespace/testName/policies/commands/retrieve", got["topic"])
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package rql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Options is a builder of the search options, e.g. 'size(10),sort(+thingId,-_modified),cursor(LOREMIPSUM)'.
+type Options struct {
+	size   int
+	sort   []string
+	cursor string
+}
+
+// NewOptions creates a new empty Options instance.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// Size configures the maximum number of results per page.
+func (opts *Options) Size(size int) *Options {
+	opts.size = size
+	return opts
+}
+
+// SortAsc adds the provided property to the sort order of the results in ascending order.
+func (opts *Options) SortAsc(property string) *Options {
+	opts.sort = append(opts.sort, "+"+property)
+	return opts
+}
+
+// SortDesc adds the provided property to the sort order of the results in descending order.
+func (opts *Options) SortDesc(property string) *Options {
+	opts.sort = append(opts.sort, "-"+property)
+	return opts
+}
+
+// Cursor configures the cursor of the results page to continue the search from,
+// as provided along with the previous page of results.
+func (opts *Options) Cursor(cursor string) *Options {
+	opts.cursor = cursor
+	return opts
+}
+
+// String provides the string representation of the Options.
+func (opts *Options) String() string {
+	var options []string
+	if opts.size > 0 {
+		options = append(options, "size("+strconv.Itoa(opts.size)+")")
+	}
+	if len(opts.sort) > 0 {
+		options = append(options, "sort("+strings.Join(opts.sort, ",")+")")
+	}
+	if len(opts.cursor) > 0 {
+		options = append(options, "cursor("+opts.cursor+")")
+	}
+	return strings.Join(options, ",")
+}
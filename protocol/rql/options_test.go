@@ -0,0 +1,40 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package rql
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestOptions(t *testing.T) {
+	tests := map[string]struct {
+		arg  *Options
+		want string
+	}{
+		"test_empty":  {arg: NewOptions(), want: ""},
+		"test_size":   {arg: NewOptions().Size(10), want: "size(10)"},
+		"test_sort":   {arg: NewOptions().SortAsc("thingId").SortDesc("_modified"), want: "sort(+thingId,-_modified)"},
+		"test_cursor": {arg: NewOptions().Cursor("LOREMIPSUM"), want: "cursor(LOREMIPSUM)"},
+		"test_all": {
+			arg:  NewOptions().Cursor("c").SortDesc("attributes/x").Size(5),
+			want: "size(5),sort(-attributes/x),cursor(c)",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.arg.String())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package rql provides builders of the RQL (Resource Query Language) expressions supported by Ditto,
+// i.e. the search filters and options, as well as the filters of the events subscriptions.
+// See https://www.eclipse.org/ditto/basic-rql.html
+package rql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Filter represents an RQL filter expression, e.g. 'and(eq(attributes/location,"kitchen"),gt(features/temp/properties/value,20))'.
+type Filter string
+
+// String provides the RQL representation of the Filter.
+func (filter Filter) String() string {
+	return string(filter)
+}
+
+// Eq creates a filter matching the entities with the property equal to the provided value.
+func Eq(property string, value interface{}) Filter {
+	return comparison("eq", property, value)
+}
+
+// Ne creates a filter matching the entities with the property not equal to the provided value.
+func Ne(property string, value interface{}) Filter {
+	return comparison("ne", property, value)
+}
+
+// Gt creates a filter matching the entities with the property greater than the provided value.
+func Gt(property string, value interface{}) Filter {
+	return comparison("gt", property, value)
+}
+
+// Ge creates a filter matching the entities with the property greater than or equal to the provided value.
+func Ge(property string, value interface{}) Filter {
+	return comparison("ge", property, value)
+}
+
+// Lt creates a filter matching the entities with the property less than the provided value.
+func Lt(property string, value interface{}) Filter {
+	return comparison("lt", property, value)
+}
+
+// Le creates a filter matching the entities with the property less than or equal to the provided value.
+func Le(property string, value interface{}) Filter {
+	return comparison("le", property, value)
+}
+
+// Like creates a filter matching the entities with the string property matching the provided pattern,
+// where '*' matches any number of characters and '?' matches a single character.
+func Like(property string, pattern string) Filter {
+	return comparison("like", property, pattern)
+}
+
+// ILike is the case-insensitive version of Like.
+func ILike(property string, pattern string) Filter {
+	return comparison("ilike", property, pattern)
+}
+
+// In creates a filter matching the entities with the property equal to any of the provided values.
+func In(property string, values ...interface{}) Filter {
+	args := make([]string, len(values)+1)
+	args[0] = property
+	for i, value := range values {
+		args[i+1] = literal(value)
+	}
+	return operator("in", args...)
+}
+
+// Exists creates a filter matching the entities that have the property.
+func Exists(property string) Filter {
+	return operator("exists", property)
+}
+
+// And creates a filter matching the entities that match all of the provided filters.
+func And(filters ...Filter) Filter {
+	return logical("and", filters)
+}
+
+// Or creates a filter matching the entities that match any of the provided filters.
+func Or(filters ...Filter) Filter {
+	return logical("or", filters)
+}
+
+// Not creates a filter matching the entities that don't match the provided filter.
+func Not(filter Filter) Filter {
+	return operator("not", string(filter))
+}
+
+func comparison(name string, property string, value interface{}) Filter {
+	return operator(name, property, literal(value))
+}
+
+func logical(name string, filters []Filter) Filter {
+	args := make([]string, len(filters))
+	for i, filter := range filters {
+		args[i] = string(filter)
+	}
+	return operator(name, args...)
+}
+
+func operator(name string, args ...string) Filter {
+	return Filter(name + "(" + strings.Join(args, ",") + ")")
+}
+
+// literal renders the provided value as an RQL literal - strings are double-quoted and escaped as JSON strings,
+// numbers and booleans are rendered as they are and nil is rendered as null.
+func literal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	}
+	return string(data)
+}
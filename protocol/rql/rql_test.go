@@ -0,0 +1,48 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package rql
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestFilters(t *testing.T) {
+	tests := map[string]struct {
+		arg  Filter
+		want string
+	}{
+		"test_eq_string":  {arg: Eq("attributes/location", "kitchen"), want: `eq(attributes/location,"kitchen")`},
+		"test_eq_escaped": {arg: Eq("attributes/name", `a "b"`), want: `eq(attributes/name,"a \"b\"")`},
+		"test_ne_null":    {arg: Ne("attributes/owner", nil), want: `ne(attributes/owner,null)`},
+		"test_gt_int":     {arg: Gt("features/temp/properties/value", 20), want: `gt(features/temp/properties/value,20)`},
+		"test_ge_float":   {arg: Ge("attributes/x", 1.5), want: `ge(attributes/x,1.5)`},
+		"test_lt_bool":    {arg: Lt("attributes/x", false), want: `lt(attributes/x,false)`},
+		"test_le":         {arg: Le("_modified", "2022-01-01T00:00:00Z"), want: `le(_modified,"2022-01-01T00:00:00Z")`},
+		"test_like":       {arg: Like("thingId", "org.eclipse:*"), want: `like(thingId,"org.eclipse:*")`},
+		"test_ilike":      {arg: ILike("attributes/name", "lamp?"), want: `ilike(attributes/name,"lamp?")`},
+		"test_in":         {arg: In("attributes/color", "red", "green", 1), want: `in(attributes/color,"red","green",1)`},
+		"test_exists":     {arg: Exists("features/lamp"), want: `exists(features/lamp)`},
+		"test_not":        {arg: Not(Exists("attributes/x")), want: `not(exists(attributes/x))`},
+		"test_and_or": {
+			arg:  And(Eq("attributes/a", 1), Or(Exists("attributes/b"), Gt("attributes/c", 2))),
+			want: `and(eq(attributes/a,1),or(exists(attributes/b),gt(attributes/c,2)))`,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.arg.String())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import "net/http"
+
+// HTTP status constants, applicable as Envelope statuses.
+const (
+	StatusOK                  = http.StatusOK
+	StatusCreated             = http.StatusCreated
+	StatusAccepted            = http.StatusAccepted
+	StatusNoContent           = http.StatusNoContent
+	StatusNotModified         = http.StatusNotModified
+	StatusBadRequest          = http.StatusBadRequest
+	StatusUnauthorized        = http.StatusUnauthorized
+	StatusForbidden           = http.StatusForbidden
+	StatusNotFound            = http.StatusNotFound
+	StatusRequestTimeout      = http.StatusRequestTimeout
+	StatusConflict            = http.StatusConflict
+	StatusPreconditionFailed  = http.StatusPreconditionFailed
+	StatusTooManyRequests     = http.StatusTooManyRequests
+	StatusInternalServerError = http.StatusInternalServerError
+	StatusServiceUnavailable  = http.StatusServiceUnavailable
+	StatusGatewayTimeout      = http.StatusGatewayTimeout
+)
+
+// IsSuccess returns true if the Envelope's status is a successful (2xx) one.
+func (msg *Envelope) IsSuccess() bool {
+	return msg.Status >= 200 && msg.Status < 300
+}
+
+// IsClientError returns true if the Envelope's status is a client error (4xx) one.
+func (msg *Envelope) IsClientError() bool {
+	return msg.Status >= 400 && msg.Status < 500
+}
+
+// IsServerError returns true if the Envelope's status is a server error (5xx) one.
+func (msg *Envelope) IsServerError() bool {
+	return msg.Status >= 500 && msg.Status < 600
+}
+
+// IsTimeout returns true if the Envelope's status reports a timeout, i.e. it's either 408 or 504.
+func (msg *Envelope) IsTimeout() bool {
+	return msg.Status == StatusRequestTimeout || msg.Status == StatusGatewayTimeout
+}
+
+// ExpectedStatusFor returns the statuses of a successful response to a command with the provided action:
+// 201 for create, 201 (created) or 204 (modified) for modify, 204 for merge and delete and 200 for retrieve.
+// Returns nil for all other actions.
+func ExpectedStatusFor(action TopicAction) []int {
+	switch action {
+	case ActionCreate:
+		return []int{StatusCreated}
+	case ActionModify:
+		return []int{StatusCreated, StatusNoContent}
+	case ActionMerge, ActionDelete:
+		return []int{StatusNoContent}
+	case ActionRetrieve:
+		return []int{StatusOK}
+	default:
+		return nil
+	}
+}
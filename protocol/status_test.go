@@ -0,0 +1,67 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestEnvelopeStatusPredicates(t *testing.T) {
+	tests := map[string]struct {
+		status          int
+		wantSuccess     bool
+		wantClientError bool
+		wantServerError bool
+		wantTimeout     bool
+	}{
+		"test_no_status":       {status: 0},
+		"test_ok":              {status: StatusOK, wantSuccess: true},
+		"test_no_content":      {status: StatusNoContent, wantSuccess: true},
+		"test_not_modified":    {status: StatusNotModified},
+		"test_not_found":       {status: StatusNotFound, wantClientError: true},
+		"test_request_timeout": {status: StatusRequestTimeout, wantClientError: true, wantTimeout: true},
+		"test_internal_error":  {status: StatusInternalServerError, wantServerError: true},
+		"test_gateway_timeout": {status: StatusGatewayTimeout, wantServerError: true, wantTimeout: true},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			msg := &Envelope{Status: testCase.status}
+			internal.AssertEqual(t, testCase.wantSuccess, msg.IsSuccess())
+			internal.AssertEqual(t, testCase.wantClientError, msg.IsClientError())
+			internal.AssertEqual(t, testCase.wantServerError, msg.IsServerError())
+			internal.AssertEqual(t, testCase.wantTimeout, msg.IsTimeout())
+		})
+	}
+}
+
+func TestExpectedStatusFor(t *testing.T) {
+	tests := map[string]struct {
+		arg  TopicAction
+		want []int
+	}{
+		"test_create":   {arg: ActionCreate, want: []int{201}},
+		"test_modify":   {arg: ActionModify, want: []int{201, 204}},
+		"test_merge":    {arg: ActionMerge, want: []int{204}},
+		"test_delete":   {arg: ActionDelete, want: []int{204}},
+		"test_retrieve": {arg: ActionRetrieve, want: []int{200}},
+		"test_event":    {arg: ActionModified, want: nil},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, ExpectedStatusFor(testCase.arg))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/eclipse/ditto-clients-golang/protocol/rql"
+)
+
+// StreamingType defines the type of the Ditto messages a WebSocket client requests to receive.
+type StreamingType string
+
+const (
+	// StreamingEvents represents the twin events.
+	StreamingEvents StreamingType = "EVENTS"
+	// StreamingMessages represents the live messages.
+	StreamingMessages StreamingType = "MESSAGES"
+	// StreamingLiveCommands represents the live commands.
+	StreamingLiveCommands StreamingType = "LIVE-COMMANDS"
+	// StreamingLiveEvents represents the live events.
+	StreamingLiveEvents StreamingType = "LIVE-EVENTS"
+	// StreamingPolicyAnnouncements represents the policy announcements.
+	StreamingPolicyAnnouncements StreamingType = "POLICY-ANNOUNCEMENTS"
+)
+
+const (
+	streamingStartPrefix = "START-SEND-"
+	streamingStopPrefix  = "STOP-SEND-"
+	streamingAckSuffix   = ":ACK"
+
+	streamingTargetTopicPrefix = "_/_/"
+
+	streamingParamNamespaces  = "namespaces"
+	streamingParamFilter      = "filter"
+	streamingParamExtraFields = "extraFields"
+)
+
+// StreamingSubscription represents a subscription of a Ditto WebSocket client for receiving a type of messages,
+// requested via the START-SEND-* and cancelled via the STOP-SEND-* signals of the Ditto WebSocket protocol.
+// The received messages could be restricted to the provided namespaces and to the ones matching
+// the provided RQL filter, and enriched with the provided extra fields, which are ignored for the types not supporting them.
+type StreamingSubscription struct {
+	Type        StreamingType
+	Namespaces  []string
+	Filter      string
+	ExtraFields *FieldSelector
+}
+
+// NewStreamingSubscription creates a new StreamingSubscription for the provided type of messages.
+func NewStreamingSubscription(streamingType StreamingType) *StreamingSubscription {
+	return &StreamingSubscription{Type: streamingType}
+}
+
+// WithNamespaces restricts the StreamingSubscription to the messages of the entities in the provided namespaces.
+func (subscription *StreamingSubscription) WithNamespaces(namespaces ...string) *StreamingSubscription {
+	subscription.Namespaces = namespaces
+	return subscription
+}
+
+// WithFilter restricts the StreamingSubscription to the messages matching the provided RQL filter,
+// e.g. rql.Gt("features/temp/properties/value", 20).
+func (subscription *StreamingSubscription) WithFilter(filter rql.Filter) *StreamingSubscription {
+	subscription.Filter = filter.String()
+	return subscription
+}
+
+// WithExtraFields requests the messages of the StreamingSubscription to be enriched with the selected extra fields.
+func (subscription *StreamingSubscription) WithExtraFields(fields *FieldSelector) *StreamingSubscription {
+	subscription.ExtraFields = fields
+	return subscription
+}
+
+// streamingTargetTopics maps the StreamingTypes to the corresponding topics of the Ditto connection targets.
+var streamingTargetTopics = map[StreamingType]string{
+	StreamingEvents:              "things/twin/events",
+	StreamingMessages:            "things/live/messages",
+	StreamingLiveCommands:        "things/live/commands",
+	StreamingLiveEvents:          "things/live/events",
+	StreamingPolicyAnnouncements: "policies/announcements",
+}
+
+// StartSignal provides the Ditto WebSocket protocol signal requesting the StreamingSubscription,
+// e.g. 'START-SEND-EVENTS?namespaces=org.eclipse.ditto&filter=exists(attributes/location)'.
+func (subscription *StreamingSubscription) StartSignal() string {
+	return streamingStartPrefix + string(subscription.Type) + subscription.params()
+}
+
+// TargetTopic provides the topic of a Ditto connection target, e.g. of the connection the devices' MQTT broker
+// or Hono is connected with, requesting the same messages as the StreamingSubscription,
+// e.g. '_/_/things/twin/events?namespaces=org.eclipse.ditto&filter=exists(attributes/location)'.
+// This way, the filtering applies before the messages are sent to the devices, where a WebSocket connection is not an option.
+// An empty string is returned for an unknown StreamingType.
+func (subscription *StreamingSubscription) TargetTopic() string {
+	topic, ok := streamingTargetTopics[subscription.Type]
+	if !ok {
+		return ""
+	}
+	return streamingTargetTopicPrefix + topic + subscription.params()
+}
+
+// params provides the query parameters of the StreamingSubscription including the leading '?'
+// or an empty string if there are none.
+func (subscription *StreamingSubscription) params() string {
+	var params []string
+	if len(subscription.Namespaces) > 0 {
+		params = append(params, streamingParamNamespaces+"="+url.QueryEscape(strings.Join(subscription.Namespaces, ",")))
+	}
+	if subscription.Filter != "" {
+		params = append(params, streamingParamFilter+"="+url.QueryEscape(subscription.Filter))
+	}
+	if subscription.ExtraFields != nil {
+		if fields := subscription.ExtraFields.String(); fields != "" {
+			params = append(params, streamingParamExtraFields+"="+url.QueryEscape(fields))
+		}
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(params, "&")
+}
+
+// StopSignal provides the Ditto WebSocket protocol signal cancelling the StreamingSubscription, e.g. 'STOP-SEND-EVENTS'.
+func (subscription *StreamingSubscription) StopSignal() string {
+	return streamingStopPrefix + string(subscription.Type)
+}
+
+// ParseStreamingAck parses the provided Ditto WebSocket protocol acknowledgement of a START-SEND-* or STOP-SEND-*
+// signal, e.g. 'START-SEND-EVENTS:ACK', and provides the acknowledged StreamingType along with true if the streaming
+// is started or false if it is stopped. The last result is false if the provided message is not such an acknowledgement.
+func ParseStreamingAck(message string) (streamingType StreamingType, started bool, ok bool) {
+	if !strings.HasSuffix(message, streamingAckSuffix) {
+		return "", false, false
+	}
+	signal := strings.TrimSuffix(message, streamingAckSuffix)
+	switch {
+	case strings.HasPrefix(signal, streamingStartPrefix):
+		streamingType, started = StreamingType(strings.TrimPrefix(signal, streamingStartPrefix)), true
+	case strings.HasPrefix(signal, streamingStopPrefix):
+		streamingType = StreamingType(strings.TrimPrefix(signal, streamingStopPrefix))
+	default:
+		return "", false, false
+	}
+	if streamingType == "" {
+		return "", false, false
+	}
+	return streamingType, started, true
+}
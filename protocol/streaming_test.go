@@ -0,0 +1,119 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol/rql"
+)
+
+func TestStreamingSubscriptionSignals(t *testing.T) {
+	tests := map[string]struct {
+		subscription *StreamingSubscription
+		wantStart    string
+		wantStop     string
+		wantTopic    string
+	}{
+		"test_events_without_params": {
+			subscription: NewStreamingSubscription(StreamingEvents),
+			wantStart:    "START-SEND-EVENTS",
+			wantStop:     "STOP-SEND-EVENTS",
+			wantTopic:    "_/_/things/twin/events",
+		},
+		"test_events_with_all_params": {
+			subscription: NewStreamingSubscription(StreamingEvents).
+				WithNamespaces("org.eclipse.ditto", "com.example").
+				WithFilter(rql.Eq("attributes/location", "kitchen")).
+				WithExtraFields(Fields().Attributes("location")),
+			wantStart: "START-SEND-EVENTS?namespaces=org.eclipse.ditto%2Ccom.example" +
+				"&filter=eq%28attributes%2Flocation%2C%22kitchen%22%29&extraFields=attributes%2Flocation",
+			wantStop: "STOP-SEND-EVENTS",
+			wantTopic: "_/_/things/twin/events?namespaces=org.eclipse.ditto%2Ccom.example" +
+				"&filter=eq%28attributes%2Flocation%2C%22kitchen%22%29&extraFields=attributes%2Flocation",
+		},
+		"test_live_messages_with_namespaces": {
+			subscription: NewStreamingSubscription(StreamingMessages).WithNamespaces("ns"),
+			wantStart:    "START-SEND-MESSAGES?namespaces=ns",
+			wantStop:     "STOP-SEND-MESSAGES",
+			wantTopic:    "_/_/things/live/messages?namespaces=ns",
+		},
+		"test_live_commands_with_empty_extra_fields": {
+			subscription: NewStreamingSubscription(StreamingLiveCommands).WithExtraFields(Fields()),
+			wantStart:    "START-SEND-LIVE-COMMANDS",
+			wantStop:     "STOP-SEND-LIVE-COMMANDS",
+			wantTopic:    "_/_/things/live/commands",
+		},
+		"test_policy_announcements": {
+			subscription: NewStreamingSubscription(StreamingPolicyAnnouncements),
+			wantStart:    "START-SEND-POLICY-ANNOUNCEMENTS",
+			wantStop:     "STOP-SEND-POLICY-ANNOUNCEMENTS",
+			wantTopic:    "_/_/policies/announcements",
+		},
+		"test_unknown_type": {
+			subscription: NewStreamingSubscription("UNKNOWN").WithNamespaces("ns"),
+			wantStart:    "START-SEND-UNKNOWN?namespaces=ns",
+			wantStop:     "STOP-SEND-UNKNOWN",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.wantStart, testCase.subscription.StartSignal())
+			internal.AssertEqual(t, testCase.wantStop, testCase.subscription.StopSignal())
+			internal.AssertEqual(t, testCase.wantTopic, testCase.subscription.TargetTopic())
+		})
+	}
+}
+
+func TestParseStreamingAck(t *testing.T) {
+	tests := map[string]struct {
+		message     string
+		wantType    StreamingType
+		wantStarted bool
+		wantOk      bool
+	}{
+		"test_start_ack": {
+			message:     "START-SEND-LIVE-EVENTS:ACK",
+			wantType:    StreamingLiveEvents,
+			wantStarted: true,
+			wantOk:      true,
+		},
+		"test_stop_ack": {
+			message:  "STOP-SEND-EVENTS:ACK",
+			wantType: StreamingEvents,
+			wantOk:   true,
+		},
+		"test_signal_without_ack": {
+			message: "START-SEND-EVENTS",
+		},
+		"test_ack_without_type": {
+			message: "START-SEND-:ACK",
+		},
+		"test_unknown_ack": {
+			message: "JWT-TOKEN:ACK",
+		},
+		"test_protocol_message": {
+			message: `{"topic":"ns/thing/things/twin/events/modified","path":"/"}`,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			streamingType, started, ok := ParseStreamingAck(testCase.message)
+			internal.AssertEqual(t, testCase.wantType, streamingType)
+			internal.AssertEqual(t, testCase.wantStarted, started)
+			internal.AssertEqual(t, testCase.wantOk, ok)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// Built-in acknowledgement label constants.
+const (
+	// AckLabelTwinPersisted is the label of the acknowledgement issued by Ditto once a modification is persisted in the Thing's twin.
+	AckLabelTwinPersisted = "twin-persisted"
+	// AckLabelLiveResponse is the label of the acknowledgement issued by Ditto once a live command's response is received.
+	AckLabelLiveResponse = "live-response"
+	// AckLabelSearchPersisted is the label of the acknowledgement issued by Ditto once a modification is applied to the search index.
+	AckLabelSearchPersisted = "search-persisted"
+)
+
+// Acknowledgement represents an acknowledgement entity defined by the Ditto protocol for the Things group.
+// It is always bound to a specific Thing instance and identified by its label, which is provided as the topic's action.
+// An Acknowledgement provides the capabilities to configure:
+// - the status of the acknowledgement, i.e. if the acknowledged processing was successful
+// - the channel it will be sent - Twin, Live
+// - an optional payload.
+// Note: Only one channel can be configured to the acknowledgement - if using the methods for configuring it - only the last one applies.
+type Acknowledgement struct {
+	Topic   *protocol.Topic
+	Label   string
+	Status  int
+	Payload interface{}
+	Headers *protocol.Headers
+}
+
+type aggregatedAcknowledgement struct {
+	Status  int               `json:"status"`
+	Payload interface{}       `json:"payload,omitempty"`
+	Headers *protocol.Headers `json:"headers,omitempty"`
+}
+
+// NewAcknowledgement creates a new Acknowledgement instance with the provided label for the defined by the provided NamespacedID Thing.
+func NewAcknowledgement(thingID *model.NamespacedID, label string) *Acknowledgement {
+	return &Acknowledgement{
+		Topic: (&protocol.Topic{}).
+			WithNamespace(thingID.Namespace).
+			WithEntityName(thingID.Name).
+			WithGroup(protocol.GroupThings).
+			WithChannel(protocol.ChannelTwin).
+			WithCriterion(protocol.CriterionAcks).
+			WithAction(protocol.TopicAction(label)),
+		Label: label,
+	}
+}
+
+// NewAcknowledgementTo creates a new Acknowledgement instance with the provided label acknowledging the provided request Envelope.
+// The Acknowledgement is bound to the Thing and the channel of the request and has its 'correlation-id' header.
+func NewAcknowledgementTo(request *protocol.Envelope, label string) *Acknowledgement {
+	ack := NewAcknowledgement(model.NewNamespacedID(request.Topic.Namespace, request.Topic.EntityName), label)
+	if request.Topic.Channel == protocol.ChannelLive {
+		ack.Live()
+	}
+	if request.Headers != nil && len(request.Headers.CorrelationID()) > 0 {
+		ack.Headers = protocol.NewHeaders(protocol.WithCorrelationID(request.Headers.CorrelationID()))
+	}
+	return ack
+}
+
+// NewTwinPersistedAcknowledgement creates a new 'twin-persisted' Acknowledgement with the provided status
+// acknowledging the provided request Envelope, e.g. for gateways or test tooling acting on behalf of Ditto.
+func NewTwinPersistedAcknowledgement(request *protocol.Envelope, status int) *Acknowledgement {
+	return NewAcknowledgementTo(request, AckLabelTwinPersisted).WithStatus(status)
+}
+
+// WithTwinPersistedAck requests the 'twin-persisted' acknowledgement, so that Ditto confirms that the modification
+// is actually persisted. Unlike protocol.WithRequestedAcks, the label is added to any already requested acknowledgements.
+func WithTwinPersistedAck() protocol.HeaderOpt {
+	return func(headers *protocol.Headers) error {
+		labels := headers.RequestedAcks()
+		for _, label := range labels {
+			if label == AckLabelTwinPersisted {
+				return nil
+			}
+		}
+		return protocol.WithRequestedAcks(append(labels, AckLabelTwinPersisted)...)(headers)
+	}
+}
+
+// WithStatus sets the status of the Acknowledgement based on the HTTP codes available.
+func (ack *Acknowledgement) WithStatus(status int) *Acknowledgement {
+	ack.Status = status
+	return ack
+}
+
+// WithPayload sets the payload of the Acknowledgement.
+func (ack *Acknowledgement) WithPayload(payload interface{}) *Acknowledgement {
+	ack.Payload = payload
+	return ack
+}
+
+// Live configures the channel of the Acknowledgement accordingly.
+func (ack *Acknowledgement) Live() *Acknowledgement {
+	ack.Topic.WithChannel(protocol.ChannelLive)
+	return ack
+}
+
+// Twin configures the channel of the Acknowledgement accordingly.
+func (ack *Acknowledgement) Twin() *Acknowledgement {
+	ack.Topic.WithChannel(protocol.ChannelTwin)
+	return ack
+}
+
+// IsSuccess returns true if the status of the Acknowledgement is a 2xx one.
+func (ack *Acknowledgement) IsSuccess() bool {
+	return ack.Status >= 200 && ack.Status < 300
+}
+
+// Envelope generates the Ditto envelope with acknowledgement's data applying all configurations and optionally all Headers provided.
+// The correlation-id of the acknowledged request is expected to be provided via the Headers,
+// unless the Acknowledgement has been created via NewAcknowledgementTo.
+func (ack *Acknowledgement) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
+	msg := &protocol.Envelope{
+		Topic:  ack.Topic,
+		Path:   pathThing,
+		Value:  ack.Payload,
+		Status: ack.Status,
+	}
+	if ack.Headers != nil {
+		msg.Headers = protocol.NewHeadersFrom(ack.Headers, headerOpts...)
+	} else if headerOpts != nil {
+		msg.Headers = protocol.NewHeaders(headerOpts...)
+	}
+	return msg
+}
+
+// ParseAcknowledgements parses the provided Envelope into Acknowledgement instances.
+// A single acknowledgement is provided for an Envelope with a label as topic action and
+// one per label, ordered by label - for an aggregated acknowledgements Envelope, i.e. one without a topic action.
+// Returns an error if the Envelope is not an acknowledgement or its value cannot be decoded.
+func ParseAcknowledgements(env *protocol.Envelope) ([]*Acknowledgement, error) {
+	if env == nil || env.Topic == nil {
+		return nil, errors.New("envelope without topic is not an acknowledgement")
+	}
+	if env.Topic.Group != protocol.GroupThings || env.Topic.Criterion != protocol.CriterionAcks {
+		return nil, fmt.Errorf("envelope with topic '%s' is not an acknowledgement", env.Topic.String())
+	}
+
+	if len(env.Topic.Action) > 0 {
+		return []*Acknowledgement{{
+			Topic:   env.Topic,
+			Label:   string(env.Topic.Action),
+			Status:  env.Status,
+			Payload: env.Value,
+			Headers: env.Headers,
+		}}, nil
+	}
+
+	aggregated := map[string]*aggregatedAcknowledgement{}
+	if err := decodeValue(env.Value, &aggregated); err != nil {
+		return nil, err
+	}
+	labels := make([]string, 0, len(aggregated))
+	for label := range aggregated {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	acks := make([]*Acknowledgement, 0, len(aggregated))
+	for _, label := range labels {
+		value := aggregated[label]
+		if value == nil {
+			return nil, fmt.Errorf("invalid acknowledgement with label '%s'", label)
+		}
+		topic := *env.Topic
+		acks = append(acks, &Acknowledgement{
+			Topic:   topic.WithAction(protocol.TopicAction(label)),
+			Label:   label,
+			Status:  value.Status,
+			Payload: value.Payload,
+			Headers: value.Headers,
+		})
+	}
+	return acks, nil
+}
+
+// FindAcknowledgement returns the Acknowledgement with the provided label among the provided ones or nil if there is no such.
+func FindAcknowledgement(acks []*Acknowledgement, label string) *Acknowledgement {
+	for _, ack := range acks {
+		if ack.Label == label {
+			return ack
+		}
+	}
+	return nil
+}
+
+// IsTwinPersisted returns true if the provided acknowledgement(s) Envelope contains a successful 'twin-persisted' acknowledgement.
+// Returns an error if the Envelope is not an acknowledgement or its value cannot be decoded.
+func IsTwinPersisted(env *protocol.Envelope) (bool, error) {
+	acks, err := ParseAcknowledgements(env)
+	if err != nil {
+		return false, err
+	}
+	ack := FindAcknowledgement(acks, AckLabelTwinPersisted)
+	return ack != nil && ack.IsSuccess(), nil
+}
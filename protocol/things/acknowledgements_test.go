@@ -0,0 +1,200 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func TestNewAcknowledgement(t *testing.T) {
+	want := &Acknowledgement{
+		Topic: &protocol.Topic{
+			Namespace:  testNamespaceID.Namespace,
+			EntityName: testNamespaceID.Name,
+			Group:      protocol.GroupThings,
+			Channel:    protocol.ChannelTwin,
+			Criterion:  protocol.CriterionAcks,
+			Action:     "custom-ack",
+		},
+		Label: "custom-ack",
+	}
+
+	got := NewAcknowledgement(testNamespaceID, "custom-ack")
+	internal.AssertEqual(t, want, got)
+}
+
+func TestAcknowledgementEnvelope(t *testing.T) {
+	ack := NewAcknowledgement(testNamespaceID, "custom-ack").Live().WithStatus(200).WithPayload("done")
+
+	got := ack.Envelope(protocol.WithCorrelationID("testCorrelationID"))
+	internal.AssertTrue(t, ack.IsSuccess())
+
+	data, err := json.Marshal(got)
+	internal.AssertNil(t, err)
+	want := `{"topic":"testNamespace/testName/things/live/acks/custom-ack",` +
+		`"headers":{"correlation-id":"testCorrelationID"},"path":"/","value":"done","status":200}`
+	internal.AssertEqual(t, want, string(data))
+}
+
+func TestParseAcknowledgementsSingle(t *testing.T) {
+	env := unmarshalEnvelope(t, `{"topic":"testNamespace/testName/things/twin/acks/custom-ack",
+		"headers":{"correlation-id":"testCorrelationID"},"path":"/","value":{"a":1},"status":400}`)
+
+	got, err := ParseAcknowledgements(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 1, len(got))
+	internal.AssertEqual(t, "custom-ack", got[0].Label)
+	internal.AssertEqual(t, 400, got[0].Status)
+	internal.AssertEqual(t, map[string]interface{}{"a": float64(1)}, got[0].Payload)
+	internal.AssertEqual(t, "testCorrelationID", got[0].Headers.CorrelationID())
+	internal.AssertFalse(t, got[0].IsSuccess())
+}
+
+func TestParseAcknowledgementsAggregated(t *testing.T) {
+	env := unmarshalEnvelope(t, `{"topic":"testNamespace/testName/things/twin/acks","path":"/","status":200,
+		"value":{"twin-persisted":{"status":204},"custom-ack":{"status":200,"payload":"ok","headers":{"x":"y"}}}}`)
+
+	got, err := ParseAcknowledgements(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, 2, len(got))
+
+	internal.AssertEqual(t, "custom-ack", got[0].Label)
+	internal.AssertEqual(t, protocol.TopicAction("custom-ack"), got[0].Topic.Action)
+	internal.AssertEqual(t, 200, got[0].Status)
+	internal.AssertEqual(t, "ok", got[0].Payload)
+	internal.AssertEqual(t, "y", got[0].Headers.Generic("x"))
+
+	internal.AssertEqual(t, "twin-persisted", got[1].Label)
+	internal.AssertEqual(t, protocol.TopicAction("twin-persisted"), got[1].Topic.Action)
+	internal.AssertEqual(t, 204, got[1].Status)
+	internal.AssertNil(t, got[1].Headers)
+
+	internal.AssertEqual(t, protocol.TopicAction(""), env.Topic.Action)
+}
+
+func TestParseAcknowledgementsErrors(t *testing.T) {
+	tests := map[string]struct {
+		arg *protocol.Envelope
+	}{
+		"test_nil_envelope": {
+			arg: nil,
+		},
+		"test_without_topic": {
+			arg: &protocol.Envelope{},
+		},
+		"test_not_acks_criterion": {
+			arg: NewCommand(testNamespaceID).Delete().Envelope(),
+		},
+		"test_invalid_aggregated_value": {
+			arg: &protocol.Envelope{
+				Topic: (&protocol.Topic{}).WithGroup(protocol.GroupThings).WithCriterion(protocol.CriterionAcks),
+				Value: "invalid",
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseAcknowledgements(testCase.arg)
+			internal.AssertNil(t, got)
+			internal.AssertNotNil(t, err)
+		})
+	}
+}
+
+func TestWithTwinPersistedAck(t *testing.T) {
+	tests := map[string]struct {
+		opts []protocol.HeaderOpt
+		want []string
+	}{
+		"test_without_requested_acks": {
+			opts: []protocol.HeaderOpt{WithTwinPersistedAck()},
+			want: []string{AckLabelTwinPersisted},
+		},
+		"test_with_other_requested_acks": {
+			opts: []protocol.HeaderOpt{protocol.WithRequestedAcks("custom-ack"), WithTwinPersistedAck()},
+			want: []string{"custom-ack", AckLabelTwinPersisted},
+		},
+		"test_with_twin_persisted_already_requested": {
+			opts: []protocol.HeaderOpt{protocol.WithRequestedAcks(AckLabelTwinPersisted), WithTwinPersistedAck()},
+			want: []string{AckLabelTwinPersisted},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := protocol.NewHeaders(testCase.opts...)
+			internal.AssertEqual(t, testCase.want, got.RequestedAcks())
+		})
+	}
+}
+
+func TestNewTwinPersistedAcknowledgement(t *testing.T) {
+	request := NewCommand(testNamespaceID).Live().Feature("lamp").Modify(map[string]interface{}{}).
+		Envelope(protocol.WithCorrelationID("testCorrelationID"), WithTwinPersistedAck())
+
+	ack := NewTwinPersistedAcknowledgement(request, 204)
+	internal.AssertEqual(t, AckLabelTwinPersisted, ack.Label)
+	internal.AssertTrue(t, ack.IsSuccess())
+
+	data, err := json.Marshal(ack.Envelope())
+	internal.AssertNil(t, err)
+	want := `{"topic":"testNamespace/testName/things/live/acks/twin-persisted",` +
+		`"headers":{"correlation-id":"testCorrelationID"},"path":"/","status":204}`
+	internal.AssertEqual(t, want, string(data))
+}
+
+func TestIsTwinPersisted(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    bool
+		wantErr bool
+	}{
+		"test_single_twin_persisted": {
+			data: `{"topic":"testNamespace/testName/things/twin/acks/twin-persisted","path":"/","status":204}`,
+			want: true,
+		},
+		"test_single_twin_persisted_failed": {
+			data: `{"topic":"testNamespace/testName/things/twin/acks/twin-persisted","path":"/","status":408}`,
+			want: false,
+		},
+		"test_aggregated_twin_persisted": {
+			data: `{"topic":"testNamespace/testName/things/twin/acks","path":"/","status":424,
+				"value":{"twin-persisted":{"status":204},"custom-ack":{"status":408}}}`,
+			want: true,
+		},
+		"test_without_twin_persisted": {
+			data: `{"topic":"testNamespace/testName/things/twin/acks/custom-ack","path":"/","status":200}`,
+			want: false,
+		},
+		"test_not_acknowledgement": {
+			data:    `{"topic":"testNamespace/testName/things/twin/commands/modify","path":"/","status":204}`,
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := IsTwinPersisted(unmarshalEnvelope(t, testCase.data))
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+			}
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
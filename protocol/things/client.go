@@ -0,0 +1,273 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// Client is a high-level client for managing Things over a ditto.Client. Each of its operations builds the Twin Command,
+// sends it with a generated correlation ID, waits for the correlated response and decodes either its value
+// or the Ditto error it reports (as a *protocol.ErrorResponse). The Client also watches the received Twin events
+// for changes of the Things' entities, e.g. via OnFeaturePropertyChanged.
+// Note: The Client subscribes a handler to the ditto.Client on creation, thus it is to be closed when no longer used.
+type Client struct {
+	client       ditto.Client
+	subscription []ditto.HandlerID
+	maxTimeout   time.Duration
+
+	pendingLock sync.Mutex
+	pending     map[string]chan *protocol.Envelope
+
+	watchersLock  sync.Mutex
+	watchers      map[int]*changeWatcher
+	lastWatcherID int
+}
+
+// ClientOpt represents a configuration option of a Client.
+type ClientOpt func(client *Client)
+
+// WithMaxTimeout configures the maximum 'timeout' header value accepted by the Client's validation of the requests,
+// for Ditto installations configured to allow more than the default protocol.DefaultMaxTimeout.
+func WithMaxTimeout(maxTimeout time.Duration) ClientOpt {
+	return func(client *Client) {
+		client.maxTimeout = maxTimeout
+	}
+}
+
+// NewClient creates a new Client over the provided ditto.Client, configured with the provided ClientOpts.
+func NewClient(client ditto.Client, opts ...ClientOpt) *Client {
+	thingsClient := &Client{
+		client:     client,
+		maxTimeout: protocol.DefaultMaxTimeout,
+		pending:    make(map[string]chan *protocol.Envelope),
+		watchers:   make(map[int]*changeWatcher),
+	}
+	for _, opt := range opts {
+		opt(thingsClient)
+	}
+	thingsClient.subscription = client.Subscribe(thingsClient.handleMessage)
+	return thingsClient
+}
+
+// Close unsubscribes the Client from the underlying ditto.Client.
+func (client *Client) Close() {
+	client.client.UnsubscribeIDs(client.subscription...)
+}
+
+// CreateThing creates the provided Thing and returns it as created by Ditto.
+func (client *Client) CreateThing(ctx context.Context, thing *model.Thing) (*model.Thing, error) {
+	if thing.ID == nil {
+		return nil, errors.New("thing ID is not set")
+	}
+	resp, err := client.Execute(ctx, NewCommand(thing.ID).Create(thing))
+	if err != nil {
+		return nil, err
+	}
+	created := &model.Thing{}
+	if err := resp.ValueAs(created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// RetrieveThing retrieves the Thing with the provided ID.
+func (client *Client) RetrieveThing(ctx context.Context, thingID *model.NamespacedID) (*model.Thing, error) {
+	thing := &model.Thing{}
+	if err := client.retrieve(ctx, NewCommand(thingID), thing); err != nil {
+		return nil, err
+	}
+	return thing, nil
+}
+
+// DeleteThing deletes the Thing with the provided ID.
+func (client *Client) DeleteThing(ctx context.Context, thingID *model.NamespacedID) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).Delete())
+	return err
+}
+
+// MergeThing merges the Thing with the provided ID with the provided JSON merge patch, e.g. one created by model.NewMergePatch.
+func (client *Client) MergeThing(ctx context.Context, thingID *model.NamespacedID, patch interface{}) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).Merge(patch), protocol.WithContentType(protocol.ContentTypeMergePatch))
+	return err
+}
+
+// ModifyAttribute modifies (or creates) the attribute of the Thing with the provided ID.
+func (client *Client) ModifyAttribute(ctx context.Context, thingID *model.NamespacedID, attributePath string, value interface{}) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).Attribute(attributePath).Modify(value))
+	return err
+}
+
+// RetrieveAttribute retrieves the attribute of the Thing with the provided ID and decodes it into the provided target.
+func (client *Client) RetrieveAttribute(ctx context.Context, thingID *model.NamespacedID, attributePath string, target interface{}) error {
+	return client.retrieve(ctx, NewCommand(thingID).Attribute(attributePath), target)
+}
+
+// ModifyFeature modifies (or creates) the Feature of the Thing with the provided ID.
+func (client *Client) ModifyFeature(ctx context.Context, thingID *model.NamespacedID, featureID string, feature *model.Feature) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).Feature(featureID).Modify(feature))
+	return err
+}
+
+// RetrieveFeature retrieves the Feature of the Thing with the provided ID.
+func (client *Client) RetrieveFeature(ctx context.Context, thingID *model.NamespacedID, featureID string) (*model.Feature, error) {
+	feature := &model.Feature{}
+	if err := client.retrieve(ctx, NewCommand(thingID).Feature(featureID), feature); err != nil {
+		return nil, err
+	}
+	return feature, nil
+}
+
+// DeleteFeature deletes the Feature of the Thing with the provided ID.
+func (client *Client) DeleteFeature(ctx context.Context, thingID *model.NamespacedID, featureID string) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).Feature(featureID).Delete())
+	return err
+}
+
+// ModifyFeatureProperty modifies (or creates) the property of the Feature of the Thing with the provided ID.
+func (client *Client) ModifyFeatureProperty(ctx context.Context, thingID *model.NamespacedID, featureID, propertyPath string, value interface{}) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).FeatureProperty(featureID, propertyPath).Modify(value))
+	return err
+}
+
+// RetrieveFeatureProperty retrieves the property of the Feature of the Thing with the provided ID
+// and decodes it into the provided target.
+func (client *Client) RetrieveFeatureProperty(ctx context.Context, thingID *model.NamespacedID, featureID, propertyPath string, target interface{}) error {
+	return client.retrieve(ctx, NewCommand(thingID).FeatureProperty(featureID, propertyPath), target)
+}
+
+// DeleteFeatureProperty deletes the property of the Feature of the Thing with the provided ID.
+func (client *Client) DeleteFeatureProperty(ctx context.Context, thingID *model.NamespacedID, featureID, propertyPath string) error {
+	_, err := client.Execute(ctx, NewCommand(thingID).FeatureProperty(featureID, propertyPath).Delete())
+	return err
+}
+
+// Execute sends the provided Command, along with the provided Headers, and waits for its response until the context is done.
+// The Command is sent with a generated correlation ID, unless one is provided, and requires a response.
+// A *protocol.ErrorResponse is returned if Ditto responds with an error.
+func (client *Client) Execute(ctx context.Context, cmd *Command, headerOpts ...protocol.HeaderOpt) (*protocol.Envelope, error) {
+	opts := append([]protocol.HeaderOpt{protocol.WithGeneratedCorrelationID(), protocol.WithResponseRequired(true)}, headerOpts...)
+	if err := cmd.validate(client.maxTimeout, opts...); err != nil {
+		return nil, err
+	}
+	resp, err := client.request(ctx, cmd.Envelope(opts...))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("unexpected response status %d", resp.Status)
+	}
+	return resp, nil
+}
+
+// SendMessage sends the provided live Message, e.g. a device-originated outbox one, and waits for the correlated
+// response Message routed back from the business application. The response's payload is decoded into the provided
+// target, if any, as per its content type. Unlike the Things operations, a non-2xx status of the response Message
+// is not an error, as it is the business application's reply. A Ditto error, e.g. no business application
+// responding in time, is returned as a *protocol.ErrorResponse.
+// The provided Headers are applied over a generated correlation ID and a required response.
+func (client *Client) SendMessage(ctx context.Context, msg *Message, target interface{}, headerOpts ...protocol.HeaderOpt) (*Message, error) {
+	opts := append([]protocol.HeaderOpt{protocol.WithGeneratedCorrelationID(), protocol.WithResponseRequired(true)}, headerOpts...)
+	resp, err := client.request(ctx, msg.Envelope(opts...))
+	if err != nil {
+		return nil, err
+	}
+	res, err := NewMessageFromEnvelope(resp)
+	if err != nil {
+		return nil, err
+	}
+	if target != nil {
+		if err := resp.ValueAs(target); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// request sends the provided Envelope and waits for the response correlated to it.
+// An error is returned if the Envelope's Headers are missing or invalid, or if a request with the same correlation ID is already pending.
+func (client *Client) request(ctx context.Context, msg *protocol.Envelope) (*protocol.Envelope, error) {
+	if msg.Headers == nil {
+		return nil, errors.New("invalid headers")
+	}
+	if err := msg.Headers.ValidateMaxTimeout(client.maxTimeout); err != nil {
+		return nil, err
+	}
+	correlationID := msg.Headers.CorrelationID()
+
+	responses := make(chan *protocol.Envelope, 1)
+	client.pendingLock.Lock()
+	if _, ok := client.pending[correlationID]; ok {
+		client.pendingLock.Unlock()
+		return nil, fmt.Errorf("a request with correlation ID '%s' is already pending", correlationID)
+	}
+	client.pending[correlationID] = responses
+	client.pendingLock.Unlock()
+	defer func() {
+		client.pendingLock.Lock()
+		delete(client.pending, correlationID)
+		client.pendingLock.Unlock()
+	}()
+
+	if err := client.client.Send(msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-responses:
+		if protocol.IsError(resp) {
+			errResp, err := protocol.ParseError(resp)
+			if err != nil {
+				return nil, err
+			}
+			return nil, errResp
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (client *Client) retrieve(ctx context.Context, cmd *Command, target interface{}) error {
+	resp, err := client.Execute(ctx, cmd.Retrieve())
+	if err != nil {
+		return err
+	}
+	return resp.ValueAs(target)
+}
+
+func (client *Client) handleMessage(requestID string, msg *protocol.Envelope) {
+	if msg.Status == 0 {
+		client.notifyWatchers(msg)
+		return
+	}
+	if msg.Headers == nil {
+		return
+	}
+	client.pendingLock.Lock()
+	responses, ok := client.pending[msg.Headers.CorrelationID()]
+	client.pendingLock.Unlock()
+	if ok {
+		select {
+		case responses <- msg:
+		default:
+		}
+	}
+}
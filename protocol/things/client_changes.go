@@ -0,0 +1,231 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// ChangeHandler is notified on each change of a watched Thing's entity with its previous and its current value,
+// decoded as generic JSON values. The previous value is the last notified one, thus it's nil for the first change
+// after the watching has started. A nil current value means that the entity has been deleted.
+type ChangeHandler func(oldValue, newValue interface{})
+
+type changeWatcher struct {
+	thingID string
+	tokens  []string
+	handler ChangeHandler
+
+	lock  sync.Mutex
+	value interface{}
+}
+
+// OnAttributeChanged watches the Twin events of the Thing with the provided ID for changes of its attribute,
+// defined as for Command.Attribute, and notifies the provided ChangeHandler on each change.
+// Returns a function to stop the watching.
+func (client *Client) OnAttributeChanged(thingID *model.NamespacedID, attributePath string, handler ChangeHandler) func() {
+	return client.onChange(thingID, NewCommand(thingID).Attribute(attributePath).Path, handler)
+}
+
+// OnFeatureChanged watches the Twin events of the Thing with the provided ID for changes of its feature
+// and notifies the provided ChangeHandler on each change.
+// Returns a function to stop the watching.
+func (client *Client) OnFeatureChanged(thingID *model.NamespacedID, featureID string, handler ChangeHandler) func() {
+	return client.onChange(thingID, NewCommand(thingID).Feature(featureID).Path, handler)
+}
+
+// OnFeaturePropertyChanged watches the Twin events of the Thing with the provided ID for changes of its feature's property,
+// defined as for Command.FeatureProperty, and notifies the provided ChangeHandler on each change.
+// The changes are detected regardless of the level of the event, e.g. a modification of the whole feature
+// or a merge of the Thing, and the handler is notified only if the property's value has actually changed.
+// Returns a function to stop the watching.
+func (client *Client) OnFeaturePropertyChanged(thingID *model.NamespacedID, featureID, propertyPath string, handler ChangeHandler) func() {
+	return client.onChange(thingID, NewCommand(thingID).FeatureProperty(featureID, propertyPath).Path, handler)
+}
+
+func (client *Client) onChange(thingID *model.NamespacedID, path string, handler ChangeHandler) func() {
+	watcher := &changeWatcher{
+		thingID: thingID.String(),
+		tokens:  pathTokens(path),
+		handler: handler,
+	}
+
+	client.watchersLock.Lock()
+	client.lastWatcherID++
+	id := client.lastWatcherID
+	client.watchers[id] = watcher
+	client.watchersLock.Unlock()
+
+	return func() {
+		client.watchersLock.Lock()
+		delete(client.watchers, id)
+		client.watchersLock.Unlock()
+	}
+}
+
+func (client *Client) notifyWatchers(msg *protocol.Envelope) {
+	topic := msg.Topic
+	if topic == nil || topic.Group != protocol.GroupThings || topic.Channel != protocol.ChannelTwin ||
+		topic.Criterion != protocol.CriterionEvents {
+		return
+	}
+	switch topic.Action {
+	case protocol.ActionCreated, protocol.ActionModified, protocol.ActionMerged, protocol.ActionDeleted:
+	default:
+		return
+	}
+
+	thingID := model.NewNamespacedID(topic.Namespace, topic.EntityName).String()
+	client.watchersLock.Lock()
+	var watchers []*changeWatcher
+	for _, watcher := range client.watchers {
+		if watcher.thingID == thingID {
+			watchers = append(watchers, watcher)
+		}
+	}
+	client.watchersLock.Unlock()
+	if len(watchers) == 0 {
+		return
+	}
+
+	var value interface{}
+	if msg.Value != nil {
+		if err := msg.ValueAs(&value); err != nil {
+			return
+		}
+	}
+	tokens := pathTokens(msg.Path)
+	for _, watcher := range watchers {
+		watcher.apply(topic.Action, tokens, value)
+	}
+}
+
+// apply applies the change of the entity at the event's path to the watched entity value and notifies the handler if it has changed.
+func (watcher *changeWatcher) apply(action protocol.TopicAction, tokens []string, value interface{}) {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+
+	var newValue interface{}
+	switch {
+	case hasTokensPrefix(watcher.tokens, tokens):
+		nested := watcher.tokens[len(tokens):]
+		switch action {
+		case protocol.ActionDeleted:
+		case protocol.ActionMerged:
+			patch, ok := lookupTokens(value, nested)
+			if !ok {
+				return
+			}
+			newValue = mergeValue(watcher.value, patch)
+		default:
+			newValue, _ = lookupTokens(value, nested)
+		}
+	case hasTokensPrefix(tokens, watcher.tokens):
+		nested := tokens[len(watcher.tokens):]
+		newValue = updateValue(watcher.value, nested, func(current interface{}) interface{} {
+			switch action {
+			case protocol.ActionDeleted:
+				return nil
+			case protocol.ActionMerged:
+				return mergeValue(current, value)
+			default:
+				return value
+			}
+		})
+	default:
+		return
+	}
+
+	if reflect.DeepEqual(watcher.value, newValue) {
+		return
+	}
+	oldValue := watcher.value
+	watcher.value = newValue
+	watcher.handler(oldValue, newValue)
+}
+
+func pathTokens(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if len(trimmed) == 0 {
+		return nil
+	}
+	tokens := strings.Split(trimmed, "/")
+	for i, token := range tokens {
+		tokens[i] = protocol.UnescapePointerToken(token)
+	}
+	return tokens
+}
+
+func hasTokensPrefix(tokens, prefix []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i, token := range prefix {
+		if tokens[i] != token {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupTokens provides the value referenced by the provided tokens. A nil value on the way is considered
+// as a reference to a nil value, as it's either a deletion in a merge patch or a missing value.
+func lookupTokens(value interface{}, tokens []string) (interface{}, bool) {
+	for _, token := range tokens {
+		if value == nil {
+			return nil, true
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[token]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// updateValue provides a copy of the value with the nested value referenced by the provided tokens updated,
+// the objects on the way are copied, so that the original value is not modified.
+func updateValue(value interface{}, tokens []string, update func(current interface{}) interface{}) interface{} {
+	if len(tokens) == 0 {
+		return update(value)
+	}
+	object, _ := value.(map[string]interface{})
+	updated := make(map[string]interface{}, len(object)+1)
+	for key, item := range object {
+		updated[key] = item
+	}
+	if nested := updateValue(object[tokens[0]], tokens[1:], update); nested != nil {
+		updated[tokens[0]] = nested
+	} else {
+		delete(updated, tokens[0])
+	}
+	return updated
+}
+
+// mergeValue provides a copy of the value with the provided JSON merge patch (https://tools.ietf.org/html/rfc7396) applied
+// as per model.MergeMaps.
+func mergeValue(value interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, _ := value.(map[string]interface{})
+	return model.MergeMaps(object, patchObject)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+type testChange struct {
+	oldValue interface{}
+	newValue interface{}
+}
+
+func TestClientOnFeaturePropertyChanged(t *testing.T) {
+	dittoClient := &testDittoClient{}
+	client := NewClient(dittoClient)
+
+	var changes []testChange
+	stop := client.OnFeaturePropertyChanged(testNamespaceID, "lamp", "config/color", func(oldValue, newValue interface{}) {
+		changes = append(changes, testChange{oldValue, newValue})
+	})
+
+	event := func() *Event {
+		return NewEvent(testNamespaceID)
+	}
+	envelopes := []*protocol.Envelope{
+		// property modified
+		event().FeatureProperty("lamp", "config/color").Modified("red").Envelope(),
+		// same value, no change
+		event().FeatureProperty("lamp", "config/color").Modified("red").Envelope(),
+		// other property, no change
+		event().FeatureProperty("lamp", "on").Modified(true).Envelope(),
+		// whole feature modified
+		event().Feature("lamp").Modified(map[string]interface{}{
+			"properties": map[string]interface{}{"config": map[string]interface{}{"color": "green"}},
+		}).Envelope(),
+		// merged without the property, no change
+		event().Merged(map[string]interface{}{"attributes": map[string]interface{}{"a": 1}}).Envelope(),
+		// merged with the property
+		event().Features().Merged(map[string]interface{}{
+			"lamp": map[string]interface{}{"properties": map[string]interface{}{"config": map[string]interface{}{"color": "blue"}}},
+		}).Envelope(),
+		// other thing, no change
+		NewEvent(model.NewNamespacedID("testNamespace", "other")).FeatureProperty("lamp", "config/color").Modified("black").Envelope(),
+		// live channel, no change
+		event().Live().FeatureProperty("lamp", "config/color").Modified("black").Envelope(),
+		// property deleted by a merge patch
+		event().Merged(map[string]interface{}{"features": map[string]interface{}{"lamp": nil}}).Envelope(),
+		// nested change within the property
+		event().FeatureProperty("lamp", "config/color/r").Modified(255).Envelope(),
+		// property deleted
+		event().FeatureProperties("lamp").Deleted().Envelope(),
+	}
+	for _, env := range envelopes {
+		client.handleMessage("", env)
+	}
+
+	internal.AssertEqual(t, []testChange{
+		{nil, "red"},
+		{"red", "green"},
+		{"green", "blue"},
+		{"blue", nil},
+		{nil, map[string]interface{}{"r": float64(255)}},
+		{map[string]interface{}{"r": float64(255)}, nil},
+	}, changes)
+
+	stop()
+	client.handleMessage("", event().FeatureProperty("lamp", "config/color").Modified("red").Envelope())
+	internal.AssertEqual(t, 6, len(changes))
+}
+
+func TestClientOnFeatureChanged(t *testing.T) {
+	client := NewClient(&testDittoClient{})
+
+	var changes []testChange
+	client.OnFeatureChanged(testNamespaceID, "lamp", func(oldValue, newValue interface{}) {
+		changes = append(changes, testChange{oldValue, newValue})
+	})
+
+	client.handleMessage("", NewEvent(testNamespaceID).Feature("lamp").Modified(map[string]interface{}{
+		"properties": map[string]interface{}{"on": false},
+	}).Envelope())
+	client.handleMessage("", NewEvent(testNamespaceID).FeatureProperty("lamp", "on").Modified(true).Envelope())
+	client.handleMessage("", NewEvent(testNamespaceID).FeatureProperty("lamp", "on").Merged(nil).Envelope())
+
+	internal.AssertEqual(t, []testChange{
+		{nil, map[string]interface{}{"properties": map[string]interface{}{"on": false}}},
+		{map[string]interface{}{"properties": map[string]interface{}{"on": false}}, map[string]interface{}{"properties": map[string]interface{}{"on": true}}},
+		{map[string]interface{}{"properties": map[string]interface{}{"on": true}}, map[string]interface{}{"properties": map[string]interface{}{}}},
+	}, changes)
+}
+
+func TestClientOnAttributeChanged(t *testing.T) {
+	client := NewClient(&testDittoClient{})
+
+	var changes []testChange
+	client.OnAttributeChanged(testNamespaceID, "a/b", func(oldValue, newValue interface{}) {
+		changes = append(changes, testChange{oldValue, newValue})
+	})
+
+	client.handleMessage("", NewEvent(testNamespaceID).Attribute("a/b").Modified("x").Envelope())
+	client.handleMessage("", NewEvent(testNamespaceID).Attributes().Modified(map[string]interface{}{"a": "y"}).Envelope())
+	client.handleMessage("", NewEvent(testNamespaceID).Modified(map[string]interface{}{
+		"thingId": testNamespaceID.String(), "attributes": map[string]interface{}{"a": map[string]interface{}{"b": "z"}},
+	}).Envelope())
+	client.handleMessage("", NewEvent(testNamespaceID).Deleted().Envelope())
+
+	internal.AssertEqual(t, []testChange{{nil, "x"}, {"x", nil}, {nil, "z"}, {"z", nil}}, changes)
+}
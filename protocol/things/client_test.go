@@ -0,0 +1,392 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// testDittoClient is a ditto.Client that responds to each sent Envelope using its respond function.
+type testDittoClient struct {
+	handler ditto.Handler
+	sent    []*protocol.Envelope
+	sendErr error
+	respond func(request *protocol.Envelope) *protocol.Envelope
+}
+
+func (c *testDittoClient) Connect() error { return nil }
+
+func (c *testDittoClient) Disconnect() {}
+
+func (c *testDittoClient) Reply(requestID string, message *protocol.Envelope) error { return nil }
+func (c *testDittoClient) ReplyTo(requestID string, request, message *protocol.Envelope) error {
+	return nil
+}
+
+func (c *testDittoClient) Send(message *protocol.Envelope) error {
+	if c.sendErr != nil {
+		return c.sendErr
+	}
+	c.sent = append(c.sent, message)
+	if c.respond != nil {
+		if resp := c.respond(message); resp != nil {
+			go c.handler("", resp)
+		}
+	}
+	return nil
+}
+
+func (c *testDittoClient) SendFrozen(message *protocol.FrozenEnvelope) error {
+	env, err := message.Thaw(protocol.NewCorrelationID(), time.Now())
+	if err != nil {
+		return err
+	}
+	return c.Send(env)
+}
+
+func (c *testDittoClient) SendBatch(messages []*protocol.Envelope) error {
+	for _, message := range messages {
+		if err := c.Send(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *testDittoClient) Subscribe(handlers ...ditto.Handler) []ditto.HandlerID {
+	c.handler = handlers[0]
+	return []ditto.HandlerID{1}
+}
+
+func (c *testDittoClient) SubscribeOnce(handlers ...ditto.Handler) []ditto.HandlerID {
+	return c.Subscribe(handlers...)
+}
+
+func (c *testDittoClient) Unsubscribe(handlers ...ditto.Handler) {
+	c.handler = nil
+}
+
+func (c *testDittoClient) UnsubscribeIDs(ids ...ditto.HandlerID) {
+	c.handler = nil
+}
+
+func (c *testDittoClient) Handlers() []ditto.HandlerInfo {
+	return nil
+}
+
+func (c *testDittoClient) WaitForIdle(timeout time.Duration) error {
+	return nil
+}
+
+func (c *testDittoClient) Stats() ditto.Stats {
+	return ditto.Stats{}
+}
+
+func TestClientOperations(t *testing.T) {
+	thing := (&model.Thing{}).WithIDFrom("testNamespace:testName").
+		WithFeature("lamp", (&model.Feature{}).WithProperty("on", true))
+
+	tests := map[string]struct {
+		op         func(client *Client) (interface{}, error)
+		respond    func(request *protocol.Envelope) *protocol.Envelope
+		wantPath   string
+		wantAction protocol.TopicAction
+		want       interface{}
+		wantErr    bool
+	}{
+		"test_create_thing": {
+			op: func(client *Client) (interface{}, error) {
+				return client.CreateThing(context.Background(), thing)
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return NewResponseTo(request).Created(request.Value).Envelope()
+			},
+			wantPath:   "/",
+			wantAction: protocol.ActionCreate,
+			want:       thing,
+		},
+		"test_retrieve_thing": {
+			op: func(client *Client) (interface{}, error) {
+				return client.RetrieveThing(context.Background(), thing.ID)
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return NewResponseTo(request).Retrieved(map[string]interface{}{
+					"thingId":  "testNamespace:testName",
+					"features": map[string]interface{}{"lamp": map[string]interface{}{"properties": map[string]interface{}{"on": true}}},
+				}).Envelope()
+			},
+			wantPath:   "/",
+			wantAction: protocol.ActionRetrieve,
+			want:       thing,
+		},
+		"test_retrieve_feature_property": {
+			op: func(client *Client) (interface{}, error) {
+				var on bool
+				err := client.RetrieveFeatureProperty(context.Background(), thing.ID, "lamp", "on", &on)
+				return on, err
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return NewResponseTo(request).Retrieved(true).Envelope()
+			},
+			wantPath:   "/features/lamp/properties/on",
+			wantAction: protocol.ActionRetrieve,
+			want:       true,
+		},
+		"test_modify_feature_property": {
+			op: func(client *Client) (interface{}, error) {
+				return nil, client.ModifyFeatureProperty(context.Background(), thing.ID, "lamp", "on", false)
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return NewResponseTo(request).Modified().Envelope()
+			},
+			wantPath:   "/features/lamp/properties/on",
+			wantAction: protocol.ActionModify,
+		},
+		"test_merge_thing": {
+			op: func(client *Client) (interface{}, error) {
+				return nil, client.MergeThing(context.Background(), thing.ID, map[string]interface{}{"attributes": nil})
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return NewResponseTo(request).Merged().Envelope()
+			},
+			wantPath:   "/",
+			wantAction: protocol.ActionMerge,
+		},
+		"test_delete_feature_error": {
+			op: func(client *Client) (interface{}, error) {
+				return nil, client.DeleteFeature(context.Background(), thing.ID, "lamp")
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return protocol.NewErrorEnvelope(request, 404, protocol.ErrorCodeFeatureNotFound, "not found")
+			},
+			wantPath:   "/features/lamp",
+			wantAction: protocol.ActionDelete,
+			wantErr:    true,
+		},
+		"test_unexpected_status": {
+			op: func(client *Client) (interface{}, error) {
+				return nil, client.DeleteThing(context.Background(), thing.ID)
+			},
+			respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return protocol.NewResponseEnvelope(request, 304, nil)
+			},
+			wantPath:   "/",
+			wantAction: protocol.ActionDelete,
+			wantErr:    true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			dittoClient := &testDittoClient{respond: testCase.respond}
+			client := NewClient(dittoClient)
+			defer client.Close()
+
+			got, err := testCase.op(client)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				if testCase.want != nil {
+					internal.AssertEqual(t, testCase.want, got)
+				}
+			}
+
+			internal.AssertEqual(t, 1, len(dittoClient.sent))
+			request := dittoClient.sent[0]
+			internal.AssertEqual(t, testCase.wantPath, request.Path)
+			internal.AssertEqual(t, testCase.wantAction, request.Topic.Action)
+			internal.AssertTrue(t, request.Headers.IsResponseRequired())
+			internal.AssertTrue(t, len(request.Headers.CorrelationID()) > 0)
+		})
+	}
+}
+
+func TestClientErrorResponse(t *testing.T) {
+	dittoClient := &testDittoClient{respond: func(request *protocol.Envelope) *protocol.Envelope {
+		return protocol.NewErrorEnvelope(request, 404, protocol.ErrorCodeThingNotFound, "not found")
+	}}
+	client := NewClient(dittoClient)
+
+	_, err := client.RetrieveThing(context.Background(), testNamespaceID)
+	errResp, ok := err.(*protocol.ErrorResponse)
+	internal.AssertTrue(t, ok)
+	internal.AssertEqual(t, protocol.ErrorCodeThingNotFound, errResp.ErrorCode)
+	internal.AssertEqual(t, 404, errResp.Status)
+}
+
+func TestClientSendMessage(t *testing.T) {
+	tests := map[string]struct {
+		status int
+		want   map[string]interface{}
+	}{
+		"test_success_response": {
+			status: 200,
+			want:   map[string]interface{}{"acknowledged": true},
+		},
+		"test_failure_response": {
+			status: 400,
+			want:   map[string]interface{}{"acknowledged": false},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			dittoClient := &testDittoClient{respond: func(request *protocol.Envelope) *protocol.Envelope {
+				res, err := ResponseTo(request, testCase.status)
+				internal.AssertNil(t, err)
+				return res.WithPayload(testCase.want).Envelope()
+			}}
+			client := NewClient(dittoClient)
+
+			msg := NewMessage(testNamespaceID).Feature("lamp").Outbox("alert").WithPayload("overheated")
+			var got map[string]interface{}
+			res, err := client.SendMessage(context.Background(), msg, &got)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.status, res.Status)
+			internal.AssertEqual(t, "alert", res.Subject)
+			internal.AssertEqual(t, inbox, res.Mailbox)
+			internal.AssertEqual(t, "lamp", res.FeatureID())
+			internal.AssertEqual(t, testCase.want, got)
+
+			sent := dittoClient.sent[0]
+			internal.AssertEqual(t, "/features/lamp/outbox/messages/alert", sent.Path)
+			internal.AssertTrue(t, sent.Headers.IsResponseRequired())
+			internal.AssertTrue(t, len(sent.Headers.CorrelationID()) > 0)
+		})
+	}
+}
+
+func TestClientSendMessageErrorResponse(t *testing.T) {
+	dittoClient := &testDittoClient{respond: func(request *protocol.Envelope) *protocol.Envelope {
+		return protocol.NewErrorEnvelope(request, 408, "messages:timeout", "timeout")
+	}}
+	client := NewClient(dittoClient)
+
+	_, err := client.SendMessage(context.Background(), NewMessage(testNamespaceID).Outbox("alert"), nil)
+	errResp, ok := err.(*protocol.ErrorResponse)
+	internal.AssertTrue(t, ok)
+	internal.AssertEqual(t, 408, errResp.Status)
+}
+
+func TestClientSendMessageInvalidHeaders(t *testing.T) {
+	tests := map[string]struct {
+		opt protocol.HeaderOpt
+	}{
+		"test_failing_header_opt": {
+			opt: func(headers *protocol.Headers) error { return errors.New("header error") },
+		},
+		"test_invalid_header_value": {
+			opt: protocol.WithTimeoutDuration(2 * time.Minute),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			dittoClient := &testDittoClient{}
+			client := NewClient(dittoClient)
+
+			_, err := client.SendMessage(context.Background(), NewMessage(testNamespaceID).Outbox("alert"), nil, testCase.opt)
+			internal.AssertNotNil(t, err)
+			internal.AssertEqual(t, 0, len(dittoClient.sent))
+			internal.AssertEqual(t, 0, len(client.pending))
+		})
+	}
+}
+
+func TestClientMaxTimeout(t *testing.T) {
+	tests := map[string]struct {
+		opts    []ClientOpt
+		wantErr bool
+	}{
+		"test_default_max_timeout": {
+			wantErr: true,
+		},
+		"test_increased_max_timeout": {
+			opts: []ClientOpt{WithMaxTimeout(5 * time.Minute)},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			dittoClient := &testDittoClient{respond: func(request *protocol.Envelope) *protocol.Envelope {
+				return protocol.NewResponseEnvelope(request, 204, nil)
+			}}
+			client := NewClient(dittoClient, testCase.opts...)
+
+			_, err := client.Execute(context.Background(), NewCommand(testNamespaceID).Delete(), protocol.WithTimeoutDuration(2*time.Minute))
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				internal.AssertEqual(t, 0, len(dittoClient.sent))
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, 1, len(dittoClient.sent))
+			}
+		})
+	}
+}
+
+func TestClientPendingCorrelationID(t *testing.T) {
+	dittoClient := &testDittoClient{}
+	client := NewClient(dittoClient)
+	pending := make(chan *protocol.Envelope, 1)
+	client.pending["pending-id"] = pending
+
+	_, err := client.Execute(context.Background(), NewCommand(testNamespaceID).Delete(), protocol.WithCorrelationID("pending-id"))
+	internal.AssertNotNil(t, err)
+	internal.AssertEqual(t, 0, len(dittoClient.sent))
+	internal.AssertEqual(t, pending, client.pending["pending-id"])
+}
+
+func TestClientNoResponse(t *testing.T) {
+	client := NewClient(&testDittoClient{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.DeleteThing(ctx, testNamespaceID)
+	internal.AssertEqual(t, context.DeadlineExceeded, err)
+	internal.AssertEqual(t, 0, len(client.pending))
+}
+
+func TestClientSendError(t *testing.T) {
+	sendErr := errors.New("send error")
+	client := NewClient(&testDittoClient{sendErr: sendErr})
+
+	internal.AssertEqual(t, sendErr, client.DeleteThing(context.Background(), testNamespaceID))
+}
+
+func TestClientInvalidCommand(t *testing.T) {
+	dittoClient := &testDittoClient{}
+	client := NewClient(dittoClient)
+
+	internal.AssertNotNil(t, client.DeleteFeature(context.Background(), testNamespaceID, ""))
+	_, err := client.CreateThing(context.Background(), &model.Thing{})
+	internal.AssertNotNil(t, err)
+	internal.AssertEqual(t, 0, len(dittoClient.sent))
+}
+
+func TestClientClose(t *testing.T) {
+	dittoClient := &testDittoClient{}
+	client := NewClient(dittoClient)
+	internal.AssertNotNil(t, dittoClient.handler)
+
+	client.Close()
+	internal.AssertNil(t, dittoClient.handler)
+}
@@ -12,7 +12,10 @@
 package things
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/model"
 	"github.com/eclipse/ditto-clients-golang/protocol"
@@ -48,6 +51,7 @@ type Command struct {
 	Topic   *protocol.Topic
 	Path    string
 	Payload interface{}
+	Fields  *protocol.FieldSelector
 }
 
 // NewCommand creates a new Command instance for the defined by the provided NamespacedID Thing.
@@ -70,6 +74,14 @@ func (cmd *Command) Create(thing *model.Thing) *Command {
 	return cmd
 }
 
+// CreateWithPolicy creates a new Thing entity based on the provided information along with the provided Policy,
+// so that both are created atomically. The provided Thing is not changed, the Policy is set inline to a copy of it.
+func (cmd *Command) CreateWithPolicy(thing *model.Thing, policy *model.Policy) *Command {
+	payload := *thing
+	payload.Policy = policy
+	return cmd.Create(&payload)
+}
+
 // Modify sets the action of the command instance accordingly.
 // The provided payload must be the new value to be used for modification
 // compliant with the (part of) the Thing it is to be applied to.
@@ -144,6 +156,13 @@ func (cmd *Command) Attribute(attributePath string) *Command {
 	return cmd
 }
 
+// AttributeKey configures the command to affect a specified top-level attribute of the Thing,
+// defined by the attributeKey, which is escaped as a single JSON pointer key, e.g. 'a/b' refers to the attribute 'a/b'.
+func (cmd *Command) AttributeKey(attributeKey string) *Command {
+	cmd.Path = fmt.Sprintf(pathThingAttributeFormat, protocol.EscapePointerToken(attributeKey))
+	return cmd
+}
+
 // Features configures the command to affect all the features of the Thing.
 func (cmd *Command) Features() *Command {
 	cmd.Path = pathThingFeatures
@@ -152,40 +171,73 @@ func (cmd *Command) Features() *Command {
 
 // Feature configures the command to affect a specified by the provided featureID feature of the Thing.
 func (cmd *Command) Feature(featureID string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeatureFormat, featureID)
+	cmd.Path = fmt.Sprintf(pathThingFeatureFormat, protocol.EscapePointerToken(featureID))
 	return cmd
 }
 
 // FeatureDefinition configures the command to affect the definition of a specified by the provided featureID feature of the Thing.
 func (cmd *Command) FeatureDefinition(featureID string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeatureDefinitionFormat, featureID)
+	cmd.Path = fmt.Sprintf(pathThingFeatureDefinitionFormat, protocol.EscapePointerToken(featureID))
 	return cmd
 }
 
 // FeatureProperties configures the command to affect all properties of a specified by the provided featureID feature of the Thing.
 func (cmd *Command) FeatureProperties(featureID string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeaturePropertiesFormat, featureID)
+	cmd.Path = fmt.Sprintf(pathThingFeaturePropertiesFormat, protocol.EscapePointerToken(featureID))
 	return cmd
 }
 
 // FeatureProperty configures the command to affect a specified property via the provided featureID feature
 // of the Thing and the propertyPath as JSON pointer path (https://tools.ietf.org/html/rfc6901).
 func (cmd *Command) FeatureProperty(featureID, propertyPath string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, featureID, propertyPath)
+	cmd.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, protocol.EscapePointerToken(featureID), propertyPath)
+	return cmd
+}
+
+// FeaturePropertyKey configures the command to affect a specified top-level property via the provided featureID feature
+// of the Thing and the propertyKey, which is escaped as a single JSON pointer key.
+func (cmd *Command) FeaturePropertyKey(featureID, propertyKey string) *Command {
+	cmd.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, protocol.EscapePointerToken(featureID), protocol.EscapePointerToken(propertyKey))
 	return cmd
 }
 
 // FeatureDesiredProperties configures the command to affect all desired properties of a specified
 // by the provided featureID feature of the Thing.
 func (cmd *Command) FeatureDesiredProperties(featureID string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, featureID)
+	cmd.Path = fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, protocol.EscapePointerToken(featureID))
 	return cmd
 }
 
 // FeatureDesiredProperty configures the command to affect a specified desired property via the provided featureID feature
 // of the Thing and the propertyPath as JSON pointer path (https://tools.ietf.org/html/rfc6901).
 func (cmd *Command) FeatureDesiredProperty(featureID, propertyPath string) *Command {
-	cmd.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, featureID, propertyPath)
+	cmd.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, protocol.EscapePointerToken(featureID), propertyPath)
+	return cmd
+}
+
+// FeatureDesiredPropertyKey configures the command to affect a specified top-level desired property via the provided
+// featureID feature of the Thing and the propertyKey, which is escaped as a single JSON pointer key.
+func (cmd *Command) FeatureDesiredPropertyKey(featureID, propertyKey string) *Command {
+	cmd.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, protocol.EscapePointerToken(featureID), protocol.EscapePointerToken(propertyKey))
+	return cmd
+}
+
+// WithFields configures the fields to be selected by a Retrieve command, so that only a part of the entity is retrieved.
+func (cmd *Command) WithFields(fields *protocol.FieldSelector) *Command {
+	cmd.Fields = fields
+	return cmd
+}
+
+// WithExtraFields adds the fields referenced by the provided JSON pointers to the fields to be selected by a Retrieve command,
+// e.g. the Thing's special fields like '_revision', '_modified' or '_metadata' which are retrieved only if explicitly selected.
+// Note: If any fields are selected, only they are retrieved, i.e. the regular fields must be selected as well if needed.
+func (cmd *Command) WithExtraFields(pointers ...string) *Command {
+	if cmd.Fields == nil {
+		cmd.Fields = protocol.Fields()
+	}
+	for _, pointer := range pointers {
+		cmd.Fields.Field(pointer)
+	}
 	return cmd
 }
 
@@ -208,8 +260,102 @@ func (cmd *Command) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelop
 		Path:  cmd.Path,
 		Value: cmd.Payload,
 	}
+	if cmd.Fields != nil {
+		msg.Fields = cmd.Fields.String()
+	}
 	if headerOpts != nil {
 		msg.Headers = protocol.NewHeaders(headerOpts...)
 	}
 	return msg
 }
+
+// Validate checks the command configurations, along with the Headers to be applied to its Envelope, for combinations
+// that would be rejected by Ditto, e.g. a Merge without the merge patch content type, a Delete with payload,
+// a Retrieve with payload on the Live channel, fields for a non-Retrieve or an empty feature ID, attribute or property path.
+// It's meant to be used before generating the command's Envelope.
+func (cmd *Command) Validate(headerOpts ...protocol.HeaderOpt) error {
+	return cmd.validate(protocol.DefaultMaxTimeout, headerOpts...)
+}
+
+// validate checks the command the same way as Validate does, with the 'timeout' header value not exceeding the provided maximum.
+func (cmd *Command) validate(maxTimeout time.Duration, headerOpts ...protocol.HeaderOpt) error {
+	if cmd.Topic == nil || len(cmd.Topic.Action) == 0 {
+		return errors.New("command action is not configured")
+	}
+
+	segments := strings.Split(cmd.Path, "/")
+	if len(segments) > 2 && segments[1] == "features" && len(segments[2]) == 0 {
+		return errors.New("command feature ID is empty")
+	}
+	if cmd.Path != pathThing {
+		for _, segment := range segments[1:] {
+			if len(segment) == 0 {
+				return fmt.Errorf("command path '%s' has an empty segment", cmd.Path)
+			}
+		}
+	}
+
+	headers := protocol.NewHeaders(headerOpts...)
+	if headers == nil {
+		return errors.New("command headers cannot be applied")
+	}
+	if err := headers.ValidateMaxTimeout(maxTimeout); err != nil {
+		return err
+	}
+
+	switch cmd.Topic.Action {
+	case protocol.ActionCreate:
+		if thing, ok := cmd.Payload.(*model.Thing); ok && thing != nil && thing.Policy != nil {
+			if err := thing.Policy.Validate(); err != nil {
+				return fmt.Errorf("invalid inline policy: %v", err)
+			}
+		}
+	case protocol.ActionMerge:
+		if contentType := strings.TrimSpace(strings.Split(headers.ContentType(), ";")[0]); contentType != protocol.ContentTypeMergePatch {
+			return fmt.Errorf("merge command content type must be '%s'", protocol.ContentTypeMergePatch)
+		}
+	case protocol.ActionRetrieve:
+		if cmd.Topic.Channel == protocol.ChannelLive && cmd.Payload != nil {
+			return errors.New("live retrieve command must not have payload")
+		}
+	case protocol.ActionDelete:
+		if cmd.Payload != nil {
+			return errors.New("delete command must not have payload")
+		}
+	}
+	if cmd.Fields != nil && cmd.Topic.Action != protocol.ActionRetrieve {
+		return errors.New("fields are applicable to retrieve commands only")
+	}
+	return nil
+}
+
+// NewCommandFromEnvelope creates a new Command instance from the provided Envelope of a Thing's command,
+// so that it can be inspected or modified via the Command's methods, e.g. to change its channel.
+// Returns an error if the Envelope is not a Thing's command or its path doesn't refer to a Thing's entity.
+func NewCommandFromEnvelope(env *protocol.Envelope) (*Command, error) {
+	if env == nil || env.Topic == nil {
+		return nil, errors.New("envelope without topic is not a thing command")
+	}
+	if env.Topic.Group != protocol.GroupThings || env.Topic.Criterion != protocol.CriterionCommands {
+		return nil, fmt.Errorf("envelope with topic '%s' is not a thing command", env.Topic.String())
+	}
+	switch env.Topic.Action {
+	case protocol.ActionCreate, protocol.ActionModify, protocol.ActionMerge, protocol.ActionRetrieve, protocol.ActionDelete:
+	default:
+		return nil, fmt.Errorf("unsupported thing command action: %s", env.Topic.Action)
+	}
+	if _, err := ParseTarget(env.Path); err != nil {
+		return nil, err
+	}
+
+	topic := *env.Topic
+	cmd := &Command{
+		Topic:   &topic,
+		Path:    env.Path,
+		Payload: env.Value,
+	}
+	if len(env.Fields) > 0 {
+		cmd.Fields = protocol.FieldsFrom(env.Fields)
+	}
+	return cmd, nil
+}
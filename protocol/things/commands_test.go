@@ -12,6 +12,7 @@
 package things
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -63,6 +64,24 @@ func TestCreate(t *testing.T) {
 	internal.AssertEqual(t, want, got)
 }
 
+func TestCreateWithPolicy(t *testing.T) {
+	thing := (&model.Thing{}).WithID(testNamespaceID)
+	policy := model.NewPolicy().WithEntry("owner",
+		model.Subject("nginx:ditto", "generated"), model.Grant("thing:/", model.PermissionRead, model.PermissionWrite))
+
+	got := NewCommand(testNamespaceID).CreateWithPolicy(thing, policy)
+	internal.AssertEqual(t, protocol.ActionCreate, got.Topic.Action)
+	internal.AssertEqual(t, (&model.Thing{}).WithID(testNamespaceID).WithPolicy(policy), got.Payload)
+	internal.AssertNil(t, thing.Policy)
+
+	data, err := json.Marshal(got.Envelope())
+	internal.AssertNil(t, err)
+	want := `{"topic":"testNamespace/testName/things/twin/commands/create","path":"/","value":` +
+		`{"thingId":"testNamespace:testName","_policy":{"entries":{"owner":{"subjects":{"nginx:ditto":{"type":"generated"}},` +
+		`"resources":{"thing:/":{"grant":["READ","WRITE"],"revoke":[]}}}}}}}`
+	internal.AssertEqual(t, want, string(data))
+}
+
 func TestModify(t *testing.T) {
 	testCommand := &Command{
 		Topic: &protocol.Topic{},
@@ -374,3 +393,205 @@ func TestEnvelope(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandValidate(t *testing.T) {
+	mergePatch := protocol.WithContentType(protocol.ContentTypeMergePatch)
+
+	tests := map[string]struct {
+		cmd     *Command
+		opts    []protocol.HeaderOpt
+		wantErr bool
+	}{
+		"test_modify_valid": {
+			cmd: NewCommand(testNamespaceID).FeatureProperty(testFeatureID, "a/b").Modify(1),
+		},
+		"test_merge_valid": {
+			cmd:  NewCommand(testNamespaceID).Attributes().Merge(map[string]interface{}{"a": nil}),
+			opts: []protocol.HeaderOpt{mergePatch},
+		},
+		"test_merge_valid_with_parameters": {
+			cmd:  NewCommand(testNamespaceID).Merge(map[string]interface{}{}),
+			opts: []protocol.HeaderOpt{protocol.WithContentType(protocol.ContentTypeMergePatch + "; charset=utf-8")},
+		},
+		"test_create_with_policy_valid": {
+			cmd: NewCommand(testNamespaceID).CreateWithPolicy((&model.Thing{}).WithID(testNamespaceID),
+				model.NewPolicy().WithEntry("owner", model.Subject("nginx:ditto", "generated"))),
+		},
+		"test_create_with_invalid_policy": {
+			cmd: NewCommand(testNamespaceID).CreateWithPolicy((&model.Thing{}).WithID(testNamespaceID),
+				model.NewPolicy().WithEntry("owner", model.Subject("ditto", "generated"))),
+			wantErr: true,
+		},
+		"test_twin_retrieve_with_payload_valid": {
+			cmd: NewCommand(testNamespaceID).Retrieve(*testNamespaceID),
+		},
+		"test_no_action": {
+			cmd:     NewCommand(testNamespaceID),
+			wantErr: true,
+		},
+		"test_merge_without_content_type": {
+			cmd:     NewCommand(testNamespaceID).Attributes().Merge(map[string]interface{}{}),
+			wantErr: true,
+		},
+		"test_merge_json_content_type": {
+			cmd:     NewCommand(testNamespaceID).Attributes().Merge(map[string]interface{}{}),
+			opts:    []protocol.HeaderOpt{protocol.WithContentType(protocol.ContentTypeJSON)},
+			wantErr: true,
+		},
+		"test_live_retrieve_with_payload": {
+			cmd:     NewCommand(testNamespaceID).Live().Retrieve(*testNamespaceID),
+			wantErr: true,
+		},
+		"test_delete_with_payload": {
+			cmd:     &Command{Topic: NewCommand(testNamespaceID).Delete().Topic, Path: pathThing, Payload: 1},
+			wantErr: true,
+		},
+		"test_empty_feature_id": {
+			cmd:     NewCommand(testNamespaceID).FeatureProperty("", "a").Modify(1),
+			wantErr: true,
+		},
+		"test_empty_property_path": {
+			cmd:     NewCommand(testNamespaceID).FeatureProperty(testFeatureID, "").Delete(),
+			wantErr: true,
+		},
+		"test_fields_on_retrieve": {
+			cmd: NewCommand(testNamespaceID).WithFields(protocol.Fields().ThingID()).Retrieve(),
+		},
+		"test_fields_on_modify": {
+			cmd:     NewCommand(testNamespaceID).WithExtraFields("_revision").Attributes().Modify(1),
+			wantErr: true,
+		},
+		"test_invalid_headers": {
+			cmd:     NewCommand(testNamespaceID).Delete(),
+			opts:    []protocol.HeaderOpt{protocol.WithTimeout("forever")},
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := testCase.cmd.Validate(testCase.opts...)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+			}
+		})
+	}
+}
+
+func TestCommandPathEscaping(t *testing.T) {
+	tests := map[string]struct {
+		cmd  *Command
+		want string
+	}{
+		"test_attribute_key": {
+			cmd:  NewCommand(testNamespaceID).AttributeKey("a/b~c"),
+			want: "/attributes/a~1b~0c",
+		},
+		"test_attribute_pointer": {
+			cmd:  NewCommand(testNamespaceID).Attribute("location/latitude"),
+			want: "/attributes/location/latitude",
+		},
+		"test_feature": {
+			cmd:  NewCommand(testNamespaceID).FeatureDefinition("lamp/1"),
+			want: "/features/lamp~11/definition",
+		},
+		"test_feature_property_key": {
+			cmd:  NewCommand(testNamespaceID).FeaturePropertyKey("lamp/1", "on/off"),
+			want: "/features/lamp~11/properties/on~1off",
+		},
+		"test_feature_property_pointer": {
+			cmd:  NewCommand(testNamespaceID).FeatureProperty("lamp", "config/on"),
+			want: "/features/lamp/properties/config/on",
+		},
+		"test_feature_desired_property_key": {
+			cmd:  NewCommand(testNamespaceID).FeatureDesiredPropertyKey("lamp", "on/off"),
+			want: "/features/lamp/desiredProperties/on~1off",
+		},
+		"test_feature_desired_property_pointer": {
+			cmd:  NewCommand(testNamespaceID).FeatureDesiredProperty("lamp", "config/on~1off"),
+			want: "/features/lamp/desiredProperties/config/on~1off",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.cmd.Path)
+		})
+	}
+}
+
+func TestCommandWithFields(t *testing.T) {
+	fields := protocol.Fields().ThingID().Attributes("location")
+
+	got := NewCommand(testNamespaceID).WithFields(fields).Retrieve()
+	internal.AssertEqual(t, fields, got.Fields)
+	internal.AssertEqual(t, "thingId,attributes/location", got.Envelope().Fields)
+}
+
+func TestCommandWithExtraFields(t *testing.T) {
+	t.Run("TestCommandWithExtraFieldsOnly", func(t *testing.T) {
+		got := NewCommand(testNamespaceID).WithExtraFields("_revision", "_modified").Retrieve()
+		internal.AssertEqual(t, "_revision,_modified", got.Envelope().Fields)
+	})
+
+	t.Run("TestCommandWithExtraFieldsAdded", func(t *testing.T) {
+		got := NewCommand(testNamespaceID).WithFields(protocol.Fields().Features()).WithExtraFields("_metadata").Retrieve()
+		internal.AssertEqual(t, "features,_metadata", got.Envelope().Fields)
+	})
+
+	t.Run("TestCommandWithoutFields", func(t *testing.T) {
+		internal.AssertEqual(t, "", NewCommand(testNamespaceID).Retrieve().Envelope().Fields)
+	})
+}
+
+func TestNewCommandFromEnvelope(t *testing.T) {
+	t.Run("TestNewCommandFromEnvelopeRoundTrip", func(t *testing.T) {
+		tests := map[string]*Command{
+			"test_create":           NewCommand(testNamespaceID).Create((&model.Thing{}).WithIDFrom("testNamespace:testName")),
+			"test_modify_property":  NewCommand(testNamespaceID).Live().FeatureProperty(testFeatureID, "a/b").Modify(1),
+			"test_merge_attributes": NewCommand(testNamespaceID).Attributes().Merge(map[string]interface{}{"a": nil}),
+			"test_retrieve_fields":  NewCommand(testNamespaceID).WithFields(protocol.Fields().ThingID().Features()).Retrieve(),
+			"test_delete_feature":   NewCommand(testNamespaceID).Feature(testFeatureID).Delete(),
+		}
+
+		for testName, cmd := range tests {
+			t.Run(testName, func(t *testing.T) {
+				env := cmd.Envelope(protocol.WithCorrelationID("id"))
+				got, err := NewCommandFromEnvelope(env)
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, env, got.Envelope(protocol.WithCorrelationID("id")))
+				internal.AssertFalse(t, env.Topic == got.Topic)
+			})
+		}
+	})
+
+	t.Run("TestNewCommandFromEnvelopeRewrite", func(t *testing.T) {
+		env := NewCommand(testNamespaceID).Attribute("a").Modify(1).Envelope()
+
+		got, err := NewCommandFromEnvelope(env)
+		internal.AssertNil(t, err)
+		got.Live().Topic.WithEntityName("other")
+		internal.AssertEqual(t, "testNamespace/other/things/live/commands/modify", got.Envelope().Topic.String())
+		internal.AssertEqual(t, "testNamespace/testName/things/twin/commands/modify", env.Topic.String())
+	})
+
+	t.Run("TestNewCommandFromEnvelopeErrors", func(t *testing.T) {
+		tests := map[string]*protocol.Envelope{
+			"test_nil":          nil,
+			"test_no_topic":     {},
+			"test_event":        NewEvent(testNamespaceID).Deleted().Envelope(),
+			"test_message":      NewMessage(testNamespaceID).Inbox("subject").Envelope(),
+			"test_no_action":    NewCommand(testNamespaceID).Envelope(),
+			"test_invalid_path": NewCommand(testNamespaceID).Delete().Envelope().WithPath("/unknown"),
+		}
+
+		for testName, env := range tests {
+			t.Run(testName, func(t *testing.T) {
+				_, err := NewCommandFromEnvelope(env)
+				internal.AssertNotNil(t, err)
+			})
+		}
+	})
+}
@@ -100,6 +100,13 @@ func (event *Event) Attribute(attributePath string) *Event {
 	return event
 }
 
+// AttributeKey configures the Event to notify for a change in the Thing's top-level attribute
+// defined by the provided attributeKey, which is escaped as a single JSON pointer key.
+func (event *Event) AttributeKey(attributeKey string) *Event {
+	event.Path = fmt.Sprintf(pathThingAttributeFormat, protocol.EscapePointerToken(attributeKey))
+	return event
+}
+
 // Features configures the Event to notify for a change in the Thing's features.
 func (event *Event) Features() *Event {
 	event.Path = pathThingFeatures
@@ -108,42 +115,56 @@ func (event *Event) Features() *Event {
 
 // Feature configures the Event to notify for a change in the Thing's feature defined by the provided featureID.
 func (event *Event) Feature(featureID string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeatureFormat, featureID)
+	event.Path = fmt.Sprintf(pathThingFeatureFormat, protocol.EscapePointerToken(featureID))
 	return event
 }
 
 // FeatureDefinition configures the Event to notify for a change in the Thing's feature's definition for the feature
 // defined by the provided featureID.
 func (event *Event) FeatureDefinition(featureID string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeatureDefinitionFormat, featureID)
+	event.Path = fmt.Sprintf(pathThingFeatureDefinitionFormat, protocol.EscapePointerToken(featureID))
 	return event
 }
 
 // FeatureProperties configures the Event to notify for a change in the Thing's feature's properties of the feature
 // defined by the provided featureID.
 func (event *Event) FeatureProperties(featureID string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeaturePropertiesFormat, featureID)
+	event.Path = fmt.Sprintf(pathThingFeaturePropertiesFormat, protocol.EscapePointerToken(featureID))
 	return event
 }
 
 // FeatureProperty configures the Event to notify for a change in the Thing's feature's property
 // defined by the provided featureID and propertyPath as JSON pointer path (https://tools.ietf.org/html/rfc6901).
 func (event *Event) FeatureProperty(featureID, propertyPath string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, featureID, propertyPath)
+	event.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, protocol.EscapePointerToken(featureID), propertyPath)
+	return event
+}
+
+// FeaturePropertyKey configures the Event to notify for a change in the Thing's feature's top-level property
+// defined by the provided featureID and propertyKey, which is escaped as a single JSON pointer key.
+func (event *Event) FeaturePropertyKey(featureID, propertyKey string) *Event {
+	event.Path = fmt.Sprintf(pathThingFeaturePropertyFormat, protocol.EscapePointerToken(featureID), protocol.EscapePointerToken(propertyKey))
 	return event
 }
 
 // FeatureDesiredProperties configures the Event to notify for a change in the Thing's feature's desired properties
 // of the feature defined by the provided featureID.
 func (event *Event) FeatureDesiredProperties(featureID string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, featureID)
+	event.Path = fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, protocol.EscapePointerToken(featureID))
 	return event
 }
 
 // FeatureDesiredProperty configures the Event to notify for a change in the Thing's feature's desired property
 // defined by the provided featureID and propertyPath as JSON pointer path (https://tools.ietf.org/html/rfc6901).
 func (event *Event) FeatureDesiredProperty(featureID, propertyPath string) *Event {
-	event.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, featureID, propertyPath)
+	event.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, protocol.EscapePointerToken(featureID), propertyPath)
+	return event
+}
+
+// FeatureDesiredPropertyKey configures the Event to notify for a change in the Thing's feature's top-level desired property
+// defined by the provided featureID and propertyKey, which is escaped as a single JSON pointer key.
+func (event *Event) FeatureDesiredPropertyKey(featureID, propertyKey string) *Event {
+	event.Path = fmt.Sprintf(pathThingFeatureDesiredPropertyFormat, protocol.EscapePointerToken(featureID), protocol.EscapePointerToken(propertyKey))
 	return event
 }
 
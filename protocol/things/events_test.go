@@ -288,3 +288,12 @@ func TestEventEnvelope(t *testing.T) {
 		})
 	}
 }
+
+func TestEventPathEscaping(t *testing.T) {
+	internal.AssertEqual(t, "/attributes/a~1b", NewEvent(testNamespaceID).AttributeKey("a/b").Path)
+	internal.AssertEqual(t, "/attributes/a/b", NewEvent(testNamespaceID).Attribute("a/b").Path)
+	internal.AssertEqual(t, "/features/f~01/properties/p~1q", NewEvent(testNamespaceID).FeaturePropertyKey("f~1", "p/q").Path)
+	internal.AssertEqual(t, "/features/f/properties/p/q", NewEvent(testNamespaceID).FeatureProperty("f", "p/q").Path)
+	internal.AssertEqual(t, "/features/f/desiredProperties/p~1q", NewEvent(testNamespaceID).FeatureDesiredPropertyKey("f", "p/q").Path)
+	internal.AssertEqual(t, "/features/f~1g", NewEvent(testNamespaceID).Feature("f/g").Path)
+}
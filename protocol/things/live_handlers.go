@@ -0,0 +1,216 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"sync"
+
+	ditto "github.com/eclipse/ditto-clients-golang"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+const errorCodeLiveHandlerFailed = "things:live.handler.failed"
+
+// RetrieveThingHandler handles a live retrieve command of the Thing with the provided ID by providing the Thing.
+type RetrieveThingHandler func(thingID *model.NamespacedID) (*model.Thing, error)
+
+// RetrieveFeaturePropertyHandler handles a live retrieve command of a feature's property by providing the property's value.
+// The property is referenced by its JSON pointer within the feature's properties, e.g. '/config/color'.
+type RetrieveFeaturePropertyHandler func(thingID *model.NamespacedID, featureID, propertyPointer string) (interface{}, error)
+
+// ModifyFeaturePropertyHandler handles a live modify command of a feature's property with the provided value.
+// The property is referenced by its JSON pointer within the feature's properties, e.g. '/config/color'.
+type ModifyFeaturePropertyHandler func(thingID *model.NamespacedID, featureID, propertyPointer string, value interface{}) error
+
+// MessageHandler handles a live Message sent to the inbox of a Thing or a Feature by providing the response payload.
+// If the provided payload is nil, the response has no value.
+type MessageHandler func(msg *Message) (interface{}, error)
+
+// LiveHandlers is a registry of the typed handlers of the live commands and messages received by a device.
+// On each handled command or message, the correctly-formed response is built from the handler's result and replied:
+// - the handler's value with 200 status for the retrieve commands and the messages (204 if the message has no response payload)
+// - 204 status for the modify commands
+// - the Ditto error for the handler's error - as it is if it's a *protocol.ErrorResponse or with 500 status otherwise.
+// No response is replied if the 'response-required' header of the command or the message is set to false.
+// The live commands and messages without a registered handler are ignored.
+// Note: The LiveHandlers subscribes a handler to the ditto.Client on creation, thus only one LiveHandlers
+// is to be created per ditto.Client and it is to be closed when no longer used.
+type LiveHandlers struct {
+	client       ditto.Client
+	subscription []ditto.HandlerID
+
+	lock                    sync.RWMutex
+	retrieveThing           RetrieveThingHandler
+	retrieveFeatureProperty RetrieveFeaturePropertyHandler
+	modifyFeatureProperty   ModifyFeaturePropertyHandler
+	messages                map[string]MessageHandler
+}
+
+// NewLiveHandlers creates a new LiveHandlers registry over the provided ditto.Client.
+func NewLiveHandlers(client ditto.Client) *LiveHandlers {
+	handlers := &LiveHandlers{
+		client:   client,
+		messages: make(map[string]MessageHandler),
+	}
+	handlers.subscription = client.Subscribe(handlers.handle)
+	return handlers
+}
+
+// Close unsubscribes the LiveHandlers from the underlying ditto.Client.
+func (handlers *LiveHandlers) Close() {
+	handlers.client.UnsubscribeIDs(handlers.subscription...)
+}
+
+// OnRetrieveThing registers the handler of the live retrieve commands of the whole Thing.
+func (handlers *LiveHandlers) OnRetrieveThing(handler RetrieveThingHandler) *LiveHandlers {
+	handlers.lock.Lock()
+	defer handlers.lock.Unlock()
+	handlers.retrieveThing = handler
+	return handlers
+}
+
+// OnRetrieveFeatureProperty registers the handler of the live retrieve commands of the features' properties.
+func (handlers *LiveHandlers) OnRetrieveFeatureProperty(handler RetrieveFeaturePropertyHandler) *LiveHandlers {
+	handlers.lock.Lock()
+	defer handlers.lock.Unlock()
+	handlers.retrieveFeatureProperty = handler
+	return handlers
+}
+
+// OnModifyFeatureProperty registers the handler of the live modify commands of the features' properties.
+func (handlers *LiveHandlers) OnModifyFeatureProperty(handler ModifyFeaturePropertyHandler) *LiveHandlers {
+	handlers.lock.Lock()
+	defer handlers.lock.Unlock()
+	handlers.modifyFeatureProperty = handler
+	return handlers
+}
+
+// OnMessage registers the handler of the live messages with the provided subject sent to the inbox of the Thing or its features.
+func (handlers *LiveHandlers) OnMessage(subject string, handler MessageHandler) *LiveHandlers {
+	handlers.lock.Lock()
+	defer handlers.lock.Unlock()
+	handlers.messages[subject] = handler
+	return handlers
+}
+
+func (handlers *LiveHandlers) handle(requestID string, request *protocol.Envelope) {
+	if request.Topic == nil || request.Topic.Group != protocol.GroupThings || request.Topic.Channel != protocol.ChannelLive ||
+		request.Status != 0 {
+		return
+	}
+
+	var response *protocol.Envelope
+	switch request.Topic.Criterion {
+	case protocol.CriterionCommands:
+		response = handlers.handleCommand(request)
+	case protocol.CriterionMessages:
+		response = handlers.handleMessage(request)
+	}
+	if response == nil || !isResponseRequired(request) {
+		return
+	}
+
+	var err error
+	if len(requestID) > 0 {
+		err = handlers.client.Reply(requestID, response)
+	} else {
+		err = handlers.client.Send(response)
+	}
+	if err != nil {
+		ditto.LoggerFor(handlers.client, requestID, request).
+			Log(ditto.LevelError, "error replying to live "+string(request.Topic.Criterion), ditto.Field(ditto.LogKeyError, err))
+	}
+}
+
+func (handlers *LiveHandlers) handleCommand(request *protocol.Envelope) *protocol.Envelope {
+	target, err := ParseTarget(request.Path)
+	if err != nil {
+		return nil
+	}
+	thingID := model.NewNamespacedID(request.Topic.Namespace, request.Topic.EntityName)
+
+	handlers.lock.RLock()
+	retrieveThing, retrieveFeatureProperty, modifyFeatureProperty :=
+		handlers.retrieveThing, handlers.retrieveFeatureProperty, handlers.modifyFeatureProperty
+	handlers.lock.RUnlock()
+
+	switch {
+	case request.Topic.Action == protocol.ActionRetrieve && target.Kind == TargetThing && retrieveThing != nil:
+		thing, err := retrieveThing(thingID)
+		if err != nil {
+			return errorResponse(request, err)
+		}
+		return NewResponseTo(request).Retrieved(thing).Envelope()
+
+	case request.Topic.Action == protocol.ActionRetrieve && target.Kind == TargetFeatureProperty && retrieveFeatureProperty != nil:
+		value, err := retrieveFeatureProperty(thingID, target.FeatureID, target.Pointer)
+		if err != nil {
+			return errorResponse(request, err)
+		}
+		return NewResponseTo(request).Retrieved(value).Envelope()
+
+	case request.Topic.Action == protocol.ActionModify && target.Kind == TargetFeatureProperty && modifyFeatureProperty != nil:
+		if err := modifyFeatureProperty(thingID, target.FeatureID, target.Pointer, request.Value); err != nil {
+			return errorResponse(request, err)
+		}
+		return NewResponseTo(request).Modified().Envelope()
+
+	default:
+		return nil
+	}
+}
+
+func (handlers *LiveHandlers) handleMessage(request *protocol.Envelope) *protocol.Envelope {
+	msg, err := NewMessageFromEnvelope(request)
+	if err != nil || msg.Mailbox != inbox {
+		return nil
+	}
+
+	handlers.lock.RLock()
+	handler, ok := handlers.messages[msg.Subject]
+	handlers.lock.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	payload, err := handler(msg)
+	if err != nil {
+		return errorResponse(request, err)
+	}
+	status := protocol.StatusOK
+	if payload == nil {
+		status = protocol.StatusNoContent
+	}
+	response, err := ResponseTo(request, status)
+	if err != nil {
+		return nil
+	}
+	return response.WithPayload(payload).Envelope()
+}
+
+func errorResponse(request *protocol.Envelope, err error) *protocol.Envelope {
+	if errResp, ok := err.(*protocol.ErrorResponse); ok {
+		response := protocol.NewErrorEnvelope(request, errResp.Status, errResp.ErrorCode, errResp.Message)
+		response.Value = errResp
+		return response
+	}
+	return protocol.NewErrorEnvelope(request, protocol.StatusInternalServerError, errorCodeLiveHandlerFailed, err.Error())
+}
+
+func isResponseRequired(request *protocol.Envelope) bool {
+	if request.Headers == nil {
+		return true
+	}
+	responseRequired, ok := request.Headers.Get(protocol.HeaderResponseRequired).(bool)
+	return !ok || responseRequired
+}
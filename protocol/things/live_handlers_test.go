@@ -0,0 +1,171 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// testReplyingDittoClient is a testDittoClient recording the replies along with their request IDs.
+type testReplyingDittoClient struct {
+	testDittoClient
+	requestIDs []string
+	replies    []*protocol.Envelope
+}
+
+func (c *testReplyingDittoClient) Reply(requestID string, message *protocol.Envelope) error {
+	c.requestIDs = append(c.requestIDs, requestID)
+	c.replies = append(c.replies, message)
+	return nil
+}
+
+func newTestLiveHandlers() (*LiveHandlers, *testReplyingDittoClient) {
+	dittoClient := &testReplyingDittoClient{}
+	handlers := NewLiveHandlers(dittoClient).
+		OnRetrieveThing(func(thingID *model.NamespacedID) (*model.Thing, error) {
+			return (&model.Thing{}).WithID(thingID).WithAttribute("a", 1), nil
+		}).
+		OnRetrieveFeatureProperty(func(thingID *model.NamespacedID, featureID, propertyPointer string) (interface{}, error) {
+			if featureID == "lamp" && propertyPointer == "/config/color" {
+				return "red", nil
+			}
+			return nil, &protocol.ErrorResponse{Status: 404, ErrorCode: protocol.ErrorCodeFeaturePropertyNotFound, Message: "not found"}
+		}).
+		OnModifyFeatureProperty(func(thingID *model.NamespacedID, featureID, propertyPointer string, value interface{}) error {
+			if value == "broken" {
+				return errors.New("cannot apply")
+			}
+			return nil
+		}).
+		OnMessage("switch", func(msg *Message) (interface{}, error) {
+			return map[string]interface{}{"switched": msg.Payload, "feature": msg.FeatureID()}, nil
+		}).
+		OnMessage("reset", func(msg *Message) (interface{}, error) {
+			return nil, nil
+		})
+	return handlers, dittoClient
+}
+
+func TestLiveHandlers(t *testing.T) {
+	correlationID := protocol.WithCorrelationID("id")
+
+	tests := map[string]struct {
+		request    *protocol.Envelope
+		wantStatus int
+		wantPath   string
+		wantValue  interface{}
+		wantError  string
+	}{
+		"test_retrieve_thing": {
+			request:    NewCommand(testNamespaceID).Live().Retrieve().Envelope(correlationID),
+			wantStatus: 200,
+			wantPath:   "/",
+			wantValue:  (&model.Thing{}).WithID(testNamespaceID).WithAttribute("a", 1),
+		},
+		"test_retrieve_feature_property": {
+			request:    NewCommand(testNamespaceID).Live().FeatureProperty("lamp", "config/color").Retrieve().Envelope(correlationID),
+			wantStatus: 200,
+			wantPath:   "/features/lamp/properties/config/color",
+			wantValue:  "red",
+		},
+		"test_retrieve_feature_property_error": {
+			request:    NewCommand(testNamespaceID).Live().FeatureProperty("lamp", "on").Retrieve().Envelope(correlationID),
+			wantStatus: 404,
+			wantPath:   "/features/lamp/properties/on",
+			wantError:  protocol.ErrorCodeFeaturePropertyNotFound,
+		},
+		"test_modify_feature_property": {
+			request:    NewCommand(testNamespaceID).Live().FeatureProperty("lamp", "on").Modify(true).Envelope(correlationID),
+			wantStatus: 204,
+			wantPath:   "/features/lamp/properties/on",
+		},
+		"test_modify_feature_property_error": {
+			request:    NewCommand(testNamespaceID).Live().FeatureProperty("lamp", "on").Modify("broken").Envelope(correlationID),
+			wantStatus: 500,
+			wantPath:   "/features/lamp/properties/on",
+			wantError:  errorCodeLiveHandlerFailed,
+		},
+		"test_message": {
+			request:    NewMessage(testNamespaceID).Feature("lamp").Inbox("switch").WithPayload("on").Envelope(correlationID),
+			wantStatus: 200,
+			wantPath:   "/features/lamp/outbox/messages/switch",
+			wantValue:  map[string]interface{}{"switched": "on", "feature": "lamp"},
+		},
+		"test_message_without_response_payload": {
+			request:    NewMessage(testNamespaceID).Inbox("reset").Envelope(correlationID),
+			wantStatus: 204,
+			wantPath:   "/outbox/messages/reset",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			handlers, dittoClient := newTestLiveHandlers()
+			handlers.handle("req-1", testCase.request)
+
+			internal.AssertEqual(t, []string{"req-1"}, dittoClient.requestIDs)
+			response := dittoClient.replies[0]
+			internal.AssertEqual(t, testCase.wantStatus, response.Status)
+			internal.AssertEqual(t, testCase.wantPath, response.Path)
+			internal.AssertEqual(t, "id", response.Headers.CorrelationID())
+			if len(testCase.wantError) > 0 {
+				internal.AssertTrue(t, protocol.IsError(response))
+				internal.AssertEqual(t, testCase.wantError, response.Value.(*protocol.ErrorResponse).ErrorCode)
+			} else {
+				internal.AssertEqual(t, testCase.request.Topic.String(), response.Topic.String())
+				internal.AssertEqual(t, testCase.wantValue, response.Value)
+			}
+		})
+	}
+}
+
+func TestLiveHandlersIgnored(t *testing.T) {
+	tests := map[string]*protocol.Envelope{
+		"test_twin_command":          NewCommand(testNamespaceID).Retrieve().Envelope(),
+		"test_unhandled_command":     NewCommand(testNamespaceID).Live().Attributes().Retrieve().Envelope(),
+		"test_unhandled_subject":     NewMessage(testNamespaceID).Inbox("unknown").Envelope(),
+		"test_outbox_message":        NewMessage(testNamespaceID).Outbox("switch").Envelope(),
+		"test_response":              NewResponse(NewCommand(testNamespaceID).Live().Retrieve()).Retrieved(nil).Envelope(),
+		"test_response_not_required": NewCommand(testNamespaceID).Live().Retrieve().Envelope(protocol.WithResponseRequired(false)),
+		"test_invalid_path":          NewCommand(testNamespaceID).Live().Retrieve().Envelope().WithPath("/unknown"),
+	}
+
+	for testName, request := range tests {
+		t.Run(testName, func(t *testing.T) {
+			handlers, dittoClient := newTestLiveHandlers()
+			handlers.handle("req-1", request)
+			internal.AssertEqual(t, 0, len(dittoClient.replies))
+		})
+	}
+}
+
+func TestLiveHandlersSendWithoutRequestID(t *testing.T) {
+	handlers, dittoClient := newTestLiveHandlers()
+	handlers.handle("", NewMessage(testNamespaceID).Inbox("reset").Envelope())
+
+	internal.AssertEqual(t, 0, len(dittoClient.replies))
+	internal.AssertEqual(t, 1, len(dittoClient.sent))
+	internal.AssertEqual(t, 204, dittoClient.sent[0].Status)
+}
+
+func TestLiveHandlersClose(t *testing.T) {
+	handlers, dittoClient := newTestLiveHandlers()
+	internal.AssertNotNil(t, dittoClient.handler)
+
+	handlers.Close()
+	internal.AssertNil(t, dittoClient.handler)
+}
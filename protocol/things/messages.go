@@ -12,7 +12,11 @@
 package things
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/model"
 	"github.com/eclipse/ditto-clients-golang/protocol"
@@ -24,6 +28,8 @@ const (
 	pathMessagesFormat = "%s/%s/messages/%s"
 )
 
+var errNotMessage = errors.New("envelope is not a live message")
+
 // Message represents a message entity defined by the Ditto protocol for the Things group that defines an instant communication with the underlying device/implementation.
 // This is a special Message that is always bound to a specific Thing instance, it's always exchanged vie the
 // Live communication channel and it provides the capabilities to configure:
@@ -31,12 +37,19 @@ const (
 // - the entity that was affected - the whole Thing (the default) or a single Feature of the Thing (Feature).
 // Note: Only one communication type can be configured to the live message - if using the methods for configuring it - only the last one applies.
 // Note: Only one entity that the message targets can be configured to the live message - if using the methods for configuring it - only the last one applies.
+// A live Message responding to another one is created via Response or ResponseTo and has its Status set.
+// The ContentType and Timeout, if set, are applied as the 'content-type' and 'timeout' headers of the Message's Envelope.
 type Message struct {
 	Topic                *protocol.Topic
 	Subject              string
 	Mailbox              string
 	AddressedPartOfThing string
 	Payload              interface{}
+	Status               int
+	ContentType          string
+	Timeout              *time.Duration
+
+	request *protocol.Envelope
 }
 
 // NewMessage creates a new Message instance for the defined by the provided NamespacedID Thing.
@@ -76,21 +89,144 @@ func (msg *Message) WithPayload(payload interface{}) *Message {
 	return msg
 }
 
+// WithBinaryPayload sets the provided binary data to be sent in the message, e.g. an image or a firmware chunk.
+// Depending on the Message's content type the data is sent:
+// - as a raw JSON value for the JSON content types
+// - as a string for the text content types
+// - base64 encoded for any other content type, 'application/octet-stream' being the default one if none is set.
+func (msg *Message) WithBinaryPayload(data []byte) *Message {
+	msg.Payload = data
+	return msg
+}
+
+// WithContentType sets the content type of the message's payload.
+func (msg *Message) WithContentType(contentType string) *Message {
+	msg.ContentType = contentType
+	return msg
+}
+
+// WithTimeout sets how long the sender waits for a response to the message.
+// A zero timeout means that no response is to be waited for.
+func (msg *Message) WithTimeout(timeout time.Duration) *Message {
+	msg.Timeout = &timeout
+	return msg
+}
+
 // Feature configures the Message's target to be the specified by the featureID Thing's Feature.
 func (msg *Message) Feature(featureID string) *Message {
-	msg.AddressedPartOfThing = fmt.Sprintf(pathThingFeatureFormat, featureID)
+	msg.AddressedPartOfThing = fmt.Sprintf(pathThingFeatureFormat, protocol.EscapePointerToken(featureID))
 	return msg
 }
 
+// Response creates a new live Message responding to this one with the provided status.
+// The response has the same subject and target entity, while its mailbox is switched - inbox to outbox and vice versa.
+func (msg *Message) Response(status int) *Message {
+	topic := *msg.Topic
+	return &Message{
+		Topic:                &topic,
+		Subject:              msg.Subject,
+		Mailbox:              oppositeMailbox(msg.Mailbox),
+		AddressedPartOfThing: msg.AddressedPartOfThing,
+		Status:               status,
+	}
+}
+
+// ResponseTo creates a new live Message responding to the provided live message Envelope with the provided status.
+// The response has the same subject and target entity, while its mailbox is switched - inbox to outbox and vice versa.
+// The response's Envelope preserves the request's 'correlation-id' and 'content-type' headers
+// as defined by protocol.NewResponseEnvelope.
+// Returns an error if the provided Envelope is not a live message.
+func ResponseTo(request *protocol.Envelope, status int) (*Message, error) {
+	msg, err := NewMessageFromEnvelope(request)
+	if err != nil {
+		return nil, err
+	}
+	res := msg.Response(status)
+	res.request = request
+	return res, nil
+}
+
+// NewMessageFromEnvelope creates a new live Message instance from the provided live message Envelope
+// with its mailbox, subject, addressed part of the Thing, payload and status, so that e.g. the handlers can switch on its Subject.
+// Returns an error if the provided Envelope is not a live message.
+func NewMessageFromEnvelope(env *protocol.Envelope) (*Message, error) {
+	if env == nil || env.Topic == nil || env.Topic.Criterion != protocol.CriterionMessages {
+		return nil, errNotMessage
+	}
+	for _, mailbox := range []string{inbox, outbox} {
+		separator := "/" + mailbox + "/messages/"
+		if i := strings.Index(env.Path, separator); i >= 0 {
+			topic := *env.Topic
+			return &Message{
+				Topic:                &topic,
+				Subject:              env.Path[i+len(separator):],
+				Mailbox:              mailbox,
+				AddressedPartOfThing: env.Path[:i],
+				Payload:              env.Value,
+				Status:               env.Status,
+			}, nil
+		}
+	}
+	return nil, errNotMessage
+}
+
+// FeatureID returns the ID of the Feature the Message is addressed to or empty string if it's addressed to the whole Thing.
+func (msg *Message) FeatureID() string {
+	if !strings.HasPrefix(msg.AddressedPartOfThing, pathThingFeatures+"/") {
+		return ""
+	}
+	return protocol.UnescapePointerToken(strings.TrimPrefix(msg.AddressedPartOfThing, pathThingFeatures+"/"))
+}
+
+func oppositeMailbox(mailbox string) string {
+	if mailbox == inbox {
+		return outbox
+	}
+	return inbox
+}
+
 // Envelope generates the Ditto envelope with message's data applying all configurations and optionally all Headers provided.
+// The provided Headers take precedence over the Message's content type and timeout.
 func (msg *Message) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
 	res := &protocol.Envelope{
-		Topic: msg.Topic,
-		Path:  fmt.Sprintf(pathMessagesFormat, msg.AddressedPartOfThing, msg.Mailbox, msg.Subject),
-		Value: msg.Payload,
+		Topic:  msg.Topic,
+		Path:   fmt.Sprintf(pathMessagesFormat, msg.AddressedPartOfThing, msg.Mailbox, msg.Subject),
+		Value:  msg.Payload,
+		Status: msg.Status,
+	}
+	contentType := msg.ContentType
+	if data, ok := msg.Payload.([]byte); ok {
+		if contentType == "" {
+			contentType = protocol.ContentTypeOctetStream
+		}
+		res.Value = binaryValue(data, contentType)
+	}
+	var msgOpts []protocol.HeaderOpt
+	if contentType != "" {
+		msgOpts = append(msgOpts, protocol.WithContentType(contentType))
+	}
+	if msg.Timeout != nil {
+		msgOpts = append(msgOpts, protocol.WithTimeoutDuration(*msg.Timeout))
 	}
-	if headerOpts != nil {
+	if msgOpts != nil {
+		headerOpts = append(msgOpts, headerOpts...)
+	}
+	if msg.request != nil {
+		res.Headers = protocol.NewHeadersFrom(protocol.NewResponseEnvelope(msg.request, msg.Status, nil).Headers, headerOpts...)
+	} else if headerOpts != nil {
 		res.Headers = protocol.NewHeaders(headerOpts...)
 	}
 	return res
 }
+
+func binaryValue(data []byte, contentType string) interface{} {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	switch {
+	case mediaType == protocol.ContentTypeJSON || strings.HasSuffix(mediaType, "+json"):
+		return json.RawMessage(data)
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(data)
+	default:
+		return data
+	}
+}
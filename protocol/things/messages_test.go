@@ -11,8 +11,10 @@
 package things
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
 	"github.com/eclipse/ditto-clients-golang/model"
@@ -136,3 +138,204 @@ func TestMessageEnvelope(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageEnvelopeBinaryPayload(t *testing.T) {
+	data := []byte(`{"a":1}`)
+
+	tests := map[string]struct {
+		contentType     string
+		wantValue       interface{}
+		wantContentType string
+	}{
+		"test_default_content_type": {
+			wantValue:       data,
+			wantContentType: protocol.ContentTypeOctetStream,
+		},
+		"test_image_content_type": {
+			contentType:     "image/png",
+			wantValue:       data,
+			wantContentType: "image/png",
+		},
+		"test_json_content_type": {
+			contentType:     protocol.ContentTypeJSON,
+			wantValue:       json.RawMessage(data),
+			wantContentType: protocol.ContentTypeJSON,
+		},
+		"test_json_suffix_content_type": {
+			contentType:     "application/vnd.custom+json; charset=utf-8",
+			wantValue:       json.RawMessage(data),
+			wantContentType: "application/vnd.custom+json; charset=utf-8",
+		},
+		"test_text_content_type": {
+			contentType:     protocol.ContentTypeText,
+			wantValue:       string(data),
+			wantContentType: protocol.ContentTypeText,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewMessage(testNamespaceID).Inbox("upload").
+				WithContentType(testCase.contentType).
+				WithBinaryPayload(data).
+				Envelope()
+			internal.AssertEqual(t, testCase.wantValue, got.Value)
+			internal.AssertEqual(t, testCase.wantContentType, got.Headers.ContentType())
+		})
+	}
+}
+
+func TestMessageEnvelopeBinaryPayloadJSON(t *testing.T) {
+	env := NewMessage(testNamespaceID).Inbox("upload").WithBinaryPayload([]byte("chunk")).Envelope()
+
+	data, err := json.Marshal(env)
+	internal.AssertNil(t, err)
+	want := `{"topic":"testNamespace/testName/things/live/messages/upload",` +
+		`"headers":{"content-type":"application/octet-stream"},"path":"/inbox/messages/upload","value":"Y2h1bms="}`
+	internal.AssertEqual(t, want, string(data))
+}
+
+func TestMessageEnvelopeTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout time.Duration
+		opts    []protocol.HeaderOpt
+		want    string
+	}{
+		"test_timeout": {
+			timeout: 30 * time.Second,
+			want:    "30s",
+		},
+		"test_zero_timeout": {
+			timeout: 0,
+			want:    "0",
+		},
+		"test_timeout_overridden_by_headers": {
+			timeout: 30 * time.Second,
+			opts:    []protocol.HeaderOpt{protocol.WithTimeout("5s")},
+			want:    "5s",
+		},
+		"test_timeout_exceeding_max": {
+			timeout: 2 * time.Minute,
+			opts:    []protocol.HeaderOpt{protocol.WithCorrelationID("correlation-id")},
+			want:    "120s",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := NewMessage(testNamespaceID).Inbox("ping").WithTimeout(testCase.timeout).Envelope(testCase.opts...)
+			internal.AssertEqual(t, testCase.want, got.Headers.Timeout())
+		})
+	}
+
+	got := NewMessage(testNamespaceID).Inbox("ping").WithTimeout(2 * time.Minute).Envelope(protocol.WithCorrelationID("correlation-id"))
+	internal.AssertEqual(t, "correlation-id", got.Headers.CorrelationID())
+	internal.AssertNotNil(t, got.Headers.Validate())
+}
+
+func TestMessageResponse(t *testing.T) {
+	tests := map[string]struct {
+		arg         *Message
+		wantMailbox string
+	}{
+		"test_inbox_response": {
+			arg:         NewMessage(testNamespaceID).Feature(testFeatureID).Inbox("subject"),
+			wantMailbox: outbox,
+		},
+		"test_outbox_response": {
+			arg:         NewMessage(testNamespaceID).Outbox("subject"),
+			wantMailbox: inbox,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := testCase.arg.WithPayload("request").Response(200).WithPayload("response")
+			internal.AssertEqual(t, testCase.wantMailbox, got.Mailbox)
+			internal.AssertEqual(t, "subject", got.Subject)
+			internal.AssertEqual(t, testCase.arg.AddressedPartOfThing, got.AddressedPartOfThing)
+			internal.AssertEqual(t, testCase.arg.Topic, got.Topic)
+			internal.AssertFalse(t, testCase.arg.Topic == got.Topic)
+			internal.AssertEqual(t, "request", testCase.arg.Payload)
+
+			env := got.Envelope()
+			internal.AssertEqual(t, 200, env.Status)
+			internal.AssertEqual(t, "response", env.Value)
+		})
+	}
+}
+
+func TestMessageResponseTo(t *testing.T) {
+	t.Run("TestMessageResponseToFeatureInbox", func(t *testing.T) {
+		request := NewMessage(testNamespaceID).Feature(testFeatureID).Inbox("do/it").
+			Envelope(protocol.WithCorrelationID("id"), protocol.WithResponseRequired(true))
+
+		got, err := ResponseTo(request, 204)
+		internal.AssertNil(t, err)
+		env := got.Envelope(protocol.WithChannel("live"))
+		internal.AssertEqual(t, "/features/"+testFeatureID+"/outbox/messages/do/it", env.Path)
+		internal.AssertEqual(t, request.Topic, env.Topic)
+		internal.AssertEqual(t, 204, env.Status)
+		internal.AssertEqual(t, "id", env.Headers.CorrelationID())
+		internal.AssertEqual(t, "live", env.Headers.Channel())
+		internal.AssertFalse(t, env.Headers.IsResponseRequired())
+	})
+
+	t.Run("TestMessageResponseToThingOutbox", func(t *testing.T) {
+		request := NewMessage(testNamespaceID).Outbox("subject").Envelope()
+
+		got, err := ResponseTo(request, 200)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, "/inbox/messages/subject", got.Envelope().Path)
+	})
+
+	t.Run("TestMessageResponseToNotMessage", func(t *testing.T) {
+		_, err := ResponseTo(NewCommand(testNamespaceID).Delete().Envelope(), 200)
+		internal.AssertNotNil(t, err)
+
+		request := NewMessage(testNamespaceID).Inbox("subject").Envelope()
+		request.Path = "/features"
+		_, err = ResponseTo(request, 200)
+		internal.AssertNotNil(t, err)
+	})
+}
+
+func TestMessageFeatureEscaping(t *testing.T) {
+	got := NewMessage(testNamespaceID).Feature("lamp/1").Inbox("switch/on").Envelope()
+	internal.AssertEqual(t, "/features/lamp~11/inbox/messages/switch/on", got.Path)
+}
+
+func TestNewMessageFromEnvelope(t *testing.T) {
+	t.Run("TestNewMessageFromEnvelopeFeatureInbox", func(t *testing.T) {
+		env := NewMessage(testNamespaceID).Feature("lamp/1").Inbox("switch/on").WithPayload("on").Envelope()
+
+		got, err := NewMessageFromEnvelope(env)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, inbox, got.Mailbox)
+		internal.AssertEqual(t, "switch/on", got.Subject)
+		internal.AssertEqual(t, "lamp/1", got.FeatureID())
+		internal.AssertEqual(t, "on", got.Payload)
+		internal.AssertEqual(t, env, got.Envelope())
+		internal.AssertFalse(t, env.Topic == got.Topic)
+	})
+
+	t.Run("TestNewMessageFromEnvelopeThingOutboxResponse", func(t *testing.T) {
+		env := NewMessage(testNamespaceID).Outbox("subject").Response(200).WithPayload(1).Envelope()
+
+		got, err := NewMessageFromEnvelope(env)
+		internal.AssertNil(t, err)
+		internal.AssertEqual(t, inbox, got.Mailbox)
+		internal.AssertEqual(t, "subject", got.Subject)
+		internal.AssertEqual(t, "", got.FeatureID())
+		internal.AssertEqual(t, 200, got.Status)
+	})
+
+	t.Run("TestNewMessageFromEnvelopeErrors", func(t *testing.T) {
+		_, err := NewMessageFromEnvelope(nil)
+		internal.AssertNotNil(t, err)
+		_, err = NewMessageFromEnvelope(NewCommand(testNamespaceID).Delete().Envelope())
+		internal.AssertNotNil(t, err)
+		_, err = NewMessageFromEnvelope(NewMessage(testNamespaceID).Inbox("subject").Envelope().WithPath("/features/lamp"))
+		internal.AssertNotNil(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// Response represents a message entity defined by the Ditto protocol for the Things group that responds to a Command.
+// The Response is bound to the same Thing, channel, action and path as the Command it responds to
+// and provides the capabilities to configure the outcome of the Command's execution:
+// - Created - the entity has been created by a create or modify command, the created entity is the value
+// - Modified - an existing entity has been modified, there is no value
+// - Merged - an existing entity has been merged, there is no value
+// - Retrieved - the entity has been retrieved, the retrieved entity is the value
+// - Deleted - the entity has been deleted, there is no value
+// Note: Only one outcome can be configured to the response - if using the methods for configuring it - only the last one applies.
+type Response struct {
+	Topic   *protocol.Topic
+	Path    string
+	Payload interface{}
+	Status  int
+
+	request *protocol.Envelope
+}
+
+// NewResponse creates a new Response instance to the provided Command.
+func NewResponse(cmd *Command) *Response {
+	topic := *cmd.Topic
+	return &Response{
+		Topic: &topic,
+		Path:  cmd.Path,
+	}
+}
+
+// NewResponseTo creates a new Response instance to the provided command Envelope.
+// The Response's Envelope preserves the request's 'correlation-id' and 'content-type' headers
+// as defined by protocol.NewResponseEnvelope.
+func NewResponseTo(request *protocol.Envelope) *Response {
+	res := &Response{
+		Path:    request.Path,
+		request: request,
+	}
+	if request.Topic != nil {
+		topic := *request.Topic
+		res.Topic = &topic
+	}
+	return res
+}
+
+// Created configures the Response to notify that the entity has been created using the provided payload instance.
+// It applies to both create and modify (upsert) commands.
+func (res *Response) Created(payload interface{}) *Response {
+	res.Status = protocol.StatusCreated
+	res.Payload = payload
+	return res
+}
+
+// Modified configures the Response to notify that an existing entity has been modified.
+func (res *Response) Modified() *Response {
+	res.Status = protocol.StatusNoContent
+	res.Payload = nil
+	return res
+}
+
+// Merged configures the Response to notify that an existing entity has been merged with the Command's patch.
+func (res *Response) Merged() *Response {
+	res.Status = protocol.StatusNoContent
+	res.Payload = nil
+	return res
+}
+
+// Retrieved configures the Response to provide the retrieved entity defined by the provided payload.
+func (res *Response) Retrieved(payload interface{}) *Response {
+	res.Status = protocol.StatusOK
+	res.Payload = payload
+	return res
+}
+
+// Deleted configures the Response to notify that the entity has been deleted.
+func (res *Response) Deleted() *Response {
+	res.Status = protocol.StatusNoContent
+	res.Payload = nil
+	return res
+}
+
+// Envelope generates the Ditto envelope with response's data applying all configurations and optionally all Headers provided.
+func (res *Response) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
+	msg := &protocol.Envelope{
+		Topic:  res.Topic,
+		Path:   res.Path,
+		Value:  res.Payload,
+		Status: res.Status,
+	}
+	if res.request != nil {
+		msg.Headers = protocol.NewHeadersFrom(protocol.NewResponseEnvelope(res.request, res.Status, nil).Headers, headerOpts...)
+	} else if headerOpts != nil {
+		msg.Headers = protocol.NewHeaders(headerOpts...)
+	}
+	return msg
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func TestNewResponse(t *testing.T) {
+	cmd := NewCommand(testNamespaceID).Live().Feature(testFeatureID).Retrieve()
+
+	got := NewResponse(cmd)
+	internal.AssertEqual(t, cmd.Topic, got.Topic)
+	internal.AssertEqual(t, cmd.Path, got.Path)
+
+	got.Topic.WithAction(protocol.ActionDelete)
+	internal.AssertEqual(t, protocol.ActionRetrieve, cmd.Topic.Action)
+}
+
+func TestResponseOutcomes(t *testing.T) {
+	payload := map[string]interface{}{"properties": map[string]interface{}{"on": true}}
+
+	tests := map[string]struct {
+		res         func(res *Response) *Response
+		wantStatus  int
+		wantPayload interface{}
+	}{
+		"test_created": {
+			res:         func(res *Response) *Response { return res.Created(payload) },
+			wantStatus:  201,
+			wantPayload: payload,
+		},
+		"test_modified": {
+			res:        func(res *Response) *Response { return res.Retrieved(payload).Modified() },
+			wantStatus: 204,
+		},
+		"test_merged": {
+			res:        func(res *Response) *Response { return res.Merged() },
+			wantStatus: 204,
+		},
+		"test_retrieved": {
+			res:         func(res *Response) *Response { return res.Retrieved(payload) },
+			wantStatus:  200,
+			wantPayload: payload,
+		},
+		"test_deleted": {
+			res:        func(res *Response) *Response { return res.Deleted() },
+			wantStatus: 204,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := testCase.res(NewResponse(NewCommand(testNamespaceID).Feature(testFeatureID).Modify(payload)))
+			internal.AssertEqual(t, testCase.wantStatus, got.Status)
+			internal.AssertEqual(t, testCase.wantPayload, got.Payload)
+		})
+	}
+}
+
+func TestResponseEnvelope(t *testing.T) {
+	thing := (&model.Thing{}).WithIDFrom("testNamespace:testName")
+
+	t.Run("TestResponseEnvelopeFromCommand", func(t *testing.T) {
+		cmd := NewCommand(testNamespaceID).Create(thing)
+
+		got := NewResponse(cmd).Created(thing).Envelope(protocol.WithCorrelationID("id"))
+		want := &protocol.Envelope{
+			Topic:   cmd.Topic,
+			Headers: protocol.NewHeaders(protocol.WithCorrelationID("id")),
+			Path:    pathThing,
+			Value:   thing,
+			Status:  201,
+		}
+		internal.AssertEqual(t, want, got)
+	})
+
+	t.Run("TestResponseEnvelopeWithoutHeaders", func(t *testing.T) {
+		got := NewResponse(NewCommand(testNamespaceID).Delete()).Deleted().Envelope()
+		internal.AssertNil(t, got.Headers)
+		internal.AssertNil(t, got.Value)
+		internal.AssertEqual(t, 204, got.Status)
+	})
+
+	t.Run("TestResponseEnvelopeToRequest", func(t *testing.T) {
+		request := NewCommand(testNamespaceID).Attribute(testAttributeID).Retrieve().
+			Envelope(protocol.WithCorrelationID("id"), protocol.WithResponseRequired(true),
+				protocol.WithContentType(protocol.ContentTypeJSON))
+
+		got := NewResponseTo(request).Retrieved("value").Envelope(protocol.WithChannel("live"))
+		internal.AssertEqual(t, request.Topic, got.Topic)
+		internal.AssertFalse(t, request.Topic == got.Topic)
+		internal.AssertEqual(t, request.Path, got.Path)
+		internal.AssertEqual(t, "value", got.Value)
+		internal.AssertEqual(t, 200, got.Status)
+		internal.AssertEqual(t, "id", got.Headers.CorrelationID())
+		internal.AssertEqual(t, protocol.ContentTypeJSON, got.Headers.ContentType())
+		internal.AssertEqual(t, "live", got.Headers.Channel())
+		internal.AssertFalse(t, got.Headers.IsResponseRequired())
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/eclipse/ditto-clients-golang/protocol/rql"
+)
+
+// SearchPayload represents the value of a search command as defined by the Ditto protocol.
+// Filter, Options and Namespaces are applicable for the subscribe action,
+// SubscriptionID and Demand - for the request action and SubscriptionID only - for the cancel action.
+type SearchPayload struct {
+	SubscriptionID string   `json:"subscriptionId,omitempty"`
+	Demand         int64    `json:"demand,omitempty"`
+	Filter         string   `json:"filter,omitempty"`
+	Options        string   `json:"options,omitempty"`
+	Namespaces     []string `json:"namespaces,omitempty"`
+}
+
+// SearchCommand represents a message entity defined by the Ditto protocol for the Things group that defines a search action.
+// It is not bound to a specific Thing instance and provides the capabilities to:
+// - subscribe for search results matching the configured filter, options and namespaces (Subscribe)
+// - request a demand of search result pages for an existing subscription (Request)
+// - cancel an existing subscription (Cancel).
+// Note: Only one action can be configured to the command - if using the methods for configuring it - only the last one applies.
+type SearchCommand struct {
+	Topic   *protocol.Topic
+	Path    string
+	Payload *SearchPayload
+	Fields  string
+}
+
+// NewSearchCommand creates a new SearchCommand instance.
+func NewSearchCommand() *SearchCommand {
+	return &SearchCommand{
+		Topic: (&protocol.Topic{}).
+			WithNamespace(protocol.TopicPlaceholder).
+			WithEntityName(protocol.TopicPlaceholder).
+			WithGroup(protocol.GroupThings).
+			WithChannel(protocol.ChannelTwin).
+			WithCriterion(protocol.CriterionSearch),
+		Path:    pathThing,
+		Payload: &SearchPayload{},
+	}
+}
+
+// Filter configures the RQL filter expression the searched Things must match, e.g. 'eq(attributes/location,"kitchen")'.
+func (cmd *SearchCommand) Filter(filter string) *SearchCommand {
+	cmd.Payload.Filter = filter
+	return cmd
+}
+
+// Options configures the search options, e.g. 'sort(+thingId),size(10)'.
+func (cmd *SearchCommand) Options(options string) *SearchCommand {
+	cmd.Payload.Options = options
+	return cmd
+}
+
+// WithFilter configures the RQL filter expression the searched Things must match as built via the rql package,
+// e.g. rql.Eq("attributes/location", "kitchen").
+func (cmd *SearchCommand) WithFilter(filter rql.Filter) *SearchCommand {
+	cmd.Payload.Filter = filter.String()
+	return cmd
+}
+
+// WithOptions configures the search options as built via the rql package, e.g. rql.NewOptions().Size(10).SortAsc("thingId").
+func (cmd *SearchCommand) WithOptions(options *rql.Options) *SearchCommand {
+	cmd.Payload.Options = options.String()
+	return cmd
+}
+
+// Namespaces configures the namespaces the search is to be limited to.
+func (cmd *SearchCommand) Namespaces(namespaces ...string) *SearchCommand {
+	cmd.Payload.Namespaces = namespaces
+	return cmd
+}
+
+// WithFields configures the fields of the Things to be included in the search results, e.g. 'thingId,attributes'.
+func (cmd *SearchCommand) WithFields(fields string) *SearchCommand {
+	cmd.Fields = fields
+	return cmd
+}
+
+// WithFieldSelector configures the fields of the Things to be included in the search results as rendered by the provided FieldSelector.
+func (cmd *SearchCommand) WithFieldSelector(fields *protocol.FieldSelector) *SearchCommand {
+	cmd.Fields = fields.String()
+	return cmd
+}
+
+// Subscribe sets the action of the command instance accordingly to subscribe for search results
+// using the configured filter, options and namespaces.
+func (cmd *SearchCommand) Subscribe() *SearchCommand {
+	cmd.Topic.WithAction(protocol.ActionSubscribe)
+	return cmd
+}
+
+// Request sets the action of the command instance accordingly to request the provided demand
+// of search result pages for the subscription with the provided subscriptionID.
+func (cmd *SearchCommand) Request(subscriptionID string, demand int64) *SearchCommand {
+	cmd.Topic.WithAction(protocol.ActionRequest)
+	cmd.Payload.SubscriptionID = subscriptionID
+	cmd.Payload.Demand = demand
+	return cmd
+}
+
+// Cancel sets the action of the command instance accordingly to cancel the subscription with the provided subscriptionID.
+func (cmd *SearchCommand) Cancel(subscriptionID string) *SearchCommand {
+	cmd.Topic.WithAction(protocol.ActionCancel)
+	cmd.Payload.SubscriptionID = subscriptionID
+	return cmd
+}
+
+// Envelope generates the Ditto envelope with search command's data applying all configurations and optionally all Headers provided.
+func (cmd *SearchCommand) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
+	msg := &protocol.Envelope{
+		Topic:  cmd.Topic,
+		Path:   cmd.Path,
+		Value:  cmd.Payload,
+		Fields: cmd.Fields,
+	}
+	if headerOpts != nil {
+		msg.Headers = protocol.NewHeaders(headerOpts...)
+	}
+	return msg
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// SearchEvent represents a search event defined by the Ditto protocol for the Things group that is received
+// as a result of a SearchCommand. Depending on its action it provides:
+// - created - the SubscriptionID of the newly created subscription
+// - next - the SubscriptionID along with a page of the matching Things as Items
+// - complete - the SubscriptionID of the subscription for which all results have been delivered
+// - failed - the SubscriptionID along with the Error payload describing the failure.
+type SearchEvent struct {
+	Action         protocol.TopicAction    `json:"-"`
+	SubscriptionID string                  `json:"subscriptionId"`
+	Items          []model.Thing           `json:"items,omitempty"`
+	Error          *protocol.ErrorResponse `json:"error,omitempty"`
+}
+
+// ParseSearchEvent parses the provided Envelope into a SearchEvent instance.
+// Returns an error if the Envelope is not a search event or its value cannot be decoded.
+func ParseSearchEvent(env *protocol.Envelope) (*SearchEvent, error) {
+	if env == nil || env.Topic == nil {
+		return nil, errors.New("envelope without topic is not a search event")
+	}
+	if env.Topic.Group != protocol.GroupThings || env.Topic.Criterion != protocol.CriterionSearch {
+		return nil, fmt.Errorf("envelope with topic '%s' is not a search event", env.Topic.String())
+	}
+	switch env.Topic.Action {
+	case protocol.ActionCreated, protocol.ActionNext, protocol.ActionComplete, protocol.ActionFailed:
+	default:
+		return nil, fmt.Errorf("unsupported search event action: %s", env.Topic.Action)
+	}
+
+	event := &SearchEvent{}
+	if env.Value != nil {
+		if err := decodeValue(env.Value, event); err != nil {
+			return nil, err
+		}
+	}
+	event.Action = env.Topic.Action
+	return event, nil
+}
+
+// IsCreated returns true if the SearchEvent notifies that a subscription has been created.
+func (event *SearchEvent) IsCreated() bool {
+	return event.Action == protocol.ActionCreated
+}
+
+// IsNext returns true if the SearchEvent provides a page of search results.
+func (event *SearchEvent) IsNext() bool {
+	return event.Action == protocol.ActionNext
+}
+
+// IsComplete returns true if the SearchEvent notifies that all search results have been delivered.
+func (event *SearchEvent) IsComplete() bool {
+	return event.Action == protocol.ActionComplete
+}
+
+// IsFailed returns true if the SearchEvent notifies that the subscription has failed.
+func (event *SearchEvent) IsFailed() bool {
+	return event.Action == protocol.ActionFailed
+}
+
+func decodeValue(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
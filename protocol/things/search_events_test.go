@@ -0,0 +1,113 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func unmarshalEnvelope(t *testing.T, data string) *protocol.Envelope {
+	env := &protocol.Envelope{}
+	if err := json.Unmarshal([]byte(data), env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return env
+}
+
+func TestParseSearchEventNext(t *testing.T) {
+	env := unmarshalEnvelope(t, `{"topic":"_/_/things/twin/search/next","path":"/",
+		"value":{"subscriptionId":"sub-1","items":[{"thingId":"org.eclipse.ditto:thing1","attributes":{"a":1}}]}}`)
+
+	got, err := ParseSearchEvent(env)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, got.IsNext())
+	internal.AssertEqual(t, "sub-1", got.SubscriptionID)
+	internal.AssertEqual(t, []model.Thing{{
+		ID:         model.NewNamespacedIDFrom("org.eclipse.ditto:thing1"),
+		Attributes: map[string]interface{}{"a": float64(1)},
+	}}, got.Items)
+}
+
+func TestParseSearchEventActions(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want *SearchEvent
+	}{
+		"test_created": {
+			data: `{"topic":"_/_/things/twin/search/created","path":"/","value":{"subscriptionId":"sub-1"}}`,
+			want: &SearchEvent{Action: protocol.ActionCreated, SubscriptionID: "sub-1"},
+		},
+		"test_complete": {
+			data: `{"topic":"_/_/things/twin/search/complete","path":"/","value":{"subscriptionId":"sub-1"}}`,
+			want: &SearchEvent{Action: protocol.ActionComplete, SubscriptionID: "sub-1"},
+		},
+		"test_failed": {
+			data: `{"topic":"_/_/things/twin/search/failed","path":"/",
+				"value":{"subscriptionId":"sub-1","error":{"status":400,"error":"thing-search:invalid.filter"}}}`,
+			want: &SearchEvent{
+				Action:         protocol.ActionFailed,
+				SubscriptionID: "sub-1",
+				Error: &protocol.ErrorResponse{
+					Status:    400,
+					ErrorCode: "thing-search:invalid.filter",
+				},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseSearchEvent(unmarshalEnvelope(t, testCase.data))
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestParseSearchEventErrors(t *testing.T) {
+	tests := map[string]struct {
+		arg *protocol.Envelope
+	}{
+		"test_nil_envelope": {
+			arg: nil,
+		},
+		"test_without_topic": {
+			arg: &protocol.Envelope{},
+		},
+		"test_not_search_criterion": {
+			arg: NewEvent(testNamespaceID).Deleted().Envelope(),
+		},
+		"test_search_command": {
+			arg: NewSearchCommand().Subscribe().Envelope(),
+		},
+		"test_invalid_value": {
+			arg: &protocol.Envelope{
+				Topic: (&protocol.Topic{}).WithGroup(protocol.GroupThings).
+					WithCriterion(protocol.CriterionSearch).WithAction(protocol.ActionNext),
+				Value: map[string]interface{}{"items": "invalid"},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseSearchEvent(testCase.arg)
+			internal.AssertNil(t, got)
+			internal.AssertNotNil(t, err)
+		})
+	}
+}
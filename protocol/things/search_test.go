@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+	"github.com/eclipse/ditto-clients-golang/protocol/rql"
+)
+
+func TestNewSearchCommand(t *testing.T) {
+	want := &SearchCommand{
+		Topic: &protocol.Topic{
+			Namespace:  protocol.TopicPlaceholder,
+			EntityName: protocol.TopicPlaceholder,
+			Group:      protocol.GroupThings,
+			Channel:    protocol.ChannelTwin,
+			Criterion:  protocol.CriterionSearch,
+		},
+		Path:    pathThing,
+		Payload: &SearchPayload{},
+	}
+
+	got := NewSearchCommand()
+	internal.AssertEqual(t, want, got)
+}
+
+func TestSearchCommandSubscribe(t *testing.T) {
+	got := NewSearchCommand().
+		Filter("eq(attributes/location,\"kitchen\")").
+		Options("size(10)").
+		Namespaces("org.eclipse.ditto", "org.eclipse.hono").
+		WithFields("thingId").
+		Subscribe()
+
+	internal.AssertEqual(t, protocol.ActionSubscribe, got.Topic.Action)
+	internal.AssertEqual(t, &SearchPayload{
+		Filter:     "eq(attributes/location,\"kitchen\")",
+		Options:    "size(10)",
+		Namespaces: []string{"org.eclipse.ditto", "org.eclipse.hono"},
+	}, got.Payload)
+	internal.AssertEqual(t, "thingId", got.Fields)
+}
+
+func TestSearchCommandRequest(t *testing.T) {
+	got := NewSearchCommand().Request("testSubscriptionID", 5)
+
+	internal.AssertEqual(t, protocol.ActionRequest, got.Topic.Action)
+	internal.AssertEqual(t, &SearchPayload{SubscriptionID: "testSubscriptionID", Demand: 5}, got.Payload)
+}
+
+func TestSearchCommandCancel(t *testing.T) {
+	got := NewSearchCommand().Cancel("testSubscriptionID")
+
+	internal.AssertEqual(t, protocol.ActionCancel, got.Topic.Action)
+	internal.AssertEqual(t, &SearchPayload{SubscriptionID: "testSubscriptionID"}, got.Payload)
+}
+
+func TestSearchCommandEnvelope(t *testing.T) {
+	cmd := NewSearchCommand().Filter("exists(thingId)").WithFields("thingId").Subscribe()
+
+	tests := map[string]struct {
+		arg  []protocol.HeaderOpt
+		want *protocol.Envelope
+	}{
+		"test_without_header": {
+			arg: nil,
+			want: &protocol.Envelope{
+				Topic:  cmd.Topic,
+				Path:   cmd.Path,
+				Value:  cmd.Payload,
+				Fields: "thingId",
+			},
+		},
+		"test_with_any_headers": {
+			arg: []protocol.HeaderOpt{
+				protocol.WithCorrelationID("testCorrelationID"),
+			},
+			want: &protocol.Envelope{
+				Topic:  cmd.Topic,
+				Path:   cmd.Path,
+				Value:  cmd.Payload,
+				Fields: "thingId",
+				Headers: &protocol.Headers{
+					Values: map[string]interface{}{
+						protocol.HeaderCorrelationID: "testCorrelationID",
+					},
+				},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := cmd.Envelope(testCase.arg...)
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
+
+func TestSearchCommandEnvelopeJSON(t *testing.T) {
+	data, err := json.Marshal(NewSearchCommand().Request("testSubscriptionID", 2).Envelope())
+	internal.AssertNil(t, err)
+
+	want := `{"topic":"_/_/things/twin/search/request","path":"/","value":{"subscriptionId":"testSubscriptionID","demand":2}}`
+	internal.AssertEqual(t, want, string(data))
+}
+
+func TestSearchCommandTyped(t *testing.T) {
+	got := NewSearchCommand().
+		WithFilter(rql.And(rql.Eq("attributes/location", "kitchen"), rql.Exists("features/lamp"))).
+		WithOptions(rql.NewOptions().Size(10).SortAsc("thingId")).
+		WithFieldSelector(protocol.Fields().ThingID().Attributes()).
+		Namespaces("ns").
+		Subscribe().
+		Envelope()
+
+	internal.AssertEqual(t, &SearchPayload{
+		Filter:     `and(eq(attributes/location,"kitchen"),exists(features/lamp))`,
+		Options:    "size(10),sort(+thingId)",
+		Namespaces: []string{"ns"},
+	}, got.Value)
+	internal.AssertEqual(t, "thingId,attributes", got.Fields)
+	internal.AssertEqual(t, protocol.ActionSubscribe, got.Topic.Action)
+}
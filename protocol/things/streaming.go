@@ -0,0 +1,124 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// StreamingPayload represents the value of a streaming subscription command as defined by the Ditto protocol.
+// The historical revisions and timestamps are applicable for the subscribeForPersistedEvents action,
+// SubscriptionID and Demand - for the request action and SubscriptionID only - for the cancel action.
+type StreamingPayload struct {
+	SubscriptionID          string `json:"subscriptionId,omitempty"`
+	Demand                  int64  `json:"demand,omitempty"`
+	FromHistoricalRevision  int64  `json:"fromHistoricalRevision,omitempty"`
+	ToHistoricalRevision    int64  `json:"toHistoricalRevision,omitempty"`
+	FromHistoricalTimestamp string `json:"fromHistoricalTimestamp,omitempty"`
+	ToHistoricalTimestamp   string `json:"toHistoricalTimestamp,omitempty"`
+}
+
+// StreamingCommand represents a message entity defined by the Ditto protocol for the Things group that defines
+// a streaming subscription for the persisted events of a Thing, i.e. for replaying its history. It provides the capabilities to:
+// - subscribe for the persisted events in a range of revisions or timestamps (SubscribeForPersistedEvents)
+// - request a demand of persisted events for an existing subscription (Request)
+// - cancel an existing subscription (Cancel).
+// Note: Only one action can be configured to the command - if using the methods for configuring it - only the last one applies.
+type StreamingCommand struct {
+	Topic   *protocol.Topic
+	Path    string
+	Payload *StreamingPayload
+}
+
+// NewStreamingCommand creates a new StreamingCommand instance for the defined by the provided NamespacedID Thing.
+// The subscription is for the persisted events of the whole Thing, use Path to restrict it to a part of the Thing.
+func NewStreamingCommand(thingID *model.NamespacedID) *StreamingCommand {
+	return &StreamingCommand{
+		Topic: (&protocol.Topic{}).
+			WithNamespace(thingID.Namespace).
+			WithEntityName(thingID.Name).
+			WithGroup(protocol.GroupThings).
+			WithChannel(protocol.ChannelTwin).
+			WithCriterion(protocol.CriterionStreaming),
+		Path:    pathThing,
+		Payload: &StreamingPayload{},
+	}
+}
+
+// WithPath restricts the subscription to the persisted events affecting the provided path of the Thing, e.g. '/attributes'.
+func (cmd *StreamingCommand) WithPath(path string) *StreamingCommand {
+	cmd.Path = path
+	return cmd
+}
+
+// FromRevision configures the first revision of the Thing's history to be streamed.
+func (cmd *StreamingCommand) FromRevision(revision int64) *StreamingCommand {
+	cmd.Payload.FromHistoricalRevision = revision
+	return cmd
+}
+
+// ToRevision configures the last revision of the Thing's history to be streamed.
+func (cmd *StreamingCommand) ToRevision(revision int64) *StreamingCommand {
+	cmd.Payload.ToHistoricalRevision = revision
+	return cmd
+}
+
+// FromTimestamp configures the point in time the Thing's history is to be streamed from.
+func (cmd *StreamingCommand) FromTimestamp(timestamp time.Time) *StreamingCommand {
+	cmd.Payload.FromHistoricalTimestamp = timestamp.UTC().Format(time.RFC3339Nano)
+	return cmd
+}
+
+// ToTimestamp configures the point in time the Thing's history is to be streamed to.
+func (cmd *StreamingCommand) ToTimestamp(timestamp time.Time) *StreamingCommand {
+	cmd.Payload.ToHistoricalTimestamp = timestamp.UTC().Format(time.RFC3339Nano)
+	return cmd
+}
+
+// SubscribeForPersistedEvents sets the action of the command instance accordingly to subscribe for the Thing's
+// persisted events in the configured range of revisions or timestamps.
+func (cmd *StreamingCommand) SubscribeForPersistedEvents() *StreamingCommand {
+	cmd.Topic.WithAction(protocol.ActionSubscribeForPersistedEvents)
+	return cmd
+}
+
+// Request sets the action of the command instance accordingly to request the provided demand
+// of persisted events for the subscription with the provided subscriptionID.
+func (cmd *StreamingCommand) Request(subscriptionID string, demand int64) *StreamingCommand {
+	cmd.Topic.WithAction(protocol.ActionRequest)
+	cmd.Payload.SubscriptionID = subscriptionID
+	cmd.Payload.Demand = demand
+	return cmd
+}
+
+// Cancel sets the action of the command instance accordingly to cancel the subscription with the provided subscriptionID.
+func (cmd *StreamingCommand) Cancel(subscriptionID string) *StreamingCommand {
+	cmd.Topic.WithAction(protocol.ActionCancel)
+	cmd.Payload.SubscriptionID = subscriptionID
+	return cmd
+}
+
+// Envelope generates the Ditto envelope with streaming command's data applying all configurations and optionally all Headers provided.
+func (cmd *StreamingCommand) Envelope(headerOpts ...protocol.HeaderOpt) *protocol.Envelope {
+	msg := &protocol.Envelope{
+		Topic: cmd.Topic,
+		Path:  cmd.Path,
+		Value: cmd.Payload,
+	}
+	if headerOpts != nil {
+		msg.Headers = protocol.NewHeaders(headerOpts...)
+	}
+	return msg
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// StreamingEvent represents a streaming subscription event defined by the Ditto protocol for the Things group
+// that is received as a result of a StreamingCommand. Depending on its action it provides:
+// - created - the SubscriptionID of the newly created subscription
+// - next - the SubscriptionID along with a single persisted event of the Thing as Item
+// - complete - the SubscriptionID of the subscription for which all persisted events have been delivered
+// - failed - the SubscriptionID along with the Error payload describing the failure.
+type StreamingEvent struct {
+	Action         protocol.TopicAction    `json:"-"`
+	SubscriptionID string                  `json:"subscriptionId"`
+	Item           *protocol.Envelope      `json:"item,omitempty"`
+	Error          *protocol.ErrorResponse `json:"error,omitempty"`
+}
+
+// ParseStreamingEvent parses the provided Envelope into a StreamingEvent instance.
+// Returns an error if the Envelope is not a streaming subscription event or its value cannot be decoded.
+func ParseStreamingEvent(env *protocol.Envelope) (*StreamingEvent, error) {
+	if env == nil || env.Topic == nil {
+		return nil, errors.New("envelope without topic is not a streaming event")
+	}
+	if env.Topic.Group != protocol.GroupThings || env.Topic.Criterion != protocol.CriterionStreaming {
+		return nil, fmt.Errorf("envelope with topic '%s' is not a streaming event", env.Topic.String())
+	}
+	switch env.Topic.Action {
+	case protocol.ActionCreated, protocol.ActionNext, protocol.ActionComplete, protocol.ActionFailed:
+	default:
+		return nil, fmt.Errorf("unsupported streaming event action: %s", env.Topic.Action)
+	}
+
+	event := &StreamingEvent{}
+	if env.Value != nil {
+		if err := decodeValue(env.Value, event); err != nil {
+			return nil, err
+		}
+	}
+	event.Action = env.Topic.Action
+	return event, nil
+}
+
+// IsCreated returns true if the StreamingEvent notifies that a subscription has been created.
+func (event *StreamingEvent) IsCreated() bool {
+	return event.Action == protocol.ActionCreated
+}
+
+// IsNext returns true if the StreamingEvent provides a persisted event of the Thing.
+func (event *StreamingEvent) IsNext() bool {
+	return event.Action == protocol.ActionNext
+}
+
+// IsComplete returns true if the StreamingEvent notifies that all persisted events have been delivered.
+func (event *StreamingEvent) IsComplete() bool {
+	return event.Action == protocol.ActionComplete
+}
+
+// IsFailed returns true if the StreamingEvent notifies that the subscription has failed.
+func (event *StreamingEvent) IsFailed() bool {
+	return event.Action == protocol.ActionFailed
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func TestParseStreamingEventNext(t *testing.T) {
+	env := unmarshalEnvelope(t, `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/next","path":"/",
+		"value":{"subscriptionId":"sub-1","item":{"topic":"org.eclipse.ditto/thing/things/twin/events/modified",
+		"headers":{"correlation-id":"id"},"path":"/attributes/location","value":"kitchen","revision":4,
+		"timestamp":"2022-01-01T00:00:00Z"}}}`)
+
+	got, err := ParseStreamingEvent(env)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, got.IsNext())
+	internal.AssertEqual(t, "sub-1", got.SubscriptionID)
+	internal.AssertEqual(t, protocol.ActionModified, got.Item.Topic.Action)
+	internal.AssertEqual(t, "id", got.Item.Headers.CorrelationID())
+	internal.AssertEqual(t, "/attributes/location", got.Item.Path)
+	internal.AssertEqual(t, "kitchen", got.Item.Value)
+	internal.AssertEqual(t, int64(4), got.Item.Revision)
+	internal.AssertEqual(t, "2022-01-01T00:00:00Z", got.Item.Timestamp)
+}
+
+func TestParseStreamingEventActions(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want *StreamingEvent
+	}{
+		"test_created": {
+			data: `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/created","path":"/","value":{"subscriptionId":"sub-1"}}`,
+			want: &StreamingEvent{Action: protocol.ActionCreated, SubscriptionID: "sub-1"},
+		},
+		"test_complete": {
+			data: `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/complete","path":"/","value":{"subscriptionId":"sub-1"}}`,
+			want: &StreamingEvent{Action: protocol.ActionComplete, SubscriptionID: "sub-1"},
+		},
+		"test_failed": {
+			data: `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/failed","path":"/",
+				"value":{"subscriptionId":"sub-1","error":{"status":400,"error":"things:history.notaccessible"}}}`,
+			want: &StreamingEvent{
+				Action:         protocol.ActionFailed,
+				SubscriptionID: "sub-1",
+				Error: &protocol.ErrorResponse{
+					Status:    400,
+					ErrorCode: "things:history.notaccessible",
+				},
+			},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseStreamingEvent(unmarshalEnvelope(t, testCase.data))
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.want, got)
+			internal.AssertEqual(t, testCase.want.Action == protocol.ActionCreated, got.IsCreated())
+			internal.AssertEqual(t, testCase.want.Action == protocol.ActionComplete, got.IsComplete())
+			internal.AssertEqual(t, testCase.want.Action == protocol.ActionFailed, got.IsFailed())
+		})
+	}
+}
+
+func TestParseStreamingEventErrors(t *testing.T) {
+	tests := map[string]*protocol.Envelope{
+		"test_nil_envelope":     nil,
+		"test_without_topic":    {Path: "/"},
+		"test_search_event":     unmarshalEnvelope(t, `{"topic":"_/_/things/twin/search/next","path":"/"}`),
+		"test_unsupported":      unmarshalEnvelope(t, `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/request","path":"/"}`),
+		"test_invalid_value":    unmarshalEnvelope(t, `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/next","path":"/","value":"invalid"}`),
+		"test_invalid_item_val": unmarshalEnvelope(t, `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/next","path":"/","value":{"item":{"topic":"invalid"}}}`),
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseStreamingEvent(testCase)
+			internal.AssertNotNil(t, err)
+			internal.AssertNil(t, got)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+var testStreamingThingID = model.NewNamespacedID("org.eclipse.ditto", "thing")
+
+func TestNewStreamingCommand(t *testing.T) {
+	want := &StreamingCommand{
+		Topic: &protocol.Topic{
+			Namespace:  "org.eclipse.ditto",
+			EntityName: "thing",
+			Group:      protocol.GroupThings,
+			Channel:    protocol.ChannelTwin,
+			Criterion:  protocol.CriterionStreaming,
+		},
+		Path:    pathThing,
+		Payload: &StreamingPayload{},
+	}
+
+	got := NewStreamingCommand(testStreamingThingID)
+	internal.AssertEqual(t, want, got)
+}
+
+func TestStreamingCommandSubscribeForPersistedEvents(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 2, 12, 30, 0, 500000000, time.FixedZone("CET", 3600))
+
+	got := NewStreamingCommand(testStreamingThingID).
+		WithPath("/attributes").
+		FromRevision(1).
+		ToRevision(10).
+		FromTimestamp(from).
+		ToTimestamp(to).
+		SubscribeForPersistedEvents()
+
+	internal.AssertEqual(t, protocol.ActionSubscribeForPersistedEvents, got.Topic.Action)
+	internal.AssertEqual(t, "/attributes", got.Path)
+	internal.AssertEqual(t, &StreamingPayload{
+		FromHistoricalRevision:  1,
+		ToHistoricalRevision:    10,
+		FromHistoricalTimestamp: "2022-01-01T00:00:00Z",
+		ToHistoricalTimestamp:   "2022-01-02T11:30:00.5Z",
+	}, got.Payload)
+}
+
+func TestStreamingCommandRequest(t *testing.T) {
+	got := NewStreamingCommand(testStreamingThingID).Request("testSubscriptionID", 5)
+
+	internal.AssertEqual(t, protocol.ActionRequest, got.Topic.Action)
+	internal.AssertEqual(t, &StreamingPayload{SubscriptionID: "testSubscriptionID", Demand: 5}, got.Payload)
+}
+
+func TestStreamingCommandCancel(t *testing.T) {
+	got := NewStreamingCommand(testStreamingThingID).Cancel("testSubscriptionID")
+
+	internal.AssertEqual(t, protocol.ActionCancel, got.Topic.Action)
+	internal.AssertEqual(t, &StreamingPayload{SubscriptionID: "testSubscriptionID"}, got.Payload)
+}
+
+func TestStreamingCommandEnvelope(t *testing.T) {
+	env := NewStreamingCommand(testStreamingThingID).
+		FromRevision(3).
+		SubscribeForPersistedEvents().
+		Envelope(protocol.WithCorrelationID("testCorrelationID"))
+
+	internal.AssertEqual(t, "testCorrelationID", env.Headers.CorrelationID())
+	internal.AssertNil(t, env.Validate())
+
+	data, err := json.Marshal(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, `{"topic":"org.eclipse.ditto/thing/things/twin/streaming/subscribeForPersistedEvents",`+
+		`"headers":{"correlation-id":"testCorrelationID"},"path":"/","value":{"fromHistoricalRevision":3}}`, string(data))
+
+	internal.AssertNil(t, NewStreamingCommand(testStreamingThingID).Cancel("id").Envelope().Headers)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// TargetKind defines the kind of the Thing's entity a Ditto path refers to.
+type TargetKind string
+
+// Target kinds constants.
+const (
+	TargetThing                    TargetKind = "thing"
+	TargetPolicyID                 TargetKind = "policyId"
+	TargetDefinition               TargetKind = "definition"
+	TargetAttributes               TargetKind = "attributes"
+	TargetAttribute                TargetKind = "attribute"
+	TargetFeatures                 TargetKind = "features"
+	TargetFeature                  TargetKind = "feature"
+	TargetFeatureDefinition        TargetKind = "featureDefinition"
+	TargetFeatureProperties        TargetKind = "featureProperties"
+	TargetFeatureProperty          TargetKind = "featureProperty"
+	TargetFeatureDesiredProperties TargetKind = "featureDesiredProperties"
+	TargetFeatureDesiredProperty   TargetKind = "featureDesiredProperty"
+)
+
+// Target represents the Thing's entity a Ditto path refers to in a structured form.
+// The FeatureID is set for all feature targets and the Pointer - the JSON pointer of the attribute or property
+// within the Thing's attributes or the feature's (desired) properties, e.g. '/location/latitude',
+// is set for the attribute and the (desired) property targets.
+type Target struct {
+	Kind      TargetKind
+	FeatureID string
+	Pointer   string
+}
+
+// ParseTarget parses the provided Ditto path of a Thing's entity into a Target instance,
+// e.g. '/features/lamp/properties/on' is parsed into a feature property target of the 'lamp' feature with '/on' pointer.
+// Returns an error if the path doesn't refer to a Thing's entity.
+func ParseTarget(path string) (*Target, error) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(path) == 0 || path == pathThing {
+		return &Target{Kind: TargetThing}, nil
+	}
+
+	switch segments[0] {
+	case "policyId":
+		if len(segments) == 1 {
+			return &Target{Kind: TargetPolicyID}, nil
+		}
+	case "definition":
+		if len(segments) == 1 {
+			return &Target{Kind: TargetDefinition}, nil
+		}
+	case "attributes":
+		if len(segments) == 1 {
+			return &Target{Kind: TargetAttributes}, nil
+		}
+		if pointer, ok := targetPointer(segments[1:]); ok {
+			return &Target{Kind: TargetAttribute, Pointer: pointer}, nil
+		}
+	case "features":
+		if len(segments) == 1 {
+			return &Target{Kind: TargetFeatures}, nil
+		}
+		if len(segments[1]) > 0 {
+			return parseFeatureTarget(protocol.UnescapePointerToken(segments[1]), segments[2:], path)
+		}
+	}
+	return nil, fmt.Errorf("invalid Thing path '%s'", path)
+}
+
+func parseFeatureTarget(featureID string, segments []string, path string) (*Target, error) {
+	if len(segments) == 0 {
+		return &Target{Kind: TargetFeature, FeatureID: featureID}, nil
+	}
+
+	var kind, nestedKind TargetKind
+	switch segments[0] {
+	case "definition":
+		if len(segments) == 1 {
+			return &Target{Kind: TargetFeatureDefinition, FeatureID: featureID}, nil
+		}
+	case "properties":
+		kind, nestedKind = TargetFeatureProperties, TargetFeatureProperty
+	case "desiredProperties":
+		kind, nestedKind = TargetFeatureDesiredProperties, TargetFeatureDesiredProperty
+	}
+	if len(kind) > 0 {
+		if len(segments) == 1 {
+			return &Target{Kind: kind, FeatureID: featureID}, nil
+		}
+		if pointer, ok := targetPointer(segments[1:]); ok {
+			return &Target{Kind: nestedKind, FeatureID: featureID, Pointer: pointer}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid Thing path '%s'", path)
+}
+
+func targetPointer(segments []string) (string, bool) {
+	for _, segment := range segments {
+		if len(segment) == 0 {
+			return "", false
+		}
+	}
+	return "/" + strings.Join(segments, "/"), true
+}
+
+// Path provides the Ditto path of the Thing's entity the Target refers to.
+func (target *Target) Path() string {
+	featureID := protocol.EscapePointerToken(target.FeatureID)
+	switch target.Kind {
+	case TargetPolicyID:
+		return pathThingPolicyID
+	case TargetDefinition:
+		return pathThingDefinition
+	case TargetAttributes:
+		return pathThingAttributes
+	case TargetAttribute:
+		return pathThingAttributes + target.Pointer
+	case TargetFeatures:
+		return pathThingFeatures
+	case TargetFeature:
+		return fmt.Sprintf(pathThingFeatureFormat, featureID)
+	case TargetFeatureDefinition:
+		return fmt.Sprintf(pathThingFeatureDefinitionFormat, featureID)
+	case TargetFeatureProperties:
+		return fmt.Sprintf(pathThingFeaturePropertiesFormat, featureID)
+	case TargetFeatureProperty:
+		return fmt.Sprintf(pathThingFeaturePropertiesFormat, featureID) + target.Pointer
+	case TargetFeatureDesiredProperties:
+		return fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, featureID)
+	case TargetFeatureDesiredProperty:
+		return fmt.Sprintf(pathThingFeatureDesiredPropertiesFormat, featureID) + target.Pointer
+	default:
+		return pathThing
+	}
+}
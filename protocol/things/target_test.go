@@ -0,0 +1,59 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		want    *Target
+		wantErr bool
+	}{
+		"test_thing":                 {arg: "/", want: &Target{Kind: TargetThing}},
+		"test_policy_id":             {arg: "/policyId", want: &Target{Kind: TargetPolicyID}},
+		"test_definition":            {arg: "/definition", want: &Target{Kind: TargetDefinition}},
+		"test_attributes":            {arg: "/attributes", want: &Target{Kind: TargetAttributes}},
+		"test_attribute":             {arg: "/attributes/location/x", want: &Target{Kind: TargetAttribute, Pointer: "/location/x"}},
+		"test_features":              {arg: "/features", want: &Target{Kind: TargetFeatures}},
+		"test_feature":               {arg: "/features/lamp~11", want: &Target{Kind: TargetFeature, FeatureID: "lamp/1"}},
+		"test_feature_definition":    {arg: "/features/lamp/definition", want: &Target{Kind: TargetFeatureDefinition, FeatureID: "lamp"}},
+		"test_feature_properties":    {arg: "/features/lamp/properties", want: &Target{Kind: TargetFeatureProperties, FeatureID: "lamp"}},
+		"test_feature_property":      {arg: "/features/lamp/properties/on~1off", want: &Target{Kind: TargetFeatureProperty, FeatureID: "lamp", Pointer: "/on~1off"}},
+		"test_desired_properties":    {arg: "/features/lamp/desiredProperties", want: &Target{Kind: TargetFeatureDesiredProperties, FeatureID: "lamp"}},
+		"test_desired_property":      {arg: "/features/lamp/desiredProperties/a/b", want: &Target{Kind: TargetFeatureDesiredProperty, FeatureID: "lamp", Pointer: "/a/b"}},
+		"test_unknown":               {arg: "/unknown", wantErr: true},
+		"test_policy_id_nested":      {arg: "/policyId/x", wantErr: true},
+		"test_empty_attribute":       {arg: "/attributes/", wantErr: true},
+		"test_empty_feature_id":      {arg: "/features//properties", wantErr: true},
+		"test_unknown_feature_part":  {arg: "/features/lamp/unknown", wantErr: true},
+		"test_feature_definition_in": {arg: "/features/lamp/definition/0", wantErr: true},
+		"test_empty_property":        {arg: "/features/lamp/properties/a//b", wantErr: true},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseTarget(testCase.arg)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got)
+				internal.AssertEqual(t, testCase.arg, got.Path())
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+// ThingEvent represents a Thing's event defined by the Ditto protocol for the Things group, decoded from its Envelope.
+// The Value is decoded according to the Target of the event:
+// - *model.Thing for the Thing
+// - *model.NamespacedID for the Thing's policy ID
+// - *model.DefinitionID for the Thing's definition
+// - map[string]*model.Feature for the Thing's features
+// - *model.Feature for a single feature
+// - []*model.DefinitionID for a feature's definition
+// - map[string]interface{} for the Thing's attributes and a feature's (desired) properties
+// - the generic JSON value for a single attribute or a single feature's (desired) property.
+// The Value of the merged events is always the generic JSON value, as the merge patches may contain nulls
+// for the removed fields. The Value of the deleted events is always nil.
+type ThingEvent struct {
+	ThingID   *model.NamespacedID
+	Channel   protocol.TopicChannel
+	Action    protocol.TopicAction
+	Target    *Target
+	Value     interface{}
+	Revision  int64
+	Timestamp string
+}
+
+// ParseEvent parses the provided Envelope into a ThingEvent instance.
+// Returns an error if the Envelope is not a Thing's event or its value cannot be decoded.
+func ParseEvent(env *protocol.Envelope) (*ThingEvent, error) {
+	if env == nil || env.Topic == nil {
+		return nil, errors.New("envelope without topic is not a thing event")
+	}
+	if env.Topic.Group != protocol.GroupThings || env.Topic.Criterion != protocol.CriterionEvents {
+		return nil, fmt.Errorf("envelope with topic '%s' is not a thing event", env.Topic.String())
+	}
+	switch env.Topic.Action {
+	case protocol.ActionCreated, protocol.ActionModified, protocol.ActionMerged, protocol.ActionDeleted:
+	default:
+		return nil, fmt.Errorf("unsupported thing event action: %s", env.Topic.Action)
+	}
+	target, err := ParseTarget(env.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &ThingEvent{
+		ThingID:   model.NewNamespacedID(env.Topic.Namespace, env.Topic.EntityName),
+		Channel:   env.Topic.Channel,
+		Action:    env.Topic.Action,
+		Target:    target,
+		Revision:  env.Revision,
+		Timestamp: env.Timestamp,
+	}
+	if env.Value == nil || event.Action == protocol.ActionDeleted {
+		return event, nil
+	}
+	if event.Action == protocol.ActionMerged {
+		err = env.ValueAs(&event.Value)
+	} else {
+		event.Value, err = decodeTargetValue(env, target.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func decodeTargetValue(env *protocol.Envelope, kind TargetKind) (interface{}, error) {
+	var value interface{}
+	switch kind {
+	case TargetThing:
+		value = &model.Thing{}
+	case TargetPolicyID:
+		value = &model.NamespacedID{}
+	case TargetDefinition:
+		value = &model.DefinitionID{}
+	case TargetFeatures:
+		features := make(map[string]*model.Feature)
+		value = &features
+	case TargetFeature:
+		value = &model.Feature{}
+	case TargetFeatureDefinition:
+		var definition []*model.DefinitionID
+		value = &definition
+	case TargetAttributes, TargetFeatureProperties, TargetFeatureDesiredProperties:
+		properties := make(map[string]interface{})
+		value = &properties
+	default:
+		var generic interface{}
+		value = &generic
+	}
+	if err := env.ValueAs(value); err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case *map[string]*model.Feature:
+		return *v, nil
+	case *[]*model.DefinitionID:
+		return *v, nil
+	case *map[string]interface{}:
+		return *v, nil
+	case *interface{}:
+		return *v, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package things
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+	"github.com/eclipse/ditto-clients-golang/protocol"
+)
+
+func parseTestEnvelope(t *testing.T, data string) *protocol.Envelope {
+	env := &protocol.Envelope{}
+	internal.AssertNil(t, json.Unmarshal([]byte(data), env))
+	return env
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := map[string]struct {
+		arg        string
+		wantTarget *Target
+		wantValue  interface{}
+	}{
+		"test_thing_created": {
+			arg:        `{"topic":"ns/thing/things/twin/events/created","path":"/","value":{"thingId":"ns:thing","attributes":{"a":1}}}`,
+			wantTarget: &Target{Kind: TargetThing},
+			wantValue:  (&model.Thing{}).WithIDFrom("ns:thing").WithAttribute("a", float64(1)),
+		},
+		"test_policy_id_modified": {
+			arg:        `{"topic":"ns/thing/things/twin/events/modified","path":"/policyId","value":"ns:policy"}`,
+			wantTarget: &Target{Kind: TargetPolicyID},
+			wantValue:  model.NewNamespacedIDFrom("ns:policy"),
+		},
+		"test_definition_modified": {
+			arg:        `{"topic":"ns/thing/things/twin/events/modified","path":"/definition","value":"ns:model:1.0.0"}`,
+			wantTarget: &Target{Kind: TargetDefinition},
+			wantValue:  model.NewDefinitionIDFrom("ns:model:1.0.0"),
+		},
+		"test_features_modified": {
+			arg:        `{"topic":"ns/thing/things/twin/events/modified","path":"/features","value":{"lamp":{"properties":{"on":true}}}}`,
+			wantTarget: &Target{Kind: TargetFeatures},
+			wantValue:  map[string]*model.Feature{"lamp": (&model.Feature{}).WithProperty("on", true)},
+		},
+		"test_feature_created": {
+			arg:        `{"topic":"ns/thing/things/twin/events/created","path":"/features/lamp","value":{"properties":{"on":true}}}`,
+			wantTarget: &Target{Kind: TargetFeature, FeatureID: "lamp"},
+			wantValue:  (&model.Feature{}).WithProperty("on", true),
+		},
+		"test_feature_definition_modified": {
+			arg:        `{"topic":"ns/thing/things/twin/events/modified","path":"/features/lamp/definition","value":["ns:lamp:1.0.0"]}`,
+			wantTarget: &Target{Kind: TargetFeatureDefinition, FeatureID: "lamp"},
+			wantValue:  []*model.DefinitionID{model.NewDefinitionIDFrom("ns:lamp:1.0.0")},
+		},
+		"test_attributes_modified": {
+			arg:        `{"topic":"ns/thing/things/live/events/modified","path":"/attributes","value":{"a":"b"}}`,
+			wantTarget: &Target{Kind: TargetAttributes},
+			wantValue:  map[string]interface{}{"a": "b"},
+		},
+		"test_feature_property_modified": {
+			arg:        `{"topic":"ns/thing/things/twin/events/modified","path":"/features/lamp/properties/on","value":false}`,
+			wantTarget: &Target{Kind: TargetFeatureProperty, FeatureID: "lamp", Pointer: "/on"},
+			wantValue:  false,
+		},
+		"test_thing_merged": {
+			arg:        `{"topic":"ns/thing/things/twin/events/merged","path":"/","value":{"attributes":{"a":null}}}`,
+			wantTarget: &Target{Kind: TargetThing},
+			wantValue:  map[string]interface{}{"attributes": map[string]interface{}{"a": nil}},
+		},
+		"test_attribute_deleted": {
+			arg:        `{"topic":"ns/thing/things/twin/events/deleted","path":"/attributes/a"}`,
+			wantTarget: &Target{Kind: TargetAttribute, Pointer: "/a"},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			env := parseTestEnvelope(t, testCase.arg)
+			got, err := ParseEvent(env)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, model.NewNamespacedID("ns", "thing"), got.ThingID)
+			internal.AssertEqual(t, env.Topic.Channel, got.Channel)
+			internal.AssertEqual(t, env.Topic.Action, got.Action)
+			internal.AssertEqual(t, testCase.wantTarget, got.Target)
+			internal.AssertEqual(t, testCase.wantValue, got.Value)
+		})
+	}
+}
+
+func TestParseEventRevision(t *testing.T) {
+	env := parseTestEnvelope(t, `{"topic":"ns/thing/things/twin/events/deleted","path":"/","revision":3,"timestamp":"2022-01-01T00:00:00Z"}`)
+
+	got, err := ParseEvent(env)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, int64(3), got.Revision)
+	internal.AssertEqual(t, "2022-01-01T00:00:00Z", got.Timestamp)
+}
+
+func TestParseEventErrors(t *testing.T) {
+	tests := map[string]struct {
+		arg *protocol.Envelope
+	}{
+		"test_nil":          {arg: nil},
+		"test_no_topic":     {arg: &protocol.Envelope{}},
+		"test_command":      {arg: NewCommand(testNamespaceID).Delete().Envelope()},
+		"test_action":       {arg: &protocol.Envelope{Topic: NewEvent(testNamespaceID).Topic.WithAction(protocol.ActionNext), Path: "/"}},
+		"test_invalid_path": {arg: NewEvent(testNamespaceID).Deleted().Envelope().WithPath("/unknown")},
+		"test_invalid_value": {
+			arg: NewEvent(testNamespaceID).Feature(testFeatureID).Modified("not a feature").Envelope(),
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := ParseEvent(testCase.arg)
+			internal.AssertNotNil(t, err)
+		})
+	}
+}
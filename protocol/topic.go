@@ -15,7 +15,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"path"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/eclipse/ditto-clients-golang/model"
 )
@@ -34,6 +36,10 @@ const (
 	CriterionMessages TopicCriterion = "messages"
 	// CriterionErrors represents the errors topic criterion.
 	CriterionErrors TopicCriterion = "errors"
+	// CriterionAcks represents the acknowledgements topic criterion.
+	CriterionAcks TopicCriterion = "acks"
+	// CriterionStreaming represents the streaming subscriptions topic criterion.
+	CriterionStreaming TopicCriterion = "streaming"
 )
 
 // TopicChannel is a representation of the defined by Ditto topic channel options.
@@ -66,6 +72,8 @@ const (
 	ActionNext      TopicAction = "next"
 	ActionComplete  TopicAction = "complete"
 	ActionFailed    TopicAction = "failed"
+
+	ActionSubscribeForPersistedEvents TopicAction = "subscribeForPersistedEvents"
 )
 
 // TopicGroup is a representation of the defined by Ditto topic group options.
@@ -82,13 +90,12 @@ const (
 const TopicPlaceholder = "_"
 
 const (
-	topicFormatPolicies       = "%s/%s/%s/%s/%s"
-	topicFormatThings         = "%s/%s/%s/%s/%s/%s"
-	topicFormatThingsNoAction = "%s/%s/%s/%s/%s"
+	topicFormatPolicies         = "%s/%s/%s/%s/%s"
+	topicFormatPoliciesNoAction = "%s/%s/%s/%s"
+	topicFormatThings           = "%s/%s/%s/%s/%s/%s"
+	topicFormatThingsNoAction   = "%s/%s/%s/%s/%s"
 )
 
-var regexTopic = regexp.MustCompile("^([^/]+)/([^/]+)/(" + string(GroupThings) + "|" + string(GroupPolicies) + ")/([^/]+)/([^/]+)(/([^/]{1}.*))?$")
-
 // Topic represents the Ditto protocol's Topic entity. It's represented in the form of:
 // <namespace>/<entity-name>/<group>/<channel>/<criterion>/<action>.
 // Each of the components is configurable based on the Ditto's specification for the specific group and/or channel/criterion/etc.
@@ -110,17 +117,45 @@ func (topic *Topic) String() string {
 		}
 		return fmt.Sprintf(topicFormatThings, topic.Namespace, topic.EntityName, topic.Group, topic.Channel, topic.Criterion, topic.Action)
 	case GroupPolicies:
+		if len(topic.Action) == 0 {
+			return fmt.Sprintf(topicFormatPoliciesNoAction, topic.Namespace, topic.EntityName, topic.Group, topic.Criterion)
+		}
 		return fmt.Sprintf(topicFormatPolicies, topic.Namespace, topic.EntityName, topic.Group, topic.Criterion, topic.Action)
 	default:
 		return ""
 	}
 }
 
+// Matches reports whether the Topic matches the provided pattern, e.g. 'my.namespace/*/things/twin/events/*'.
+// The pattern is matched segment by segment, where each segment supports the wildcards of path.Match,
+// e.g. '*' matches any single segment and 'sensor-*' any segment with the 'sensor-' prefix.
+// A trailing '*' segment matches all remaining segments, so that actions containing slashes (like message subjects) are matched as well.
+// Malformed patterns never match.
+func (topic *Topic) Matches(pattern string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	topicSegments := strings.Split(topic.String(), "/")
+
+	last := len(patternSegments) - 1
+	if patternSegments[last] == "*" && len(topicSegments) > len(patternSegments) {
+		// the trailing wildcard consumes all remaining topic segments
+		patternSegments = patternSegments[:last]
+		topicSegments = topicSegments[:last]
+	}
+	if len(patternSegments) != len(topicSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if matched, err := path.Match(segment, topicSegments[i]); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // MarshalJSON marshals Topic.
 func (topic *Topic) MarshalJSON() ([]byte, error) {
 	topicStr := topic.String()
-	matches := regexTopic.FindAllStringSubmatch(topicStr, -1)
-	if matches == nil {
+	if _, ok := splitTopic(topicStr); !ok {
 		return nil, errors.New("invalid topic: " + topicStr)
 	}
 	return json.Marshal(topicStr)
@@ -132,38 +167,193 @@ func (topic *Topic) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &v); err != nil {
 		return err
 	}
-	matches := regexTopic.FindAllStringSubmatch(v, -1)
-	if matches == nil {
-		return errors.New("invalid topic: " + v)
+	parsed, err := ParseTopic(v)
+	if err != nil {
+		return err
+	}
+	*topic = *parsed
+	return nil
+}
+
+// ParseTopic parses and validates the provided Ditto topic string, e.g. 'org.eclipse.ditto/thing/things/twin/commands/modify'.
+// An error is returned if the topic is not compliant with the Ditto protocol specification.
+func ParseTopic(topic string) (*Topic, error) {
+	elements, ok := splitTopic(topic)
+	if !ok {
+		return nil, errors.New("invalid topic: " + topic)
 	}
 
-	elements := matches[0]
-	ns := elements[1]
-	name := elements[2]
+	ns := elements[topicNamespace]
+	name := elements[topicEntityName]
 
 	if err := validateNamespacedID(ns, name); err != nil {
-		return err
+		return nil, err
 	}
 
-	topic.Namespace = ns
-	topic.EntityName = name
-	topic.Group = TopicGroup(elements[3])
+	res := &Topic{
+		Namespace:  ns,
+		EntityName: name,
+		Group:      TopicGroup(elements[topicGroup]),
+	}
 
-	switch topic.Group {
+	switch res.Group {
 	case GroupThings:
-		topic.Channel = TopicChannel(elements[4])
-		topic.Criterion = TopicCriterion(elements[5])
-		topic.Action = TopicAction(elements[7])
+		if len(elements[topicSecond]) == 0 {
+			return nil, errors.New("invalid topic: " + topic)
+		}
+		res.Channel = TopicChannel(elements[topicFirst])
+		res.Criterion = TopicCriterion(elements[topicSecond])
+		res.Action = TopicAction(elements[topicRest])
 	case GroupPolicies:
 		// skip channel - not supported for policies group
-		topic.Channel = ""
-		topic.Criterion = TopicCriterion(elements[4])
-		topic.Action = TopicAction(elements[5])
+		res.Criterion = TopicCriterion(elements[topicFirst])
+		res.Action = TopicAction(elements[topicSecond])
 	default:
-		return errors.New("unsupported topic group provided for topic: " + v)
+		return nil, errors.New("unsupported topic group provided for topic: " + topic)
 	}
 
-	return nil
+	return res, nil
+}
+
+// The elements of a topic string as split by splitTopic.
+const (
+	topicNamespace = iota
+	topicEntityName
+	topicGroup
+	// the channel for the things group or the criterion for the policies group
+	topicFirst
+	// the criterion for the things group or the action for the policies group
+	topicSecond
+	// the action for the things group, which might contain slashes, e.g. as a message subject
+	topicRest
+	topicElements
+)
+
+// splitTopic splits the provided topic string into its elements without a regular expression, as it's done for each
+// incoming message. The topic consists of a namespace, an entity name, a group, which is either 'things' or 'policies',
+// and a non-empty element, optionally followed by a non-empty element without slashes and/or by a rest, which doesn't
+// start with a slash and contains no new lines except in its first character. Each of the first elements is non-empty
+// and contains no slashes. If both optional elements cannot be matched, the whole remainder is treated as the rest.
+func splitTopic(topic string) ([topicElements]string, bool) {
+	var elements [topicElements]string
+	remainder := topic
+	for i := topicNamespace; i <= topicFirst; i++ {
+		end := strings.IndexByte(remainder, '/')
+		if i == topicFirst && end < 0 {
+			end = len(remainder)
+		}
+		if end <= 0 {
+			return elements, false
+		}
+		elements[i] = remainder[:end]
+		remainder = remainder[end:]
+		if i < topicFirst {
+			remainder = remainder[1:]
+		}
+	}
+	if elements[topicGroup] != string(GroupThings) && elements[topicGroup] != string(GroupPolicies) {
+		return elements, false
+	}
+	if len(remainder) == 0 {
+		return elements, true
+	}
+
+	// the remainder starts with a slash
+	second := remainder[1:]
+	if end := strings.IndexByte(second, '/'); end >= 0 {
+		second = second[:end]
+	}
+	if rest := remainder[1+len(second):]; len(second) > 0 && (len(rest) == 0 || isTopicRest(rest)) {
+		elements[topicSecond] = second
+		if len(rest) > 0 {
+			elements[topicRest] = rest[1:]
+		}
+		return elements, true
+	}
+	if isTopicRest(remainder) {
+		elements[topicRest] = remainder[1:]
+		return elements, true
+	}
+	return elements, false
+}
+
+// isTopicRest checks that the provided string is a slash followed by a character other than a slash
+// and any characters other than new lines.
+func isTopicRest(rest string) bool {
+	if len(rest) < 2 || rest[0] != '/' || rest[1] == '/' {
+		return false
+	}
+	_, size := utf8.DecodeRuneInString(rest[1:])
+	return strings.IndexByte(rest[1+size:], '\n') < 0
+}
+
+var (
+	thingCommandActions  = []TopicAction{ActionCreate, ActionModify, ActionMerge, ActionDelete, ActionRetrieve}
+	thingEventActions    = []TopicAction{ActionCreated, ActionModified, ActionMerged, ActionDeleted}
+	policyCommandActions = []TopicAction{ActionCreate, ActionModify, ActionDelete, ActionRetrieve}
+)
+
+// NewThingTwinCommandTopic creates a new things twin commands Topic validating the provided namespace, name and action.
+func NewThingTwinCommandTopic(ns, name string, action TopicAction) (*Topic, error) {
+	return newTopic(ns, name, GroupThings, ChannelTwin, CriterionCommands, action, thingCommandActions)
+}
+
+// NewThingLiveCommandTopic creates a new things live commands Topic validating the provided namespace, name and action.
+func NewThingLiveCommandTopic(ns, name string, action TopicAction) (*Topic, error) {
+	return newTopic(ns, name, GroupThings, ChannelLive, CriterionCommands, action, thingCommandActions)
+}
+
+// NewThingTwinEventTopic creates a new things twin events Topic validating the provided namespace, name and action.
+func NewThingTwinEventTopic(ns, name string, action TopicAction) (*Topic, error) {
+	return newTopic(ns, name, GroupThings, ChannelTwin, CriterionEvents, action, thingEventActions)
+}
+
+// NewThingLiveEventTopic creates a new things live events Topic validating the provided namespace, name and action.
+func NewThingLiveEventTopic(ns, name string, action TopicAction) (*Topic, error) {
+	return newTopic(ns, name, GroupThings, ChannelLive, CriterionEvents, action, thingEventActions)
+}
+
+// NewThingMessageTopic creates a new things live messages Topic validating the provided namespace, name and message subject.
+func NewThingMessageTopic(ns, name, subject string) (*Topic, error) {
+	return newTopic(ns, name, GroupThings, ChannelLive, CriterionMessages, TopicAction(subject), nil)
+}
+
+// NewPolicyCommandTopic creates a new policies commands Topic validating the provided namespace, name and action.
+func NewPolicyCommandTopic(ns, name string, action TopicAction) (*Topic, error) {
+	return newTopic(ns, name, GroupPolicies, "", CriterionCommands, action, policyCommandActions)
+}
+
+func newTopic(ns, name string, group TopicGroup, channel TopicChannel, criterion TopicCriterion,
+	action TopicAction, allowedActions []TopicAction) (*Topic, error) {
+	if (ns == TopicPlaceholder || name == TopicPlaceholder) && action != ActionRetrieve {
+		return nil, fmt.Errorf("topic placeholders are only allowed for the %s action", ActionRetrieve)
+	}
+	if err := validateNamespacedID(ns, name); err != nil {
+		return nil, err
+	}
+	if len(action) == 0 {
+		return nil, fmt.Errorf("missing action for %s %s", group, criterion)
+	}
+	if allowedActions != nil && !containsAction(allowedActions, action) {
+		return nil, fmt.Errorf("unsupported action '%s' for %s %s", action, group, criterion)
+	}
+	return &Topic{
+		Namespace:  ns,
+		EntityName: name,
+		Group:      group,
+		Channel:    channel,
+		Criterion:  criterion,
+		Action:     action,
+	}, nil
+}
+
+func containsAction(actions []TopicAction, action TopicAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
 }
 
 func validateNamespacedID(ns, entityName string) error {
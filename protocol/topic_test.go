@@ -14,6 +14,9 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/eclipse/ditto-clients-golang/internal"
@@ -104,6 +107,16 @@ func TestTopicMarshalJSON(t *testing.T) {
 			want:          `"namespace/test/policies/commands/modify"`,
 			expectedError: nil,
 		},
+		"test_marshalJSON_without_action_policies": {
+			topic: &Topic{
+				Namespace:  "namespace",
+				EntityName: "test",
+				Group:      GroupPolicies,
+				Criterion:  CriterionErrors,
+			},
+			want:          `"namespace/test/policies/errors"`,
+			expectedError: nil,
+		},
 		"test_marshalJSON_without_namespace": {
 			topic: &Topic{
 				EntityName: "test",
@@ -186,6 +199,8 @@ func TestTopicValidUnmarshalJSON(t *testing.T) {
 	tests := []string{
 		`"namespace/test/things/twin/messages/subscribe"`,
 		`"namespace/test/policies/commands/create"`,
+		`"namespace/test/policies/errors"`,
+		`"namespace/test/things/twin/errors"`,
 		`"namespace/test/things/live/messages/$set.configuration/name"`,
 		`"namespace/test/things/live/messages/$refresh.name"`,
 		`"namespace/test/things/live/messages/$refresh"`,
@@ -222,6 +237,247 @@ func TestTopicInvalidUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestParseTopic(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		want    *Topic
+		wantErr bool
+	}{
+		"test_things_command": {
+			arg: "namespace/test/things/twin/commands/modify",
+			want: &Topic{
+				Namespace:  "namespace",
+				EntityName: "test",
+				Group:      GroupThings,
+				Channel:    ChannelTwin,
+				Criterion:  CriterionCommands,
+				Action:     ActionModify,
+			},
+		},
+		"test_things_message_with_slashes": {
+			arg: "namespace/test/things/live/messages/$set.configuration/name",
+			want: &Topic{
+				Namespace:  "namespace",
+				EntityName: "test",
+				Group:      GroupThings,
+				Channel:    ChannelLive,
+				Criterion:  CriterionMessages,
+				Action:     "$set.configuration/name",
+			},
+		},
+		"test_policies_errors": {
+			arg: "namespace/test/policies/errors",
+			want: &Topic{
+				Namespace:  "namespace",
+				EntityName: "test",
+				Group:      GroupPolicies,
+				Criterion:  CriterionErrors,
+			},
+		},
+		"test_placeholders": {
+			arg: "_/_/things/twin/search/subscribe",
+			want: &Topic{
+				Namespace:  TopicPlaceholder,
+				EntityName: TopicPlaceholder,
+				Group:      GroupThings,
+				Channel:    ChannelTwin,
+				Criterion:  CriterionSearch,
+				Action:     ActionSubscribe,
+			},
+		},
+		"test_missing_criterion": {
+			arg:     "namespace/test/things/twin",
+			wantErr: true,
+		},
+		"test_unsupported_group": {
+			arg:     "namespace/test/connections/commands/create",
+			wantErr: true,
+		},
+		"test_invalid_namespace": {
+			arg:     "name space/test/things/twin/commands/modify",
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := ParseTopic(testCase.arg)
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				internal.AssertNil(t, got)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got)
+				internal.AssertEqual(t, testCase.arg, got.String())
+			}
+		})
+	}
+}
+
+func TestTopicMatches(t *testing.T) {
+	eventTopic := &Topic{
+		Namespace:  "my.ns",
+		EntityName: "thing",
+		Group:      GroupThings,
+		Channel:    ChannelTwin,
+		Criterion:  CriterionEvents,
+		Action:     ActionModified,
+	}
+	messageTopic := &Topic{
+		Namespace:  "my.ns",
+		EntityName: "sensor-1",
+		Group:      GroupThings,
+		Channel:    ChannelLive,
+		Criterion:  CriterionMessages,
+		Action:     "$set.configuration/name",
+	}
+	errorTopic := &Topic{
+		Namespace:  "my.ns",
+		EntityName: "policy",
+		Group:      GroupPolicies,
+		Criterion:  CriterionErrors,
+	}
+
+	tests := map[string]struct {
+		topic   *Topic
+		pattern string
+		want    bool
+	}{
+		"test_exact": {
+			topic:   eventTopic,
+			pattern: "my.ns/thing/things/twin/events/modified",
+			want:    true,
+		},
+		"test_wildcards": {
+			topic:   eventTopic,
+			pattern: "my.ns/*/things/twin/events/*",
+			want:    true,
+		},
+		"test_segment_prefix": {
+			topic:   messageTopic,
+			pattern: "my.ns/sensor-*/things/live/messages/*",
+			want:    true,
+		},
+		"test_trailing_wildcard_multiple_segments": {
+			topic:   messageTopic,
+			pattern: "*/*/things/live/*",
+			want:    true,
+		},
+		"test_different_criterion": {
+			topic:   eventTopic,
+			pattern: "my.ns/*/things/twin/commands/*",
+			want:    false,
+		},
+		"test_different_namespace": {
+			topic:   eventTopic,
+			pattern: "other.ns/*/things/twin/events/*",
+			want:    false,
+		},
+		"test_missing_action": {
+			topic:   errorTopic,
+			pattern: "my.ns/*/policies/errors/*",
+			want:    false,
+		},
+		"test_without_action": {
+			topic:   errorTopic,
+			pattern: "my.ns/*/policies/errors",
+			want:    true,
+		},
+		"test_shorter_pattern": {
+			topic:   eventTopic,
+			pattern: "my.ns/*/things/twin/events",
+			want:    false,
+		},
+		"test_malformed_pattern": {
+			topic:   eventTopic,
+			pattern: "my.ns/[/things/twin/events/*",
+			want:    false,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, testCase.topic.Matches(testCase.pattern))
+		})
+	}
+}
+
+func TestNewTopic(t *testing.T) {
+	tests := map[string]struct {
+		create  func() (*Topic, error)
+		want    string
+		wantErr bool
+	}{
+		"test_thing_twin_command": {
+			create: func() (*Topic, error) { return NewThingTwinCommandTopic("ns", "thing", ActionMerge) },
+			want:   "ns/thing/things/twin/commands/merge",
+		},
+		"test_thing_live_command": {
+			create: func() (*Topic, error) { return NewThingLiveCommandTopic("ns", "thing", ActionRetrieve) },
+			want:   "ns/thing/things/live/commands/retrieve",
+		},
+		"test_thing_twin_event": {
+			create: func() (*Topic, error) { return NewThingTwinEventTopic("ns", "thing", ActionModified) },
+			want:   "ns/thing/things/twin/events/modified",
+		},
+		"test_thing_live_event": {
+			create: func() (*Topic, error) { return NewThingLiveEventTopic("ns", "thing", ActionDeleted) },
+			want:   "ns/thing/things/live/events/deleted",
+		},
+		"test_thing_message": {
+			create: func() (*Topic, error) { return NewThingMessageTopic("ns", "thing", "$set.configuration/name") },
+			want:   "ns/thing/things/live/messages/$set.configuration/name",
+		},
+		"test_policy_command": {
+			create: func() (*Topic, error) { return NewPolicyCommandTopic("ns", "policy", ActionCreate) },
+			want:   "ns/policy/policies/commands/create",
+		},
+		"test_retrieve_with_placeholders": {
+			create: func() (*Topic, error) {
+				return NewThingTwinCommandTopic(TopicPlaceholder, TopicPlaceholder, ActionRetrieve)
+			},
+			want: "_/_/things/twin/commands/retrieve",
+		},
+		"test_modify_with_placeholders": {
+			create:  func() (*Topic, error) { return NewThingTwinCommandTopic(TopicPlaceholder, "thing", ActionModify) },
+			wantErr: true,
+		},
+		"test_command_with_event_action": {
+			create:  func() (*Topic, error) { return NewThingTwinCommandTopic("ns", "thing", ActionModified) },
+			wantErr: true,
+		},
+		"test_event_with_command_action": {
+			create:  func() (*Topic, error) { return NewThingTwinEventTopic("ns", "thing", ActionModify) },
+			wantErr: true,
+		},
+		"test_policy_merge": {
+			create:  func() (*Topic, error) { return NewPolicyCommandTopic("ns", "policy", ActionMerge) },
+			wantErr: true,
+		},
+		"test_message_without_subject": {
+			create:  func() (*Topic, error) { return NewThingMessageTopic("ns", "thing", "") },
+			wantErr: true,
+		},
+		"test_invalid_namespace": {
+			create:  func() (*Topic, error) { return NewThingTwinCommandTopic("n s", "thing", ActionModify) },
+			wantErr: true,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got, err := testCase.create()
+			if testCase.wantErr {
+				internal.AssertNotNil(t, err)
+				internal.AssertNil(t, got)
+			} else {
+				internal.AssertNil(t, err)
+				internal.AssertEqual(t, testCase.want, got.String())
+			}
+		})
+	}
+}
+
 func TestTopicNamespace(t *testing.T) {
 	var (
 		testValidNamespace    = "namespace"
@@ -380,3 +636,44 @@ func TestTopicWithAction(t *testing.T) {
 		internal.AssertEqual(t, arg, got.Action)
 	})
 }
+
+// The regular expression formerly used for parsing the topics, kept as the reference splitTopic is validated against.
+var regexTopic = regexp.MustCompile("^([^/]+)/([^/]+)/(" + string(GroupThings) + "|" + string(GroupPolicies) + ")/([^/]+)(/([^/]+))?(/([^/]{1}.*))?$")
+
+func TestSplitTopicMatchesRegex(t *testing.T) {
+	inputs := []string{
+		"ns/name/things/twin/commands/modify",
+		"ns/name/things/live/messages/subject/with/slashes",
+		"ns/name/things/twin/events/",
+		"ns/name/things/twin//modify",
+		"ns/name/things/twin/commands/\nmodify",
+		"ns/name/things/twin/commands/mod\nify",
+		"ns/name/policies/commands/create",
+		"ns/name/policies/commands",
+		"ns/name/things/twin",
+		"ns/name/things/",
+		"ns//things/twin/commands",
+		"ns/name/other/twin/commands",
+	}
+	elements := []string{"ns", "name", "things", "policies", "twin", "commands", "a", "/", "//", "\n", "é", "\xff"}
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		var input strings.Builder
+		for j := random.Intn(14); j >= 0; j-- {
+			input.WriteString(elements[random.Intn(len(elements))])
+		}
+		inputs = append(inputs, input.String())
+	}
+
+	for _, input := range inputs {
+		got, ok := splitTopic(input)
+		var want [topicElements]string
+		matches := regexTopic.FindStringSubmatch(input)
+		if matches != nil {
+			want = [topicElements]string{matches[1], matches[2], matches[3], matches[4], matches[6], matches[8]}
+		}
+		if ok != (matches != nil) || (ok && got != want) {
+			t.Errorf("topic %q: expected %q (%v), got %q (%v)", input, want, matches != nil, got, ok)
+		}
+	}
+}
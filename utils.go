@@ -13,45 +13,202 @@ package ditto
 
 import (
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
 	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/eclipse/ditto-clients-golang/protocol"
 )
 
-var regexHonoMQTTTopicRequest, _ = regexp.Compile("^command///req/([^/]+)/([^/]+)$")
-
 const (
-	honoMQTTTopicCommandResponseFormat = "command///res/%s/%d"
+	honoMQTTTopicCommandPrefix         = "command/"
+	honoMQTTTopicCommandRequest        = "req/"
+	honoMQTTTopicCommandResponseFormat = "command/%s/%s/res/%s/%d"
 )
 
+// extractHonoRequestID provides the request ID of the provided Hono command topic in the form of
+// 'command/<tenant-id>/<device-id>/req/<request-id>/<command>', where the tenant and device IDs are empty
+// in the short form of the topic and the other elements are non-empty. None of the elements contain slashes.
+// An empty string is returned for any other topic.
 func extractHonoRequestID(honoTopic string) string {
-	if regexHonoMQTTTopicRequest.MatchString(honoTopic) {
-		reqIDInfo := regexHonoMQTTTopicRequest.FindStringSubmatch(honoTopic)
-		return reqIDInfo[1]
+	honoTopic = stripSharePrefix(honoTopic)
+	if !strings.HasPrefix(honoTopic, honoMQTTTopicCommandPrefix) {
+		return ""
 	}
-	return ""
+	elements := honoTopic[len(honoMQTTTopicCommandPrefix):]
+	// skip the tenant and device IDs
+	for i := 0; i < 2; i++ {
+		end := strings.IndexByte(elements, '/')
+		if end < 0 {
+			return ""
+		}
+		elements = elements[end+1:]
+	}
+	if !strings.HasPrefix(elements, honoMQTTTopicCommandRequest) {
+		return ""
+	}
+	elements = elements[len(honoMQTTTopicCommandRequest):]
+	end := strings.IndexByte(elements, '/')
+	if end <= 0 || end == len(elements)-1 || strings.IndexByte(elements[end+1:], '/') >= 0 {
+		return ""
+	}
+	return elements[:end]
+}
+
+// sharedTopic provides the provided topic filter as the one of an MQTT shared subscription for the provided group,
+// or as is if no group is provided.
+func sharedTopic(group string, topic string) string {
+	if group == "" {
+		return topic
+	}
+	return mqttTopicSharePrefix + group + "/" + topic
+}
+
+// stripSharePrefix provides the provided topic without its MQTT shared subscription's '$share/<group>/' prefix, if any.
+func stripSharePrefix(topic string) string {
+	if !strings.HasPrefix(topic, mqttTopicSharePrefix) {
+		return topic
+	}
+	group := topic[len(mqttTopicSharePrefix):]
+	end := strings.IndexByte(group, '/')
+	if end < 0 {
+		return topic
+	}
+	return group[end+1:]
 }
 
 func generateHonoResponseTopic(requestID string, status int) string {
-	return fmt.Sprintf(honoMQTTTopicCommandResponseFormat, requestID, status)
+	return generateHonoDeviceResponseTopic("", "", requestID, status)
+}
+
+// generateHonoDeviceResponseTopic provides the response topic including the provided tenant and device IDs,
+// i.e. the short form of the topic if both are empty.
+func generateHonoDeviceResponseTopic(tenantID string, deviceID string, requestID string, status int) string {
+	return fmt.Sprintf(honoMQTTTopicCommandResponseFormat, tenantID, deviceID, requestID, status)
 }
 
-func getEnvelope(mqttPayload []byte) (*protocol.Envelope, error) {
+// getEnvelope decodes the provided JSON or CBOR encoded MQTT payload with the provided protocol.Decoder,
+// the zero one if nil is provided.
+func getEnvelope(mqttPayload []byte, decoder *protocol.Decoder) (*protocol.Envelope, error) {
+	if decoder == nil {
+		decoder = &protocol.Decoder{}
+	}
 	env := &protocol.Envelope{Headers: protocol.NewHeaders()}
-	if err := json.Unmarshal(mqttPayload, env); err != nil {
+	if protocol.IsCBOR(mqttPayload) {
+		if err := decoder.DecodeCBOR(mqttPayload, env); err != nil {
+			return nil, err
+		}
+	} else if err := decoder.Decode(mqttPayload, env); err != nil {
 		return nil, err
 	}
 	return env, nil
 }
 
-// Get the function name of a handler
-func getHandlerName(handler Handler) string {
-	return runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+// Get a copy of the envelope with the 'creation-time' header set to now, if not already present
+func withCreationTime(message *protocol.Envelope) *protocol.Envelope {
+	if message.Headers != nil && message.Headers.Values[protocol.HeaderCreationTime] != nil {
+		return message
+	}
+	stamped := *message
+	stamped.Headers = protocol.NewHeadersFrom(message.Headers, protocol.WithCreationTime(time.Now()))
+	return &stamped
+}
+
+// Get a copy of the envelope with a generated 'correlation-id' and a 'response-required' header of false,
+// if not already present
+func withSendDefaults(message *protocol.Envelope) *protocol.Envelope {
+	var opts []protocol.HeaderOpt
+	if message.Headers == nil || message.Headers.CorrelationID() == "" {
+		opts = append(opts, protocol.WithCorrelationID(protocol.NewCorrelationID()))
+	}
+	if message.Headers == nil || message.Headers.Values[protocol.HeaderResponseRequired] == nil {
+		opts = append(opts, protocol.WithResponseRequired(false))
+	}
+	if len(opts) == 0 {
+		return message
+	}
+	defaulted := *message
+	defaulted.Headers = protocol.NewHeadersFrom(message.Headers, opts...)
+	return &defaulted
+}
+
+// Get a copy of the reply envelope with the headers of the request envelope expected for the reply
+func withReplyHeaders(request *protocol.Envelope, message *protocol.Envelope) *protocol.Envelope {
+	reply := *message
+	reply.Headers = protocol.NewHeadersFrom(message.Headers, protocol.WithReplyHeadersFrom(request.Headers))
+	return &reply
+}
+
+// subscribedHandler is a Handler subscribed to a Client along with its subscription's ID and its code pointer,
+// which is resolved once on subscribing for the Handler to be unsubscribed by its value.
+type subscribedHandler struct {
+	id      HandlerID
+	handler Handler
+	code    uintptr
+}
+
+func handlerCode(handler Handler) uintptr {
+	return reflect.ValueOf(handler).Pointer()
+}
+
+func handlerName(code uintptr) string {
+	if fn := runtime.FuncForPC(code); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// activity tracks the number of ongoing operations, e.g. Handler invocations and publishes, for waiting until there are none.
+// Its zero value is ready to use.
+type activity struct {
+	lock  sync.Mutex
+	count int
+	// idle is closed once the count drops to zero, it is nil while there are no ongoing operations
+	idle chan struct{}
+}
+
+func (a *activity) start() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.count == 0 {
+		a.idle = make(chan struct{})
+	}
+	a.count++
+}
+
+func (a *activity) done() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.count--
+	if a.count == 0 {
+		close(a.idle)
+		a.idle = nil
+	}
+}
+
+// wait waits until the operations ongoing by the time of the call finish and returns false if they don't within the timeout.
+func (a *activity) wait(timeout time.Duration) bool {
+	a.lock.Lock()
+	idle := a.idle
+	a.lock.Unlock()
+
+	if idle == nil {
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return true
+	case <-timer.C:
+		return false
+	}
 }
 
 func validateConfiguration(cfg *Configuration) error {
@@ -70,6 +227,31 @@ func validateConfiguration(cfg *Configuration) error {
 		return errors.New("connectTimeout is not expected when using external MQTT client")
 	} else if cfg.tlsConfig != nil {
 		return errors.New("TLS configuration is not expected when using external MQTT client")
+	} else if cfg.protocolVersion != MQTTProtocolVersionDefault {
+		return errors.New("protocol version is not expected when using external MQTT client")
+	} else if cfg.clientID != "" {
+		return errors.New("client ID is not expected when using external MQTT client")
+	} else if cfg.store != nil {
+		return errors.New("store is not expected when using external MQTT client")
+	}
+	return validateConnectConfiguration(cfg)
+}
+
+// validateConnectConfiguration checks the Configuration for combinations the Client cannot be connected with.
+func validateConnectConfiguration(cfg *Configuration) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.store != nil && cfg.clientID == "" {
+		return errors.New("client ID is required when using a store")
+	}
+	if cfg.CommandQoS() > CommandQoSExactlyOnce {
+		return fmt.Errorf("invalid command QoS %d, expected 0, 1 or 2", cfg.CommandQoS())
+	}
+	for _, subscription := range cfg.subscriptions {
+		if subscription.Handler == nil && subscription.RawHandler == nil {
+			return fmt.Errorf("subscription to '%s' has neither a handler nor a raw handler", subscription.Topic)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package ditto
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+// The regular expression formerly used for extracting the request IDs, kept as the reference extractHonoRequestID is validated against.
+var regexHonoMQTTTopicRequest = regexp.MustCompile("^command///req/([^/]+)/([^/]+)$")
+
+func TestExtractHonoRequestID(t *testing.T) {
+	tests := map[string]struct {
+		topic string
+		want  string
+	}{
+		"test_request":              {topic: "command///req/req-id/modify", want: "req-id"},
+		"test_one_way":              {topic: "command///req//modify"},
+		"test_missing_command":      {topic: "command///req/req-id/"},
+		"test_missing_separator":    {topic: "command///req/req-id"},
+		"test_command_with_slashes": {topic: "command///req/req-id/modify/more"},
+		"test_response":             {topic: "command///res/req-id/204"},
+		"test_event":                {topic: "e"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := extractHonoRequestID(testCase.topic)
+			internal.AssertEqual(t, testCase.want, got)
+
+			var want string
+			if matches := regexHonoMQTTTopicRequest.FindStringSubmatch(testCase.topic); matches != nil {
+				want = matches[1]
+			}
+			internal.AssertEqual(t, want, got)
+		})
+	}
+}
+
+func TestExtractHonoRequestIDSharedSubscription(t *testing.T) {
+	tests := map[string]struct {
+		topic string
+		want  string
+	}{
+		"test_shared_request":      {topic: "$share/consumers/command///req/req-id/modify", want: "req-id"},
+		"test_shared_one_way":      {topic: "$share/consumers/command///req//modify"},
+		"test_share_without_group": {topic: "$share/command///req/req-id/modify"},
+		"test_share_prefix_only":   {topic: "$share/consumers"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, extractHonoRequestID(testCase.topic))
+		})
+	}
+}
+
+func TestExtractHonoRequestIDDeviceTopic(t *testing.T) {
+	tests := map[string]struct {
+		topic string
+		want  string
+	}{
+		"test_tenant_and_device":  {topic: "command/my-tenant/my-device/req/req-id/modify", want: "req-id"},
+		"test_device_only":        {topic: "command//my-device/req/req-id/modify", want: "req-id"},
+		"test_one_way":            {topic: "command/my-tenant/my-device/req//modify"},
+		"test_missing_device":     {topic: "command/my-tenant/req/req-id/modify"},
+		"test_response":           {topic: "command/my-tenant/my-device/res/req-id/204"},
+		"test_shared_device":      {topic: "$share/consumers/command/my-tenant/my-device/req/req-id/modify", want: "req-id"},
+		"test_missing_separators": {topic: "command/my-tenant"},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			internal.AssertEqual(t, testCase.want, extractHonoRequestID(testCase.topic))
+		})
+	}
+}
+
+func TestGenerateHonoResponseTopic(t *testing.T) {
+	internal.AssertEqual(t, "command///res/req-id/204", generateHonoResponseTopic("req-id", 204))
+	internal.AssertEqual(t, "command/my-tenant/my-device/res/req-id/204",
+		generateHonoDeviceResponseTopic("my-tenant", "my-device", "req-id", 204))
+}
+
+func TestSharedTopic(t *testing.T) {
+	internal.AssertEqual(t, honoMQTTTopicSubscribeCommands, sharedTopic("", honoMQTTTopicSubscribeCommands))
+	shared := sharedTopic("consumers", honoMQTTTopicSubscribeCommands)
+	internal.AssertEqual(t, "$share/consumers/command///req/#", shared)
+	internal.AssertEqual(t, honoMQTTTopicSubscribeCommands, stripSharePrefix(shared))
+}
+
+func TestActivity(t *testing.T) {
+	a := &activity{}
+	internal.AssertTrue(t, a.wait(time.Millisecond))
+
+	a.start()
+	a.start()
+	internal.AssertFalse(t, a.wait(10*time.Millisecond))
+
+	a.done()
+	internal.AssertFalse(t, a.wait(10*time.Millisecond))
+
+	go a.done()
+	internal.AssertTrue(t, a.wait(5*time.Second))
+	internal.AssertTrue(t, a.wait(time.Millisecond))
+
+	// the activity is reusable after getting idle
+	a.start()
+	internal.AssertFalse(t, a.wait(10*time.Millisecond))
+	a.done()
+	internal.AssertTrue(t, a.wait(time.Millisecond))
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package wot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+// GenerateThing generates the skeleton of the Thing with the provided ID from the Thing Model referenced by the provided
+// URL and resolved via the provided Resolver, e.g. to build a device's initial twin state from its model.
+// The Thing's definition is the model's URL and its attributes are generated from the model's properties. Each submodel
+// of the model is generated as a Feature named after the link's instance name, with the submodel's URL as its definition
+// and properties generated from the submodel's properties.
+// The generated properties have their default or constant values, the objects are generated from their nested
+// properties, and the required properties without such values have the zero values of their types. An object,
+// which is not required, is generated only if any of its nested properties has a default or constant value.
+// Returns an error if any of the Thing Models cannot be resolved.
+func GenerateThing(ctx context.Context, resolver Resolver, thingID *model.NamespacedID, modelURL string) (*model.Thing, error) {
+	definitionID := model.NewDefinitionIDFromURL(modelURL)
+	if definitionID == nil {
+		return nil, fmt.Errorf("invalid thing model URL '%s'", modelURL)
+	}
+	tm, err := resolver.Resolve(ctx, modelURL)
+	if err != nil {
+		return nil, err
+	}
+	thing := (&model.Thing{}).
+		WithID(thingID).
+		WithDefinition(definitionID).
+		WithAttributes(generateProperties(tm))
+
+	for _, link := range tm.Submodels() {
+		featureModelURL := resolveLink(modelURL, link)
+		featureTM, err := resolver.Resolve(ctx, featureModelURL)
+		if err != nil {
+			return nil, err
+		}
+		feature := (&model.Feature{}).WithProperties(generateProperties(featureTM))
+		if featureDefinitionID := model.NewDefinitionIDFromURL(featureModelURL); featureDefinitionID != nil {
+			feature.WithDefinition(featureDefinitionID)
+		}
+		thing.WithFeature(link.InstanceName, feature)
+	}
+	return thing, nil
+}
+
+func generateProperties(tm *ThingModel) map[string]interface{} {
+	var res map[string]interface{}
+	for name, schema := range tm.Properties {
+		value, ok := generateValue(schema, tm.IsRequired(name))
+		if !ok {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]interface{})
+		}
+		if schema.Category == "" {
+			res[name] = value
+			continue
+		}
+		category, _ := res[schema.Category].(map[string]interface{})
+		if category == nil {
+			category = make(map[string]interface{})
+			res[schema.Category] = category
+		}
+		category[name] = value
+	}
+	return res
+}
+
+func generateValue(schema *DataSchema, required bool) (interface{}, bool) {
+	if schema == nil {
+		return nil, false
+	}
+	if schema.Default != nil {
+		return schema.Default, true
+	}
+	if schema.Const != nil {
+		return schema.Const, true
+	}
+	if schema.Type == TypeObject {
+		object := make(map[string]interface{})
+		for name, nested := range schema.Properties {
+			if value, ok := generateValue(nested, required && containsString(schema.Required, name)); ok {
+				object[name] = value
+			}
+		}
+		if required {
+			return object, true
+		}
+		if len(object) > 0 {
+			// once generated, the object is to have its required properties as well
+			return generateValue(schema, true)
+		}
+		return nil, false
+	}
+	if !required {
+		return nil, false
+	}
+	switch schema.Type {
+	case TypeString:
+		return "", true
+	case TypeNumber, TypeInteger:
+		return 0, true
+	case TypeBoolean:
+		return false, true
+	case TypeArray:
+		return []interface{}{}, true
+	default:
+		return nil, true
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package wot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+func TestGenerateThing(t *testing.T) {
+	models := testModels(t)
+	thingID := model.NewNamespacedIDFrom("test.namespace:test-name")
+
+	got, err := GenerateThing(context.Background(), models, thingID, testLampModelURL)
+	internal.AssertNil(t, err)
+
+	want := (&model.Thing{}).
+		WithID(thingID).
+		WithDefinition(model.NewDefinitionIDFromURL(testLampModelURL)).
+		WithAttribute("serial", "").
+		WithFeature("switch", (&model.Feature{}).
+			WithDefinition(model.NewDefinitionIDFromURL(testSwitchModelURL)).
+			WithProperty("status", map[string]interface{}{"on": false}))
+	internal.AssertEqual(t, want, got)
+
+	report, err := Validate(context.Background(), models, got)
+	internal.AssertNil(t, err)
+	internal.AssertTrue(t, report.Valid())
+}
+
+func TestGenerateThingDefaults(t *testing.T) {
+	tm := &ThingModel{}
+	internal.AssertNil(t, json.Unmarshal([]byte(`{"properties":{
+		"brightness":{"type":"integer","default":50,"ditto:category":"configuration"},
+		"kind":{"type":"string","const":"lamp"},
+		"location":{"type":"object","properties":{"lat":{"type":"number","default":1.5},"lon":{"type":"number"},
+			"alt":{"type":"number"}},"required":["alt"]},
+		"optional":{"type":"object","properties":{"a":{"type":"string"}}},
+		"ratings":{"type":"array"}},
+		"tm:required":["#/properties/ratings"]}`), tm))
+	thingID := model.NewNamespacedIDFrom("test.namespace:test-name")
+
+	got, err := GenerateThing(context.Background(), Models{testSensorModelURL: tm}, thingID, testSensorModelURL)
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, map[string]interface{}{
+		"configuration": map[string]interface{}{"brightness": float64(50)},
+		"kind":          "lamp",
+		"location":      map[string]interface{}{"lat": 1.5, "alt": 0},
+		"ratings":       []interface{}{},
+	}, got.Attributes)
+	internal.AssertNil(t, got.Features)
+}
+
+func TestGenerateThingErrors(t *testing.T) {
+	thingID := model.NewNamespacedIDFrom("test.namespace:test-name")
+
+	tests := map[string]struct {
+		models   Models
+		modelURL string
+	}{
+		"test_invalid_model_url": {
+			models:   testModels(t),
+			modelURL: "test.namespace:lamp:1.0.0",
+		},
+		"test_unresolved_model": {
+			models:   Models{},
+			modelURL: testLampModelURL,
+		},
+		"test_unresolved_submodel": {
+			models:   Models{testLampModelURL: testModels(t)[testLampModelURL]},
+			modelURL: testLampModelURL,
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := GenerateThing(context.Background(), testCase.models, thingID, testCase.modelURL)
+			internal.AssertNotNil(t, err)
+		})
+	}
+}
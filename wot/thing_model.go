@@ -0,0 +1,180 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package wot provides the support of the WoT (Web of Things) Thing Models referenced by the Things' definitions,
+// as they are mapped by Ditto, e.g. to validate the Things against their models.
+package wot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Data schema types constants.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeNull    = "null"
+)
+
+// LinkRelSubmodel is the relation of the links to the submodels of a Thing Model, which Ditto maps to the Thing's Features.
+const LinkRelSubmodel = "tm:submodel"
+
+const requiredPropertyPrefix = "#/properties/"
+
+// ThingModel represents the parts of a WoT Thing Model (https://www.w3.org/TR/wot-thing-description11/#thing-model)
+// relevant to the Ditto Things. As Ditto maps the Thing Models, the properties of the Thing's model represent
+// its attributes, the properties of a Feature's model represent the Feature's properties and the submodels linked
+// by the Thing's model represent its Features, each named after the link's instance name.
+type ThingModel struct {
+	Title      string                 `json:"title,omitempty"`
+	Properties map[string]*DataSchema `json:"properties,omitempty"`
+	Required   []string               `json:"tm:required,omitempty"`
+	Links      []*Link                `json:"links,omitempty"`
+}
+
+// DataSchema represents a WoT data schema of a property or of a nested value of it.
+// The Category is the Ditto's 'ditto:category' of a property, which is nested in an object named after it.
+type DataSchema struct {
+	Type       string                 `json:"type,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Category   string                 `json:"ditto:category,omitempty"`
+	Default    interface{}            `json:"default,omitempty"`
+	Const      interface{}            `json:"const,omitempty"`
+	Enum       []interface{}          `json:"enum,omitempty"`
+	Properties map[string]*DataSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *DataSchema            `json:"items,omitempty"`
+}
+
+// Link represents a WoT link, e.g. to a submodel of a Thing Model.
+type Link struct {
+	Rel          string `json:"rel,omitempty"`
+	Href         string `json:"href"`
+	Type         string `json:"type,omitempty"`
+	InstanceName string `json:"instanceName,omitempty"`
+}
+
+// IsRequired returns true if the property with the provided name is listed as required by the ThingModel.
+func (tm *ThingModel) IsRequired(property string) bool {
+	for _, required := range tm.Required {
+		if strings.TrimPrefix(required, requiredPropertyPrefix) == property {
+			return true
+		}
+	}
+	return false
+}
+
+// Submodels returns the links to the submodels of the ThingModel.
+func (tm *ThingModel) Submodels() []*Link {
+	var res []*Link
+	for _, link := range tm.Links {
+		if link.Rel == LinkRelSubmodel {
+			res = append(res, link)
+		}
+	}
+	return res
+}
+
+// Resolver resolves the ThingModel documents referenced by URLs, e.g. by the URL DefinitionIDs of the Things.
+type Resolver interface {
+	Resolve(ctx context.Context, modelURL string) (*ThingModel, error)
+}
+
+// Models is a Resolver of the ThingModels already at hand, mapped by their URLs.
+type Models map[string]*ThingModel
+
+// Resolve provides the ThingModel mapped to the provided URL or an error if there is no such.
+func (models Models) Resolve(ctx context.Context, modelURL string) (*ThingModel, error) {
+	if tm, ok := models[modelURL]; ok {
+		return tm, nil
+	}
+	return nil, fmt.Errorf("unknown thing model '%s'", modelURL)
+}
+
+// HTTPResolver is a Resolver fetching the ThingModels over HTTP(S) and caching them by their URLs.
+type HTTPResolver struct {
+	client *http.Client
+
+	cacheLock sync.Mutex
+	cache     map[string]*ThingModel
+}
+
+// NewHTTPResolver creates a new HTTPResolver fetching the ThingModels using the provided HTTP client
+// or the http.DefaultClient if nil is provided.
+func NewHTTPResolver(client *http.Client) *HTTPResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPResolver{
+		client: client,
+		cache:  make(map[string]*ThingModel),
+	}
+}
+
+// Resolve fetches the ThingModel from the provided URL, unless it's already fetched.
+func (resolver *HTTPResolver) Resolve(ctx context.Context, modelURL string) (*ThingModel, error) {
+	resolver.cacheLock.Lock()
+	tm, ok := resolver.cache[modelURL]
+	resolver.cacheLock.Unlock()
+	if ok {
+		return tm, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, modelURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := resolver.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch thing model '%s': unexpected status %d", modelURL, resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	tm = &ThingModel{}
+	if err := json.Unmarshal(data, tm); err != nil {
+		return nil, fmt.Errorf("invalid thing model '%s': %v", modelURL, err)
+	}
+
+	resolver.cacheLock.Lock()
+	resolver.cache[modelURL] = tm
+	resolver.cacheLock.Unlock()
+	return tm, nil
+}
+
+// resolveLink resolves the provided link's URL, which may be relative, against the URL of the model it's part of.
+func resolveLink(modelURL string, link *Link) string {
+	base, err := url.Parse(modelURL)
+	if err != nil {
+		return link.Href
+	}
+	ref, err := url.Parse(link.Href)
+	if err != nil {
+		return link.Href
+	}
+	return base.ResolveReference(ref).String()
+}
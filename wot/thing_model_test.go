@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package wot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+)
+
+const testThingModelJSON = `{
+	"@context": ["https://www.w3.org/2022/wot/td/v1.1"],
+	"@type": "tm:ThingModel",
+	"title": "Lamp",
+	"properties": {
+		"serial": {"type": "string"},
+		"location": {"type": "object", "properties": {"lat": {"type": "number"}}, "required": ["lat"]}
+	},
+	"tm:required": ["#/properties/serial"],
+	"links": [
+		{"rel": "tm:submodel", "href": "./switch.tm.jsonld", "type": "application/tm+json", "instanceName": "switch"},
+		{"rel": "tm:extends", "href": "https://models.example.com/base.tm.jsonld"}
+	]
+}`
+
+func TestThingModelUnmarshalJSON(t *testing.T) {
+	tm := &ThingModel{}
+	internal.AssertNil(t, json.Unmarshal([]byte(testThingModelJSON), tm))
+
+	internal.AssertEqual(t, "Lamp", tm.Title)
+	internal.AssertEqual(t, TypeString, tm.Properties["serial"].Type)
+	internal.AssertEqual(t, []string{"lat"}, tm.Properties["location"].Required)
+	internal.AssertTrue(t, tm.IsRequired("serial"))
+	internal.AssertFalse(t, tm.IsRequired("location"))
+	internal.AssertEqual(t, []*Link{{
+		Rel:          LinkRelSubmodel,
+		Href:         "./switch.tm.jsonld",
+		Type:         "application/tm+json",
+		InstanceName: "switch",
+	}}, tm.Submodels())
+}
+
+func TestModelsResolve(t *testing.T) {
+	tm := &ThingModel{Title: "Lamp"}
+	models := Models{"https://models.example.com/lamp.tm.jsonld": tm}
+
+	got, err := models.Resolve(context.Background(), "https://models.example.com/lamp.tm.jsonld")
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, tm, got)
+
+	_, err = models.Resolve(context.Background(), "https://models.example.com/other.tm.jsonld")
+	internal.AssertNotNil(t, err)
+}
+
+func TestHTTPResolver(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/lamp.tm.jsonld":
+			w.Write([]byte(testThingModelJSON))
+		case "/invalid.tm.jsonld":
+			w.Write([]byte("invalid"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewHTTPResolver(server.Client())
+
+	got, err := resolver.Resolve(context.Background(), server.URL+"/lamp.tm.jsonld")
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, "Lamp", got.Title)
+
+	cached, err := resolver.Resolve(context.Background(), server.URL+"/lamp.tm.jsonld")
+	internal.AssertNil(t, err)
+	internal.AssertEqual(t, got, cached)
+	internal.AssertEqual(t, 1, requests)
+
+	_, err = resolver.Resolve(context.Background(), server.URL+"/missing.tm.jsonld")
+	internal.AssertNotNil(t, err)
+
+	_, err = resolver.Resolve(context.Background(), server.URL+"/invalid.tm.jsonld")
+	internal.AssertNotNil(t, err)
+}
+
+func TestResolveLink(t *testing.T) {
+	tests := map[string]struct {
+		href string
+		want string
+	}{
+		"test_relative_link": {
+			href: "./switch.tm.jsonld",
+			want: "https://models.example.com/lamp/switch.tm.jsonld",
+		},
+		"test_absolute_link": {
+			href: "https://other.example.com/switch.tm.jsonld",
+			want: "https://other.example.com/switch.tm.jsonld",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			got := resolveLink("https://models.example.com/lamp/lamp.tm.jsonld", &Link{Href: testCase.href})
+			internal.AssertEqual(t, testCase.want, got)
+		})
+	}
+}
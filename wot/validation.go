@@ -0,0 +1,216 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package wot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+// Violation represents a non-conformance of a Thing to its Thing Model, referenced by the Ditto path
+// of the non-conforming entity, e.g. '/features/Temp/properties/value'.
+type Violation struct {
+	Path   string
+	Reason string
+}
+
+// Report represents the result of a Thing's validation against its Thing Model.
+type Report struct {
+	Violations []*Violation
+}
+
+// Valid returns true if there are no violations reported.
+func (report *Report) Valid() bool {
+	return len(report.Violations) == 0
+}
+
+func (report *Report) add(path, format string, args ...interface{}) {
+	report.Violations = append(report.Violations, &Violation{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks that the provided Thing conforms to the Thing Models referenced by its URL definitions and resolved
+// via the provided Resolver. The Thing's attributes are validated against the properties of its model, the Features
+// linked as its model's submodels are required to be present and each Feature, having a submodel or a URL definition
+// on its own, has its properties validated against the properties of the Feature's model.
+// The properties marked as required are to be present and all known properties are to match the types of their schemas.
+// Returns an error if any of the Thing Models cannot be resolved.
+func Validate(ctx context.Context, resolver Resolver, thing *model.Thing) (*Report, error) {
+	report := &Report{}
+	featureModels := make(map[string]string)
+	if thing.DefinitionID != nil && thing.DefinitionID.IsURL() {
+		tm, err := resolver.Resolve(ctx, thing.DefinitionID.URL)
+		if err != nil {
+			return nil, err
+		}
+		validateProperties(report, "/attributes", tm, thing.Attributes)
+		for _, link := range tm.Submodels() {
+			featureModels[link.InstanceName] = resolveLink(thing.DefinitionID.URL, link)
+		}
+	}
+	for featureID, feature := range thing.Features {
+		if _, ok := featureModels[featureID]; ok || feature == nil {
+			continue
+		}
+		for _, definitionID := range feature.Definition {
+			if definitionID != nil && definitionID.IsURL() {
+				featureModels[featureID] = definitionID.URL
+				break
+			}
+		}
+	}
+
+	featureIDs := make([]string, 0, len(featureModels))
+	for featureID := range featureModels {
+		featureIDs = append(featureIDs, featureID)
+	}
+	sort.Strings(featureIDs)
+	for _, featureID := range featureIDs {
+		path := "/features/" + featureID
+		feature := thing.Features[featureID]
+		if feature == nil {
+			report.add(path, "missing feature of submodel '%s'", featureModels[featureID])
+			continue
+		}
+		tm, err := resolver.Resolve(ctx, featureModels[featureID])
+		if err != nil {
+			return nil, err
+		}
+		validateProperties(report, path+"/properties", tm, feature.Properties)
+	}
+	return report, nil
+}
+
+func validateProperties(report *Report, path string, tm *ThingModel, values map[string]interface{}) {
+	names := make([]string, 0, len(tm.Properties))
+	for name := range tm.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := tm.Properties[name]
+		propertyPath := path
+		container := values
+		if schema != nil && schema.Category != "" {
+			propertyPath = path + "/" + schema.Category
+			container, _ = values[schema.Category].(map[string]interface{})
+		}
+		propertyPath = propertyPath + "/" + name
+		value, ok := container[name]
+		if !ok {
+			if tm.IsRequired(name) {
+				report.add(propertyPath, "missing required property")
+			}
+			continue
+		}
+		validateValue(report, propertyPath, schema, value)
+	}
+}
+
+func validateValue(report *Report, path string, schema *DataSchema, value interface{}) {
+	if schema == nil {
+		return
+	}
+	if schema.Type != "" && !matchesType(schema.Type, value) {
+		report.add(path, "expected a value of type '%s', got %T", schema.Type, value)
+		return
+	}
+	if schema.Enum != nil && !containsValue(schema.Enum, value) {
+		report.add(path, "value %v is not one of %v", value, schema.Enum)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, required := range schema.Required {
+			if _, ok := v[required]; !ok {
+				report.add(path+"/"+required, "missing required property")
+			}
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if nested, ok := v[name]; ok {
+				validateValue(report, path+"/"+name, schema.Properties[name], nested)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			validateValue(report, fmt.Sprintf("%s/%d", path, i), schema.Items, item)
+		}
+	}
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	if value == nil {
+		return schemaType == TypeNull
+	}
+	v := reflect.ValueOf(value)
+	switch schemaType {
+	case TypeString:
+		_, isNumber := value.(json.Number)
+		return v.Kind() == reflect.String && !isNumber
+	case TypeBoolean:
+		return v.Kind() == reflect.Bool
+	case TypeNumber:
+		_, ok := numberValue(v)
+		return ok
+	case TypeInteger:
+		n, ok := numberValue(v)
+		return ok && n == math.Trunc(n)
+	case TypeObject:
+		return v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String
+	case TypeArray:
+		return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	case TypeNull:
+		return false
+	default:
+		return true
+	}
+}
+
+func numberValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		if n, ok := v.Interface().(json.Number); ok {
+			f, err := n.Float64()
+			return f, err == nil
+		}
+	}
+	return 0, false
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+		if n1, ok := numberValue(reflect.ValueOf(v)); ok {
+			if n2, ok := numberValue(reflect.ValueOf(value)); ok && n1 == n2 {
+				return true
+			}
+		}
+	}
+	return false
+}
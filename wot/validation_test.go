@@ -0,0 +1,144 @@
+// Copyright (c) 2022 Contributors to the Eclipse Foundation
+//
+// See the NOTICE file(s) distributed with this work for additional
+// information regarding copyright ownership.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0 which is available at
+// http://www.eclipse.org/legal/epl-2.0
+//
+// SPDX-License-Identifier: EPL-2.0
+
+package wot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eclipse/ditto-clients-golang/internal"
+	"github.com/eclipse/ditto-clients-golang/model"
+)
+
+const (
+	testLampModelURL   = "https://models.example.com/lamp.tm.jsonld"
+	testSwitchModelURL = "https://models.example.com/switch.tm.jsonld"
+	testSensorModelURL = "https://models.example.com/sensor.tm.jsonld"
+)
+
+func testModels(t *testing.T) Models {
+	models := Models{}
+	for modelURL, data := range map[string]string{
+		testLampModelURL: testThingModelJSON,
+		testSwitchModelURL: `{"properties":{
+			"on":{"type":"boolean","ditto:category":"status"},
+			"mode":{"type":"string","enum":["auto","manual"],"ditto:category":"configuration"}},
+			"tm:required":["#/properties/on"]}`,
+		testSensorModelURL: `{"properties":{
+			"value":{"type":"integer"},
+			"history":{"type":"array","items":{"type":"number"}}}}`,
+	} {
+		tm := &ThingModel{}
+		internal.AssertNil(t, json.Unmarshal([]byte(data), tm))
+		models[modelURL] = tm
+	}
+	return models
+}
+
+func newValidationTestThing() *model.Thing {
+	return (&model.Thing{}).
+		WithIDFrom("test.namespace:test-name").
+		WithDefinition(model.NewDefinitionIDFromURL(testLampModelURL)).
+		WithAttribute("serial", "SN-1").
+		WithAttribute("location", map[string]interface{}{"lat": 1.5}).
+		WithFeature("switch", (&model.Feature{}).
+			WithProperty("status", map[string]interface{}{"on": true}).
+			WithProperty("configuration", map[string]interface{}{"mode": "auto"})).
+		WithFeature("sensor", (&model.Feature{}).
+			WithDefinition(model.NewDefinitionIDFromURL(testSensorModelURL)).
+			WithProperty("value", json.Number("21")).
+			WithProperty("history", []interface{}{1, 2.5}))
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		change func(thing *model.Thing)
+		want   []*Violation
+	}{
+		"test_valid_thing": {
+			change: func(thing *model.Thing) {},
+		},
+		"test_thing_without_definition": {
+			change: func(thing *model.Thing) {
+				thing.DefinitionID = model.NewDefinitionIDFrom("test.namespace:lamp:1.0.0")
+				thing.Attributes = nil
+				delete(thing.Features, "switch")
+			},
+		},
+		"test_missing_required_attribute": {
+			change: func(thing *model.Thing) {
+				delete(thing.Attributes, "serial")
+			},
+			want: []*Violation{{Path: "/attributes/serial", Reason: "missing required property"}},
+		},
+		"test_missing_nested_required_attribute": {
+			change: func(thing *model.Thing) {
+				thing.Attributes["location"] = map[string]interface{}{}
+			},
+			want: []*Violation{{Path: "/attributes/location/lat", Reason: "missing required property"}},
+		},
+		"test_attribute_type_mismatch": {
+			change: func(thing *model.Thing) {
+				thing.Attributes["serial"] = 1
+			},
+			want: []*Violation{{Path: "/attributes/serial", Reason: "expected a value of type 'string', got int"}},
+		},
+		"test_missing_submodel_feature": {
+			change: func(thing *model.Thing) {
+				delete(thing.Features, "switch")
+			},
+			want: []*Violation{{Path: "/features/switch", Reason: "missing feature of submodel '" + testSwitchModelURL + "'"}},
+		},
+		"test_missing_required_categorized_property": {
+			change: func(thing *model.Thing) {
+				delete(thing.Features["switch"].Properties, "status")
+			},
+			want: []*Violation{{Path: "/features/switch/properties/status/on", Reason: "missing required property"}},
+		},
+		"test_enum_mismatch": {
+			change: func(thing *model.Thing) {
+				thing.Features["switch"].Properties["configuration"] = map[string]interface{}{"mode": "off"}
+			},
+			want: []*Violation{{Path: "/features/switch/properties/configuration/mode", Reason: "value off is not one of [auto manual]"}},
+		},
+		"test_integer_mismatch": {
+			change: func(thing *model.Thing) {
+				thing.Features["sensor"].Properties["value"] = 21.5
+			},
+			want: []*Violation{{Path: "/features/sensor/properties/value", Reason: "expected a value of type 'integer', got float64"}},
+		},
+		"test_array_item_mismatch": {
+			change: func(thing *model.Thing) {
+				thing.Features["sensor"].Properties["history"] = []interface{}{1, "2"}
+			},
+			want: []*Violation{{Path: "/features/sensor/properties/history/1", Reason: "expected a value of type 'number', got string"}},
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			thing := newValidationTestThing()
+			testCase.change(thing)
+
+			got, err := Validate(context.Background(), testModels(t), thing)
+			internal.AssertNil(t, err)
+			internal.AssertEqual(t, testCase.want, got.Violations)
+			internal.AssertEqual(t, testCase.want == nil, got.Valid())
+		})
+	}
+}
+
+func TestValidateUnresolvedModel(t *testing.T) {
+	_, err := Validate(context.Background(), Models{}, newValidationTestThing())
+	internal.AssertNotNil(t, err)
+}